@@ -168,7 +168,7 @@ func generateSchema(swaggerPath string) schema.PackageSpec {
 }
 
 // This is to mostly filter resources from the spec.
-var resourcesToFilterFromTemplate = codegen.NewStringSet("kubernetes:helm.sh/v3:Release")
+var resourcesToFilterFromTemplate = codegen.NewStringSet("kubernetes:helm.sh/v3:Release", "kubernetes:index:PodExec")
 
 func writeNodeJSClient(pkg *schema.Package, outdir, templateDir string) {
 	resources, err := nodejsgen.LanguageResources(pkg)
@@ -376,12 +376,17 @@ func writeGoClient(pkg *schema.Package, outdir string, templateDir string) {
 	// `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes/providers` that was later removed in v3.6.0.
 	// Re-add this file with deprecation notices in preparation for future removal.
 	deprecatedComment := "// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead"
-	deprecatedProviderFile := string(files["kubernetes/provider.go"])
-	deprecatedProviderFile = strings.ReplaceAll(deprecatedProviderFile, "\nfunc", fmt.Sprintf("\n%s\nfunc", deprecatedComment))
-	deprecatedProviderFile = strings.ReplaceAll(deprecatedProviderFile, "\ntype", fmt.Sprintf("\n%s\ntype", deprecatedComment))
-	files["kubernetes/providers/provider.go"] = []byte(deprecatedProviderFile)
+	// The types and utilities that the Provider uses are copied along with it.
+	for _, f := range []string{"provider.go", "pulumiTypes.go"} {
+		deprecatedFile := string(files["kubernetes/"+f])
+		deprecatedFile = strings.ReplaceAll(deprecatedFile, "\nfunc", fmt.Sprintf("\n%s\nfunc", deprecatedComment))
+		deprecatedFile = strings.ReplaceAll(deprecatedFile, "\ntype", fmt.Sprintf("\n%s\ntype", deprecatedComment))
+		files["kubernetes/providers/"+f] = []byte(deprecatedFile)
+	}
+	files["kubernetes/providers/pulumiUtilities.go"] = files["kubernetes/pulumiUtilities.go"]
 
-	files["kubernetes/pulumiTypes.go"] = mustLoadGoFile(filepath.Join(templateDir, "pulumiTypes.go"))
+	// Rename pulumiTypes.go to avoid conflict with schema generated types, such as the Impersonation provider config.
+	files["kubernetes/untypedPulumiTypes.go"] = mustLoadGoFile(filepath.Join(templateDir, "pulumiTypes.go"))
 	files["kubernetes/apiextensions/customResource.go"] = mustLoadGoFile(filepath.Join(templateDir, "apiextensions", "customResource.go"))
 	files["kubernetes/helm/v2/chart.go"] = mustLoadGoFile(filepath.Join(templateDir, "helm", "v2", "chart.go"))
 	files["kubernetes/helm/v2/pulumiTypes.go"] = mustLoadGoFile(filepath.Join(templateDir, "helm", "v2", "pulumiTypes.go"))
@@ -3,9 +3,13 @@ module github.com/pulumi/pulumi-kubernetes/provider/v3
 go 1.16
 
 require (
+	filippo.io/age v1.2.0
+	github.com/ProtonMail/go-crypto v1.1.6
 	github.com/ahmetb/go-linq v3.0.0+incompatible
+	github.com/bitnami-labs/sealed-secrets v0.16.0
 	github.com/evanphx/json-patch v4.11.0+incompatible
 	github.com/golang/protobuf v1.5.2
+	github.com/google/cel-go v0.12.6
 	github.com/googleapis/gnostic v0.5.1
 	github.com/imdario/mergo v0.3.12
 	github.com/mitchellh/mapstructure v1.4.1
@@ -15,11 +19,13 @@ require (
 	github.com/pulumi/pulumi/pkg/v3 v3.9.0
 	github.com/pulumi/pulumi/sdk/v3 v3.9.0
 	github.com/stretchr/testify v1.7.0
-	golang.org/x/crypto v0.0.0-20210506145944-38f3c27a63bf
-	google.golang.org/grpc v1.37.0
-	gopkg.in/yaml.v3 v3.0.0-20200615113413-eeeca48fe776
+	go.mozilla.org/sops/v3 v3.7.1
+	golang.org/x/crypto v0.24.0
+	google.golang.org/grpc v1.46.0
+	gopkg.in/yaml.v3 v3.0.0-20210107172259-749611fa9fcc
 	helm.sh/helm/v3 v3.6.3
 	k8s.io/api v0.21.0
+	k8s.io/apiextensions-apiserver v0.21.0
 	k8s.io/apimachinery v0.21.0
 	k8s.io/cli-runtime v0.21.0
 	k8s.io/client-go v0.21.0
@@ -27,6 +33,7 @@ require (
 	k8s.io/kubectl v0.21.0
 	sigs.k8s.io/kustomize/api v0.8.11
 	sigs.k8s.io/kustomize/kyaml v0.11.0
+	sigs.k8s.io/structured-merge-diff/v4 v4.1.0
 	sigs.k8s.io/yaml v1.2.0
 )
 
@@ -82,6 +82,8 @@ type UpdateConfig struct {
 	Inputs   *unstructured.Unstructured
 	Timeout  float64
 	DryRun   bool
+	// IgnoreFields are the paths of fields that are excluded from the update patch.
+	IgnoreFields []openapi.FieldPath
 }
 
 type DeleteConfig struct {
@@ -365,8 +367,12 @@ func Update(c UpdateConfig) (*unstructured.Unstructured, error) {
 		return nil, err
 	}
 
-	// Create merge patch (prefer strategic merge patch, fall back to JSON merge patch).
-	patch, patchType, _, err := openapi.PatchForResourceUpdate(c.Resources, c.Previous, c.Inputs, liveOldObj)
+	// Create merge patch (prefer strategic merge patch, fall back to JSON merge patch). Ignored fields are removed
+	// from all three objects, so that the patch neither sets nor clears them.
+	patch, patchType, _, err := openapi.PatchForResourceUpdate(c.Resources,
+		openapi.WithoutFields(c.Previous, c.IgnoreFields),
+		openapi.WithoutFields(c.Inputs, c.IgnoreFields),
+		openapi.WithoutFields(liveOldObj, c.IgnoreFields))
 	if err != nil {
 		return nil, err
 	}
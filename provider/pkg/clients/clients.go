@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/kinds"
 	corev1 "k8s.io/api/core/v1"
@@ -58,6 +59,23 @@ func NewDynamicClientSet(clientConfig *rest.Config) (*DynamicClientSet, error) {
 		return nil, fmt.Errorf("failed to initialize discovery client: %v", err)
 	}
 
+	return newDynamicClientSet(clientConfig, disco)
+}
+
+// NewDynamicClientSetWithDiskCache returns a client set whose discovery information is also cached on disk, in the
+// given directory and for the given TTL. See NewDiskCachedDiscoveryClient.
+func NewDynamicClientSetWithDiskCache(
+	clientConfig *rest.Config, cacheDir string, ttl time.Duration,
+) (*DynamicClientSet, error) {
+	disco, err := NewDiskCachedDiscoveryClient(clientConfig, cacheDir, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return newDynamicClientSet(clientConfig, disco)
+}
+
+func newDynamicClientSet(clientConfig *rest.Config, disco discovery.DiscoveryInterface) (*DynamicClientSet, error) {
 	// Cache the discovery information (OpenAPI schema, etc.) so we don't have to retrieve it for
 	// every request.
 	discoCacheClient := NewMemCacheClient(disco)
@@ -204,10 +222,25 @@ func NewLogClient(clientConfig *rest.Config) (*LogClient, error) {
 	return &LogClient{clientset: clientset}, nil
 }
 
-func (lc *LogClient) Logs(namespace, name string) (io.ReadCloser, error) {
-	podLogOpts := corev1.PodLogOptions{Follow: true}
-	req := lc.clientset.CoreV1().Pods(namespace).GetLogs(name, &podLogOpts)
-	return req.Stream(context.TODO())
+// Logs opens a stream of the logs of the named Pod. The stream is closed when the context is cancelled.
+func (lc *LogClient) Logs(
+	ctx context.Context, namespace, name string, opts *corev1.PodLogOptions,
+) (io.ReadCloser, error) {
+	req := lc.clientset.CoreV1().Pods(namespace).GetLogs(name, opts)
+	return req.Stream(ctx)
+}
+
+// PodNames returns the names of the Pods in the namespace that match the label selector.
+func (lc *LogClient) PodNames(ctx context.Context, namespace, labelSelector string) ([]string, error) {
+	pods, err := lc.clientset.CoreV1().Pods(namespace).List(ctx, v1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		names = append(names, pod.Name)
+	}
+	return names, nil
 }
 
 type NoNamespaceInfoErr struct {
@@ -0,0 +1,204 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/pkg/apis/clientauthentication"
+	"k8s.io/client-go/pkg/apis/clientauthentication/v1alpha1"
+	"k8s.io/client-go/pkg/apis/clientauthentication/v1beta1"
+	execplugin "k8s.io/client-go/plugin/pkg/client/auth/exec"
+	"k8s.io/client-go/rest"
+	clientapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/transport"
+)
+
+// ShareCredentials returns a copy of the client config whose credentials are shared by every client built from the
+// copy, rather than requested again for each client.
+//
+// Exec plugins are run by the exec authenticator of client-go, which is shared by every client config with the same
+// exec config, including the configs that the Helm clients load from the kubeconfig themselves. It refreshes the
+// credentials when they expire, or when the API server rejects them. The plugin is run once up front, and a plugin
+// that fails, then or on a later refresh, is reported with its command and standard error. Auth provider plugins are
+// shared by wrapping every transport with the same instance of the plugin.
+func ShareCredentials(config *rest.Config) (*rest.Config, error) {
+	if config.ExecProvider != nil {
+		return shareExecCredentials(config)
+	}
+	shared := rest.CopyConfig(config)
+	if config.AuthProvider != nil {
+		provider, err := rest.GetAuthProvider(config.Host, config.AuthProvider, config.AuthConfigPersister)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load auth provider %q: %v", config.AuthProvider.Name, err)
+		}
+		shared.AuthProvider = nil
+		shared.Wrap(provider.WrapTransport)
+	}
+	return shared, nil
+}
+
+// shareExecCredentials returns a copy of a client config with an exec plugin, whose transports are wrapped with the
+// shared exec authenticator of client-go themselves, so that the failures of the plugin can be reported. Client
+// certificates can only be set up by client-go, so the config is left as it is if the plugin returns one.
+func shareExecCredentials(config *rest.Config) (*rest.Config, error) {
+	var cluster *clientauthentication.Cluster
+	if config.ExecProvider.ProvideClusterInfo {
+		var err error
+		if cluster, err = rest.ConfigToExecCluster(config); err != nil {
+			return nil, err
+		}
+	}
+	authenticator, err := execplugin.GetAuthenticator(config.ExecProvider, cluster)
+	if err != nil {
+		return nil, err
+	}
+	transportConfig := &transport.Config{
+		BearerToken:     config.BearerToken,
+		BearerTokenFile: config.BearerTokenFile,
+		Dial:            config.Dial,
+	}
+	if err := authenticator.UpdateTransportConfig(transportConfig); err != nil {
+		return nil, err
+	}
+	// A token in the config takes precedence over the plugin.
+	if transportConfig.WrapTransport == nil {
+		return rest.CopyConfig(config), nil
+	}
+
+	cert, err := transportConfig.TLS.GetCert()
+	if err != nil {
+		return nil, execPluginError(config.ExecProvider, cluster, err)
+	}
+	if cert != nil {
+		return rest.CopyConfig(config), nil
+	}
+
+	shared := rest.CopyConfig(config)
+	shared.ExecProvider = nil
+	// The dialer of the authenticator closes the connections that were made with rotated credentials.
+	shared.Dial = transportConfig.Dial
+	shared.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &execPluginRoundTripper{
+			config:  config.ExecProvider,
+			cluster: cluster,
+			rt:      transportConfig.WrapTransport(rt),
+		}
+	})
+	return shared, nil
+}
+
+// execPluginRoundTripper reports the failures of the exec plugin that the round tripper it wraps gets credentials
+// from.
+type execPluginRoundTripper struct {
+	config  *clientapi.ExecConfig
+	cluster *clientauthentication.Cluster
+	rt      http.RoundTripper
+}
+
+func (rt *execPluginRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := rt.rt.RoundTrip(req)
+	// The exec authenticator reports the failure of the plugin as an error getting credentials.
+	if err != nil && strings.HasPrefix(err.Error(), "getting credentials: ") {
+		return nil, execPluginError(rt.config, rt.cluster, err)
+	}
+	return resp, err
+}
+
+func (rt *execPluginRoundTripper) WrappedRoundTripper() http.RoundTripper { return rt.rt }
+
+// execPluginScheme encodes the ExecCredential that is passed to exec plugins, as the exec authenticator does.
+var execPluginScheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(v1alpha1.AddToScheme(execPluginScheme))
+	utilruntime.Must(v1beta1.AddToScheme(execPluginScheme))
+	utilruntime.Must(clientauthentication.AddToScheme(execPluginScheme))
+}
+
+// execPluginError returns an error that reports the failure of an exec plugin with its command. The exec authenticator
+// passes the standard error of the plugin through to that of the provider, where it is not shown, and only reports
+// the exit code of a plugin that exits with an error, so such a plugin is run again to capture its standard error.
+func execPluginError(config *clientapi.ExecConfig, cluster *clientauthentication.Cluster, err error) error {
+	if !strings.Contains(err.Error(), "failed with exit code") {
+		return fmt.Errorf("exec plugin %q failed: %v", config.Command, err)
+	}
+
+	cred := &clientauthentication.ExecCredential{Spec: clientauthentication.ExecCredentialSpec{Cluster: cluster}}
+	gv, parseErr := schema.ParseGroupVersion(config.APIVersion)
+	if parseErr != nil {
+		return fmt.Errorf("exec plugin %q failed: %v", config.Command, err)
+	}
+	info, encodeErr := runtime.Encode(serializer.NewCodecFactory(execPluginScheme).LegacyCodec(gv), cred)
+	if encodeErr != nil {
+		return fmt.Errorf("exec plugin %q failed: %v", config.Command, err)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(config.Command, config.Args...)
+	cmd.Env = os.Environ()
+	for _, env := range config.Env {
+		cmd.Env = append(cmd.Env, env.Name+"="+env.Value)
+	}
+	cmd.Env = append(cmd.Env, "KUBERNETES_EXEC_INFO="+string(info))
+	cmd.Stdout = ioutil.Discard
+	cmd.Stderr = &stderr
+	if cmd.Run() == nil || strings.TrimSpace(stderr.String()) == "" {
+		return fmt.Errorf("exec plugin %q failed: %v", config.Command, err)
+	}
+	return fmt.Errorf("exec plugin %q failed: %v: %s", config.Command, err, strings.TrimSpace(stderr.String()))
+}
+
+// impersonateUIDHeader is the header that impersonates the UID of a user. The impersonation config of client-go does
+// not support it yet.
+const impersonateUIDHeader = "Impersonate-Uid"
+
+// ImpersonateUID returns a copy of the client config that also impersonates the given UID in every request that
+// impersonates a user. Requests that do not impersonate a user are sent as they are.
+func ImpersonateUID(config *rest.Config, uid string) *rest.Config {
+	impersonating := rest.CopyConfig(config)
+	impersonating.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &impersonateUIDRoundTripper{uid: uid, rt: rt}
+	})
+	return impersonating
+}
+
+type impersonateUIDRoundTripper struct {
+	uid string
+	rt  http.RoundTripper
+}
+
+func (rt *impersonateUIDRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The user is impersonated by a wrapper that runs before this one.
+	if req.Header.Get(transport.ImpersonateUserHeader) == "" || req.Header.Get(impersonateUIDHeader) != "" {
+		return rt.rt.RoundTrip(req)
+	}
+	req = utilnet.CloneRequest(req)
+	req.Header.Set(impersonateUIDHeader, rt.uid)
+	return rt.rt.RoundTrip(req)
+}
+
+func (rt *impersonateUIDRoundTripper) WrappedRoundTripper() http.RoundTripper { return rt.rt }
@@ -0,0 +1,146 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+	clientapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/transport"
+)
+
+func TestShareCredentialsExecPlugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test plugin is a shell script")
+	}
+
+	// The plugin returns a token that changes on every run.
+	runs := filepath.Join(t.TempDir(), "runs")
+	script := fmt.Sprintf(`echo x >> %[1]q; n=$(wc -l < %[1]q | tr -d ' '); `+
+		`echo '{"apiVersion": "client.authentication.k8s.io/v1beta1", "kind": "ExecCredential", '`+
+		`'"status": {"token": "token-'$n'"}}'`, runs)
+
+	var authorizations []string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	config, err := ShareCredentials(&rest.Config{
+		Host: server.URL,
+		ExecProvider: &clientapi.ExecConfig{
+			Command:    "sh",
+			Args:       []string{"-c", script},
+			APIVersion: "client.authentication.k8s.io/v1beta1",
+		},
+	})
+	require.NoError(t, err)
+
+	// Clients that are built from copies of the config share the credentials of the plugin.
+	for i := 0; i < 2; i++ {
+		rt, err := rest.TransportFor(rest.CopyConfig(config))
+		require.NoError(t, err)
+		resp, err := (&http.Client{Transport: rt}).Get(server.URL + "/api")
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+	assert.Equal(t, []string{"Bearer token-1", "Bearer token-1"}, authorizations)
+}
+
+func TestShareCredentialsExecPluginError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test plugin is a shell script")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	execConfig := func(script string) *clientapi.ExecConfig {
+		return &clientapi.ExecConfig{
+			Command:    "sh",
+			Args:       []string{"-c", script},
+			APIVersion: "client.authentication.k8s.io/v1beta1",
+		}
+	}
+
+	// A plugin that fails up front is reported with its standard error.
+	_, err := ShareCredentials(&rest.Config{
+		Host:         server.URL,
+		ExecProvider: execConfig(`echo 'the session has expired, log in again' >&2; exit 1`),
+	})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `exec plugin "sh" failed`)
+		assert.Contains(t, err.Error(), "the session has expired, log in again")
+	}
+
+	// The first run returns credentials that have already expired, and every later run fails.
+	runs := filepath.Join(t.TempDir(), "runs")
+	script := fmt.Sprintf(`if [ -e %[1]q ]; then echo 'refresh token revoked' >&2; exit 2; fi; touch %[1]q; `+
+		`echo '{"apiVersion": "client.authentication.k8s.io/v1beta1", "kind": "ExecCredential", '`+
+		`'"status": {"token": "token", "expirationTimestamp": "2000-01-01T00:00:00Z"}}'`, runs)
+	config, err := ShareCredentials(&rest.Config{Host: server.URL, ExecProvider: execConfig(script)})
+	require.NoError(t, err)
+
+	// A plugin that fails to refresh the credentials is reported with its standard error too.
+	rt, err := rest.TransportFor(config)
+	require.NoError(t, err)
+	_, err = (&http.Client{Transport: rt}).Get(server.URL + "/api")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `exec plugin "sh" failed`)
+		assert.Contains(t, err.Error(), "refresh token revoked")
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestImpersonateUID(t *testing.T) {
+	var headers http.Header
+	server := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		headers = req.Header
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+	})
+	send := func(config *rest.Config) {
+		rt, err := rest.HTTPWrappersForConfig(ImpersonateUID(config, "1234"), server)
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodGet, "https://cluster/api", nil)
+		require.NoError(t, err)
+		_, err = rt.RoundTrip(req)
+		require.NoError(t, err)
+	}
+
+	send(&rest.Config{Impersonate: rest.ImpersonationConfig{UserName: "deployer", Groups: []string{"tenants"}}})
+	assert.Equal(t, "deployer", headers.Get(transport.ImpersonateUserHeader))
+	assert.Equal(t, []string{"tenants"}, headers.Values(transport.ImpersonateGroupHeader))
+	assert.Equal(t, "1234", headers.Get(impersonateUIDHeader))
+
+	// The UID is only impersonated along with a user.
+	send(&rest.Config{})
+	assert.Empty(t, headers.Get(impersonateUIDHeader))
+}
@@ -0,0 +1,62 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/discovery"
+	diskcached "k8s.io/client-go/discovery/cached/disk"
+	"k8s.io/client-go/rest"
+)
+
+// illegalCacheDirCharacters matches the characters of a server URL that are replaced in the name of its cache
+// directory, in the same way as kubectl.
+var illegalCacheDirCharacters = regexp.MustCompile(`[^(\w/.)]`)
+
+// discoveryCacheDir returns the directory of the discovery cache for the given API server and version.
+func discoveryCacheDir(cacheDir, host, version string) string {
+	schemeless := strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	return filepath.Join(cacheDir, "discovery",
+		illegalCacheDirCharacters.ReplaceAllString(schemeless, "_"),
+		illegalCacheDirCharacters.ReplaceAllString(version, "_"))
+}
+
+// NewDiskCachedDiscoveryClient returns a discovery client that caches discovery information on disk, so that it is
+// shared by every provider process that talks to the same API server. The API groups and resources are cached for the
+// given TTL, in a directory that is keyed by the URL and version of the API server, so that an upgrade of the cluster
+// is seen immediately. The OpenAPI schema is cached in an HTTP cache, and revalidated with its ETag on every request,
+// so that it is only downloaded when it has changed.
+//
+// Invalidating the client ignores the cached API groups and resources that were written before it was invalidated.
+func NewDiskCachedDiscoveryClient(
+	clientConfig *rest.Config, cacheDir string, ttl time.Duration,
+) (discovery.CachedDiscoveryInterface, error) {
+	disco, err := discovery.NewDiscoveryClientForConfig(clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize discovery client: %v", err)
+	}
+	version, err := disco.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the version of the API server: %v", err)
+	}
+
+	return diskcached.NewCachedDiscoveryClientForConfig(clientConfig,
+		discoveryCacheDir(cacheDir, clientConfig.Host, version.GitVersion), filepath.Join(cacheDir, "http"), ttl)
+}
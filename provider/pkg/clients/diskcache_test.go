@@ -0,0 +1,113 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/rest"
+)
+
+func TestDiscoveryCacheDir(t *testing.T) {
+	assert.Equal(t, filepath.Join("cache", "discovery", "1.2.3.4_6443", "v1.21.2"),
+		discoveryCacheDir("cache", "https://1.2.3.4:6443", "v1.21.2"))
+	assert.Equal(t, filepath.Join("cache", "discovery", "cluster.example.com", "v1.21.2_gke.100"),
+		discoveryCacheDir("cache", "https://cluster.example.com", "v1.21.2+gke.100"))
+}
+
+// fakeDiscoveryServer serves the discovery documents of an API server with only the core API group, and counts the
+// requests for them.
+type fakeDiscoveryServer struct {
+	mu       sync.Mutex
+	requests map[string]int
+}
+
+func (s *fakeDiscoveryServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	s.requests[req.URL.Path]++
+	s.mu.Unlock()
+
+	var body interface{}
+	switch req.URL.Path {
+	case "/version":
+		body = version.Info{GitVersion: "v1.21.2"}
+	case "/api":
+		body = metav1.APIVersions{TypeMeta: metav1.TypeMeta{Kind: "APIVersions"}, Versions: []string{"v1"}}
+	case "/apis":
+		body = metav1.APIGroupList{TypeMeta: metav1.TypeMeta{Kind: "APIGroupList", APIVersion: "v1"}}
+	case "/api/v1":
+		body = metav1.APIResourceList{
+			TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{{Name: "pods", Namespaced: true, Kind: "Pod", Verbs: []string{"get"}}},
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func (s *fakeDiscoveryServer) count(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+func TestDiskCachedDiscoveryClient(t *testing.T) {
+	fake := &fakeDiscoveryServer{requests: map[string]int{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	cacheDir, err := ioutil.TempDir("", "discovery-cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	config := &rest.Config{Host: server.URL}
+	resources := func() *DynamicClientSet {
+		cs, err := NewDynamicClientSetWithDiskCache(config, cacheDir, time.Hour)
+		require.NoError(t, err)
+		list, err := cs.DiscoveryClientCached.ServerResourcesForGroupVersion("v1")
+		require.NoError(t, err)
+		assert.Equal(t, "pods", list.APIResources[0].Name)
+		return cs
+	}
+
+	// The first client discovers the API server, and the second one, e.g., in another provider process, reads the
+	// cache.
+	resources()
+	assert.Equal(t, 1, fake.count("/api/v1"))
+	cs := resources()
+	assert.Equal(t, 1, fake.count("/api/v1"))
+	assert.Equal(t, 2, fake.count("/version"))
+
+	// Invalidating the cache, e.g., after creating a CRD, discovers the API server again.
+	cs.DiscoveryClientCached.Invalidate()
+	_, err = cs.DiscoveryClientCached.ServerResourcesForGroupVersion("v1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, fake.count("/api/v1"))
+}
@@ -0,0 +1,178 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// LimitRate returns a copy of the client config whose requests are limited to the given number of queries per second,
+// with bursts of up to the given number of requests. Unlike the QPS and Burst of a client config, which limit every
+// client that is built from the config separately, the limit is shared by every client that is built from the copy.
+// A QPS or burst of 0 uses the client-go default.
+func LimitRate(config *rest.Config, qps float32, burst int) *rest.Config {
+	if qps == 0 {
+		qps = rest.DefaultQPS
+	}
+	if burst == 0 {
+		burst = rest.DefaultBurst
+	}
+	limited := rest.CopyConfig(config)
+	limited.QPS, limited.Burst = qps, burst
+	limited.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(qps, burst)
+	return limited
+}
+
+// watchSlotTimeout is how long a watch waits for another watch to be stopped when the limit of open watches is
+// reached.
+const watchSlotTimeout = 10 * time.Second
+
+// LimitWatches returns a client that allows at most max watches to be open at the same time. This includes the watches
+// of informers that are built from the client. A watch that would exceed the limit waits until another watch is
+// stopped, or until its context is cancelled.
+//
+// As an await opens several informers, e.g., for the ReplicaSets and Pods of a Deployment, concurrent awaits could
+// each hold some of the watches while waiting for the others. A watch therefore fails with a 429 Too Many Requests
+// error if it waits longer than watchSlotTimeout. An informer whose watch fails lists its objects again after a
+// backoff, so that the await still sees the changes, and then tries to watch again.
+func LimitWatches(client dynamic.Interface, max int) dynamic.Interface {
+	return limitWatches(client, max, watchSlotTimeout)
+}
+
+func limitWatches(client dynamic.Interface, max int, timeout time.Duration) dynamic.Interface {
+	return &watchLimitedClient{Interface: client, slots: &watchSlots{slots: make(chan struct{}, max), timeout: timeout}}
+}
+
+// watchSlots are the watches that may be open at the same time.
+type watchSlots struct {
+	slots   chan struct{}
+	timeout time.Duration
+}
+
+// acquire waits until a slot is free, the context is cancelled, or the timeout has elapsed.
+func (s *watchSlots) acquire(ctx context.Context) error {
+	timer := time.NewTimer(s.timeout)
+	defer timer.Stop()
+	select {
+	case s.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return errors.NewTooManyRequests(
+			fmt.Sprintf("the limit of %d concurrent watches has been reached", cap(s.slots)),
+			int(s.timeout/time.Second))
+	}
+}
+
+func (s *watchSlots) release() {
+	<-s.slots
+}
+
+type watchLimitedClient struct {
+	dynamic.Interface
+	slots *watchSlots
+}
+
+func (c *watchLimitedClient) Resource(resource schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return &watchLimitedResourceClient{NamespaceableResourceInterface: c.Interface.Resource(resource), slots: c.slots}
+}
+
+type watchLimitedResourceClient struct {
+	dynamic.NamespaceableResourceInterface
+	slots *watchSlots
+}
+
+func (c *watchLimitedResourceClient) Namespace(namespace string) dynamic.ResourceInterface {
+	return &watchLimitedNamespacedClient{
+		ResourceInterface: c.NamespaceableResourceInterface.Namespace(namespace),
+		slots:             c.slots,
+	}
+}
+
+func (c *watchLimitedResourceClient) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	return limitWatch(ctx, c.slots, func() (watch.Interface, error) {
+		return c.NamespaceableResourceInterface.Watch(ctx, opts)
+	})
+}
+
+type watchLimitedNamespacedClient struct {
+	dynamic.ResourceInterface
+	slots *watchSlots
+}
+
+func (c *watchLimitedNamespacedClient) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	return limitWatch(ctx, c.slots, func() (watch.Interface, error) {
+		return c.ResourceInterface.Watch(ctx, opts)
+	})
+}
+
+// limitWatch starts a watch once one of the slots is free. The slot is freed when the watch is stopped or ends.
+func limitWatch(
+	ctx context.Context, slots *watchSlots, start func() (watch.Interface, error),
+) (watch.Interface, error) {
+	if err := slots.acquire(ctx); err != nil {
+		return nil, err
+	}
+	w, err := start()
+	if err != nil {
+		slots.release()
+		return nil, err
+	}
+
+	limited := &limitedWatch{w: w, result: make(chan watch.Event), stop: make(chan struct{})}
+	go func() {
+		defer slots.release()
+		defer close(limited.result)
+		for event := range w.ResultChan() {
+			select {
+			case limited.result <- event:
+			case <-limited.stop:
+				return
+			}
+		}
+	}()
+	return limited, nil
+}
+
+// limitedWatch forwards the events of a watch until the watch is stopped.
+type limitedWatch struct {
+	w      watch.Interface
+	result chan watch.Event
+	stop   chan struct{}
+	once   sync.Once
+}
+
+func (w *limitedWatch) Stop() {
+	w.once.Do(func() {
+		close(w.stop)
+		w.w.Stop()
+	})
+}
+
+func (w *limitedWatch) ResultChan() <-chan watch.Event {
+	return w.result
+}
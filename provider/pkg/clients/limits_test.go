@@ -0,0 +1,146 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestLimitRate(t *testing.T) {
+	config := &rest.Config{Host: "https://1.2.3.4"}
+	limited := LimitRate(config, 50, 0)
+	assert.Nil(t, config.RateLimiter)
+	assert.Equal(t, float32(50), limited.QPS)
+	assert.Equal(t, rest.DefaultBurst, limited.Burst)
+	require.NotNil(t, limited.RateLimiter)
+	assert.Equal(t, float32(50), limited.RateLimiter.QPS())
+
+	// Clients built from copies of the limited config share its limit.
+	assert.Same(t, limited.RateLimiter, rest.CopyConfig(limited).RateLimiter)
+}
+
+func TestLimitWatches(t *testing.T) {
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	client := LimitWatches(fake.NewSimpleDynamicClient(runtime.NewScheme()), 1)
+
+	first, err := client.Resource(gvr).Namespace("default").Watch(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+
+	// A second watch waits until the first one is stopped.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = client.Resource(gvr).Watch(ctx, metav1.ListOptions{})
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	first.Stop()
+	_, ok := <-first.ResultChan()
+	assert.False(t, ok)
+
+	second, err := client.Resource(gvr).Watch(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	second.Stop()
+}
+
+func TestLimitWatchesTimeout(t *testing.T) {
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	client := limitWatches(fake.NewSimpleDynamicClient(runtime.NewScheme()), 1, 10*time.Millisecond)
+
+	first, err := client.Resource(gvr).Namespace("default").Watch(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	defer first.Stop()
+
+	// A watch whose context is never cancelled, like the watch of an informer, fails rather than waiting forever.
+	_, err = client.Resource(gvr).Watch(context.Background(), metav1.ListOptions{})
+	assert.True(t, errors.IsTooManyRequests(err))
+}
+
+func TestLimitWatchesConcurrentInformers(t *testing.T) {
+	pods := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	configMaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	fakeClient := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{pods: "PodList", configMaps: "ConfigMapList"})
+	client := limitWatches(fakeClient, 2, 10*time.Millisecond)
+
+	// Each await opens an informer for both resources, so that the awaits need more watches than the limit.
+	const awaits = 3
+	stop := make(chan struct{})
+	defer close(stop)
+	events := make(chan string, 2*awaits)
+	for i := 0; i < awaits; i++ {
+		factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(client, 0, "default", nil)
+		for _, gvr := range []schema.GroupVersionResource{pods, configMaps} {
+			resource := gvr.Resource
+			factory.ForResource(gvr).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+				UpdateFunc: func(_, obj interface{}) {
+					if obj.(*unstructured.Unstructured).GetLabels()["updated"] == "true" {
+						events <- resource
+					}
+				},
+			})
+		}
+		factory.Start(stop)
+		factory.WaitForCacheSync(stop)
+	}
+
+	object := func(kind, name, updated string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       kind,
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "default",
+				"labels":    map[string]interface{}{"updated": updated},
+			},
+		}}
+	}
+	ctx := context.Background()
+	podClient := fakeClient.Resource(pods).Namespace("default")
+	configMapClient := fakeClient.Resource(configMaps).Namespace("default")
+	_, err := podClient.Create(ctx, object("Pod", "nginx", "false"), metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = configMapClient.Create(ctx, object("ConfigMap", "config", "false"), metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = podClient.Update(ctx, object("Pod", "nginx", "true"), metav1.UpdateOptions{})
+	require.NoError(t, err)
+	_, err = configMapClient.Update(ctx, object("ConfigMap", "config", "true"), metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// Every informer sees the update, either through its watch, or by listing its objects again after its watch
+	// failed.
+	received := map[string]int{}
+	timeout := time.After(20 * time.Second)
+	for len(received) < 2 || received["pods"] < awaits || received["configmaps"] < awaits {
+		select {
+		case resource := <-events:
+			received[resource]++
+		case <-timeout:
+			t.Fatalf("timed out waiting for the informers to see the updates: %v", received)
+		}
+	}
+}
@@ -26,6 +26,9 @@ limitations under the License.
 // Additionally, we improved caching of the OpenAPI schema in the OpenAPISchema method.
 // If this change merges upstream, we will reconcile those changes in a future release.
 //
+// Invalidate and Fresh are also forwarded to a delegate that caches discovery information
+// itself, e.g., on disk.
+//
 // [1] https://github.com/kubernetes/client-go/blob/2568220050f6fdd4a8a0dbae292517559731905f/discovery/cached/memory/memcache.go
 
 package clients
@@ -176,7 +179,15 @@ func (d *memCacheClient) Fresh() bool {
 	// Return whether the cache is populated at all. It is still possible that
 	// a single entry is missing due to transient errors and the attempt to read
 	// that entry will trigger retry.
-	return d.cacheValid
+	if !d.cacheValid {
+		return false
+	}
+	// If the cache was populated from a cache of the delegate, e.g., on disk, it may be stale, so a
+	// RESTMapper that doesn't find a kind invalidates the cache and tries again.
+	if cached, ok := d.delegate.(discovery.CachedDiscoveryInterface); ok {
+		return cached.Fresh()
+	}
+	return true
 }
 
 // Invalidate enforces that no cached data that is older than the current time
@@ -188,6 +199,9 @@ func (d *memCacheClient) Invalidate() {
 	d.groupToServerResources = nil
 	d.groupList = nil
 	d.schema = nil
+	if cached, ok := d.delegate.(discovery.CachedDiscoveryInterface); ok {
+		cached.Invalidate()
+	}
 }
 
 // refreshLocked refreshes the state of cache. The caller must hold d.lock for
@@ -0,0 +1,205 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
+	"k8s.io/client-go/util/exec"
+)
+
+// PodClient connects to the containers of Pods over SPDY streams.
+type PodClient struct {
+	config    *rest.Config
+	clientset *kubernetes.Clientset
+}
+
+// NewPodClient returns a client for the Pods of the cluster with the given config.
+func NewPodClient(clientConfig *rest.Config) (*PodClient, error) {
+	clientset, err := kubernetes.NewForConfig(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PodClient{config: clientConfig, clientset: clientset}, nil
+}
+
+// ExecResult is the output and exit code of a command that was run in a container.
+type ExecResult struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// Exec runs a command in a container of the named Pod, and returns its output and exit code. A command that exits with
+// a non-zero code is not an error. If the context is cancelled before the command exits, the stream is closed, and
+// Exec returns the context's error.
+func (pc *PodClient) Exec(
+	ctx context.Context, namespace, name string, opts *corev1.PodExecOptions, stdin io.Reader,
+) (*ExecResult, error) {
+	opts.Stdin = stdin != nil
+	opts.Stdout, opts.Stderr, opts.TTY = true, true, false
+	req := pc.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(name).
+		SubResource("exec").
+		VersionedParams(opts, scheme.ParameterCodec)
+
+	transport, upgrader, err := spdy.RoundTripperFor(pc.config)
+	if err != nil {
+		return nil, err
+	}
+	executor, err := remotecommand.NewSPDYExecutorForTransports(
+		&contextRoundTripper{ctx: ctx, roundTripper: transport}, &contextUpgrader{ctx: ctx, upgrader: upgrader},
+		"POST", req.URL())
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	err = executor.Stream(remotecommand.StreamOptions{Stdin: stdin, Stdout: &stdout, Stderr: &stderr})
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	result := &ExecResult{}
+	if exitErr, ok := err.(exec.ExitError); ok && exitErr.Exited() {
+		result.ExitCode = exitErr.ExitStatus()
+	} else if err != nil {
+		return nil, err
+	}
+	result.Stdout, result.Stderr = stdout.String(), stderr.String()
+	return result, nil
+}
+
+// contextRoundTripper sends the requests that open a stream with the given context, so that connecting to the API
+// server stops when the context is cancelled.
+type contextRoundTripper struct {
+	ctx          context.Context
+	roundTripper http.RoundTripper
+}
+
+func (rt *contextRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return rt.roundTripper.RoundTrip(req.WithContext(rt.ctx))
+}
+
+// contextUpgrader closes the SPDY connection of a stream when the given context is cancelled, which ends the stream.
+type contextUpgrader struct {
+	ctx      context.Context
+	upgrader spdy.Upgrader
+}
+
+func (u *contextUpgrader) NewConnection(resp *http.Response) (httpstream.Connection, error) {
+	conn, err := u.upgrader.NewConnection(resp)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		select {
+		case <-u.ctx.Done():
+			_ = conn.Close()
+		case <-conn.CloseChan():
+		}
+	}()
+	return conn, nil
+}
+
+// PortForward forwards a local port on the given address to a port of the named Pod, until the context is cancelled.
+// If localPort is 0, a free port is chosen. Once the local port is listening, its number is sent on the ready channel.
+// Returns nil when the context is cancelled, or an error if the connection to the Pod fails or is lost.
+func (pc *PodClient) PortForward(
+	ctx context.Context, namespace, name, address string, localPort, remotePort int, ready chan<- int,
+) error {
+	transport, upgrader, err := spdy.RoundTripperFor(pc.config)
+	if err != nil {
+		return err
+	}
+	req := pc.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(name).
+		SubResource("portforward")
+	// Dialing the Pod stops when the context is cancelled, so that the forwarder can always be waited for.
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: &contextRoundTripper{ctx: ctx, roundTripper: transport}},
+		"POST", req.URL())
+
+	stop := make(chan struct{})
+	listening := make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{address},
+		[]string{fmt.Sprintf("%d:%d", localPort, remotePort)}, stop, listening, ioutil.Discard, ioutil.Discard)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- forwarder.ForwardPorts()
+	}()
+	// stopAndWait stops the forwarder and waits for it to return, so that its listener and its connection to the Pod
+	// are closed before PortForward returns.
+	stopAndWait := func(err error) error {
+		close(stop)
+		<-done
+		return err
+	}
+	// stopped returns the error of a forwarder that returned by itself. ForwardPorts returns nil both when it is
+	// stopped and when the connection to the Pod is lost.
+	stopped := func(err error) error {
+		close(stop)
+		if err == nil && ctx.Err() == nil {
+			err = fmt.Errorf("lost connection to Pod %s/%s", namespace, name)
+		}
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return stopAndWait(nil)
+	case err = <-done:
+		return stopped(err)
+	case <-listening:
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil {
+		return stopAndWait(err)
+	}
+	select {
+	case ready <- int(ports[0].Local):
+	case <-ctx.Done():
+		return stopAndWait(nil)
+	}
+
+	select {
+	case <-ctx.Done():
+		return stopAndWait(nil)
+	case err = <-done:
+		return stopped(err)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/rest"
+)
+
+// fakeConnection is a SPDY connection without streams that records whether it was closed.
+type fakeConnection struct {
+	httpstream.Connection
+	closeOnce sync.Once
+	closed    chan bool
+}
+
+func (c *fakeConnection) Close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+	return nil
+}
+
+func (c *fakeConnection) CloseChan() <-chan bool {
+	return c.closed
+}
+
+type fakeUpgrader struct {
+	conn *fakeConnection
+}
+
+func (u *fakeUpgrader) NewConnection(*http.Response) (httpstream.Connection, error) {
+	return u.conn, nil
+}
+
+func TestContextUpgrader(t *testing.T) {
+	isClosed := func(conn *fakeConnection) bool {
+		select {
+		case <-conn.closed:
+			return true
+		case <-time.After(time.Second):
+			return false
+		}
+	}
+
+	// Cancelling the context closes the connection, which ends the stream.
+	ctx, cancel := context.WithCancel(context.Background())
+	upgraded := &fakeConnection{closed: make(chan bool)}
+	conn, err := (&contextUpgrader{ctx: ctx, upgrader: &fakeUpgrader{conn: upgraded}}).NewConnection(nil)
+	require.NoError(t, err)
+	assert.Same(t, upgraded, conn)
+	cancel()
+	assert.True(t, isClosed(upgraded))
+
+	// A connection that was closed by the stream is left alone.
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	upgraded = &fakeConnection{closed: make(chan bool)}
+	_, err = (&contextUpgrader{ctx: ctx, upgrader: &fakeUpgrader{conn: upgraded}}).NewConnection(nil)
+	require.NoError(t, err)
+	require.NoError(t, upgraded.Close())
+	assert.True(t, isClosed(upgraded))
+}
+
+func TestPortForwardReturns(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "port forwarding is disabled", http.StatusForbidden)
+	}))
+	defer server.Close()
+	pc, err := NewPodClient(&rest.Config{Host: server.URL})
+	require.NoError(t, err)
+
+	// A forwarder that fails to connect to the Pod is not left running.
+	err = pc.PortForward(context.Background(), "default", "nginx", "localhost", 0, 80, make(chan int))
+	assert.Error(t, err)
+
+	// Cancelling the context while the Pod is being dialed stops the forwarder without an error.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.NoError(t, pc.PortForward(ctx, "default", "nginx", "localhost", 0, 80, make(chan int)))
+}
@@ -73,6 +73,29 @@ func TryGetServerVersion(cdi discovery.CachedDiscoveryInterface) ServerVersion {
 	return defaultSV
 }
 
+// ParseServerVersion parses a user-specified Kubernetes version such as "1.20", "v1.20", or "v1.20.4" into a
+// ServerVersion. Any patch version or suffix is ignored.
+func ParseServerVersion(versionString string) (ServerVersion, error) {
+	versionRe := regexp.MustCompile(`^v?([0-9]+)\.([0-9]+)(\.[0-9]+)?([-+].*)?$`)
+
+	parsedVersion := versionRe.FindStringSubmatch(versionString)
+	if parsedVersion == nil {
+		return ServerVersion{}, fmt.Errorf("unable to parse Kubernetes version %q; expected a version like %q",
+			versionString, "1.20")
+	}
+
+	major, err := strconv.Atoi(parsedVersion[1])
+	if err != nil {
+		return ServerVersion{}, err
+	}
+	minor, err := strconv.Atoi(parsedVersion[2])
+	if err != nil {
+		return ServerVersion{}, err
+	}
+
+	return ServerVersion{Major: major, Minor: minor}, nil
+}
+
 // gitVersion captures k8s major.minor.patch version in a parsed form
 type gitVersion struct {
 	Major, Minor, Patch int
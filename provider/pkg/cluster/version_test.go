@@ -163,3 +163,36 @@ func TestParseVersion(t *testing.T) {
 		}
 	}
 }
+
+func TestParseServerVersion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected ServerVersion
+		error    bool
+	}{
+		{input: "1.20", expected: ServerVersion{Major: 1, Minor: 20}},
+		{input: "v1.20", expected: ServerVersion{Major: 1, Minor: 20}},
+		{input: "v1.20.4", expected: ServerVersion{Major: 1, Minor: 20}},
+		{input: "1.21.2-gke.1", expected: ServerVersion{Major: 1, Minor: 21}},
+		{input: "1", error: true},
+		{input: "latest", error: true},
+		{input: "", error: true},
+	}
+
+	for _, test := range tests {
+		v, err := ParseServerVersion(test.input)
+		if test.error {
+			if err == nil {
+				t.Errorf("test %q should have failed and did not", test.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %q failed: %v", test.input, err)
+			continue
+		}
+		if v != test.expected {
+			t.Errorf("Expected %#v, got %#v", test.expected, v)
+		}
+	}
+}
@@ -436,6 +436,35 @@ var typeOverlays = map[string]pschema.ComplexTypeSpec{
 			Type: "object",
 		},
 	},
+	"kubernetes:index:Impersonation": {
+		ObjectTypeSpec: pschema.ObjectTypeSpec{
+			Description: "The identity that the provider impersonates in every request to the Kubernetes API server.",
+			Properties: map[string]pschema.PropertySpec{
+				"user": {
+					TypeSpec: pschema.TypeSpec{
+						Type: "string",
+					},
+					Description: "The user to impersonate, e.g., `system:serviceaccount:<namespace>:<name>`.",
+				},
+				"groups": {
+					TypeSpec: pschema.TypeSpec{
+						Type: "array",
+						Items: &pschema.TypeSpec{
+							Type: "string",
+						},
+					},
+					Description: "The groups to impersonate. Requires `user` to be set.",
+				},
+				"uid": {
+					TypeSpec: pschema.TypeSpec{
+						Type: "string",
+					},
+					Description: "The UID to impersonate. Requires `user` to be set, and Kubernetes v1.22 or later.",
+				},
+			},
+			Type: "object",
+		},
+	},
 }
 
 // resourceOverlays augment the resources defined by the kubernetes schema.
@@ -940,4 +969,203 @@ var resourceOverlays = map[string]pschema.ResourceSpec{
 			"values",
 		},
 	},
+	"kubernetes:index:PodExec": {
+		ObjectTypeSpec: pschema.ObjectTypeSpec{
+			Description: "PodExec runs a command in a container of a Pod when it is created, and again whenever its inputs change, e.g., to run database migrations or smoke tests as part of a stack. The command is not run during previews, where its result is unknown. Deleting a PodExec does not undo the effects of its command.",
+			Properties: map[string]pschema.PropertySpec{
+				"command": {
+					TypeSpec: pschema.TypeSpec{
+						Type: "array",
+						Items: &pschema.TypeSpec{
+							Type: "string",
+						},
+					},
+					Description: "The command to run, and its arguments. The command is not run in a shell.",
+				},
+				"container": {
+					TypeSpec: pschema.TypeSpec{
+						Type: "string",
+					},
+					Description: "The container to run the command in. Defaults to the only container of the Pod.",
+				},
+				"exitCode": {
+					TypeSpec: pschema.TypeSpec{
+						Type: "integer",
+					},
+					Description: "The exit code of the command.",
+				},
+				"namespace": {
+					TypeSpec: pschema.TypeSpec{
+						Type: "string",
+					},
+					Description: "The namespace of the Pod. Defaults to the namespace of the provider.",
+				},
+				"pod": {
+					TypeSpec: pschema.TypeSpec{
+						Type: "string",
+					},
+					Description: "The name of the Pod to run the command in.",
+				},
+				"stderr": {
+					TypeSpec: pschema.TypeSpec{
+						Type: "string",
+					},
+					Description: "The standard error of the command.",
+				},
+				"stdin": {
+					TypeSpec: pschema.TypeSpec{
+						Type: "string",
+					},
+					Description: "The input of the command.",
+				},
+				"stdout": {
+					TypeSpec: pschema.TypeSpec{
+						Type: "string",
+					},
+					Description: "The standard output of the command.",
+				},
+				"timeoutSeconds": {
+					TypeSpec: pschema.TypeSpec{
+						Type: "integer",
+					},
+					Description: "How long to wait for the `waitFor` condition and for the command to exit, in seconds.",
+				},
+				"waitFor": {
+					TypeSpec: pschema.TypeSpec{
+						Type: "string",
+					},
+					Description: "A condition that the Pod must satisfy before the command is run, in the syntax of `kubectl wait --for`, e.g., `condition=Ready`.",
+				},
+			},
+			Type: "object",
+			Required: []string{
+				"command",
+				"exitCode",
+				"pod",
+				"stderr",
+				"stdout",
+			},
+		},
+		InputProperties: map[string]pschema.PropertySpec{
+			"command": {
+				TypeSpec: pschema.TypeSpec{
+					Type: "array",
+					Items: &pschema.TypeSpec{
+						Type: "string",
+					},
+				},
+				Description: "The command to run, and its arguments. The command is not run in a shell.",
+			},
+			"container": {
+				TypeSpec: pschema.TypeSpec{
+					Type: "string",
+				},
+				Description: "The container to run the command in. Defaults to the only container of the Pod.",
+			},
+			"namespace": {
+				TypeSpec: pschema.TypeSpec{
+					Type: "string",
+				},
+				Description: "The namespace of the Pod. Defaults to the namespace of the provider.",
+			},
+			"pod": {
+				TypeSpec: pschema.TypeSpec{
+					Type: "string",
+				},
+				Description: "The name of the Pod to run the command in.",
+			},
+			"stdin": {
+				TypeSpec: pschema.TypeSpec{
+					Type: "string",
+				},
+				Description: "The input of the command.",
+			},
+			"timeoutSeconds": {
+				TypeSpec: pschema.TypeSpec{
+					Type: "integer",
+				},
+				Description: "How long to wait for the `waitFor` condition and for the command to exit, in seconds.",
+			},
+			"waitFor": {
+				TypeSpec: pschema.TypeSpec{
+					Type: "string",
+				},
+				Description: "A condition that the Pod must satisfy before the command is run, in the syntax of `kubectl wait --for`, e.g., `condition=Ready`.",
+			},
+		},
+		RequiredInputs: []string{
+			"command",
+			"pod",
+		},
+	},
+}
+
+// functionOverlays are the functions that the provider implements in addition to the kubernetes schema.
+var functionOverlays = map[string]pschema.FunctionSpec{
+	"kubernetes:index:getObject": {
+		Description: "Reads a live object from the cluster, optionally waiting until it satisfies a condition, e.g., to use a value that a controller sets on a resource. The data of Secrets, and their last-applied-configuration annotation, are returned as secrets. Managed fields are omitted.",
+		Inputs: &pschema.ObjectTypeSpec{
+			Properties: map[string]pschema.PropertySpec{
+				"apiVersion": {
+					TypeSpec: pschema.TypeSpec{
+						Type: "string",
+					},
+					Description: "The apiVersion of the object, e.g., `apps/v1`.",
+				},
+				"kind": {
+					TypeSpec: pschema.TypeSpec{
+						Type: "string",
+					},
+					Description: "The kind of the object, e.g., `Deployment`.",
+				},
+				"name": {
+					TypeSpec: pschema.TypeSpec{
+						Type: "string",
+					},
+					Description: "The name of the object.",
+				},
+				"namespace": {
+					TypeSpec: pschema.TypeSpec{
+						Type: "string",
+					},
+					Description: "The namespace of the object. Defaults to the namespace of the provider for namespaced kinds.",
+				},
+				"waitFor": {
+					TypeSpec: pschema.TypeSpec{
+						Type: "string",
+					},
+					Description: "A condition that the object must satisfy before it is returned, in the syntax of `kubectl wait --for`: `exists`, `condition=<type>[=<status>]` or `jsonpath={<path>}[=<value>]`. If not set, the object is read once.",
+				},
+				"timeoutSeconds": {
+					TypeSpec: pschema.TypeSpec{
+						Type: "integer",
+					},
+					Description: "How long to wait for the `waitFor` condition, in seconds. Defaults to 300.",
+				},
+			},
+			Required: []string{
+				"apiVersion",
+				"kind",
+				"name",
+			},
+			Type: "object",
+		},
+		Outputs: &pschema.ObjectTypeSpec{
+			Properties: map[string]pschema.PropertySpec{
+				"result": {
+					TypeSpec: pschema.TypeSpec{
+						Type: "object",
+						AdditionalProperties: &pschema.TypeSpec{
+							Ref: "pulumi.json#/Any",
+						},
+					},
+					Description: "The live object.",
+				},
+			},
+			Required: []string{
+				"result",
+			},
+			Type: "object",
+		},
+	},
 }
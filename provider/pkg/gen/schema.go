@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//nolint: goconst
+// nolint: goconst
 package gen
 
 import (
@@ -46,6 +46,34 @@ func PulumiSchema(swagger map[string]interface{}) pschema.PackageSpec {
 						}),
 					},
 				},
+				"kubeVersion": {
+					Description: "The Kubernetes version to validate resources against when the cluster is unreachable, e.g., in render mode or in CI. When set, resources are validated and scoped using the OpenAPI schema bundled with the provider for that version.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `kubeVersion` parameter.\n2. The `PULUMI_K8S_KUBE_VERSION` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"clientQps": {
+					Description: "The maximum number of queries per second that the provider sends to the Kubernetes API server, shared by all of its clients. Defaults to 5.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `clientQps` parameter.\n2. The `PULUMI_K8S_CLIENT_QPS` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "number"},
+				},
+				"clientBurst": {
+					Description: "The maximum burst of requests that the provider sends to the Kubernetes API server above `clientQps`, shared by all of its clients. Defaults to 10.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `clientBurst` parameter.\n2. The `PULUMI_K8S_CLIENT_BURST` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "integer"},
+				},
+				"maxConcurrentWatches": {
+					Description: "The maximum number of watches that the provider keeps open at the same time, e.g., while it waits for resources to become ready. A watch that would exceed the limit waits up to 10 seconds for another watch to end. If none does, the objects of the resource are listed again periodically until a watch is free, so that waiting for resources that need several watches, e.g., 4 for a Deployment, still completes. Unlimited by default.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `maxConcurrentWatches` parameter.\n2. The `PULUMI_K8S_MAX_CONCURRENT_WATCHES` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "integer"},
+				},
+				"impersonate": {
+					Description: "The user, groups and UID that the provider impersonates in every request to the Kubernetes API server, including the requests of Helm releases, e.g., to manage the resources of a tenant with the identity of its service account. The identity is a JSON object with the `user`, `groups` and `uid` to impersonate.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `impersonate` parameter.\n2. The `PULUMI_K8S_IMPERSONATE` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"discoveryCacheDir": {
+					Description: "The directory in which the provider caches the discovery information and OpenAPI schema of the Kubernetes API server, shared by all provider processes. If this is not set, discovery information is only cached in memory.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `discoveryCacheDir` parameter.\n2. The `PULUMI_K8S_DISCOVERY_CACHE_DIR` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"discoveryCacheTtl": {
+					Description: "The number of seconds for which the cached API groups and resources of the Kubernetes API server are used, before they are discovered again. The cached OpenAPI schema is revalidated on every use. Only used if `discoveryCacheDir` is set. Set to 0 to disable the on-disk cache. Defaults to 600.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `discoveryCacheTtl` parameter.\n2. The `PULUMI_K8S_DISCOVERY_CACHE_TTL` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "integer"},
+				},
 				"context": {
 					Description: "If present, the name of the kubeconfig context to use.",
 					TypeSpec:    pschema.TypeSpec{Type: "string"},
@@ -54,6 +82,36 @@ func PulumiSchema(swagger map[string]interface{}) pschema.PackageSpec {
 					Description: "If present, the name of the kubeconfig cluster to use.",
 					TypeSpec:    pschema.TypeSpec{Type: "string"},
 				},
+				"server": {
+					Description: "The address of the Kubernetes API server, e.g., `https://1.2.3.4:6443`. If this is set, the provider connects to this server with the credentials given by `certificateAuthorityData`, `token`, `clientCertificateData` and `clientKeyData`, and no kubeconfig is loaded. It cannot be combined with `kubeconfig`, `context` or `cluster`.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `server` parameter.\n2. The `PULUMI_K8S_SERVER` environment variable, unless `kubeconfig`, `context` or `cluster` is set.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"certificateAuthorityData": {
+					Description: "PEM-encoded certificate authority certificates for the API server given by `server`, either as PEM text or base64-encoded as in a kubeconfig.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `certificateAuthorityData` parameter.\n2. The `PULUMI_K8S_CERTIFICATE_AUTHORITY_DATA` environment variable, unless `kubeconfig`, `context` or `cluster` is set.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"token": {
+					Description: "A bearer token to authenticate to the API server given by `server`.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `token` parameter.\n2. The `PULUMI_K8S_TOKEN` environment variable, unless `kubeconfig`, `context` or `cluster` is set.",
+					Secret:      true,
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"clientCertificateData": {
+					Description: "A PEM-encoded client certificate to authenticate to the API server given by `server`, either as PEM text or base64-encoded as in a kubeconfig. Requires `clientKeyData`.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `clientCertificateData` parameter.\n2. The `PULUMI_K8S_CLIENT_CERTIFICATE_DATA` environment variable, unless `kubeconfig`, `context` or `cluster` is set.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"clientKeyData": {
+					Description: "The PEM-encoded private key of the client certificate given by `clientCertificateData`, either as PEM text or base64-encoded as in a kubeconfig.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `clientKeyData` parameter.\n2. The `PULUMI_K8S_CLIENT_KEY_DATA` environment variable, unless `kubeconfig`, `context` or `cluster` is set.",
+					Secret:      true,
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"insecureSkipTlsVerify": {
+					Description: "If present and set to true, the certificate of the API server given by `server` is not verified. This makes the connection insecure, and cannot be combined with `certificateAuthorityData`.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `insecureSkipTlsVerify` parameter.\n2. The `PULUMI_K8S_INSECURE_SKIP_TLS_VERIFY` environment variable, unless `kubeconfig`, `context` or `cluster` is set.",
+					TypeSpec:    pschema.TypeSpec{Type: "boolean"},
+				},
+				"proxyUrl": {
+					Description: "The URL of an http, https or socks5 proxy to connect to the API server given by `server` through.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `proxyUrl` parameter.\n2. The `PULUMI_K8S_PROXY_URL` environment variable, unless `kubeconfig`, `context` or `cluster` is set.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
 				"namespace": {
 					Description: "If present, the default namespace to use. This flag is ignored for cluster-scoped resources.\n\nA namespace can be specified in multiple places, and the precedence is as follows:\n1. `.metadata.namespace` set on the resource.\n2. This `namespace` parameter.\n3. `namespace` set for the active context in the kubeconfig.",
 					TypeSpec:    pschema.TypeSpec{Type: "string"},
@@ -62,17 +120,57 @@ func PulumiSchema(swagger map[string]interface{}) pschema.PackageSpec {
 					Description: "BETA FEATURE - If present and set to true, enable server-side diff calculations.\nThis feature is in developer preview, and is disabled by default.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `enableDryRun` parameter.\n2. The `PULUMI_K8S_ENABLE_DRY_RUN` environment variable.",
 					TypeSpec:    pschema.TypeSpec{Type: "boolean"},
 				},
+				"driftIgnoredFieldManagers": {
+					Description: "BETA FEATURE - A comma-separated list of the field managers whose changes are not reported as drift when `enableDriftDetection` is set, such as the controllers that own the fields they update. Changes by any other field manager than the provider are reported as drift. Defaults to `kube-controller-manager,kube-scheduler,kubelet`.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `driftIgnoredFieldManagers` parameter.\n2. The `PULUMI_K8S_DRIFT_IGNORED_FIELD_MANAGERS` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"enableDriftDetection": {
+					Description: "BETA FEATURE - If present and set to true, compare the inputs of each resource to its live state during diffs, and report fields that were changed out of band (e.g., with `kubectl edit`) as drift. Fields owned by the field managers in `driftIgnoredFieldManagers`, such as `spec.replicas` managed by a HorizontalPodAutoscaler, are not reported.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `enableDriftDetection` parameter.\n2. The `PULUMI_K8S_ENABLE_DRIFT_DETECTION` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "boolean"},
+				},
+				"ignoreFields": {
+					Description: "BETA FEATURE - A JSON object mapping resource types to lists of fields that are excluded from diffs and updates, e.g., `{\"apps/v1/Deployment\": [\"/spec/replicas\"]}`. Resource types are given as `<apiVersion>/<kind>`, and fields as JSON pointers or JSONPaths, where `*` matches every map key or list element. This is useful for fields that are set by controllers or mutating webhooks, such as injected sidecar containers or `caBundle` values. Fields can also be ignored for a single resource with the `pulumi.com/ignoreFields` annotation.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `ignoreFields` parameter.\n2. The `PULUMI_K8S_IGNORE_FIELDS` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"policyDirectory": {
+					Description: "A directory of policies that are evaluated against every resource in `Check`. Each YAML or JSON file in the directory contains one or more policies, whose validations are CEL expressions of the resource as `object` and of its previous inputs as `oldObject`. Violations of `mandatory` policies (the default) fail the check, violations of `advisory` policies are reported as warnings, and `disabled` policies are not evaluated. Validations that depend on values that are unknown in a preview are evaluated again when the resource is created or updated. Rego policies are not supported.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `policyDirectory` parameter.\n2. The `PULUMI_K8S_POLICY_DIRECTORY` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
 				"renderYamlToDirectory": {
 					Description: "BETA FEATURE - If present, render resource manifests to this directory. In this mode, resources will not\nbe created on a Kubernetes cluster, but the rendered manifests will be kept in sync with changes\nto the Pulumi program. This feature is in developer preview, and is disabled by default.\n\nNote that some computed Outputs such as status fields will not be populated\nsince the resources are not created on a Kubernetes cluster. These Output values will remain undefined,\nand may result in an error if they are referenced by other resources. Also note that any secret values\nused in these resources will be rendered in plaintext to the resulting YAML.",
 					TypeSpec:    pschema.TypeSpec{Type: "string"},
 				},
+				"renderYamlClean": {
+					Description: "BETA FEATURE - If present and set to true, and `renderYamlToDirectory` is set, the rendered manifests are cleaned up\nso that they can be applied directly with `kubectl apply -k` or consumed by GitOps tooling. The\n`last-applied-configuration` and Pulumi-internal annotations are removed, the manifests are ordered so that\nCustomResourceDefinitions and Namespaces are applied first and webhook configurations last, and a `kustomization.yaml`\nlisting every rendered file is written to the root of the directory.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `renderYamlClean` parameter.\n2. The `PULUMI_K8S_RENDER_YAML_CLEAN` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "boolean"},
+				},
+				"renderYamlLayout": {
+					Description: "BETA FEATURE - If present, and `renderYamlToDirectory` is set, controls how the rendered manifests are arranged in the\ndirectory. Values are:\n- `directory` (default): one file per resource, in the `0-crd` and `1-manifest` subdirectories.\n- `component`: one file per resource, in a subdirectory per component resource (e.g., a Chart or ConfigGroup).\n- `single`: a single multi-document file per stack, named `<stack>.yaml`.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `renderYamlLayout` parameter.\n2. The `PULUMI_K8S_RENDER_YAML_LAYOUT` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"renderYamlSecrets": {
+					Description: "Controls how the data of Secrets is written when `renderYamlToDirectory` is set. `plaintext` (the default) writes it unencrypted. `sops` encrypts the `data` and `stringData` fields in the SOPS format. `sealedSecrets` renders each Secret as a Bitnami SealedSecret.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `renderYamlSecrets` parameter.\n2. The `PULUMI_K8S_RENDER_YAML_SECRETS` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"renderYamlSecretsKey": {
+					Description: "The key used to encrypt Secrets when `renderYamlSecrets` is set. For `sops`, a comma-separated list of age recipients, or the path to (or contents of) an ASCII-armored PGP public key. For `sealedSecrets`, the path to (or contents of) the PEM-encoded certificate of the sealed-secrets controller.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `renderYamlSecretsKey` parameter.\n2. The `PULUMI_K8S_RENDER_YAML_SECRETS_KEY` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"renderYamlUnknowns": {
+					Description: "Controls how resources with unknown values are rendered when `renderYamlToDirectory` is set. `defer` (the default) does not write a resource until all of its values are known, and lists its unknown values in `unresolved-fields.json` in the render directory. `placeholder` renders unknown values as `<unresolved>` and lists them in `unresolved-fields.json` in the render directory.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `renderYamlUnknowns` parameter.\n2. The `PULUMI_K8S_RENDER_YAML_UNKNOWNS` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
 				"suppressDeprecationWarnings": {
 					Description: "If present and set to true, suppress apiVersion deprecation warnings from the CLI.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `suppressDeprecationWarnings` parameter.\n2. The `PULUMI_K8S_SUPPRESS_DEPRECATION_WARNINGS` environment variable.",
 					TypeSpec:    pschema.TypeSpec{Type: "boolean"},
 				},
+				"migrateApiVersions": {
+					Description: "If present and set to true, resources that use a deprecated or removed apiVersion are migrated to the newest apiVersion of their kind that the cluster supports, along with the changes of their fields that can be made automatically, such as the backends of an Ingress. Resources that cannot be migrated automatically are left unchanged with a warning.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `migrateApiVersions` parameter.\n2. The `PULUMI_K8S_MIGRATE_API_VERSIONS` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "boolean"},
+				},
 				"suppressHelmReleaseBetaWarning": {
 					Description: "While Helm Release provider is in beta, by default 'pulumi up' will log a warning if the resource is used. If present and set to true, this warning is omitted.",
-					TypeSpec: pschema.TypeSpec{Type: "boolean"},
+					TypeSpec:    pschema.TypeSpec{Type: "boolean"},
 				},
 				"helmDriver": {
 					Description: "BETA FEATURE - Used for supporting Helm Release resource (Beta). The backend storage driver for Helm. Values are: configmap, secret, memory, sql.",
@@ -121,6 +219,64 @@ func PulumiSchema(swagger map[string]interface{}) pschema.PackageSpec {
 						}),
 					},
 				},
+				"kubeVersion": {
+					DefaultInfo: &pschema.DefaultSpec{
+						Environment: []string{
+							"PULUMI_K8S_KUBE_VERSION",
+						},
+					},
+					Description: "The Kubernetes version to validate resources against when the cluster is unreachable, e.g., in render mode or in CI. When set, resources are validated and scoped using the OpenAPI schema bundled with the provider for that version.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `kubeVersion` parameter.\n2. The `PULUMI_K8S_KUBE_VERSION` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"clientQps": {
+					DefaultInfo: &pschema.DefaultSpec{
+						Environment: []string{
+							"PULUMI_K8S_CLIENT_QPS",
+						},
+					},
+					Description: "The maximum number of queries per second that the provider sends to the Kubernetes API server, shared by all of its clients. Defaults to 5.",
+					TypeSpec:    pschema.TypeSpec{Type: "number"},
+				},
+				"clientBurst": {
+					DefaultInfo: &pschema.DefaultSpec{
+						Environment: []string{
+							"PULUMI_K8S_CLIENT_BURST",
+						},
+					},
+					Description: "The maximum burst of requests that the provider sends to the Kubernetes API server above `clientQps`, shared by all of its clients. Defaults to 10.",
+					TypeSpec:    pschema.TypeSpec{Type: "integer"},
+				},
+				"maxConcurrentWatches": {
+					DefaultInfo: &pschema.DefaultSpec{
+						Environment: []string{
+							"PULUMI_K8S_MAX_CONCURRENT_WATCHES",
+						},
+					},
+					Description: "The maximum number of watches that the provider keeps open at the same time, e.g., while it waits for resources to become ready. A watch that would exceed the limit waits up to 10 seconds for another watch to end. If none does, the objects of the resource are listed again periodically until a watch is free, so that waiting for resources that need several watches, e.g., 4 for a Deployment, still completes. Unlimited by default.",
+					TypeSpec:    pschema.TypeSpec{Type: "integer"},
+				},
+				"impersonate": {
+					Description: "The user, groups and UID that the provider impersonates in every request to the Kubernetes API server, including the requests of Helm releases, e.g., to manage the resources of a tenant with the identity of its service account. If not set, the JSON encoding of the identity is read from the `PULUMI_K8S_IMPERSONATE` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Ref: "#/types/kubernetes:index:Impersonation"},
+				},
+				"discoveryCacheDir": {
+					DefaultInfo: &pschema.DefaultSpec{
+						Environment: []string{
+							"PULUMI_K8S_DISCOVERY_CACHE_DIR",
+						},
+					},
+					Description: "The directory in which the provider caches the discovery information and OpenAPI schema of the Kubernetes API server, shared by all provider processes. If this is not set, discovery information is only cached in memory.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"discoveryCacheTtl": {
+					DefaultInfo: &pschema.DefaultSpec{
+						Environment: []string{
+							"PULUMI_K8S_DISCOVERY_CACHE_TTL",
+						},
+					},
+					Description: "The number of seconds for which the cached API groups and resources of the Kubernetes API server are used, before they are discovered again. The cached OpenAPI schema is revalidated on every use. Only used if `discoveryCacheDir` is set. Set to 0 to disable the on-disk cache. Defaults to 600.",
+					TypeSpec:    pschema.TypeSpec{Type: "integer"},
+				},
 				"context": {
 					Description: "If present, the name of the kubeconfig context to use.",
 					TypeSpec:    pschema.TypeSpec{Type: "string"},
@@ -129,6 +285,36 @@ func PulumiSchema(swagger map[string]interface{}) pschema.PackageSpec {
 					Description: "If present, the name of the kubeconfig cluster to use.",
 					TypeSpec:    pschema.TypeSpec{Type: "string"},
 				},
+				"server": {
+					Description: "The address of the Kubernetes API server, e.g., `https://1.2.3.4:6443`. If this is set, the provider connects to this server with the credentials given by `certificateAuthorityData`, `token`, `clientCertificateData` and `clientKeyData`, and no kubeconfig is loaded. It cannot be combined with `kubeconfig`, `context` or `cluster`.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"certificateAuthorityData": {
+					Description: "PEM-encoded certificate authority certificates for the API server given by `server`, either as PEM text or base64-encoded as in a kubeconfig.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"token": {
+					Description: "A bearer token to authenticate to the API server given by `server`.",
+					Secret:      true,
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"clientCertificateData": {
+					Description: "A PEM-encoded client certificate to authenticate to the API server given by `server`, either as PEM text or base64-encoded as in a kubeconfig. Requires `clientKeyData`.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"clientKeyData": {
+					Description: "The PEM-encoded private key of the client certificate given by `clientCertificateData`, either as PEM text or base64-encoded as in a kubeconfig.",
+					Secret:      true,
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"insecureSkipTlsVerify": {
+					Description: "If present and set to true, the certificate of the API server given by `server` is not verified. This makes the connection insecure, and cannot be combined with `certificateAuthorityData`.",
+					TypeSpec:    pschema.TypeSpec{Type: "boolean"},
+				},
+				"proxyUrl": {
+					Description: "The URL of an http, https or socks5 proxy to connect to the API server given by `server` through.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
 				"namespace": {
 					Description: "If present, the default namespace to use. This flag is ignored for cluster-scoped resources.\n\nA namespace can be specified in multiple places, and the precedence is as follows:\n1. `.metadata.namespace` set on the resource.\n2. This `namespace` parameter.\n3. `namespace` set for the active context in the kubeconfig.",
 					TypeSpec:    pschema.TypeSpec{Type: "string"},
@@ -142,10 +328,91 @@ func PulumiSchema(swagger map[string]interface{}) pschema.PackageSpec {
 					Description: "BETA FEATURE - If present and set to true, enable server-side diff calculations.\nThis feature is in developer preview, and is disabled by default.",
 					TypeSpec:    pschema.TypeSpec{Type: "boolean"},
 				},
+				"driftIgnoredFieldManagers": {
+					DefaultInfo: &pschema.DefaultSpec{
+						Environment: []string{
+							"PULUMI_K8S_DRIFT_IGNORED_FIELD_MANAGERS",
+						},
+					},
+					Description: "BETA FEATURE - A comma-separated list of the field managers whose changes are not reported as drift when `enableDriftDetection` is set, such as the controllers that own the fields they update. Changes by any other field manager than the provider are reported as drift. Defaults to `kube-controller-manager,kube-scheduler,kubelet`.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"enableDriftDetection": {
+					DefaultInfo: &pschema.DefaultSpec{
+						Environment: []string{
+							"PULUMI_K8S_ENABLE_DRIFT_DETECTION",
+						},
+					},
+					Description: "BETA FEATURE - If present and set to true, compare the inputs of each resource to its live state during diffs, and report fields that were changed out of band (e.g., with `kubectl edit`) as drift. Fields owned by the field managers in `driftIgnoredFieldManagers`, such as `spec.replicas` managed by a HorizontalPodAutoscaler, are not reported.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `enableDriftDetection` parameter.\n2. The `PULUMI_K8S_ENABLE_DRIFT_DETECTION` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "boolean"},
+				},
+				"ignoreFields": {
+					DefaultInfo: &pschema.DefaultSpec{
+						Environment: []string{
+							"PULUMI_K8S_IGNORE_FIELDS",
+						},
+					},
+					Description: "BETA FEATURE - A JSON object mapping resource types to lists of fields that are excluded from diffs and updates, e.g., `{\"apps/v1/Deployment\": [\"/spec/replicas\"]}`. Resource types are given as `<apiVersion>/<kind>`, and fields as JSON pointers or JSONPaths, where `*` matches every map key or list element. This is useful for fields that are set by controllers or mutating webhooks, such as injected sidecar containers or `caBundle` values. Fields can also be ignored for a single resource with the `pulumi.com/ignoreFields` annotation.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"policyDirectory": {
+					DefaultInfo: &pschema.DefaultSpec{
+						Environment: []string{
+							"PULUMI_K8S_POLICY_DIRECTORY",
+						},
+					},
+					Description: "A directory of policies that are evaluated against every resource in `Check`. Each YAML or JSON file in the directory contains one or more policies, whose validations are CEL expressions of the resource as `object` and of its previous inputs as `oldObject`. Violations of `mandatory` policies (the default) fail the check, violations of `advisory` policies are reported as warnings, and `disabled` policies are not evaluated. Validations that depend on values that are unknown in a preview are evaluated again when the resource is created or updated. Rego policies are not supported.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
 				"renderYamlToDirectory": {
 					Description: "BETA FEATURE - If present, render resource manifests to this directory. In this mode, resources will not\nbe created on a Kubernetes cluster, but the rendered manifests will be kept in sync with changes\nto the Pulumi program. This feature is in developer preview, and is disabled by default.\n\nNote that some computed Outputs such as status fields will not be populated\nsince the resources are not created on a Kubernetes cluster. These Output values will remain undefined,\nand may result in an error if they are referenced by other resources. Also note that any secret values\nused in these resources will be rendered in plaintext to the resulting YAML.",
 					TypeSpec:    pschema.TypeSpec{Type: "string"},
 				},
+				"renderYamlClean": {
+					DefaultInfo: &pschema.DefaultSpec{
+						Environment: []string{
+							"PULUMI_K8S_RENDER_YAML_CLEAN",
+						},
+					},
+					Description: "BETA FEATURE - If present and set to true, and `renderYamlToDirectory` is set, the rendered manifests are cleaned up\nso that they can be applied directly with `kubectl apply -k` or consumed by GitOps tooling. The\n`last-applied-configuration` and Pulumi-internal annotations are removed, the manifests are ordered so that\nCustomResourceDefinitions and Namespaces are applied first and webhook configurations last, and a `kustomization.yaml`\nlisting every rendered file is written to the root of the directory.",
+					TypeSpec:    pschema.TypeSpec{Type: "boolean"},
+				},
+				"renderYamlLayout": {
+					DefaultInfo: &pschema.DefaultSpec{
+						Environment: []string{
+							"PULUMI_K8S_RENDER_YAML_LAYOUT",
+						},
+					},
+					Description: "BETA FEATURE - If present, and `renderYamlToDirectory` is set, controls how the rendered manifests are arranged in the\ndirectory. Values are:\n- `directory` (default): one file per resource, in the `0-crd` and `1-manifest` subdirectories.\n- `component`: one file per resource, in a subdirectory per component resource (e.g., a Chart or ConfigGroup).\n- `single`: a single multi-document file per stack, named `<stack>.yaml`.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"renderYamlSecrets": {
+					DefaultInfo: &pschema.DefaultSpec{
+						Environment: []string{
+							"PULUMI_K8S_RENDER_YAML_SECRETS",
+						},
+					},
+					Description: "Controls how the data of Secrets is written when `renderYamlToDirectory` is set. `plaintext` (the default) writes it unencrypted. `sops` encrypts the `data` and `stringData` fields in the SOPS format. `sealedSecrets` renders each Secret as a Bitnami SealedSecret.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"renderYamlSecretsKey": {
+					DefaultInfo: &pschema.DefaultSpec{
+						Environment: []string{
+							"PULUMI_K8S_RENDER_YAML_SECRETS_KEY",
+						},
+					},
+					Description: "The key used to encrypt Secrets when `renderYamlSecrets` is set. For `sops`, a comma-separated list of age recipients, or the path to (or contents of) an ASCII-armored PGP public key. For `sealedSecrets`, the path to (or contents of) the PEM-encoded certificate of the sealed-secrets controller.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"renderYamlUnknowns": {
+					DefaultInfo: &pschema.DefaultSpec{
+						Environment: []string{
+							"PULUMI_K8S_RENDER_YAML_UNKNOWNS",
+						},
+					},
+					Description: "Controls how resources with unknown values are rendered when `renderYamlToDirectory` is set. `defer` (the default) does not write a resource until all of its values are known, and lists its unknown values in `unresolved-fields.json` in the render directory. `placeholder` renders unknown values as `<unresolved>` and lists them in `unresolved-fields.json` in the render directory.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
 				"suppressDeprecationWarnings": {
 					DefaultInfo: &pschema.DefaultSpec{
 						Environment: []string{
@@ -155,6 +422,15 @@ func PulumiSchema(swagger map[string]interface{}) pschema.PackageSpec {
 					Description: "If present and set to true, suppress apiVersion deprecation warnings from the CLI.",
 					TypeSpec:    pschema.TypeSpec{Type: "boolean"},
 				},
+				"migrateApiVersions": {
+					DefaultInfo: &pschema.DefaultSpec{
+						Environment: []string{
+							"PULUMI_K8S_MIGRATE_API_VERSIONS",
+						},
+					},
+					Description: "If present and set to true, resources that use a deprecated or removed apiVersion are migrated to the newest apiVersion of their kind that the cluster supports, along with the changes of their fields that can be made automatically, such as the backends of an Ingress. Resources that cannot be migrated automatically are left unchanged with a warning.",
+					TypeSpec:    pschema.TypeSpec{Type: "boolean"},
+				},
 				"suppressHelmReleaseBetaWarning": {
 					DefaultInfo: &pschema.DefaultSpec{
 						Environment: []string{
@@ -347,6 +623,10 @@ func PulumiSchema(swagger map[string]interface{}) pschema.PackageSpec {
 				pkg.Resources[tok] = resourceOverlays[tok]
 			}
 		}
+
+		for tok, overlayFunction := range functionOverlays {
+			pkg.Functions[tok] = overlayFunction
+		}
 	}
 
 	// Compatibility mode for Kubernetes 2.0 SDK
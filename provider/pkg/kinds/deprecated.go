@@ -183,6 +183,11 @@ func AddedInVersion(gvk *schema.GroupVersionKind) *cluster.ServerVersion {
 		case FlowSchema, FlowSchemaList, PriorityLevelConfiguration, PriorityLevelConfigurationList:
 			return &v117
 		}
+	case NetworkingV1:
+		switch k {
+		case Ingress, IngressList, IngressClass, IngressClassList:
+			return &v119
+		}
 	case NetworkingV1B1:
 		switch k {
 		case Ingress, IngressList:
@@ -258,6 +258,75 @@ const (
 	StorageV1B1               groupVersion = "storage.k8s.io/v1beta1"
 )
 
+// builtinGroupVersions are the GroupVersions of the known resource Kinds.
+var builtinGroupVersions = []groupVersion{
+	AdmissionregistrationV1,
+	AdmissionregistrationV1B1,
+	ApiextensionsV1,
+	ApiextensionsV1B1,
+	ApiregistrationV1,
+	ApiregistrationV1B1,
+	AppsV1,
+	AppsV1B1,
+	AppsV1B2,
+	AuditregistrationV1A1,
+	AuthenticationV1,
+	AuthenticationV1B1,
+	AuthorizationV1,
+	AuthorizationV1B1,
+	AutoscalingV1,
+	AutoscalingV2B1,
+	AutoscalingV2B2,
+	BatchV1,
+	BatchV1B1,
+	BatchV2A1,
+	CertificatesV1,
+	CertificatesV1B1,
+	CoordinationV1,
+	CoordinationV1B1,
+	CoreV1,
+	DiscoveryV1,
+	DiscoveryV1B1,
+	EventsV1,
+	EventsV1B1,
+	ExtensionsV1B1,
+	FlowcontrolV1A1,
+	FlowcontrolV1B1,
+	MetaV1,
+	NetworkingV1,
+	NetworkingV1B1,
+	NodeV1,
+	NodeV1A1,
+	NodeV1B1,
+	PolicyV1,
+	PolicyV1B1,
+	RbacV1,
+	RbacV1A1,
+	RbacV1B1,
+	SchedulingV1,
+	SchedulingV1A1,
+	SchedulingV1B1,
+	SettingsV1A1,
+	StorageV1,
+	StorageV1A1,
+	StorageV1B1,
+}
+
+// IsBuiltin returns whether the given Kind of the given API group is a known resource Kind, rather than a custom
+// resource Kind of the same name in another group, e.g., the Service Kind of serving.knative.dev.
+func IsBuiltin(gk schema.GroupKind) bool {
+	if known, _ := Kind(gk.Kind).Namespaced(); !known {
+		return false
+	}
+	for _, gv := range builtinGroupVersions {
+		// The core group is named "core" in the GroupVersions of the provider, but is empty in the API.
+		if gv == CoreV1 && gk.Group == "" || toGVK(gv, Kind(gk.Kind)).Group == gk.Group {
+			return true
+		}
+	}
+	return false
+}
+
 // toGVK is a helper function that converts the internal groupVersion and Kind types to a schema.GroupVersionKind
 func toGVK(gv groupVersion, kind Kind) schema.GroupVersionKind {
 	parts := strings.Split(string(gv), "/")
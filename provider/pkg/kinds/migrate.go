@@ -0,0 +1,320 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kinds
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/cluster"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// fieldMigration converts the fields of an object to those of another apiVersion of its kind, in place.
+type fieldMigration func(obj map[string]interface{}) error
+
+// noFieldChanges is the migration between apiVersions whose schemas are the same.
+func noFieldChanges(map[string]interface{}) error {
+	return nil
+}
+
+// nextAPIVersion returns the GVK that the given GVK is migrated to. This is the suggested apiVersion, except for
+// Ingresses, whose suggested apiVersion is networking.k8s.io/v1beta1, which is itself deprecated in favor of
+// networking.k8s.io/v1, and CSIStorageCapacities, which are not part of storage.k8s.io/v1 yet.
+func nextAPIVersion(gvk schema.GroupVersionKind) schema.GroupVersionKind {
+	switch Kind(gvk.Kind) {
+	case Ingress:
+		if groupVersion(gvk.GroupVersion().String()) == NetworkingV1B1 {
+			return toGVK(NetworkingV1, Ingress)
+		}
+	case CSIStorageCapacity:
+		return gvk
+	}
+	return gvkFromStr(SuggestedAPIVersion(gvk))
+}
+
+// migrationOf returns the field migration from the given GVK to another apiVersion of its kind, and false if the
+// fields of the kind cannot be converted automatically.
+func migrationOf(from, to schema.GroupVersionKind) (fieldMigration, bool) {
+	k := Kind(from.Kind)
+	switch groupVersion(to.GroupVersion().String()) {
+	case AdmissionregistrationV1:
+		return migrateWebhooksToV1, true
+	case AppsV1:
+		switch k {
+		case DaemonSet, Deployment, ReplicaSet, StatefulSet:
+			return migrateWorkloadToAppsV1, true
+		case ControllerRevision:
+			return noFieldChanges, true
+		}
+	case AutoscalingV1:
+		if k == HorizontalPodAutoscaler {
+			return migrateHorizontalPodAutoscalerToV1, true
+		}
+	case DiscoveryV1:
+		if k == EndpointSlice {
+			return migrateEndpointSliceToV1, true
+		}
+	case NetworkingV1:
+		switch k {
+		case Ingress:
+			return migrateIngressToV1, true
+		case NetworkPolicy:
+			return noFieldChanges, true
+		}
+	case ApiregistrationV1, AuthenticationV1, AuthorizationV1, BatchV1B1, CoordinationV1, NetworkingV1B1, PolicyV1B1,
+		RbacV1, SchedulingV1, StorageV1:
+		return noFieldChanges, true
+	}
+	// CustomResourceDefinitions are not migrated, since apiextensions.k8s.io/v1 requires a structural schema, which
+	// cannot be derived from the schema of an apiextensions.k8s.io/v1beta1 CRD in general.
+	return nil, false
+}
+
+// MigrateAPIVersion returns a copy of the given object that is converted from a deprecated or removed apiVersion to
+// the newest suggested apiVersion of its kind that exists in the given version of Kubernetes, along with the known
+// changes of its fields, e.g., the backends of an Ingress of networking.k8s.io/v1. Returns nil if the object does not
+// need to be migrated, and an error if it cannot be migrated automatically. Lists are never migrated.
+func MigrateAPIVersion(
+	obj *unstructured.Unstructured, version cluster.ServerVersion,
+) (*unstructured.Unstructured, error) {
+	from := obj.GroupVersionKind()
+	if strings.HasSuffix(from.Kind, "List") {
+		return nil, nil
+	}
+	// The object may contain unknown values, which are not JSON values, so it is copied without DeepCopy.
+	migrated := &unstructured.Unstructured{Object: copyValue(obj.Object).(map[string]interface{})}
+	for gvk := from; ; {
+		next := nextAPIVersion(gvk)
+		if next.Empty() || next == gvk || !ExistsInVersion(&next, &version) {
+			break
+		}
+		migrate, ok := migrationOf(gvk, next)
+		if !ok {
+			if gvk == from {
+				return nil, fmt.Errorf("%s cannot be migrated to %s automatically", gvkStr(gvk), gvkStr(next))
+			}
+			break
+		}
+		if err := migrate(migrated.Object); err != nil {
+			return nil, fmt.Errorf("%s cannot be migrated to %s automatically: %v", gvkStr(gvk), gvkStr(next), err)
+		}
+		migrated.SetAPIVersion(next.GroupVersion().String())
+		gvk = next
+	}
+
+	if migrated.GroupVersionKind() == from {
+		return nil, nil
+	}
+	return migrated, nil
+}
+
+// migrateIngressToV1 renames the default backend of an Ingress, sets the path type that its paths are defaulted to,
+// and converts its service backends to those of networking.k8s.io/v1.
+func migrateIngressToV1(obj map[string]interface{}) error {
+	spec, ok := obj["spec"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if backend, ok := spec["backend"]; ok {
+		delete(spec, "backend")
+		spec["defaultBackend"] = backend
+	}
+	if backend, ok := spec["defaultBackend"].(map[string]interface{}); ok {
+		if err := migrateIngressBackend(backend, "spec.defaultBackend"); err != nil {
+			return err
+		}
+	}
+
+	for i, rule := range asSlice(spec["rules"]) {
+		paths, _, _ := unstructured.NestedFieldNoCopy(asMap(rule), "http", "paths")
+		for j, p := range asSlice(paths) {
+			path := asMap(p)
+			if path == nil {
+				continue
+			}
+			if _, ok := path["pathType"]; !ok {
+				path["pathType"] = "ImplementationSpecific"
+			}
+			if backend, ok := path["backend"].(map[string]interface{}); ok {
+				field := fmt.Sprintf("spec.rules[%d].http.paths[%d].backend", i, j)
+				if err := migrateIngressBackend(backend, field); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// migrateIngressBackend converts the serviceName and servicePort of an Ingress backend to a service backend.
+// Resource backends are the same in both apiVersions.
+func migrateIngressBackend(backend map[string]interface{}, path string) error {
+	name, hasName := backend["serviceName"]
+	port, hasPort := backend["servicePort"]
+	if !hasName && !hasPort {
+		return nil
+	}
+
+	servicePort := map[string]interface{}{}
+	switch port := port.(type) {
+	case int64, float64:
+		servicePort["number"] = port
+	case string:
+		if number, err := strconv.ParseInt(port, 10, 32); err == nil {
+			servicePort["number"] = number
+		} else {
+			servicePort["name"] = port
+		}
+	default:
+		return fmt.Errorf("%s.servicePort must be a port number or name", path)
+	}
+	delete(backend, "serviceName")
+	delete(backend, "servicePort")
+	backend["service"] = map[string]interface{}{"name": name, "port": servicePort}
+	return nil
+}
+
+// migrateWorkloadToAppsV1 sets the selector of a workload, which is required in apps/v1, to the labels of its pod
+// template, which is what the selector is defaulted to in the earlier apiVersions. The fields that were removed in
+// apps/v1 are removed.
+func migrateWorkloadToAppsV1(obj map[string]interface{}) error {
+	spec, ok := obj["spec"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if _, ok := spec["selector"]; !ok {
+		labels, _, _ := unstructured.NestedFieldNoCopy(spec, "template", "metadata", "labels")
+		if len(asMap(labels)) == 0 {
+			return fmt.Errorf("spec.selector is required, and cannot be defaulted without spec.template.metadata.labels")
+		}
+		matchLabels := map[string]interface{}{}
+		for key, value := range asMap(labels) {
+			matchLabels[key] = value
+		}
+		spec["selector"] = map[string]interface{}{"matchLabels": matchLabels}
+	}
+	delete(spec, "rollbackTo")
+	delete(spec, "templateGeneration")
+	return nil
+}
+
+// migrateWebhooksToV1 sets the fields of the webhooks of a webhook configuration whose defaults are different in
+// admissionregistration.k8s.io/v1 to their previous defaults, so that the behavior of the webhooks does not change.
+// The side effects of a webhook must be declared in admissionregistration.k8s.io/v1, so they are not defaulted.
+func migrateWebhooksToV1(obj map[string]interface{}) error {
+	for i, w := range asSlice(obj["webhooks"]) {
+		webhook := asMap(w)
+		if webhook == nil {
+			continue
+		}
+		setDefault(webhook, "admissionReviewVersions", []interface{}{"v1beta1"})
+		setDefault(webhook, "failurePolicy", "Ignore")
+		setDefault(webhook, "matchPolicy", "Exact")
+		setDefault(webhook, "timeoutSeconds", int64(30))
+		if sideEffects := webhook["sideEffects"]; sideEffects != "None" && sideEffects != "NoneOnDryRun" {
+			return fmt.Errorf("webhooks[%d].sideEffects must be None or NoneOnDryRun", i)
+		}
+	}
+	return nil
+}
+
+// migrateHorizontalPodAutoscalerToV1 converts the metrics of a HorizontalPodAutoscaler to the CPU utilization target
+// of autoscaling/v1, which is the only metric that it supports.
+func migrateHorizontalPodAutoscalerToV1(obj map[string]interface{}) error {
+	spec, ok := obj["spec"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	metrics := asSlice(spec["metrics"])
+	delete(spec, "metrics")
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	metric, _, _ := unstructured.NestedFieldNoCopy(asMap(metrics[0]), "resource")
+	resource := asMap(metric)
+	utilization, hasUtilization := resource["targetAverageUtilization"]
+	if len(metrics) > 1 || asMap(metrics[0])["type"] != "Resource" || resource["name"] != "cpu" || !hasUtilization ||
+		len(resource) != 2 {
+		return fmt.Errorf("only a CPU utilization metric is supported by autoscaling/v1")
+	}
+	spec["targetCPUUtilizationPercentage"] = utilization
+	return nil
+}
+
+// migrateEndpointSliceToV1 converts the topology of the endpoints of an EndpointSlice to the node name and zone of
+// discovery.k8s.io/v1. The other topology labels are kept as its deprecated topology.
+func migrateEndpointSliceToV1(obj map[string]interface{}) error {
+	for _, e := range asSlice(obj["endpoints"]) {
+		endpoint := asMap(e)
+		topology, ok := endpoint["topology"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		delete(endpoint, "topology")
+		for key, field := range map[string]string{
+			"kubernetes.io/hostname":      "nodeName",
+			"topology.kubernetes.io/zone": "zone",
+		} {
+			if value, ok := topology[key]; ok {
+				setDefault(endpoint, field, value)
+				delete(topology, key)
+			}
+		}
+		if len(topology) > 0 {
+			endpoint["deprecatedTopology"] = topology
+		}
+	}
+	return nil
+}
+
+// copyValue returns a copy of the given maps and slices. Other values are not copied.
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			copied[key] = copyValue(value)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, value := range v {
+			copied[i] = copyValue(value)
+		}
+		return copied
+	default:
+		return v
+	}
+}
+
+func asMap(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+func asSlice(v interface{}) []interface{} {
+	s, _ := v.([]interface{})
+	return s
+}
+
+func setDefault(m map[string]interface{}, key string, value interface{}) {
+	if _, ok := m[key]; !ok {
+		m[key] = value
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kinds
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func object(apiVersion, kind string, fields map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: fields}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName("test")
+	return obj
+}
+
+func TestMigrateIngress(t *testing.T) {
+	ingress := object("extensions/v1beta1", "Ingress", map[string]interface{}{
+		"spec": map[string]interface{}{
+			"backend": map[string]interface{}{"serviceName": "default", "servicePort": int64(80)},
+			"rules": []interface{}{
+				map[string]interface{}{
+					"host": "example.com",
+					"http": map[string]interface{}{"paths": []interface{}{
+						map[string]interface{}{
+							"path":    "/",
+							"backend": map[string]interface{}{"serviceName": "web", "servicePort": "http"},
+						},
+						map[string]interface{}{
+							"path":     "/api",
+							"pathType": "Prefix",
+							"backend":  map[string]interface{}{"serviceName": "api", "servicePort": "8080"},
+						},
+					}},
+				},
+			},
+		},
+	})
+	original := ingress.DeepCopy()
+
+	migrated, err := MigrateAPIVersion(ingress, v121)
+	require.NoError(t, err)
+	require.NotNil(t, migrated)
+	assert.Equal(t, "networking.k8s.io/v1", migrated.GetAPIVersion())
+	assert.Equal(t, map[string]interface{}{
+		"defaultBackend": map[string]interface{}{"service": map[string]interface{}{
+			"name": "default",
+			"port": map[string]interface{}{"number": int64(80)},
+		}},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"host": "example.com",
+				"http": map[string]interface{}{"paths": []interface{}{
+					map[string]interface{}{
+						"path":     "/",
+						"pathType": "ImplementationSpecific",
+						"backend": map[string]interface{}{"service": map[string]interface{}{
+							"name": "web",
+							"port": map[string]interface{}{"name": "http"},
+						}},
+					},
+					map[string]interface{}{
+						"path":     "/api",
+						"pathType": "Prefix",
+						"backend": map[string]interface{}{"service": map[string]interface{}{
+							"name": "api",
+							"port": map[string]interface{}{"number": int64(8080)},
+						}},
+					},
+				}},
+			},
+		},
+	}, migrated.Object["spec"])
+
+	// The given object is not modified.
+	assert.Equal(t, original, ingress)
+
+	// networking.k8s.io/v1 is not available before Kubernetes 1.19.
+	migrated, err = MigrateAPIVersion(ingress, v118)
+	require.NoError(t, err)
+	require.NotNil(t, migrated)
+	assert.Equal(t, "networking.k8s.io/v1beta1", migrated.GetAPIVersion())
+	assert.Equal(t, original.Object["spec"], migrated.Object["spec"])
+}
+
+func TestMigrateWorkload(t *testing.T) {
+	deployment := object("extensions/v1beta1", "Deployment", map[string]interface{}{
+		"spec": map[string]interface{}{
+			"rollbackTo": map[string]interface{}{"revision": int64(1)},
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{"labels": map[string]interface{}{"app": "web"}},
+			},
+		},
+	})
+	migrated, err := MigrateAPIVersion(deployment, v121)
+	require.NoError(t, err)
+	require.NotNil(t, migrated)
+	assert.Equal(t, "apps/v1", migrated.GetAPIVersion())
+	spec := migrated.Object["spec"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}}, spec["selector"])
+	assert.NotContains(t, spec, "rollbackTo")
+
+	// The selector cannot be defaulted without the labels of the pod template.
+	_, err = MigrateAPIVersion(object("apps/v1beta2", "StatefulSet", map[string]interface{}{
+		"spec": map[string]interface{}{"template": map[string]interface{}{}},
+	}), v121)
+	assert.EqualError(t, err, "apps/v1beta2/StatefulSet cannot be migrated to apps/v1/StatefulSet automatically: "+
+		"spec.selector is required, and cannot be defaulted without spec.template.metadata.labels")
+}
+
+func TestMigrateWebhooks(t *testing.T) {
+	webhooks := object("admissionregistration.k8s.io/v1beta1", "ValidatingWebhookConfiguration",
+		map[string]interface{}{"webhooks": []interface{}{
+			map[string]interface{}{"name": "a.example.com", "sideEffects": "None", "failurePolicy": "Fail"},
+		}})
+	migrated, err := MigrateAPIVersion(webhooks, v121)
+	require.NoError(t, err)
+	require.NotNil(t, migrated)
+	assert.Equal(t, "admissionregistration.k8s.io/v1", migrated.GetAPIVersion())
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"name":                    "a.example.com",
+		"sideEffects":             "None",
+		"failurePolicy":           "Fail",
+		"admissionReviewVersions": []interface{}{"v1beta1"},
+		"matchPolicy":             "Exact",
+		"timeoutSeconds":          int64(30),
+	}}, migrated.Object["webhooks"])
+
+	webhooks.Object["webhooks"] = []interface{}{map[string]interface{}{"name": "a.example.com"}}
+	_, err = MigrateAPIVersion(webhooks, v121)
+	assert.Error(t, err)
+
+	// admissionregistration.k8s.io/v1 is not available before Kubernetes 1.16.
+	migrated, err = MigrateAPIVersion(webhooks, v114)
+	require.NoError(t, err)
+	assert.Nil(t, migrated)
+}
+
+func TestMigrateHorizontalPodAutoscaler(t *testing.T) {
+	hpa := object("autoscaling/v2beta1", "HorizontalPodAutoscaler", map[string]interface{}{
+		"spec": map[string]interface{}{"metrics": []interface{}{
+			map[string]interface{}{"type": "Resource", "resource": map[string]interface{}{
+				"name":                     "cpu",
+				"targetAverageUtilization": int64(50),
+			}},
+		}},
+	})
+	migrated, err := MigrateAPIVersion(hpa, v121)
+	require.NoError(t, err)
+	require.NotNil(t, migrated)
+	assert.Equal(t, "autoscaling/v1", migrated.GetAPIVersion())
+	assert.Equal(t, map[string]interface{}{"targetCPUUtilizationPercentage": int64(50)}, migrated.Object["spec"])
+
+	hpa.Object["spec"] = map[string]interface{}{"metrics": []interface{}{
+		map[string]interface{}{"type": "Pods"},
+	}}
+	_, err = MigrateAPIVersion(hpa, v121)
+	assert.Error(t, err)
+}
+
+func TestMigrateEndpointSlice(t *testing.T) {
+	slice := object("discovery.k8s.io/v1beta1", "EndpointSlice", map[string]interface{}{
+		"endpoints": []interface{}{
+			map[string]interface{}{
+				"addresses": []interface{}{"10.0.0.1"},
+				"topology": map[string]interface{}{
+					"kubernetes.io/hostname":      "node-1",
+					"topology.kubernetes.io/zone": "us-west-2a",
+					"example.com/rack":            "r1",
+				},
+			},
+		},
+	})
+	migrated, err := MigrateAPIVersion(slice, v121)
+	require.NoError(t, err)
+	require.NotNil(t, migrated)
+	assert.Equal(t, "discovery.k8s.io/v1", migrated.GetAPIVersion())
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"addresses":          []interface{}{"10.0.0.1"},
+		"nodeName":           "node-1",
+		"zone":               "us-west-2a",
+		"deprecatedTopology": map[string]interface{}{"example.com/rack": "r1"},
+	}}, migrated.Object["endpoints"])
+}
+
+func TestMigrateAPIVersionUnsupported(t *testing.T) {
+	crd := object("apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", map[string]interface{}{})
+	_, err := MigrateAPIVersion(crd, v121)
+	assert.EqualError(t, err, "apiextensions.k8s.io/v1beta1/CustomResourceDefinition cannot be migrated to "+
+		"apiextensions.k8s.io/v1/CustomResourceDefinition automatically")
+
+	// Objects on current apiVersions, lists and kinds without a newer apiVersion are not migrated.
+	for _, obj := range []*unstructured.Unstructured{
+		object("apps/v1", "Deployment", map[string]interface{}{}),
+		object("extensions/v1beta1", "IngressList", map[string]interface{}{}),
+		object("storage.k8s.io/v1beta1", "CSIStorageCapacity", map[string]interface{}{}),
+		object("example.com/v1beta1", "Widget", map[string]interface{}{}),
+	} {
+		migrated, err := MigrateAPIVersion(obj, v121)
+		require.NoError(t, err)
+		assert.Nil(t, migrated, obj.GetKind())
+	}
+
+	role := object("rbac.authorization.k8s.io/v1beta1", "Role", map[string]interface{}{"rules": []interface{}{}})
+	migrated, err := MigrateAPIVersion(role, v121)
+	require.NoError(t, err)
+	require.NotNil(t, migrated)
+	assert.Equal(t, "rbac.authorization.k8s.io/v1", migrated.GetAPIVersion())
+}
@@ -32,6 +32,7 @@ const (
 	AnnotationTimeoutSeconds    = AnnotationPrefix + "timeoutSeconds"
 	AnnotationInitialAPIVersion = AnnotationPrefix + "initialApiVersion"
 	AnnotationReplaceUnready    = AnnotationPrefix + "replaceUnready"
+	AnnotationIgnoreFields      = AnnotationPrefix + "ignoreFields"
 
 	AnnotationHelmHook = "helm.sh/hook"
 )
@@ -16,6 +16,7 @@ package metadata
 
 import (
 	"strconv"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -31,6 +32,38 @@ func ReplaceUnready(obj *unstructured.Unstructured) bool {
 	return IsAnnotationTrue(obj, AnnotationReplaceUnready)
 }
 
+// IgnoreFields returns the field paths listed in the `pulumi.com/ignoreFields` annotation. Paths are separated by commas
+// or newlines, and may be JSON pointers or JSONPaths. Separators within the quoted keys of a JSONPath, e.g.,
+// `.metadata.annotations["a,b"]`, do not split the path.
+func IgnoreFields(obj *unstructured.Unstructured) []string {
+	var paths []string
+	add := func(path string) {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+
+	value := GetAnnotationValue(obj, AnnotationIgnoreFields)
+	var quote byte
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case quote != 0:
+			if c == quote && i+1 < len(value) && value[i+1] == ']' {
+				quote = 0
+			}
+		case c == '[' && i+1 < len(value) && (value[i+1] == '"' || value[i+1] == '\''):
+			quote = value[i+1]
+			i++
+		case c == ',' || c == '\n':
+			add(value[start:i])
+			start = i + 1
+		}
+	}
+	add(value[start:])
+	return paths
+}
+
 // TimeoutDuration returns the resource timeout duration. There are a number of things it can do here in this order
 // 1. Return the timeout as specified in the customResource options
 // 2. Return the timeout as specified in `pulumi.com/timeoutSeconds` annotation,
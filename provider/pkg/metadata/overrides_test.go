@@ -16,6 +16,7 @@
 package metadata
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
@@ -84,3 +85,32 @@ func TestTimeoutSeconds(t *testing.T) {
 		})
 	}
 }
+
+func TestIgnoreFields(t *testing.T) {
+	resource := &unstructured.Unstructured{}
+
+	annotatedResource := &unstructured.Unstructured{}
+	annotatedResource.SetAnnotations(map[string]string{
+		AnnotationIgnoreFields: "/spec/replicas, .spec.template.spec.containers[*].imagePullPolicy\n\n/data/caBundle," +
+			`.metadata.annotations["a,b"], .metadata.labels['c,d']`,
+	})
+
+	tests := []struct {
+		name string
+		obj  *unstructured.Unstructured
+		want []string
+	}{
+		{"IgnoreFields annotation unset", resource, nil},
+		{"IgnoreFields annotation set", annotatedResource, []string{
+			"/spec/replicas", ".spec.template.spec.containers[*].imagePullPolicy", "/data/caBundle",
+			`.metadata.annotations["a,b"]`, ".metadata.labels['c,d']",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IgnoreFields(tt.obj); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IgnoreFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
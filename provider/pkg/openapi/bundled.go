@@ -0,0 +1,189 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	openapi_v2 "github.com/googleapis/gnostic/openapiv2"
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/cluster"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kubectl/pkg/util/openapi"
+)
+
+// bundledSpecs are the Kubernetes OpenAPI documents that are compiled into the provider, one for each supported
+// Kubernetes version. They allow resources to be validated without access to a cluster. The documents are named
+// after the Kubernetes minor version they describe, e.g., swagger-v1.21.json.gz, and are downloaded with
+// `make bundled_openapi_specs`.
+//
+//go:embed specs/swagger-*.json.gz
+var bundledSpecs embed.FS
+
+var (
+	bundledSchemasMutex sync.Mutex
+	bundledSchemas      = map[cluster.ServerVersion]*BundledSchema{}
+)
+
+// BundledSchema is an OpenAPI schema that is bundled with the provider.
+type BundledSchema struct {
+	// Version is the Kubernetes version described by the schema. This may differ from the requested version if
+	// there is no schema bundled for that version.
+	Version cluster.ServerVersion
+	// Resources are the schemas for the resource types in the bundled document.
+	Resources openapi.Resources
+
+	namespaced map[schema.GroupVersionKind]bool
+}
+
+// IsNamespacedKind returns whether the given kind is namespaced, and false if it is not described by the schema.
+func (s *BundledSchema) IsNamespacedKind(gvk schema.GroupVersionKind) (known, namespaced bool) {
+	namespaced, known = s.namespaced[gvk]
+	return known, namespaced
+}
+
+// BundledVersions returns the Kubernetes versions that have a bundled schema, from oldest to newest.
+func BundledVersions() ([]cluster.ServerVersion, error) {
+	files, err := bundledSpecFiles()
+	if err != nil {
+		return nil, err
+	}
+	var versions []cluster.ServerVersion
+	for v := range files {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Compare(versions[j]) < 0 })
+	return versions, nil
+}
+
+// bundledSpecFiles returns the name of the bundled document for each Kubernetes version.
+func bundledSpecFiles() (map[cluster.ServerVersion]string, error) {
+	entries, err := bundledSpecs.ReadDir("specs")
+	if err != nil {
+		return nil, err
+	}
+	files := map[cluster.ServerVersion]string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		version, err := cluster.ParseServerVersion(
+			strings.TrimSuffix(strings.TrimPrefix(name, "swagger-"), ".json.gz"))
+		if err != nil {
+			return nil, fmt.Errorf("unexpected bundled OpenAPI document %q: %v", name, err)
+		}
+		files[version] = path.Join("specs", name)
+	}
+	return files, nil
+}
+
+// LoadBundledSchema returns the bundled schema for the given Kubernetes version. If there is no schema bundled for
+// that version, the schema for the newest earlier version is used, or the oldest bundled schema if there is none.
+// Callers should compare the Version of the result with the requested version to detect this.
+func LoadBundledSchema(version cluster.ServerVersion) (*BundledSchema, error) {
+	versions, err := BundledVersions()
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no OpenAPI schemas are bundled with the provider")
+	}
+
+	selected := versions[0]
+	for _, v := range versions {
+		if v.Compare(version) <= 0 {
+			selected = v
+		}
+	}
+
+	bundledSchemasMutex.Lock()
+	defer bundledSchemasMutex.Unlock()
+
+	if s, ok := bundledSchemas[selected]; ok {
+		return s, nil
+	}
+
+	b, err := readBundledSpec(selected)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load bundled OpenAPI schema for Kubernetes %s: %v", selected, err)
+	}
+	s, err := parseBundledSchema(selected, b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse bundled OpenAPI schema for Kubernetes %s: %v", selected, err)
+	}
+	bundledSchemas[selected] = s
+	return s, nil
+}
+
+// readBundledSpec returns the uncompressed bundled document for the given Kubernetes version.
+func readBundledSpec(version cluster.ServerVersion) ([]byte, error) {
+	files, err := bundledSpecFiles()
+	if err != nil {
+		return nil, err
+	}
+	compressed, err := bundledSpecs.ReadFile(files[version])
+	if err != nil {
+		return nil, err
+	}
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+// parseBundledSchema parses a Kubernetes OpenAPI document. The scope of each kind is derived from the API paths, since
+// only namespaced kinds are served under a `{namespace}` path parameter.
+func parseBundledSchema(version cluster.ServerVersion, b []byte) (*BundledSchema, error) {
+	document, err := openapi_v2.ParseDocument(b)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := openapi.NewOpenAPIData(document)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err = json.Unmarshal(b, &spec); err != nil {
+		return nil, err
+	}
+
+	namespaced := map[schema.GroupVersionKind]bool{}
+	for path, operations := range spec.Paths {
+		for _, raw := range operations {
+			// Skip the path-level parameters, which are not operations.
+			var operation struct {
+				GVK *schema.GroupVersionKind `json:"x-kubernetes-group-version-kind"`
+			}
+			if err = json.Unmarshal(raw, &operation); err != nil || operation.GVK == nil {
+				continue
+			}
+			gvk := *operation.GVK
+			namespaced[gvk] = namespaced[gvk] || strings.Contains(path, "/{namespace}/")
+		}
+	}
+
+	return &BundledSchema{Version: version, Resources: resources, namespaced: namespaced}, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/cluster"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestLoadBundledSchema(t *testing.T) {
+	versions, err := BundledVersions()
+	require.NoError(t, err)
+	assert.Contains(t, versions, cluster.ServerVersion{Major: 1, Minor: 20})
+	assert.Contains(t, versions, cluster.ServerVersion{Major: 1, Minor: 21})
+	assert.True(t, sort.SliceIsSorted(versions, func(i, j int) bool { return versions[i].Compare(versions[j]) < 0 }))
+
+	// The schema of the newest earlier version is used if there is none for the requested version, and the
+	// schema of the oldest version if there is no earlier one.
+	for requested, expected := range map[cluster.ServerVersion]cluster.ServerVersion{
+		{Major: 1, Minor: 21}: {Major: 1, Minor: 21},
+		{Major: 1, Minor: 99}: versions[len(versions)-1],
+		{Major: 1, Minor: 0}:  versions[0],
+	} {
+		s, err := LoadBundledSchema(requested)
+		require.NoError(t, err)
+		assert.Equal(t, expected, s.Version, requested.String())
+	}
+
+	s, err := LoadBundledSchema(cluster.ServerVersion{Major: 1, Minor: 20})
+	require.NoError(t, err)
+	assert.Equal(t, cluster.ServerVersion{Major: 1, Minor: 20}, s.Version)
+
+	deployment := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	assert.NotNil(t, s.Resources.LookupResource(deployment))
+
+	tests := []struct {
+		gvk        schema.GroupVersionKind
+		known      bool
+		namespaced bool
+	}{
+		{gvk: deployment, known: true, namespaced: true},
+		{gvk: schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, known: true, namespaced: true},
+		{gvk: schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, known: true, namespaced: false},
+		{
+			gvk:   schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"},
+			known: true,
+		},
+		{gvk: schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Foo"}},
+	}
+	for _, test := range tests {
+		known, namespaced := s.IsNamespacedKind(test.gvk)
+		assert.Equal(t, test.known, known, test.gvk.String())
+		assert.Equal(t, test.namespaced, namespaced, test.gvk.String())
+	}
+
+	// The schema is only parsed once.
+	again, err := LoadBundledSchema(cluster.ServerVersion{Major: 1, Minor: 20})
+	require.NoError(t, err)
+	assert.Same(t, s, again)
+}
@@ -0,0 +1,195 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"sort"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/listtype"
+	apiservervalidation "k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// CustomResourceValidation is the result of validating a custom resource against the schema in its CRD.
+type CustomResourceValidation struct {
+	// Errors are the fields that the API server would reject.
+	Errors field.ErrorList
+	// UnknownFields are the paths of the fields that are not in the schema. The API server prunes them rather than
+	// rejecting them, so they are not errors. CRDs that preserve unknown fields, e.g., CRDs of
+	// apiextensions.k8s.io/v1beta1 by default, do not have unknown fields.
+	UnknownFields []string
+}
+
+// ValidateCustomResource validates a custom resource against the OpenAPI v3 schema of its version in the given CRD, in
+// the same way as the API server, which does not require the schema to be published in the OpenAPI v2 document of the
+// cluster. The schema's value validations, e.g., types, formats, patterns and enums, are checked, along with the
+// `x-kubernetes-list-type` and `x-kubernetes-list-map-keys` extensions of a structural schema. The fields of a
+// structural schema that preserve unknown fields with `x-kubernetes-preserve-unknown-fields`, or that embed a
+// resource with `x-kubernetes-embedded-resource`, may contain fields that are not in the schema.
+//
+// Validation rules that are written in CEL are not checked, since they are not part of the CRD API of the client
+// libraries of the provider; see ValidationRules. Returns nil if the CRD does not have a schema for the version of the
+// resource.
+func ValidateCustomResource(
+	crd *apiextensionsv1.CustomResourceDefinition, obj *unstructured.Unstructured,
+) (*CustomResourceValidation, error) {
+	version := obj.GroupVersionKind().Version
+	var crdVersion *apiextensionsv1.CustomResourceDefinitionVersion
+	var versions []string
+	for i, v := range crd.Spec.Versions {
+		versions = append(versions, crd.Spec.Group+"/"+v.Name)
+		if v.Name == version {
+			crdVersion = &crd.Spec.Versions[i]
+		}
+	}
+	if crdVersion == nil {
+		return &CustomResourceValidation{Errors: field.ErrorList{
+			field.NotSupported(field.NewPath("apiVersion"), obj.GetAPIVersion(), versions),
+		}}, nil
+	}
+	if crdVersion.Schema == nil || crdVersion.Schema.OpenAPIV3Schema == nil {
+		return nil, nil
+	}
+
+	internal := &apiextensions.JSONSchemaProps{}
+	if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(
+		crdVersion.Schema.OpenAPIV3Schema, internal, nil); err != nil {
+		return nil, err
+	}
+	validator, _, err := apiservervalidation.NewSchemaValidator(
+		&apiextensions.CustomResourceValidation{OpenAPIV3Schema: internal})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CustomResourceValidation{
+		Errors: apiservervalidation.ValidateCustomResource(nil, obj.UnstructuredContent(), validator),
+	}
+	// CRDs of apiextensions.k8s.io/v1beta1 may not have a structural schema, in which case only the value validations
+	// apply.
+	if structural, err := structuralschema.NewStructural(internal); err == nil {
+		result.Errors = append(result.Errors, listtype.ValidateListSetsAndMaps(nil, structural, obj.Object)...)
+		if !crd.Spec.PreserveUnknownFields {
+			result.UnknownFields = unknownFields(nil, structural, obj.Object, true)
+		}
+	}
+	return result, nil
+}
+
+// ValidationRules returns the paths of the schemas with `x-kubernetes-validations` rules in each version of the given
+// CRD, or "<root>" for the schema of the resource itself. The CRD is unstructured, since the rules are not part of the
+// CRD API of the client libraries of the provider. The rules are written in CEL, which ValidateCustomResource does not
+// evaluate, so custom resources of a version with rules are only partially validated.
+func ValidationRules(crd *unstructured.Unstructured) map[string][]string {
+	// The top-level schema of a CRD of apiextensions.k8s.io/v1beta1 applies to each of its versions that don't have a
+	// schema of their own.
+	topLevel, _, _ := unstructured.NestedMap(crd.Object, "spec", "validation", "openAPIV3Schema")
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	if version, found, _ := unstructured.NestedString(crd.Object, "spec", "version"); found && len(versions) == 0 {
+		versions = []interface{}{map[string]interface{}{"name": version}}
+	}
+
+	rules := map[string][]string{}
+	for _, v := range versions {
+		version, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(version, "name")
+		schema, found, _ := unstructured.NestedMap(version, "schema", "openAPIV3Schema")
+		if !found {
+			schema = topLevel
+		}
+		if paths := validationRules(nil, schema); len(paths) > 0 {
+			rules[name] = paths
+		}
+	}
+	return rules
+}
+
+func validationRules(path *field.Path, schema map[string]interface{}) []string {
+	var paths []string
+	if rules, ok := schema["x-kubernetes-validations"].([]interface{}); ok && len(rules) > 0 {
+		if path == nil {
+			paths = append(paths, "<root>")
+		} else {
+			paths = append(paths, path.String())
+		}
+	}
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for _, key := range sortedKeys(properties) {
+			if property, ok := properties[key].(map[string]interface{}); ok {
+				paths = append(paths, validationRules(path.Child(key), property)...)
+			}
+		}
+	}
+	for _, key := range []string{"additionalProperties", "items"} {
+		if s, ok := schema[key].(map[string]interface{}); ok {
+			paths = append(paths, validationRules(path.Key("*"), s)...)
+		}
+	}
+	return paths
+}
+
+// unknownFields returns the paths of the fields of the value that are not in the structural schema. The apiVersion,
+// kind and metadata of a resource are validated by the API server separately, so they are not checked.
+func unknownFields(path *field.Path, s *structuralschema.Structural, value interface{}, resource bool) []string {
+	if s == nil || s.XPreserveUnknownFields {
+		return nil
+	}
+
+	var unknown []string
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if s.AdditionalProperties != nil {
+			if s.AdditionalProperties.Structural != nil {
+				for _, key := range sortedKeys(value) {
+					unknown = append(unknown, unknownFields(path.Key(key), s.AdditionalProperties.Structural, value[key],
+						false)...)
+				}
+			}
+			return unknown
+		}
+		for _, key := range sortedKeys(value) {
+			if (resource || s.XEmbeddedResource) && (key == "apiVersion" || key == "kind" || key == "metadata") {
+				continue
+			}
+			property, ok := s.Properties[key]
+			if !ok {
+				unknown = append(unknown, path.Child(key).String())
+				continue
+			}
+			unknown = append(unknown, unknownFields(path.Child(key), &property, value[key], false)...)
+		}
+	case []interface{}:
+		for i, item := range value {
+			unknown = append(unknown, unknownFields(path.Index(i), s.Items, item, false)...)
+		}
+	}
+	return unknown
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,224 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func crontabCRD() *apiextensionsv1.CustomResourceDefinition {
+	preserve := true
+	listType := "map"
+	return &apiextensionsv1.CustomResourceDefinition{
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "stable.example.com",
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{
+					Name: "v1", Served: true, Storage: true,
+					Schema: &apiextensionsv1.CustomResourceValidation{
+						OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]apiextensionsv1.JSONSchemaProps{
+								"spec": {
+									Type:     "object",
+									Required: []string{"cronSpec"},
+									Properties: map[string]apiextensionsv1.JSONSchemaProps{
+										"cronSpec": {Type: "string", Pattern: `^(\d+|\*)(/\d+)?(\s+(\d+|\*)(/\d+)?){4}$`},
+										"replicas": {Type: "integer", Minimum: float64Ptr(1)},
+										"ports": {
+											Type:         "array",
+											XListType:    &listType,
+											XListMapKeys: []string{"port"},
+											Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{
+												Type:       "object",
+												Required:   []string{"port"},
+												Properties: map[string]apiextensionsv1.JSONSchemaProps{"port": {Type: "integer"}},
+											}},
+										},
+										"template": {Type: "object", XPreserveUnknownFields: &preserve},
+									},
+								},
+							},
+						},
+					},
+				},
+				{Name: "v1beta1", Served: true},
+			},
+		},
+	}
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
+func crontab(apiVersion string, spec map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       "CronTab",
+		"metadata":   map[string]interface{}{"name": "crontab", "labels": map[string]interface{}{"app": "crontab"}},
+		"spec":       spec,
+	}}
+}
+
+func TestValidateCustomResource(t *testing.T) {
+	tests := []struct {
+		name    string
+		obj     *unstructured.Unstructured
+		errors  []string
+		unknown []string
+	}{
+		{
+			name: "valid",
+			obj: crontab("stable.example.com/v1", map[string]interface{}{
+				"cronSpec": "* * * * */5",
+				"replicas": int64(2),
+				"ports":    []interface{}{map[string]interface{}{"port": int64(80)}},
+				"template": map[string]interface{}{"anything": "goes"},
+			}),
+		},
+		{
+			name:   "missing required field",
+			obj:    crontab("stable.example.com/v1", map[string]interface{}{"replicas": int64(2)}),
+			errors: []string{"spec.cronSpec"},
+		},
+		{
+			name: "invalid values",
+			obj: crontab("stable.example.com/v1", map[string]interface{}{
+				"cronSpec": "every minute",
+				"replicas": "two",
+			}),
+			errors: []string{"spec.cronSpec", "spec.replicas"},
+		},
+		{
+			name: "duplicate list map keys",
+			obj: crontab("stable.example.com/v1", map[string]interface{}{
+				"cronSpec": "* * * * */5",
+				"ports": []interface{}{
+					map[string]interface{}{"port": int64(80)},
+					map[string]interface{}{"port": int64(80)},
+				},
+			}),
+			errors: []string{"spec.ports[1]"},
+		},
+		{
+			name: "unknown fields",
+			obj: crontab("stable.example.com/v1", map[string]interface{}{
+				"cronSpec": "* * * * */5",
+				"image":    "crontab",
+				"ports":    []interface{}{map[string]interface{}{"port": int64(80), "protocol": "TCP"}},
+			}),
+			unknown: []string{"spec.image", "spec.ports[0].protocol"},
+		},
+		{
+			name:   "version not served",
+			obj:    crontab("stable.example.com/v2", map[string]interface{}{}),
+			errors: []string{"apiVersion"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateCustomResource(crontabCRD(), tt.obj)
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			var errors []string
+			for _, fe := range result.Errors {
+				errors = append(errors, fe.Field)
+			}
+			assert.ElementsMatch(t, tt.errors, errors)
+			assert.Equal(t, tt.unknown, result.UnknownFields)
+		})
+	}
+}
+
+func TestValidateCustomResourceWithoutSchema(t *testing.T) {
+	result, err := ValidateCustomResource(crontabCRD(), crontab("stable.example.com/v1beta1", map[string]interface{}{
+		"replicas": "two",
+	}))
+	require.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+func TestValidateCustomResourceErrorType(t *testing.T) {
+	result, err := ValidateCustomResource(crontabCRD(), crontab("stable.example.com/v1", map[string]interface{}{}))
+	require.NoError(t, err)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, field.ErrorTypeRequired, result.Errors[0].Type)
+}
+
+func TestValidationRules(t *testing.T) {
+	rules := []interface{}{map[string]interface{}{"rule": "self.minReplicas <= self.replicas"}}
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"spec": map[string]interface{}{
+			"versions": []interface{}{
+				map[string]interface{}{
+					"name": "v1",
+					"schema": map[string]interface{}{
+						"openAPIV3Schema": map[string]interface{}{
+							"type":                     "object",
+							"x-kubernetes-validations": rules,
+							"properties": map[string]interface{}{
+								"spec": map[string]interface{}{
+									"type":                     "object",
+									"x-kubernetes-validations": rules,
+									"properties": map[string]interface{}{
+										"ports": map[string]interface{}{
+											"type":  "array",
+											"items": map[string]interface{}{"type": "object", "x-kubernetes-validations": rules},
+										},
+										"labels": map[string]interface{}{
+											"type": "object",
+											"additionalProperties": map[string]interface{}{
+												"type": "string", "x-kubernetes-validations": rules,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				map[string]interface{}{
+					"name":   "v1beta1",
+					"schema": map[string]interface{}{"openAPIV3Schema": map[string]interface{}{"type": "object"}},
+				},
+			},
+		},
+	}}
+	assert.Equal(t, map[string][]string{
+		"v1": {"<root>", "spec", "spec.labels[*]", "spec.ports[*]"},
+	}, ValidationRules(crd))
+
+	// The top-level schema of a CRD of apiextensions.k8s.io/v1beta1 applies to its version.
+	crd = &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1beta1",
+		"kind":       "CustomResourceDefinition",
+		"spec": map[string]interface{}{
+			"version": "v1",
+			"validation": map[string]interface{}{
+				"openAPIV3Schema": map[string]interface{}{"type": "object", "x-kubernetes-validations": rules},
+			},
+		},
+	}}
+	assert.Equal(t, map[string][]string{"v1": {"<root>"}}, ValidationRules(crd))
+}
@@ -0,0 +1,238 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// FieldPath is a path to one or more fields of an object.
+type FieldPath []fieldPathElement
+
+// fieldPathElement is a map key or list index in a FieldPath. Whether the name refers to a key or an index is resolved
+// against the object, since a JSON pointer does not distinguish them.
+type fieldPathElement struct {
+	name     string
+	wildcard bool
+}
+
+// newFieldPathElement returns the element for an unquoted name, which is a wildcard if the name is `*`.
+func newFieldPathElement(name string) fieldPathElement {
+	if name == "*" {
+		return fieldPathElement{wildcard: true}
+	}
+	return fieldPathElement{name: name}
+}
+
+// ParseFieldPath parses a field path given as a JSON pointer, e.g., `/spec/template/spec/containers/0/image`, or as a
+// JSONPath, e.g., `.spec.template.spec.containers[*].image`. A `*` element in either syntax matches every key of a map
+// or every element of a list.
+func ParseFieldPath(path string) (FieldPath, error) {
+	path = strings.TrimSpace(path)
+	switch {
+	case path == "":
+		return nil, fmt.Errorf("field path is empty")
+	case strings.HasPrefix(path, "/"):
+		return parseJSONPointer(path)
+	default:
+		return parseJSONPath(path)
+	}
+}
+
+// parseJSONPointer parses a JSON pointer as described in RFC 6901.
+func parseJSONPointer(path string) (FieldPath, error) {
+	var fieldPath FieldPath
+	for _, token := range strings.Split(path[1:], "/") {
+		if token == "" {
+			return nil, fmt.Errorf("invalid JSON pointer %q: empty reference token", path)
+		}
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		fieldPath = append(fieldPath, newFieldPathElement(token))
+	}
+	return fieldPath, nil
+}
+
+// parseJSONPath parses the subset of JSONPath that selects fields by name, index or wildcard, e.g.,
+// `.metadata.annotations["example.com/key"]` or `$.webhooks[*].clientConfig.caBundle`.
+func parseJSONPath(path string) (FieldPath, error) {
+	expr := strings.TrimSuffix(strings.TrimPrefix(path, "{"), "}")
+	expr = strings.TrimPrefix(expr, "$")
+
+	var fieldPath FieldPath
+	for i := 0; i < len(expr); {
+		switch {
+		case expr[i] == '.' || i == 0 && expr[i] != '[':
+			if expr[i] == '.' {
+				i++
+			}
+			end := i
+			for end < len(expr) && expr[end] != '.' && expr[end] != '[' {
+				end++
+			}
+			if end == i {
+				return nil, fmt.Errorf("invalid JSONPath %q: empty field name at offset %d", path, i)
+			}
+			fieldPath = append(fieldPath, newFieldPathElement(expr[i:end]))
+			i = end
+		case expr[i] == '[' && i+1 < len(expr) && (expr[i+1] == '"' || expr[i+1] == '\''):
+			// Quoted keys may contain `.` and `]`, so find the closing quote rather than the first bracket.
+			quote := expr[i+1]
+			closing := strings.IndexByte(expr[i+2:], quote)
+			if closing < 0 || i+2+closing+1 >= len(expr) || expr[i+2+closing+1] != ']' {
+				return nil, fmt.Errorf("invalid JSONPath %q: malformed quoted key", path)
+			}
+			fieldPath = append(fieldPath, fieldPathElement{name: expr[i+2 : i+2+closing]})
+			i += closing + 4
+		case expr[i] == '[':
+			end := strings.IndexByte(expr[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid JSONPath %q: unterminated '['", path)
+			}
+			switch subscript := expr[i+1 : i+end]; {
+			case subscript == "*":
+				fieldPath = append(fieldPath, fieldPathElement{wildcard: true})
+			case isIndex(subscript):
+				fieldPath = append(fieldPath, fieldPathElement{name: subscript})
+			default:
+				return nil, fmt.Errorf("invalid JSONPath %q: unsupported subscript %q", path, subscript)
+			}
+			i += end + 1
+		default:
+			return nil, fmt.Errorf("invalid JSONPath %q: unexpected character %q at offset %d", path, expr[i], i)
+		}
+	}
+	if len(fieldPath) == 0 {
+		return nil, fmt.Errorf("invalid JSONPath %q: no fields selected", path)
+	}
+	return fieldPath, nil
+}
+
+// String returns the path as a JSON pointer.
+func (p FieldPath) String() string {
+	var b strings.Builder
+	for _, element := range p {
+		b.WriteString("/")
+		if element.wildcard {
+			b.WriteString("*")
+		} else {
+			b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(element.name))
+		}
+	}
+	return b.String()
+}
+
+// SelectsListElements returns true if the path selects an element of a list in the object, or a field of one. A JSON
+// merge patch replaces lists as a whole, so such fields cannot be left out of the patch of a resource that is updated
+// with one.
+func SelectsListElements(obj *unstructured.Unstructured, path FieldPath) bool {
+	if obj == nil {
+		return false
+	}
+	return selectsListElements(obj.Object, path)
+}
+
+func selectsListElements(value interface{}, path FieldPath) bool {
+	if len(path) == 0 {
+		return false
+	}
+	element, rest := path[0], path[1:]
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if (element.wildcard || key == element.name) && selectsListElements(child, rest) {
+				return true
+			}
+		}
+		return false
+	case []interface{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// WithoutFields returns a copy of the object with the fields selected by the given paths removed. Paths that do not
+// match any field are ignored. If no paths are given, the object itself is returned.
+func WithoutFields(obj *unstructured.Unstructured, paths []FieldPath) *unstructured.Unstructured {
+	if obj == nil || len(paths) == 0 {
+		return obj
+	}
+	obj = obj.DeepCopy()
+	for _, path := range paths {
+		obj.Object, _ = removeField(obj.Object, path).(map[string]interface{})
+	}
+	return obj
+}
+
+// removeField removes the fields selected by the path from the value, and returns the updated value.
+func removeField(value interface{}, path FieldPath) interface{} {
+	if len(path) == 0 {
+		return value
+	}
+	element, rest := path[0], path[1:]
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if !element.wildcard && key != element.name {
+				continue
+			}
+			if len(rest) == 0 {
+				delete(value, key)
+			} else {
+				value[key] = removeField(child, rest)
+			}
+		}
+		return value
+	case []interface{}:
+		if element.wildcard {
+			if len(rest) == 0 {
+				return []interface{}{}
+			}
+			for i, child := range value {
+				value[i] = removeField(child, rest)
+			}
+			return value
+		}
+		index, err := strconv.Atoi(element.name)
+		if err != nil || index < 0 || index >= len(value) {
+			return value
+		}
+		if len(rest) == 0 {
+			return append(value[:index:index], value[index+1:]...)
+		}
+		value[index] = removeField(value[index], rest)
+		return value
+	default:
+		return value
+	}
+}
+
+func isIndex(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
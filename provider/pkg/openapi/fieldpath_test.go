@@ -0,0 +1,148 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParseFieldPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want FieldPath
+	}{
+		{"/spec/replicas", FieldPath{{name: "spec"}, {name: "replicas"}}},
+		{"/metadata/annotations/example.com~1key", FieldPath{{name: "metadata"}, {name: "annotations"},
+			{name: "example.com/key"}}},
+		{"/spec/containers/*/image", FieldPath{{name: "spec"}, {name: "containers"}, {wildcard: true},
+			{name: "image"}}},
+		{".spec.replicas", FieldPath{{name: "spec"}, {name: "replicas"}}},
+		{"spec.replicas", FieldPath{{name: "spec"}, {name: "replicas"}}},
+		{"{$.webhooks[*].clientConfig.caBundle}", FieldPath{{name: "webhooks"}, {wildcard: true},
+			{name: "clientConfig"}, {name: "caBundle"}}},
+		{`.metadata.annotations["example.com/key[0]"]`, FieldPath{{name: "metadata"}, {name: "annotations"},
+			{name: "example.com/key[0]"}}},
+		{".spec.containers[1].image", FieldPath{{name: "spec"}, {name: "containers"}, {name: "1"}, {name: "image"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := ParseFieldPath(tt.path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, path := range []string{"", "/spec//replicas", ".spec..replicas", ".spec[", ".spec[?(@.name)]", `.a["b]`, "$"} {
+		t.Run(path, func(t *testing.T) {
+			_, err := ParseFieldPath(path)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestWithoutFields(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{"example.com/key": "value", "other": "value"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"containers": []interface{}{
+				map[string]interface{}{"name": "app", "imagePullPolicy": "Always"},
+				map[string]interface{}{"name": "sidecar", "imagePullPolicy": "IfNotPresent"},
+			},
+		},
+	}}
+
+	var paths []FieldPath
+	for _, path := range []string{
+		"/spec/replicas",
+		".spec.containers[*].imagePullPolicy",
+		`.metadata.annotations["example.com/key"]`,
+		"/spec/containers/1",
+		"/status/missing",
+	} {
+		p, err := ParseFieldPath(path)
+		require.NoError(t, err)
+		paths = append(paths, p)
+	}
+
+	stripped := WithoutFields(obj, paths)
+	assert.Equal(t, map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{"other": "value"},
+		},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "app"},
+			},
+		},
+	}, stripped.Object)
+
+	// The original object is not modified.
+	assert.Equal(t, int64(3), obj.Object["spec"].(map[string]interface{})["replicas"])
+	assert.Same(t, obj, WithoutFields(obj, nil))
+}
+
+func TestSelectsListElements(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{"example.com/key": "value"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"containers": []interface{}{
+				map[string]interface{}{"name": "nginx", "image": "nginx"},
+			},
+		},
+	}}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/spec/replicas", false},
+		{"/spec/containers", false},
+		{"/metadata/annotations/example.com~1key", false},
+		{"/spec/containers/0", true},
+		{"/spec/containers/0/image", true},
+		{".spec.containers[*].image", true},
+		{"/*/containers/*", true},
+		{"/spec/volumes/0", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			path, err := ParseFieldPath(tt.path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, SelectsListElements(obj, path))
+		})
+	}
+}
+
+func TestFieldPathString(t *testing.T) {
+	for path, want := range map[string]string{
+		"/spec/replicas": "/spec/replicas",
+		`.metadata.annotations["example.com/k~"]`: "/metadata/annotations/example.com~1k~0",
+		".spec.containers[*].image":               "/spec/containers/*/image",
+	} {
+		fieldPath, err := ParseFieldPath(path)
+		require.NoError(t, err)
+		assert.Equal(t, want, fieldPath.String())
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"github.com/google/cel-go/interpreter"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// Expressions are compiled and evaluated with cel-go, the CEL implementation that the API server uses for the
+// validation rules of CRDs and for ValidatingAdmissionPolicies. The objects are dynamically typed, and the string
+// extension functions (e.g., lowerAscii, split and replace) are available.
+
+var (
+	envOnce sync.Once
+	env     *cel.Env
+	envErr  error
+)
+
+func celEnv() (*cel.Env, error) {
+	envOnce.Do(func() {
+		env, envErr = cel.NewEnv(
+			cel.Variable("object", cel.DynType),
+			cel.Variable("oldObject", cel.DynType),
+			ext.Strings(),
+		)
+	})
+	return env, envErr
+}
+
+// compileExpression compiles a CEL expression into a program that supports partial evaluation, so that an expression
+// that depends on an unknown value evaluates to an unknown result.
+func compileExpression(expression string) (cel.Program, error) {
+	e, err := celEnv()
+	if err != nil {
+		return nil, err
+	}
+	ast, iss := e.Compile(expression)
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("%s", strings.TrimPrefix(iss.Err().Error(), "ERROR: "))
+	}
+	return e.Program(ast, cel.EvalOptions(cel.OptPartialEval))
+}
+
+// activation returns the variables of an evaluation. Computed values of the objects are replaced by null, and are
+// declared as unknown attributes, so that an expression that selects them, or the collections that contain them,
+// cannot be decided.
+func activation(vars map[string]interface{}) (interpreter.PartialActivation, error) {
+	var unknowns []*interpreter.AttributePattern
+	values := map[string]interface{}{}
+	for name, v := range vars {
+		values[name] = activationValue(v, func(path []interface{}) {
+			pattern := cel.AttributePattern(name)
+			for _, q := range path {
+				switch q := q.(type) {
+				case string:
+					pattern.QualString(q)
+				case int64:
+					pattern.QualInt(q)
+				}
+			}
+			unknowns = append(unknowns, pattern)
+		}, nil)
+	}
+	return cel.PartialVars(values, unknowns...)
+}
+
+func activationValue(v interface{}, unknown func(path []interface{}), path []interface{}) interface{} {
+	switch v := v.(type) {
+	case resource.Computed:
+		unknown(path)
+		return nil
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = activationValue(e, unknown, append(path[:len(path):len(path)], k))
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, e := range v {
+			l[i] = activationValue(e, unknown, append(path[:len(path):len(path)], int64(i)))
+		}
+		return l
+	case float64:
+		// The API server decodes the integers of JSON objects as int64, so integral numbers are ints in expressions,
+		// as they are in the validation rules of CRDs.
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v)
+		}
+		return v
+	case int:
+		return int64(v)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// EnforcementLevel is how a violation of a policy is reported.
+type EnforcementLevel string
+
+const (
+	// Mandatory policies fail the check of an object that violates them. This is the default.
+	Mandatory EnforcementLevel = "mandatory"
+	// Advisory policies warn about an object that violates them.
+	Advisory EnforcementLevel = "advisory"
+	// Disabled policies are not evaluated.
+	Disabled EnforcementLevel = "disabled"
+)
+
+// Policy is a set of validations of the objects of the matching kinds, written as CEL expressions that evaluate to
+// true if an object is valid. The expressions can refer to the object as `object`, and to its previous inputs as
+// `oldObject`, which is null for an object that is being created.
+//
+// Policies are written in YAML or JSON, e.g.:
+//
+//	name: no-latest-tag
+//	enforcementLevel: mandatory
+//	match:
+//	- apiGroups: [""]
+//	  kinds: ["Pod"]
+//	validations:
+//	- expression: "object.spec.containers.all(c, !c.image.endsWith(':latest'))"
+//	  message: "images must not use the latest tag"
+type Policy struct {
+	Name             string           `json:"name"`
+	Description      string           `json:"description,omitempty"`
+	EnforcementLevel EnforcementLevel `json:"enforcementLevel,omitempty"`
+	// Match are the kinds of objects that the policy applies to. A policy without a match applies to every object.
+	Match       []Match      `json:"match,omitempty"`
+	Validations []Validation `json:"validations"`
+}
+
+// Match matches objects by API group and kind. An empty list, or a list that contains "*", matches every group or
+// kind. The core API group is "".
+type Match struct {
+	APIGroups []string `json:"apiGroups,omitempty"`
+	Kinds     []string `json:"kinds,omitempty"`
+}
+
+// Validation is a CEL expression that evaluates to true if an object is valid, and the message of a violation.
+type Validation struct {
+	Expression string `json:"expression"`
+	Message    string `json:"message,omitempty"`
+
+	program cel.Program
+}
+
+// Violation is a violation of a policy by an object.
+type Violation struct {
+	Policy           string
+	EnforcementLevel EnforcementLevel
+	Message          string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("[%s] %s", v.Policy, v.Message)
+}
+
+// Policies is a set of policies.
+type Policies []*Policy
+
+// LoadDirectory loads the policies in the YAML and JSON files of the given directory. Each file may contain several
+// policies, as separate YAML documents. Policies written in Rego are not supported, since a Rego engine is not part
+// of the provider, and fail to load rather than being silently ignored.
+func LoadDirectory(dir string) (Policies, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy directory: %v", err)
+	}
+
+	var policies Policies
+	names := map[string]string{}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, file.Name())
+		switch strings.ToLower(filepath.Ext(file.Name())) {
+		case ".yaml", ".yml", ".json":
+		case ".rego":
+			return nil, fmt.Errorf("%s: Rego policies are not supported, policies must be written as CEL expressions", path)
+		default:
+			continue
+		}
+
+		loaded, err := loadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range loaded {
+			if other, exists := names[p.Name]; exists {
+				return nil, fmt.Errorf("%s: policy %q is already defined in %s", path, p.Name, other)
+			}
+			names[p.Name] = path
+		}
+		policies = append(policies, loaded...)
+	}
+	return policies, nil
+}
+
+func loadFile(path string) (Policies, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var policies Policies
+	reader := k8syaml.NewYAMLReader(bufio.NewReader(f))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			return policies, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		p := &Policy{}
+		if err := yaml.UnmarshalStrict(doc, p); err != nil {
+			return nil, fmt.Errorf("%s: invalid policy: %v", path, err)
+		}
+		if err := p.compile(); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		policies = append(policies, p)
+	}
+}
+
+// compile validates the policy and compiles its expressions.
+func (p *Policy) compile() error {
+	if p.Name == "" {
+		return fmt.Errorf("policy must have a name")
+	}
+	switch p.EnforcementLevel {
+	case "":
+		p.EnforcementLevel = Mandatory
+	case Mandatory, Advisory, Disabled:
+	default:
+		return fmt.Errorf("policy %q has invalid enforcementLevel %q, expected mandatory, advisory or disabled",
+			p.Name, p.EnforcementLevel)
+	}
+	if len(p.Validations) == 0 {
+		return fmt.Errorf("policy %q must have at least one validation", p.Name)
+	}
+	for i := range p.Validations {
+		v := &p.Validations[i]
+		program, err := compileExpression(v.Expression)
+		if err != nil {
+			return fmt.Errorf("policy %q has invalid expression %q: %v", p.Name, v.Expression, err)
+		}
+		v.program = program
+	}
+	return nil
+}
+
+// Matches returns whether the policy applies to the given object.
+func (p *Policy) Matches(obj *unstructured.Unstructured) bool {
+	if len(p.Match) == 0 {
+		return true
+	}
+	gvk := obj.GroupVersionKind()
+	for _, m := range p.Match {
+		if matchesAny(m.APIGroups, gvk.Group) && matchesAny(m.Kinds, gvk.Kind) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if pattern == "*" || pattern == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate evaluates the policies that apply to the given object, and returns their violations. The old object is
+// nil if the object is being created. A validation that fails to evaluate, e.g., because it selects a field that the
+// object does not have, is a violation, so that a policy cannot be bypassed by omitting a field. The objects may
+// contain computed values, e.g., during a preview, and a validation that depends on a computed value cannot be
+// decided, so it is not a violation. Such an object must be evaluated again once its values are known.
+func (ps Policies) Evaluate(obj, oldObj *unstructured.Unstructured) []Violation {
+	vars := map[string]interface{}{"object": obj.Object, "oldObject": nil}
+	if oldObj != nil {
+		vars["oldObject"] = oldObj.Object
+	}
+
+	activation, activationErr := activation(vars)
+
+	var violations []Violation
+	for _, p := range ps {
+		if p.EnforcementLevel == Disabled || !p.Matches(obj) {
+			continue
+		}
+		for _, v := range p.Validations {
+			var message string
+			if activationErr != nil {
+				message = fmt.Sprintf("failed to evaluate %q: %v", v.Expression, activationErr)
+			} else {
+				value, _, err := v.program.Eval(activation)
+				switch {
+				case err != nil:
+					message = fmt.Sprintf("failed to evaluate %q: %v", v.Expression, err)
+				case types.IsUnknown(value):
+					continue
+				case value.Type() != types.BoolType:
+					message = fmt.Sprintf("expression %q must evaluate to a bool, but found %s",
+						v.Expression, value.Type().TypeName())
+				case value == types.True:
+					continue
+				case v.Message != "":
+					message = v.Message
+				default:
+					message = fmt.Sprintf("failed expression: %s", v.Expression)
+				}
+			}
+			violations = append(violations, Violation{
+				Policy:           p.Name,
+				EnforcementLevel: p.EnforcementLevel,
+				Message:          message,
+			})
+		}
+	}
+	return violations
+}
@@ -0,0 +1,238 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const podPolicies = `
+name: no-latest-tag
+match:
+- apiGroups: [""]
+  kinds: ["Pod"]
+validations:
+- expression: "object.spec.containers.all(c, !c.image.endsWith(':latest'))"
+  message: images must not use the latest tag
+---
+name: resource-limits
+enforcementLevel: advisory
+match:
+- apiGroups: ["", "apps"]
+  kinds: ["*"]
+validations:
+- expression: "object.spec.containers.all(c, has(c.resources) && has(c.resources.limits))"
+  message: containers should set resource limits
+`
+
+const privilegedPolicy = `{
+  "name": "no-privileged-pods",
+  "validations": [{
+    "expression": "object.spec.containers.all(c, !has(c.securityContext) || c.securityContext.privileged != true)"
+  }]
+}`
+
+func policyDir(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "policies")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	for name, content := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+	return dir
+}
+
+func pod(containers ...interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]interface{}{"name": "pod"},
+		"spec":       map[string]interface{}{"containers": containers},
+	}}
+}
+
+func TestLoadDirectory(t *testing.T) {
+	policies, err := LoadDirectory(policyDir(t, map[string]string{
+		"pods.yaml":       podPolicies,
+		"privileged.json": privilegedPolicy,
+		"README.md":       "# Policies",
+	}))
+	require.NoError(t, err)
+	require.Len(t, policies, 3)
+	assert.Equal(t, "no-latest-tag", policies[0].Name)
+	assert.Equal(t, Mandatory, policies[0].EnforcementLevel)
+	assert.Equal(t, Advisory, policies[1].EnforcementLevel)
+	assert.Equal(t, "no-privileged-pods", policies[2].Name)
+
+	violations := policies.Evaluate(pod(
+		map[string]interface{}{
+			"name":      "nginx",
+			"image":     "nginx:latest",
+			"resources": map[string]interface{}{"limits": map[string]interface{}{"cpu": "1"}},
+		},
+		map[string]interface{}{
+			"name":            "sidecar",
+			"image":           "envoy:1.19",
+			"securityContext": map[string]interface{}{"privileged": true},
+		},
+	), nil)
+	assert.Equal(t, []Violation{
+		{Policy: "no-latest-tag", EnforcementLevel: Mandatory, Message: "images must not use the latest tag"},
+		{Policy: "resource-limits", EnforcementLevel: Advisory, Message: "containers should set resource limits"},
+		{
+			Policy:           "no-privileged-pods",
+			EnforcementLevel: Mandatory,
+			Message: "failed expression: " +
+				"object.spec.containers.all(c, !has(c.securityContext) || c.securityContext.privileged != true)",
+		},
+	}, violations)
+
+	assert.Empty(t, policies.Evaluate(pod(map[string]interface{}{
+		"name":      "nginx",
+		"image":     "nginx:1.21",
+		"resources": map[string]interface{}{"limits": map[string]interface{}{"cpu": "1"}},
+	}), nil))
+}
+
+func TestEvaluate(t *testing.T) {
+	policies, err := LoadDirectory(policyDir(t, map[string]string{"policies.yaml": `
+name: immutable-replicas
+match:
+- apiGroups: ["apps"]
+  kinds: ["Deployment"]
+validations:
+- expression: "oldObject == null || object.spec.replicas == oldObject.spec.replicas"
+  message: replicas cannot be changed
+- expression: "object.spec.replicas"
+---
+name: disabled
+enforcementLevel: disabled
+validations:
+- expression: "false"
+`}))
+	require.NoError(t, err)
+
+	deployment := func(replicas int64) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"spec":       map[string]interface{}{"replicas": replicas},
+		}}
+	}
+	mustEvaluateToBool := Violation{
+		Policy:           "immutable-replicas",
+		EnforcementLevel: Mandatory,
+		Message:          `expression "object.spec.replicas" must evaluate to a bool, but found int`,
+	}
+	assert.Equal(t, []Violation{mustEvaluateToBool}, policies.Evaluate(deployment(2), nil))
+	assert.Equal(t, []Violation{mustEvaluateToBool}, policies.Evaluate(deployment(2), deployment(2)))
+	assert.Equal(t, []Violation{
+		{Policy: "immutable-replicas", EnforcementLevel: Mandatory, Message: "replicas cannot be changed"},
+		mustEvaluateToBool,
+	}, policies.Evaluate(deployment(3), deployment(2)))
+
+	// Policies only apply to the objects that they match, and a validation that fails to evaluate is a violation.
+	assert.Empty(t, policies.Evaluate(pod(), nil))
+	violations := policies.Evaluate(&unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"spec":       map[string]interface{}{},
+	}}, deployment(2))
+	require.Len(t, violations, 2)
+	assert.Equal(t, `failed to evaluate "oldObject == null || object.spec.replicas == oldObject.spec.replicas": `+
+		`no such key: replicas`, violations[0].Message)
+}
+
+func TestEvaluateUnknowns(t *testing.T) {
+	policies, err := LoadDirectory(policyDir(t, map[string]string{"pods.yaml": podPolicies + `
+---
+name: named
+validations:
+- expression: "object.metadata.name.startsWith('app-')"
+  message: names must start with app-
+`}))
+	require.NoError(t, err)
+
+	// A validation that depends on a computed value, or on a collection that contains one, cannot be decided, so it is
+	// not a violation, but the validations that do not depend on it are evaluated.
+	unknownImage := pod(map[string]interface{}{"name": "nginx", "image": resource.Computed{}})
+	assert.Equal(t, []Violation{
+		{Policy: "named", EnforcementLevel: Mandatory, Message: "names must start with app-"},
+	}, policies.Evaluate(unknownImage, nil))
+
+	unknownName := pod(map[string]interface{}{"name": "nginx", "image": "nginx:latest"})
+	unknownName.Object["metadata"] = map[string]interface{}{"name": resource.Computed{}}
+	assert.Equal(t, []Violation{
+		{Policy: "no-latest-tag", EnforcementLevel: Mandatory, Message: "images must not use the latest tag"},
+		{Policy: "resource-limits", EnforcementLevel: Advisory, Message: "containers should set resource limits"},
+	}, policies.Evaluate(unknownName, nil))
+}
+
+func TestLoadDirectoryErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		err   string
+	}{
+		{
+			name:  "rego",
+			files: map[string]string{"deny.rego": "package kubernetes"},
+			err:   "Rego policies are not supported, policies must be written as CEL expressions",
+		},
+		{
+			name:  "duplicate",
+			files: map[string]string{"a.yaml": podPolicies, "b.yaml": podPolicies},
+			err:   `policy "no-latest-tag" is already defined in`,
+		},
+		{
+			name:  "unknown field",
+			files: map[string]string{"a.yaml": "name: a\nenforcement: advisory\nvalidations: [{expression: 'true'}]"},
+			err:   `unknown field "enforcement"`,
+		},
+		{
+			name:  "enforcement level",
+			files: map[string]string{"a.yaml": "name: a\nenforcementLevel: warn\nvalidations: [{expression: 'true'}]"},
+			err:   `policy "a" has invalid enforcementLevel "warn", expected mandatory, advisory or disabled`,
+		},
+		{
+			name:  "no validations",
+			files: map[string]string{"a.yaml": "name: a"},
+			err:   `policy "a" must have at least one validation`,
+		},
+		{
+			name:  "invalid expression",
+			files: map[string]string{"a.yaml": "name: a\nvalidations: [{expression: 'object.spec.'}]"},
+			err:   `policy "a" has invalid expression "object.spec.": <input>:1:13: Syntax error: missing IDENTIFIER`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadDirectory(policyDir(t, tt.files))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+
+	_, err := LoadDirectory(filepath.Join(os.TempDir(), "does-not-exist"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,155 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// configEnvVars maps the provider config keys to the environment variables that are used when the key is not set.
+// The variables are named PULUMI_K8S_<KEY>, with the key in upper snake case.
+var configEnvVars = map[resource.PropertyKey]string{
+	"kubeVersion":                    "PULUMI_K8S_KUBE_VERSION",
+	"clientQps":                      "PULUMI_K8S_CLIENT_QPS",
+	"clientBurst":                    "PULUMI_K8S_CLIENT_BURST",
+	"maxConcurrentWatches":           "PULUMI_K8S_MAX_CONCURRENT_WATCHES",
+	"impersonate":                    "PULUMI_K8S_IMPERSONATE",
+	"discoveryCacheDir":              "PULUMI_K8S_DISCOVERY_CACHE_DIR",
+	"discoveryCacheTtl":              "PULUMI_K8S_DISCOVERY_CACHE_TTL",
+	"server":                         "PULUMI_K8S_SERVER",
+	"certificateAuthorityData":       "PULUMI_K8S_CERTIFICATE_AUTHORITY_DATA",
+	"token":                          "PULUMI_K8S_TOKEN",
+	"clientCertificateData":          "PULUMI_K8S_CLIENT_CERTIFICATE_DATA",
+	"clientKeyData":                  "PULUMI_K8S_CLIENT_KEY_DATA",
+	"insecureSkipTlsVerify":          "PULUMI_K8S_INSECURE_SKIP_TLS_VERIFY",
+	"proxyUrl":                       "PULUMI_K8S_PROXY_URL",
+	"enableDryRun":                   "PULUMI_K8S_ENABLE_DRY_RUN",
+	"enableDriftDetection":           "PULUMI_K8S_ENABLE_DRIFT_DETECTION",
+	"driftIgnoredFieldManagers":      "PULUMI_K8S_DRIFT_IGNORED_FIELD_MANAGERS",
+	"ignoreFields":                   "PULUMI_K8S_IGNORE_FIELDS",
+	"policyDirectory":                "PULUMI_K8S_POLICY_DIRECTORY",
+	"renderYamlClean":                "PULUMI_K8S_RENDER_YAML_CLEAN",
+	"renderYamlLayout":               "PULUMI_K8S_RENDER_YAML_LAYOUT",
+	"renderYamlSecrets":              "PULUMI_K8S_RENDER_YAML_SECRETS",
+	"renderYamlSecretsKey":           "PULUMI_K8S_RENDER_YAML_SECRETS_KEY",
+	"renderYamlUnknowns":             "PULUMI_K8S_RENDER_YAML_UNKNOWNS",
+	"suppressDeprecationWarnings":    "PULUMI_K8S_SUPPRESS_DEPRECATION_WARNINGS",
+	"migrateApiVersions":             "PULUMI_K8S_MIGRATE_API_VERSIONS",
+	"suppressHelmReleaseBetaWarning": "PULUMI_K8S_SUPPRESS_HELM_RELEASE_BETA_WARNING",
+	"helmDriver":                     "PULUMI_K8S_HELM_DRIVER",
+	"helmPluginsPath":                "PULUMI_K8S_HELM_PLUGINS_PATH",
+	"helmRegistryConfigPath":         "PULUMI_K8S_HELM_REGISTRY_CONFIG_PATH",
+	"helmRepositoryConfigPath":       "PULUMI_K8S_HELM_REPOSITORY_CONFIG_PATH",
+	// This variable was published with this spelling, so it is kept for compatibility.
+	"helmRepositoryCache":      "PULUMI_K8s_HELM_REPOSITORY_CACHE",
+	"suppressHelmHookWarnings": "PULUMI_K8S_SUPPRESS_HELM_HOOK_WARNINGS",
+}
+
+// booleanConfigKeys are the provider config keys whose values are booleans.
+var booleanConfigKeys = map[resource.PropertyKey]bool{
+	"insecureSkipTlsVerify":          true,
+	"enableDryRun":                   true,
+	"enableDriftDetection":           true,
+	"renderYamlClean":                true,
+	"suppressDeprecationWarnings":    true,
+	"migrateApiVersions":             true,
+	"suppressHelmReleaseBetaWarning": true,
+	"suppressHelmHookWarnings":       true,
+}
+
+// configEnvValue returns the value of the environment variable of the given provider config key, if the key has one
+// and it is set. Direct credentials describe the cluster completely, so their variables are not used if the config
+// selects a cluster through a kubeconfig.
+func configEnvValue(key resource.PropertyKey, kubeconfigSet bool) (string, bool) {
+	env, ok := configEnvVars[key]
+	if !ok {
+		return "", false
+	}
+	if kubeconfigSet {
+		for _, credential := range directCredentialKeys {
+			if key == credential {
+				return "", false
+			}
+		}
+	}
+	return os.LookupEnv(env)
+}
+
+// withConfigEnv returns the provider config variables of a Configure request, with each variable that is not set
+// taken from its environment variable.
+func withConfigEnv(vars map[string]string) map[string]string {
+	kubeconfigSet := false
+	for _, key := range []string{"kubeconfig", "context", "cluster"} {
+		if _, ok := vars["kubernetes:config:"+key]; ok {
+			kubeconfigSet = true
+		}
+	}
+
+	result := make(map[string]string, len(vars))
+	for k, v := range vars {
+		result[k] = v
+	}
+	for key := range configEnvVars {
+		name := "kubernetes:config:" + string(key)
+		if _, ok := result[name]; ok {
+			continue
+		}
+		if value, ok := configEnvValue(key, kubeconfigSet); ok {
+			result[name] = value
+		}
+	}
+	return result
+}
+
+// withConfigInputsEnv returns the provider config inputs of a CheckConfig request, with each input that is not set
+// taken from its environment variable, so that the values of the variables are validated in the same way as the
+// inputs. The result is only used for validation: the values of the variables are not added to the inputs of the
+// provider, so that they are not stored in its state.
+func withConfigInputsEnv(news resource.PropertyMap) resource.PropertyMap {
+	set := func(key resource.PropertyKey) bool {
+		return news[key].HasValue() || news[key].IsComputed()
+	}
+	kubeconfigSet := set("kubeconfig") || set("context") || set("cluster")
+
+	result := news.Copy()
+	for key := range configEnvVars {
+		if set(key) {
+			continue
+		}
+		value, ok := configEnvValue(key, kubeconfigSet)
+		if !ok {
+			continue
+		}
+		switch {
+		case booleanConfigKeys[key]:
+			result[key] = resource.NewBoolProperty(value == "true")
+		case key == "impersonate":
+			// The variable holds the JSON encoding of the config, as the config variable does. Values that are not
+			// valid JSON are kept as strings, so that they fail the validation of the config.
+			var obj map[string]interface{}
+			if err := json.Unmarshal([]byte(value), &obj); err != nil {
+				result[key] = resource.NewStringProperty(value)
+			} else {
+				result[key] = resource.NewObjectProperty(resource.NewPropertyMapFromMap(obj))
+			}
+		default:
+			result[key] = resource.NewStringProperty(value)
+		}
+	}
+	return result
+}
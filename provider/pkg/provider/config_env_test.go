@@ -0,0 +1,81 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"os"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func setEnv(t *testing.T, env map[string]string) {
+	for k, v := range env {
+		old, exists := os.LookupEnv(k)
+		_ = os.Setenv(k, v)
+		k := k
+		t.Cleanup(func() {
+			if exists {
+				_ = os.Setenv(k, old)
+			} else {
+				_ = os.Unsetenv(k)
+			}
+		})
+	}
+}
+
+func TestWithConfigEnv(t *testing.T) {
+	setEnv(t, map[string]string{
+		"PULUMI_K8S_RENDER_YAML_CLEAN": "true",
+		"PULUMI_K8S_ENABLE_DRY_RUN":    "true",
+		"PULUMI_K8S_SERVER":            "https://1.2.3.4:6443",
+	})
+
+	vars := withConfigEnv(map[string]string{"kubernetes:config:enableDryRun": "false"})
+	assert.Equal(t, map[string]string{
+		"kubernetes:config:renderYamlClean": "true",
+		"kubernetes:config:enableDryRun":    "false",
+		"kubernetes:config:server":          "https://1.2.3.4:6443",
+	}, vars)
+
+	// Direct credentials are not taken from the environment if a kubeconfig context is selected.
+	vars = withConfigEnv(map[string]string{"kubernetes:config:context": "dev"})
+	assert.Equal(t, map[string]string{
+		"kubernetes:config:context":         "dev",
+		"kubernetes:config:renderYamlClean": "true",
+		"kubernetes:config:enableDryRun":    "true",
+	}, vars)
+}
+
+func TestWithConfigInputsEnv(t *testing.T) {
+	setEnv(t, map[string]string{
+		"PULUMI_K8S_RENDER_YAML_CLEAN":  "true",
+		"PULUMI_K8S_RENDER_YAML_LAYOUT": "nested",
+		"PULUMI_K8S_IMPERSONATE":        `{"user":"deployer","groups":["tenants"]}`,
+	})
+
+	news := resource.NewPropertyMapFromMap(map[string]interface{}{"renderYamlToDirectory": "out"})
+	config := withConfigInputsEnv(news)
+	assert.Equal(t, resource.NewBoolProperty(true), config["renderYamlClean"])
+	assert.Equal(t, resource.NewStringProperty("nested"), config["renderYamlLayout"])
+	assert.Equal(t, resource.NewPropertyValue(map[string]interface{}{
+		"user":   "deployer",
+		"groups": []interface{}{"tenants"},
+	}), config["impersonate"])
+	assert.Len(t, news, 1, "the inputs must not be changed")
+
+	assert.Empty(t, checkImpersonation(config))
+}
@@ -0,0 +1,189 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/clients"
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/kinds"
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/openapi"
+	logger "github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsinstall "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/install"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var crdResource = apiextensionsv1.SchemeGroupVersion.WithResource("customresourcedefinitions")
+
+// crdScheme converts CRDs of every version of apiextensions.k8s.io to apiextensions.k8s.io/v1.
+var crdScheme = runtime.NewScheme()
+
+func init() {
+	apiextensionsinstall.Install(crdScheme)
+}
+
+// customResourceDefinition is a CRD of apiextensions.k8s.io/v1, along with the paths of the schemas with
+// `x-kubernetes-validations` rules in each of its versions, which are dropped when the CRD is decoded.
+type customResourceDefinition struct {
+	*apiextensionsv1.CustomResourceDefinition
+	validationRules map[string][]string
+}
+
+// decodeCRD returns the given CRD as a CRD of apiextensions.k8s.io/v1, e.g., with the top-level schema of a CRD of
+// apiextensions.k8s.io/v1beta1 copied to each of its versions.
+func decodeCRD(obj *unstructured.Unstructured) (*customResourceDefinition, error) {
+	typed, err := crdScheme.New(obj.GroupVersionKind())
+	if err != nil {
+		return nil, err
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, typed); err != nil {
+		return nil, err
+	}
+	crdScheme.Default(typed)
+
+	// There are only conversions between the internal version and each of the versions of the API group.
+	internal := &apiextensions.CustomResourceDefinition{}
+	if err := crdScheme.Convert(typed, internal, nil); err != nil {
+		return nil, err
+	}
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := crdScheme.Convert(internal, crd, nil); err != nil {
+		return nil, err
+	}
+	return &customResourceDefinition{CustomResourceDefinition: crd, validationRules: openapi.ValidationRules(obj)}, nil
+}
+
+// declareCRD remembers the given CRD, which is declared in the stack, so that its custom resources are validated
+// against it before it is created in the cluster, or before an update of its schema is applied. CRDs whose inputs
+// are not known yet are ignored.
+func (k *kubeProvider) declareCRD(obj *unstructured.Unstructured) {
+	if !clients.IsCRD(obj) || hasComputedValue(obj) {
+		return
+	}
+	crd, err := decodeCRD(obj)
+	if err != nil {
+		logger.V(3).Infof("unable to decode CustomResourceDefinition %s: %v", obj.GetName(), err)
+		return
+	}
+
+	k.crdsMutex.Lock()
+	defer k.crdsMutex.Unlock()
+	if k.declaredCRDs == nil {
+		k.declaredCRDs = map[schema.GroupKind]*customResourceDefinition{}
+	}
+	k.declaredCRDs[schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}] = crd
+}
+
+// customResourceDefinition returns the CRD of the given kind that is declared in the stack, or otherwise the one in
+// the cluster, or nil if the kind is not a custom resource or its CRD does not exist. CRDs from the cluster are cached
+// until the resources of the cluster are invalidated, e.g., after a CRD is created.
+func (k *kubeProvider) customResourceDefinition(gvk schema.GroupVersionKind) (*customResourceDefinition, error) {
+	if kinds.IsBuiltin(gvk.GroupKind()) || gvk.Group == "" {
+		return nil, nil
+	}
+
+	k.crdsMutex.Lock()
+	defer k.crdsMutex.Unlock()
+	if crd, declared := k.declaredCRDs[gvk.GroupKind()]; declared {
+		return crd, nil
+	}
+	if crd, cached := k.crds[gvk.GroupKind()]; cached {
+		return crd, nil
+	}
+	if k.clusterUnreachable {
+		return nil, nil
+	}
+
+	// The CRD is named after the plural resource name, which is the same for every version of the kind, so that a
+	// resource with a version that is not served is still validated.
+	mapping, err := k.clientSet.RESTMapper.RESTMapping(gvk.GroupKind())
+	if meta.IsNoMatchError(err) {
+		return k.cacheCRD(gvk.GroupKind(), nil), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	obj, err := k.clientSet.GenericClient.Resource(crdResource).Get(context.TODO(),
+		mapping.Resource.GroupResource().String(), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return k.cacheCRD(gvk.GroupKind(), nil), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	crd, err := decodeCRD(obj)
+	if err != nil {
+		return nil, err
+	}
+	return k.cacheCRD(gvk.GroupKind(), crd), nil
+}
+
+// cacheCRD caches the CRD of the given kind, which is nil if the kind is not a custom resource. The caller must hold
+// crdsMutex.
+func (k *kubeProvider) cacheCRD(gk schema.GroupKind, crd *customResourceDefinition) *customResourceDefinition {
+	if k.crds == nil {
+		k.crds = map[schema.GroupKind]*customResourceDefinition{}
+	}
+	k.crds[gk] = crd
+	return crd
+}
+
+// checkCustomResource validates a custom resource against the schema in its CRD, and returns a CheckFailure for each
+// invalid field, along with a warning for each field that the API server would prune. Custom resources without a
+// CRD are not validated, and a warning is returned if the CRD has no schema for the version of the resource, or if
+// the schema has validation rules that are not checked.
+func (k *kubeProvider) checkCustomResource(
+	obj *unstructured.Unstructured,
+) (failures []*pulumirpc.CheckFailure, warnings []string, err error) {
+	crd, err := k.customResourceDefinition(obj.GroupVersionKind())
+	if err != nil || crd == nil {
+		return nil, nil, err
+	}
+	result, err := openapi.ValidateCustomResource(crd.CustomResourceDefinition, obj)
+	if err != nil {
+		return nil, nil, err
+	}
+	version := obj.GroupVersionKind().Version
+	if result == nil {
+		return nil, []string{fmt.Sprintf(
+			"CustomResourceDefinition %q declares no schema for version %q, so the resource is not validated",
+			crd.Name, version)}, nil
+	}
+	if paths := crd.validationRules[version]; len(paths) > 0 {
+		warnings = append(warnings, fmt.Sprintf(
+			"the x-kubernetes-validations rules of %s in the schema of CustomResourceDefinition %q are not checked "+
+				"by the provider, so the resource is only partially validated",
+			strings.Join(paths, ", "), crd.Name))
+	}
+
+	for _, fe := range result.Errors {
+		failures = append(failures, &pulumirpc.CheckFailure{Property: fe.Field, Reason: fe.ErrorBody()})
+	}
+	for _, path := range result.UnknownFields {
+		warnings = append(warnings, fmt.Sprintf(
+			"%s is not in the schema of CustomResourceDefinition %q, and will be pruned by the API server", path, crd.Name))
+	}
+	return failures, warnings, nil
+}
@@ -0,0 +1,247 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/clients"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/restmapper"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// crontabCRD is a CRD of apiextensions.k8s.io/v1 with a structural schema for the CronTab kind.
+var crontabCRD = &unstructured.Unstructured{Object: map[string]interface{}{
+	"apiVersion": "apiextensions.k8s.io/v1",
+	"kind":       "CustomResourceDefinition",
+	"metadata":   map[string]interface{}{"name": "crontabs.stable.example.com"},
+	"spec": map[string]interface{}{
+		"group": "stable.example.com",
+		"names": map[string]interface{}{"plural": "crontabs", "kind": "CronTab"},
+		"scope": "Namespaced",
+		"versions": []interface{}{
+			map[string]interface{}{
+				"name":    "v1",
+				"served":  true,
+				"storage": true,
+				"schema": map[string]interface{}{
+					"openAPIV3Schema": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"spec": map[string]interface{}{
+								"type":     "object",
+								"required": []interface{}{"cronSpec"},
+								"properties": map[string]interface{}{
+									"cronSpec": map[string]interface{}{"type": "string"},
+									"replicas": map[string]interface{}{"type": "integer"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	},
+}}
+
+// crdProvider returns a provider for a fake cluster that serves the CronTab kind of crontabCRD.
+func crdProvider(crds ...runtime.Object) *kubeProvider {
+	disco := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{Resources: []*metav1.APIResourceList{{
+		GroupVersion: "stable.example.com/v1",
+		APIResources: []metav1.APIResource{{Name: "crontabs", Namespaced: true, Kind: "CronTab"}},
+	}}}}
+	cached := clients.NewMemCacheClient(disco)
+	return &kubeProvider{clientSet: &clients.DynamicClientSet{
+		GenericClient:         fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), crds...),
+		DiscoveryClientCached: cached,
+		RESTMapper:            restmapper.NewDeferredDiscoveryRESTMapper(cached),
+	}}
+}
+
+func crontab(spec map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "stable.example.com/v1",
+		"kind":       "CronTab",
+		"metadata":   map[string]interface{}{"name": "crontab"},
+		"spec":       spec,
+	}}
+}
+
+func TestCheckCustomResource(t *testing.T) {
+	k := crdProvider(crontabCRD.DeepCopy())
+
+	failures, warnings, err := k.checkCustomResource(crontab(map[string]interface{}{
+		"replicas": "two",
+		"image":    "crontab",
+	}))
+	require.NoError(t, err)
+	require.Len(t, failures, 2)
+	properties := []string{failures[0].Property, failures[1].Property}
+	assert.ElementsMatch(t, []string{"spec.cronSpec", "spec.replicas"}, properties)
+	assert.Equal(t, []string{`spec.image is not in the schema of CustomResourceDefinition ` +
+		`"crontabs.stable.example.com", and will be pruned by the API server`}, warnings)
+
+	failures, warnings, err = k.checkCustomResource(crontab(map[string]interface{}{"cronSpec": "* * * * */5"}))
+	require.NoError(t, err)
+	assert.Empty(t, failures)
+	assert.Empty(t, warnings)
+
+	// The CRD is cached until the resources of the cluster are invalidated.
+	assert.Contains(t, k.crds, schema.GroupKind{Group: "stable.example.com", Kind: "CronTab"})
+	k.invalidateResources()
+	assert.Nil(t, k.crds)
+}
+
+func TestCheckCustomResourceWithoutCRD(t *testing.T) {
+	k := crdProvider()
+
+	// Kinds that are not served by the cluster, and kinds whose CRD cannot be read, are not validated.
+	for _, obj := range []*unstructured.Unstructured{
+		crontab(map[string]interface{}{"replicas": "two"}),
+		{Object: map[string]interface{}{"apiVersion": "example.com/v1", "kind": "Widget"}},
+	} {
+		failures, warnings, err := k.checkCustomResource(obj)
+		require.NoError(t, err)
+		assert.Empty(t, failures)
+		assert.Empty(t, warnings)
+	}
+
+	// Built-in kinds are never looked up.
+	crd, err := k.customResourceDefinition(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"})
+	require.NoError(t, err)
+	assert.Nil(t, crd)
+	assert.Len(t, k.crds, 2)
+}
+
+func TestCheckCustomResourceWithDeclaredCRD(t *testing.T) {
+	// The CRD is declared in the stack, but the cluster is unreachable, e.g., in a preview of a new cluster.
+	k := &kubeProvider{clusterUnreachable: true}
+	failures, _, err := k.checkCustomResource(crontab(map[string]interface{}{"replicas": "two"}))
+	require.NoError(t, err)
+	assert.Empty(t, failures)
+
+	k.declareCRD(crontabCRD.DeepCopy())
+	failures, _, err = k.checkCustomResource(crontab(map[string]interface{}{"replicas": "two"}))
+	require.NoError(t, err)
+	assert.Len(t, failures, 2)
+
+	// A declared CRD takes precedence over the one in the cluster, so that custom resources are validated against an
+	// updated schema before it is applied.
+	k = crdProvider(crontabCRD.DeepCopy())
+	updated := crontabCRD.DeepCopy()
+	versions, _, _ := unstructured.NestedSlice(updated.Object, "spec", "versions")
+	require.NoError(t, unstructured.SetNestedField(versions[0].(map[string]interface{}),
+		map[string]interface{}{"type": "string"}, "schema", "openAPIV3Schema", "properties", "spec", "properties", "image"))
+	require.NoError(t, unstructured.SetNestedSlice(updated.Object, versions, "spec", "versions"))
+	k.declareCRD(updated)
+	_, warnings, err := k.checkCustomResource(crontab(map[string]interface{}{
+		"cronSpec": "* * * * */5",
+		"image":    "crontab",
+	}))
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+
+	// CRDs with unknown inputs are not declared.
+	k = &kubeProvider{clusterUnreachable: true}
+	computed := crontabCRD.DeepCopy()
+	computed.Object["spec"].(map[string]interface{})["group"] = resource.Computed{}
+	k.declareCRD(computed)
+	assert.Empty(t, k.declaredCRDs)
+}
+
+func TestCheckCustomResourceWithBuiltinKindName(t *testing.T) {
+	// Custom resource kinds may have the same name as a built-in kind in another group.
+	crd := crontabCRD.DeepCopy()
+	require.NoError(t, unstructured.SetNestedField(crd.Object, "serving.knative.dev", "spec", "group"))
+	require.NoError(t, unstructured.SetNestedField(crd.Object, "Service", "spec", "names", "kind"))
+	k := &kubeProvider{clusterUnreachable: true}
+	k.declareCRD(crd)
+
+	service := crontab(map[string]interface{}{"replicas": "two"})
+	service.SetAPIVersion("serving.knative.dev/v1")
+	service.SetKind("Service")
+	failures, _, err := k.checkCustomResource(service)
+	require.NoError(t, err)
+	assert.Len(t, failures, 2)
+
+	service.SetAPIVersion("v1")
+	failures, _, err = k.checkCustomResource(service)
+	require.NoError(t, err)
+	assert.Empty(t, failures)
+}
+
+func TestCheckCustomResourceWithUncheckedSchema(t *testing.T) {
+	crd := crontabCRD.DeepCopy()
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	require.NoError(t, unstructured.SetNestedSlice(versions[0].(map[string]interface{}), []interface{}{
+		map[string]interface{}{"rule": "self.replicas <= 10"},
+	}, "schema", "openAPIV3Schema", "properties", "spec", "x-kubernetes-validations"))
+	versions = append(versions, map[string]interface{}{"name": "v1beta1", "served": true, "storage": false})
+	require.NoError(t, unstructured.SetNestedSlice(crd.Object, versions, "spec", "versions"))
+	k := &kubeProvider{clusterUnreachable: true}
+	k.declareCRD(crd)
+
+	// Resources are validated against the rest of the schema, but the validation rules are not checked.
+	failures, warnings, err := k.checkCustomResource(crontab(map[string]interface{}{"replicas": "two"}))
+	require.NoError(t, err)
+	assert.Len(t, failures, 2)
+	assert.Equal(t, []string{`the x-kubernetes-validations rules of spec in the schema of CustomResourceDefinition ` +
+		`"crontabs.stable.example.com" are not checked by the provider, so the resource is only partially validated`},
+		warnings)
+
+	v1beta1 := crontab(map[string]interface{}{"replicas": "two"})
+	v1beta1.SetAPIVersion("stable.example.com/v1beta1")
+	failures, warnings, err = k.checkCustomResource(v1beta1)
+	require.NoError(t, err)
+	assert.Empty(t, failures)
+	assert.Equal(t, []string{`CustomResourceDefinition "crontabs.stable.example.com" declares no schema for ` +
+		`version "v1beta1", so the resource is not validated`}, warnings)
+}
+
+func TestDecodeV1beta1CRD(t *testing.T) {
+	crd, err := decodeCRD(&unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1beta1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": "crontabs.stable.example.com"},
+		"spec": map[string]interface{}{
+			"group":   "stable.example.com",
+			"version": "v1",
+			"names":   map[string]interface{}{"plural": "crontabs", "kind": "CronTab"},
+			"scope":   "Namespaced",
+			"validation": map[string]interface{}{
+				"openAPIV3Schema": map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{"spec": map[string]interface{}{"type": "object"}},
+				},
+			},
+		},
+	}})
+	require.NoError(t, err)
+	require.Len(t, crd.Spec.Versions, 1)
+	assert.Equal(t, "v1", crd.Spec.Versions[0].Name)
+	assert.True(t, crd.Spec.Versions[0].Served)
+	require.NotNil(t, crd.Spec.Versions[0].Schema)
+	assert.Contains(t, crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties, "spec")
+	assert.True(t, crd.Spec.PreserveUnknownFields)
+}
@@ -0,0 +1,236 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	clientapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// clusterIdentityKeys are the provider config keys that select the cluster that the provider manages. A change to
+// one of them replaces every resource of the provider if it changes the identity of the cluster.
+var clusterIdentityKeys = map[string]bool{
+	"kubeconfig":               true,
+	"context":                  true,
+	"cluster":                  true,
+	"server":                   true,
+	"certificateAuthorityData": true,
+}
+
+// settingConfigKeys are the provider config keys that only change how the provider behaves, and never replace its
+// resources.
+var settingConfigKeys = []resource.PropertyKey{
+	"namespace",
+	"enableDryRun",
+	"enableDriftDetection",
+	"kubeVersion",
+	"ignoreFields",
+	"migrateApiVersions",
+	"policyDirectory",
+	"renderYamlUnknowns",
+	// Rendered Secrets are updated by their own diffs when the format or the key of their encryption changes.
+	"renderYamlSecrets",
+	"renderYamlSecretsKey",
+	"suppressDeprecationWarnings",
+	"suppressHelmHookWarnings",
+	"helmDriver",
+	"helmPluginsPath",
+	"helmRegistryConfigPath",
+	"helmRepositoryConfigPath",
+	"helmRepositoryCache",
+	"clientQps",
+	"clientBurst",
+	"maxConcurrentWatches",
+	"impersonate",
+	"discoveryCacheDir",
+	"discoveryCacheTtl",
+}
+
+// renderConfigKeys are the provider config keys that change where or how manifests are rendered, and therefore
+// replace every rendered manifest, with the reason for the replacement.
+var renderConfigKeys = []struct {
+	key    resource.PropertyKey
+	reason string
+}{
+	{"renderYamlToDirectory", "the manifests are rendered to a different directory"},
+	{"renderYamlClean", "the rendered files move to a different directory layout"},
+	{"renderYamlLayout", "the rendered files move to different paths"},
+}
+
+// clusterIdentity is the part of the configuration of a cluster that identifies it: the address of its API server and
+// its certificate authority.
+type clusterIdentity struct {
+	server string
+	ca     string
+}
+
+// identityOfCluster returns the identity of a cluster. Differences in the case of the server's scheme and host, and a
+// trailing slash, do not change the identity.
+func identityOfCluster(cluster *clientapi.Cluster) clusterIdentity {
+	server := strings.TrimSuffix(cluster.Server, "/")
+	if u, err := url.Parse(server); err == nil && u.Host != "" {
+		u.Scheme, u.Host = strings.ToLower(u.Scheme), strings.ToLower(u.Host)
+		server = u.String()
+	}
+	ca := string(bytes.TrimSpace(cluster.CertificateAuthorityData))
+	if ca == "" {
+		ca = cluster.CertificateAuthority
+	}
+	return clusterIdentity{server: server, ca: ca}
+}
+
+// describeIdentityChange returns a description of the change from one cluster identity to another.
+func describeIdentityChange(old, new clusterIdentity) string {
+	var changes []string
+	if old.server != new.server {
+		describe := func(server string) string {
+			if server == "" {
+				return "the default cluster"
+			}
+			return server
+		}
+		changes = append(changes, fmt.Sprintf("the API server changed from %s to %s",
+			describe(old.server), describe(new.server)))
+	}
+	if old.ca != new.ca {
+		changes = append(changes, "the cluster's certificate authority changed")
+	}
+	return strings.Join(changes, " and ")
+}
+
+// providerConfigDiff is the difference between two provider configurations.
+type providerConfigDiff struct {
+	diffs    []string
+	replaces []string
+	// reason explains why the resources of the provider are or are not replaced.
+	reason string
+}
+
+// diffProviderConfig diffs two provider configurations. Every resource of the provider is replaced if the identity of
+// the cluster that it manages changes, i.e., its API server or certificate authority, or if rendered manifests move.
+// Rotated credentials, a different context for the same cluster, and changes to settings such as the default
+// namespace or Helm paths do not replace any resources.
+func diffProviderConfig(olds, news resource.PropertyMap) (*providerConfigDiff, error) {
+	// We can't tell for sure if a computed value has changed, so we make the conservative choice
+	// and force a replacement.
+	for _, key := range []string{"kubeconfig", "context", "cluster", "server", "certificateAuthorityData"} {
+		if news[resource.PropertyKey(key)].IsComputed() {
+			return &providerConfigDiff{
+				diffs:    []string{key},
+				replaces: []string{key},
+				reason: fmt.Sprintf("%q is not known yet, so the cluster may change and all resources will be "+
+					"replaced", key),
+			}, nil
+		}
+	}
+
+	oldConfig, err := parseKubeconfigPropertyValue(olds["kubeconfig"])
+	if err != nil {
+		return nil, err
+	}
+	newConfig, err := parseKubeconfigPropertyValue(news["kubeconfig"])
+	if err != nil {
+		return nil, err
+	}
+
+	d := &providerConfigDiff{}
+	if !reflect.DeepEqual(oldConfig, newConfig) {
+		d.diffs = append(d.diffs, "kubeconfig")
+	}
+	var keys []resource.PropertyKey
+	keys = append(keys, "context", "cluster")
+	keys = append(keys, directCredentialKeys...)
+	keys = append(keys, settingConfigKeys...)
+	for _, key := range keys {
+		if !olds[key].DeepEquals(news[key]) {
+			d.diffs = append(d.diffs, string(key))
+		}
+	}
+
+	var reasons []string
+	for _, render := range renderConfigKeys {
+		if !olds[render.key].DeepEquals(news[render.key]) {
+			d.diffs = append(d.diffs, string(render.key))
+			d.replaces = append(d.replaces, string(render.key))
+			reasons = append(reasons, render.reason)
+		}
+	}
+
+	// In general, it's not possible to tell from a kubeconfig if the k8s cluster it points to has
+	// changed. k8s clusters do not have a well defined identity, so the best we can do is check
+	// if the API server or certificate authority of the active cluster have changed. This is not a
+	// foolproof method; a trivial counterexample is changing the load balancer or DNS entry pointing
+	// to the same cluster.
+	//
+	// The alternative of ignoring changes to the kubeconfig is untenable; if the k8s cluster has
+	// changed, any dependent resources must be recreated, and ignoring changes prevents that from
+	// happening. Credentials, on the other hand, are rotated regularly, and must not replace anything.
+	oldIdentity := identityOfCluster(getActiveClusterFromConfig(oldConfig, olds))
+	newIdentity := identityOfCluster(getActiveClusterFromConfig(newConfig, news))
+	if oldIdentity != newIdentity {
+		for _, key := range d.diffs {
+			if clusterIdentityKeys[key] {
+				d.replaces = append(d.replaces, key)
+			}
+		}
+		reasons = append([]string{describeIdentityChange(oldIdentity, newIdentity)}, reasons...)
+	}
+
+	switch {
+	case len(d.replaces) > 0:
+		d.reason = strings.Join(reasons, ", and ") + ", so all resources of this provider will be replaced"
+	case len(d.diffs) > 0:
+		d.reason = fmt.Sprintf("the provider configuration changed (%s), but the cluster did not, so no "+
+			"resources will be replaced", strings.Join(d.diffs, ", "))
+	}
+	return d, nil
+}
+
+// detailedDiff returns the detailed diff of the provider configuration.
+func (d *providerConfigDiff) detailedDiff(olds, news resource.PropertyMap) map[string]*pulumirpc.PropertyDiff {
+	replaced := map[string]bool{}
+	for _, key := range d.replaces {
+		replaced[key] = true
+	}
+	detailedDiff := map[string]*pulumirpc.PropertyDiff{}
+	for _, key := range d.diffs {
+		kind := pulumirpc.PropertyDiff_UPDATE
+		switch {
+		case !olds.HasValue(resource.PropertyKey(key)) && !olds[resource.PropertyKey(key)].IsComputed():
+			kind = pulumirpc.PropertyDiff_ADD
+		case !news.HasValue(resource.PropertyKey(key)) && !news[resource.PropertyKey(key)].IsComputed():
+			kind = pulumirpc.PropertyDiff_DELETE
+		}
+		if replaced[key] {
+			switch kind {
+			case pulumirpc.PropertyDiff_ADD:
+				kind = pulumirpc.PropertyDiff_ADD_REPLACE
+			case pulumirpc.PropertyDiff_DELETE:
+				kind = pulumirpc.PropertyDiff_DELETE_REPLACE
+			default:
+				kind = pulumirpc.PropertyDiff_UPDATE_REPLACE
+			}
+		}
+		detailedDiff[key] = &pulumirpc.PropertyDiff{Kind: kind, InputDiff: true}
+	}
+	return detailedDiff
+}
@@ -0,0 +1,208 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testKubeconfig returns a kubeconfig for a cluster with the given server, CA data and user token.
+func testKubeconfig(server, ca, token string) string {
+	return fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: cluster
+  cluster:
+    server: %s
+    certificate-authority-data: %s
+users:
+- name: user
+  user:
+    token: %s
+contexts:
+- name: context
+  context:
+    cluster: cluster
+    user: user
+    namespace: default
+current-context: context
+`, server, ca, token)
+}
+
+func TestDiffProviderConfig(t *testing.T) {
+	cert, _ := testCertificate(t)
+	otherCert, _ := testCertificate(t)
+	ca := base64.StdEncoding.EncodeToString([]byte(cert))
+	otherCA := base64.StdEncoding.EncodeToString([]byte(otherCert))
+	kubeconfigProperty := func(server, ca, token string) resource.PropertyValue {
+		return resource.NewStringProperty(testKubeconfig(server, ca, token))
+	}
+	kubeconfig := kubeconfigProperty("https://1.2.3.4:6443", ca, "token")
+
+	tests := []struct {
+		name     string
+		olds     resource.PropertyMap
+		news     resource.PropertyMap
+		diffs    []string
+		replaces []string
+		reason   string
+	}{
+		{
+			name: "no changes",
+			olds: resource.PropertyMap{"kubeconfig": kubeconfig},
+			news: resource.PropertyMap{"kubeconfig": kubeconfig},
+		},
+		{
+			name:   "rotated token",
+			olds:   resource.PropertyMap{"kubeconfig": kubeconfig},
+			news:   resource.PropertyMap{"kubeconfig": kubeconfigProperty("https://1.2.3.4:6443", ca, "new")},
+			diffs:  []string{"kubeconfig"},
+			reason: "but the cluster did not, so no resources will be replaced",
+		},
+		{
+			name:   "equivalent server",
+			olds:   resource.PropertyMap{"kubeconfig": kubeconfig},
+			news:   resource.PropertyMap{"kubeconfig": kubeconfigProperty("https://1.2.3.4:6443/", ca, "token")},
+			diffs:  []string{"kubeconfig"},
+			reason: "but the cluster did not",
+		},
+		{
+			name:     "different server",
+			olds:     resource.PropertyMap{"kubeconfig": kubeconfig},
+			news:     resource.PropertyMap{"kubeconfig": kubeconfigProperty("https://5.6.7.8:6443", ca, "token")},
+			diffs:    []string{"kubeconfig"},
+			replaces: []string{"kubeconfig"},
+			reason:   "the API server changed from https://1.2.3.4:6443 to https://5.6.7.8:6443",
+		},
+		{
+			name:     "different CA",
+			olds:     resource.PropertyMap{"kubeconfig": kubeconfig},
+			news:     resource.PropertyMap{"kubeconfig": kubeconfigProperty("https://1.2.3.4:6443", otherCA, "token")},
+			diffs:    []string{"kubeconfig"},
+			replaces: []string{"kubeconfig"},
+			reason:   "the cluster's certificate authority changed",
+		},
+		{
+			name: "settings",
+			olds: resource.PropertyMap{"kubeconfig": kubeconfig},
+			news: resource.PropertyMap{
+				"kubeconfig":      kubeconfig,
+				"namespace":       resource.NewStringProperty("other"),
+				"helmDriver":      resource.NewStringProperty("configmap"),
+				"helmPluginsPath": resource.NewStringProperty("/plugins"),
+			},
+			diffs:  []string{"namespace", "helmDriver", "helmPluginsPath"},
+			reason: "changed (namespace, helmDriver, helmPluginsPath), but the cluster did not",
+		},
+		{
+			name: "direct credentials rotated",
+			olds: resource.PropertyMap{
+				"server":                   resource.NewStringProperty("https://1.2.3.4:6443"),
+				"certificateAuthorityData": resource.NewStringProperty(ca),
+				"token":                    resource.MakeSecret(resource.NewStringProperty("token")),
+			},
+			news: resource.PropertyMap{
+				"server":                   resource.NewStringProperty("https://1.2.3.4:6443"),
+				"certificateAuthorityData": resource.NewStringProperty(cert),
+				"token":                    resource.MakeSecret(resource.NewStringProperty("new")),
+			},
+			diffs:  []string{"certificateAuthorityData", "token"},
+			reason: "but the cluster did not",
+		},
+		{
+			name: "direct credentials for a different server",
+			olds: resource.PropertyMap{
+				"server": resource.NewStringProperty("https://1.2.3.4:6443"),
+				"token":  resource.NewStringProperty("token"),
+			},
+			news: resource.PropertyMap{
+				"server": resource.NewStringProperty("https://5.6.7.8:6443"),
+				"token":  resource.NewStringProperty("new"),
+			},
+			diffs:    []string{"server", "token"},
+			replaces: []string{"server"},
+			reason:   "the API server changed",
+		},
+		{
+			name: "computed server",
+			olds: resource.PropertyMap{"server": resource.NewStringProperty("https://1.2.3.4:6443")},
+			news: resource.PropertyMap{
+				"server": resource.MakeComputed(resource.NewStringProperty("")),
+			},
+			diffs:    []string{"server"},
+			replaces: []string{"server"},
+			reason:   `"server" is not known yet`,
+		},
+		{
+			name:     "render directory",
+			olds:     resource.PropertyMap{"renderYamlToDirectory": resource.NewStringProperty("a")},
+			news:     resource.PropertyMap{"renderYamlToDirectory": resource.NewStringProperty("b")},
+			diffs:    []string{"renderYamlToDirectory"},
+			replaces: []string{"renderYamlToDirectory"},
+			reason:   "the manifests are rendered to a different directory, so all resources",
+		},
+		{
+			name: "render secrets key",
+			olds: resource.PropertyMap{
+				"renderYamlSecrets":    resource.NewStringProperty("sops"),
+				"renderYamlSecretsKey": resource.NewStringProperty("age1a"),
+			},
+			news: resource.PropertyMap{
+				"renderYamlSecrets":    resource.NewStringProperty("sops"),
+				"renderYamlSecretsKey": resource.NewStringProperty("age1b"),
+			},
+			diffs:  []string{"renderYamlSecretsKey"},
+			reason: "so no resources will be replaced",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := diffProviderConfig(tt.olds, tt.news)
+			require.NoError(t, err)
+			assert.Equal(t, tt.diffs, d.diffs)
+			assert.Equal(t, tt.replaces, d.replaces)
+			if tt.reason == "" {
+				assert.Empty(t, d.reason)
+			} else {
+				assert.Contains(t, d.reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestProviderConfigDetailedDiff(t *testing.T) {
+	olds := resource.PropertyMap{
+		"server":    resource.NewStringProperty("https://1.2.3.4:6443"),
+		"namespace": resource.NewStringProperty("default"),
+	}
+	news := resource.PropertyMap{
+		"server": resource.NewStringProperty("https://5.6.7.8:6443"),
+		"token":  resource.NewStringProperty("token"),
+	}
+	d, err := diffProviderConfig(olds, news)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*pulumirpc.PropertyDiff{
+		"server":    {Kind: pulumirpc.PropertyDiff_UPDATE_REPLACE, InputDiff: true},
+		"token":     {Kind: pulumirpc.PropertyDiff_ADD, InputDiff: true},
+		"namespace": {Kind: pulumirpc.PropertyDiff_DELETE, InputDiff: true},
+	}, d.detailedDiff(olds, news))
+}
@@ -3,13 +3,26 @@
 package provider
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
 	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/clients"
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/cluster"
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/kinds"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/restmapper"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 type object = map[string]interface{}
@@ -196,3 +209,68 @@ func TestPatchToDiff(t *testing.T) {
 		})
 	}
 }
+
+func TestDiffMigratedDeployment(t *testing.T) {
+	disco := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{Resources: []*metav1.APIResourceList{{
+		GroupVersion: "apps/v1",
+		APIResources: []metav1.APIResource{{Name: "deployments", Namespaced: true, Kind: "Deployment"}},
+	}}}}
+	cached := clients.NewMemCacheClient(disco)
+	k := &kubeProvider{
+		providerPackage:    "kubernetes",
+		migrateAPIVersions: true,
+		clientSet: &clients.DynamicClientSet{
+			DiscoveryClientCached: cached,
+			RESTMapper:            restmapper.NewDeferredDiscoveryRESTMapper(cached),
+		},
+	}
+
+	// A Deployment of extensions/v1beta1 without a selector, whose selector was defaulted by the API server.
+	oldInputs := object{
+		"apiVersion": "extensions/v1beta1",
+		"kind":       "Deployment",
+		"metadata":   object{"name": "nginx", "namespace": "default"},
+		"spec": object{
+			"template": object{
+				"metadata": object{"labels": object{"app": "nginx"}},
+				"spec":     object{"containers": list{object{"name": "nginx", "image": "nginx:1.21"}}},
+			},
+		},
+	}
+	live := (&unstructured.Unstructured{Object: oldInputs}).DeepCopy()
+	require.NoError(t, unstructured.SetNestedMap(live.Object, object{"matchLabels": object{"app": "nginx"}},
+		"spec", "selector"))
+	olds := checkpointObject(&unstructured.Unstructured{Object: oldInputs}, live,
+		resource.NewPropertyMapFromMap(oldInputs), "extensions/v1beta1")
+
+	diff := func(news *unstructured.Unstructured) *pulumirpc.DiffResponse {
+		oldsStruct, err := plugin.MarshalProperties(olds, plugin.MarshalOptions{})
+		require.NoError(t, err)
+		newsStruct, err := plugin.MarshalProperties(resource.NewPropertyMapFromMap(news.Object),
+			plugin.MarshalOptions{})
+		require.NoError(t, err)
+		urn := resource.NewURN("test", "test", "", tokens.Type("kubernetes:extensions/v1beta1:Deployment"), "nginx")
+		resp, err := k.Diff(context.Background(), &pulumirpc.DiffRequest{
+			Urn: string(urn), Olds: oldsStruct, News: newsStruct,
+		})
+		require.NoError(t, err)
+		return resp
+	}
+
+	// The migration to apps/v1 sets the selector that the API server defaulted, so the Deployment is updated in place.
+	migrated, err := kinds.MigrateAPIVersion(&unstructured.Unstructured{Object: oldInputs},
+		cluster.ServerVersion{Major: 1, Minor: 16})
+	require.NoError(t, err)
+	require.NotNil(t, migrated)
+	resp := diff(migrated)
+	assert.Equal(t, pulumirpc.DiffResponse_DIFF_SOME, resp.Changes)
+	assert.Empty(t, resp.Replaces)
+	assert.Contains(t, resp.DetailedDiff, "apiVersion")
+	assert.NotContains(t, resp.DetailedDiff, "spec.selector")
+
+	// A selector that is different from the one that the API server defaulted still replaces the Deployment.
+	require.NoError(t, unstructured.SetNestedMap(migrated.Object, object{"matchLabels": object{"app": "web"}},
+		"spec", "selector"))
+	resp = diff(migrated)
+	assert.Equal(t, []string{"spec.selector.matchLabels.app"}, resp.Replaces)
+}
@@ -0,0 +1,255 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	clientapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// directCredentialsName is the name of the cluster, user and context of the kubeconfig that is built from direct
+// credentials in the provider config.
+const directCredentialsName = "pulumi"
+
+// directCredentialKeys are the provider config keys that describe a cluster and its credentials directly, rather than
+// through a kubeconfig.
+var directCredentialKeys = []resource.PropertyKey{
+	"server",
+	"certificateAuthorityData",
+	"token",
+	"clientCertificateData",
+	"clientKeyData",
+	"insecureSkipTlsVerify",
+	"proxyUrl",
+}
+
+// secretDirectCredentialKeys are the direct credentials that are always treated as secrets.
+var secretDirectCredentialKeys = []resource.PropertyKey{"token", "clientKeyData"}
+
+// decodeCredentialData decodes certificate or key data from the provider config, which is either PEM-encoded, or
+// base64-encoded PEM as in the `*-data` fields of a kubeconfig.
+func decodeCredentialData(data string) ([]byte, error) {
+	b := []byte(data)
+	if trimmed := strings.TrimSpace(data); !strings.HasPrefix(trimmed, "-----BEGIN") {
+		decoded, err := base64.StdEncoding.DecodeString(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("expected PEM or base64-encoded PEM data: %v", err)
+		}
+		b = decoded
+	}
+	if block, _ := pem.Decode(b); block == nil {
+		return nil, fmt.Errorf("expected PEM or base64-encoded PEM data")
+	}
+	return b, nil
+}
+
+// checkDirectCredentials validates the direct credentials in the provider config. The credentials describe the cluster
+// completely, so they cannot be combined with a kubeconfig, and require the `server` to be set. Values that are not
+// known yet are not validated.
+func checkDirectCredentials(news resource.PropertyMap) []*pulumirpc.CheckFailure {
+	var failures []*pulumirpc.CheckFailure
+	fail := func(key resource.PropertyKey, format string, args ...interface{}) {
+		failures = append(failures, &pulumirpc.CheckFailure{Property: string(key), Reason: fmt.Sprintf(format, args...)})
+	}
+	known := func(key resource.PropertyKey) (string, bool) {
+		v := news[key]
+		if v.IsSecret() {
+			v = v.SecretValue().Element
+		}
+		switch {
+		case v.IsString():
+			return v.StringValue(), true
+		case v.IsBool():
+			return fmt.Sprintf("%v", v.BoolValue()), true
+		default:
+			return "", false
+		}
+	}
+
+	var set []resource.PropertyKey
+	for _, key := range directCredentialKeys {
+		if news[key].HasValue() || news[key].IsComputed() {
+			set = append(set, key)
+		}
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	if !news["server"].HasValue() && !news["server"].IsComputed() {
+		for _, key := range set {
+			fail(key, "%q requires \"server\" to be set", key)
+		}
+		return failures
+	}
+	for _, key := range []resource.PropertyKey{"kubeconfig", "context", "cluster"} {
+		if news[key].HasValue() || news[key].IsComputed() {
+			fail(key, "%q arg is not compatible with \"server\" arg", key)
+		}
+	}
+
+	if server, ok := known("server"); ok {
+		if u, err := url.Parse(server); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			fail("server", "server must be an http or https URL, got %q", server)
+		}
+	}
+	if data, ok := known("certificateAuthorityData"); ok {
+		if b, err := decodeCredentialData(data); err != nil {
+			fail("certificateAuthorityData", "invalid certificateAuthorityData: %v", err)
+		} else if !x509.NewCertPool().AppendCertsFromPEM(b) {
+			fail("certificateAuthorityData", "certificateAuthorityData does not contain a valid certificate")
+		}
+	}
+	insecure, ok := known("insecureSkipTlsVerify")
+	if ok && insecure == "true" && news["certificateAuthorityData"].HasValue() {
+		fail("insecureSkipTlsVerify", "insecureSkipTlsVerify cannot be combined with certificateAuthorityData")
+	}
+
+	cert, certOk := known("clientCertificateData")
+	key, keyOk := known("clientKeyData")
+	switch {
+	case news["clientCertificateData"].HasValue() && !news["clientKeyData"].HasValue() &&
+		!news["clientKeyData"].IsComputed():
+		fail("clientCertificateData", "clientCertificateData requires clientKeyData to be set")
+	case news["clientKeyData"].HasValue() && !news["clientCertificateData"].HasValue() &&
+		!news["clientCertificateData"].IsComputed():
+		fail("clientKeyData", "clientKeyData requires clientCertificateData to be set")
+	case certOk && keyOk:
+		certData, err := decodeCredentialData(cert)
+		if err != nil {
+			fail("clientCertificateData", "invalid clientCertificateData: %v", err)
+			break
+		}
+		keyData, err := decodeCredentialData(key)
+		if err != nil {
+			// The key is secret, so the error does not include it.
+			fail("clientKeyData", "invalid clientKeyData")
+			break
+		}
+		if _, err = tls.X509KeyPair(certData, keyData); err != nil {
+			fail("clientKeyData", "clientCertificateData and clientKeyData are not a valid key pair: %v", err)
+		}
+	}
+
+	if proxy, ok := known("proxyUrl"); ok {
+		u, err := url.Parse(proxy)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") || u.Host == "" {
+			fail("proxyUrl", "proxyUrl must be an http, https or socks5 URL, got %q", proxy)
+		}
+	}
+	return failures
+}
+
+// markDirectCredentialSecrets marks the secret direct credentials in the provider config as secrets, and returns
+// whether any value was changed.
+func markDirectCredentialSecrets(news resource.PropertyMap) bool {
+	changed := false
+	for _, key := range secretDirectCredentialKeys {
+		if v, ok := news[key]; ok && !v.IsNull() && !v.IsSecret() {
+			news[key] = resource.MakeSecret(v)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// directCredentialsCluster returns the cluster described by the direct credentials in the provider config, or nil if
+// the config does not set a `server`.
+func directCredentialsCluster(props resource.PropertyMap) *clientapi.Cluster {
+	value := func(key resource.PropertyKey) resource.PropertyValue {
+		v := props[key]
+		if v.IsSecret() {
+			return v.SecretValue().Element
+		}
+		return v
+	}
+	if !value("server").IsString() {
+		return nil
+	}
+	cluster := &clientapi.Cluster{Server: value("server").StringValue()}
+	if v := value("certificateAuthorityData"); v.IsString() {
+		// The CA is decoded so that the same certificate in either encoding identifies the same cluster.
+		ca, err := decodeCredentialData(v.StringValue())
+		if err != nil {
+			ca = []byte(v.StringValue())
+		}
+		cluster.CertificateAuthorityData = ca
+	}
+	switch v := value("insecureSkipTlsVerify"); {
+	case v.IsBool():
+		cluster.InsecureSkipTLSVerify = v.BoolValue()
+	case v.IsString():
+		cluster.InsecureSkipTLSVerify = v.StringValue() == "true"
+	}
+	if v := value("proxyUrl"); v.IsString() {
+		cluster.ProxyURL = v.StringValue()
+	}
+	return cluster
+}
+
+// directCredentialsKubeconfig builds a kubeconfig from the direct credentials in the provider config variables.
+func directCredentialsKubeconfig(vars map[string]string) (*clientapi.Config, error) {
+	variable := func(key string) string {
+		return vars["kubernetes:config:"+key]
+	}
+
+	cluster := clientapi.NewCluster()
+	cluster.Server = variable("server")
+	cluster.InsecureSkipTLSVerify = variable("insecureSkipTlsVerify") == "true"
+	cluster.ProxyURL = variable("proxyUrl")
+	if data := variable("certificateAuthorityData"); data != "" {
+		ca, err := decodeCredentialData(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificateAuthorityData: %v", err)
+		}
+		cluster.CertificateAuthorityData = ca
+	}
+
+	user := clientapi.NewAuthInfo()
+	user.Token = variable("token")
+	if data := variable("clientCertificateData"); data != "" {
+		cert, err := decodeCredentialData(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid clientCertificateData: %v", err)
+		}
+		user.ClientCertificateData = cert
+	}
+	if data := variable("clientKeyData"); data != "" {
+		key, err := decodeCredentialData(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid clientKeyData")
+		}
+		user.ClientKeyData = key
+	}
+
+	context := clientapi.NewContext()
+	context.Cluster = directCredentialsName
+	context.AuthInfo = directCredentialsName
+
+	config := clientapi.NewConfig()
+	config.Clusters[directCredentialsName] = cluster
+	config.AuthInfos[directCredentialsName] = user
+	config.Contexts[directCredentialsName] = context
+	config.CurrentContext = directCredentialsName
+	return config, nil
+}
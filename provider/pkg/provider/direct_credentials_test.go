@@ -0,0 +1,159 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCertificate returns a PEM-encoded self-signed certificate and its key.
+func testCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+}
+
+func TestCheckDirectCredentials(t *testing.T) {
+	cert, key := testCertificate(t)
+	otherCert, _ := testCertificate(t)
+
+	failedProperties := func(config map[string]interface{}) []string {
+		props := resource.NewPropertyMapFromMap(config)
+		var properties []string
+		for _, failure := range checkDirectCredentials(props) {
+			properties = append(properties, failure.Property)
+		}
+		sort.Strings(properties)
+		return properties
+	}
+
+	assert.Empty(t, failedProperties(map[string]interface{}{"kubeconfig": "~/.kube/config"}))
+	assert.Empty(t, failedProperties(map[string]interface{}{
+		"server":                   "https://1.2.3.4:6443",
+		"certificateAuthorityData": base64.StdEncoding.EncodeToString([]byte(cert)),
+		"token":                    "token",
+		"clientCertificateData":    cert,
+		"clientKeyData":            base64.StdEncoding.EncodeToString([]byte(key)),
+		"proxyUrl":                 "socks5://localhost:1080",
+	}))
+	assert.Empty(t, failedProperties(map[string]interface{}{
+		"server":                "https://1.2.3.4:6443",
+		"insecureSkipTlsVerify": true,
+	}))
+	// Values that are not known yet are not validated.
+	props := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"clientCertificateData": cert,
+	})
+	props["server"] = resource.MakeComputed(resource.NewStringProperty(""))
+	props["clientKeyData"] = resource.MakeComputed(resource.NewStringProperty(""))
+	assert.Empty(t, checkDirectCredentials(props))
+
+	assert.Equal(t, []string{"token"}, failedProperties(map[string]interface{}{"token": "token"}))
+	assert.Equal(t, []string{"context", "kubeconfig"}, failedProperties(map[string]interface{}{
+		"server": "https://1.2.3.4:6443", "kubeconfig": "~/.kube/config", "context": "prod",
+	}))
+	assert.Equal(t, []string{"server"}, failedProperties(map[string]interface{}{"server": "1.2.3.4:6443"}))
+	assert.Equal(t, []string{"certificateAuthorityData"}, failedProperties(map[string]interface{}{
+		"server": "https://1.2.3.4:6443", "certificateAuthorityData": "bm90IGEgY2VydGlmaWNhdGU=",
+	}))
+	assert.Equal(t, []string{"insecureSkipTlsVerify"}, failedProperties(map[string]interface{}{
+		"server": "https://1.2.3.4:6443", "certificateAuthorityData": cert, "insecureSkipTlsVerify": true,
+	}))
+	assert.Equal(t, []string{"clientCertificateData"}, failedProperties(map[string]interface{}{
+		"server": "https://1.2.3.4:6443", "clientCertificateData": cert,
+	}))
+	assert.Equal(t, []string{"clientKeyData"}, failedProperties(map[string]interface{}{
+		"server": "https://1.2.3.4:6443", "clientCertificateData": otherCert, "clientKeyData": key,
+	}))
+	assert.Equal(t, []string{"proxyUrl"}, failedProperties(map[string]interface{}{
+		"server": "https://1.2.3.4:6443", "proxyUrl": "ftp://proxy",
+	}))
+}
+
+func TestMarkDirectCredentialSecrets(t *testing.T) {
+	props := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"server": "https://1.2.3.4:6443",
+		"token":  "token",
+	})
+	assert.True(t, markDirectCredentialSecrets(props))
+	assert.True(t, props["token"].IsSecret())
+	assert.False(t, props["server"].IsSecret())
+	assert.False(t, markDirectCredentialSecrets(props))
+}
+
+func TestDirectCredentialsKubeconfig(t *testing.T) {
+	cert, key := testCertificate(t)
+	config, err := directCredentialsKubeconfig(map[string]string{
+		"kubernetes:config:server":                   "https://1.2.3.4:6443",
+		"kubernetes:config:certificateAuthorityData": base64.StdEncoding.EncodeToString([]byte(cert)),
+		"kubernetes:config:token":                    "token",
+		"kubernetes:config:clientCertificateData":    cert,
+		"kubernetes:config:clientKeyData":            key,
+		"kubernetes:config:proxyUrl":                 "http://proxy:3128",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, directCredentialsName, config.CurrentContext)
+	cluster := config.Clusters[directCredentialsName]
+	assert.Equal(t, "https://1.2.3.4:6443", cluster.Server)
+	assert.Equal(t, cert, string(cluster.CertificateAuthorityData))
+	assert.Equal(t, "http://proxy:3128", cluster.ProxyURL)
+	user := config.AuthInfos[directCredentialsName]
+	assert.Equal(t, "token", user.Token)
+	assert.Equal(t, cert, string(user.ClientCertificateData))
+	assert.Equal(t, key, string(user.ClientKeyData))
+
+	_, err = directCredentialsKubeconfig(map[string]string{
+		"kubernetes:config:server":        "https://1.2.3.4:6443",
+		"kubernetes:config:clientKeyData": "not a key",
+	})
+	assert.Error(t, err)
+}
+
+func TestGetActiveClusterFromDirectCredentials(t *testing.T) {
+	cluster := getActiveClusterFromConfig(nil, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"server":                "https://1.2.3.4:6443",
+		"insecureSkipTlsVerify": "true",
+		"token":                 "token",
+	}))
+	assert.Equal(t, "https://1.2.3.4:6443", cluster.Server)
+	assert.True(t, cluster.InsecureSkipTLSVerify)
+}
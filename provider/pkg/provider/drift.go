@@ -0,0 +1,256 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+// driftedField is a field in the inputs of a resource whose live value was changed by another field manager.
+type driftedField struct {
+	path    []interface{}
+	manager string
+	diff    *pulumirpc.PropertyDiff
+}
+
+// defaultDriftIgnoredFieldManagers are the field managers whose changes are not considered drift if the
+// `driftIgnoredFieldManagers` config is not set. These are the Kubernetes controllers, which own the fields they update,
+// such as the HorizontalPodAutoscaler managing `spec.replicas`.
+var defaultDriftIgnoredFieldManagers = []string{"kube-controller-manager", "kube-scheduler", "kubelet"}
+
+// providerFieldManager returns the name of the field manager of the changes made by the provider. The provider does
+// not set a field manager, so the API server derives it from the user agent of the client.
+func providerFieldManager() string {
+	return strings.SplitN(rest.DefaultKubernetesUserAgent(), "/", 2)[0]
+}
+
+// isOutOfBandManager returns true if changes made by the given field manager are considered drift. These are edits by
+// any manager other than the provider and the configured field managers, e.g., `kubectl edit`, `kubectl apply`, or
+// another deployment tool.
+func isOutOfBandManager(entry metav1.ManagedFieldsEntry, inBandManagers map[string]bool) bool {
+	return !inBandManagers[entry.Manager]
+}
+
+// mergeSecretStringData moves the values of the `stringData` of normalized Secret inputs into its `data`, since the
+// API server stores them there and never returns `stringData`.
+func mergeSecretStringData(obj map[string]interface{}) {
+	stringData, ok := obj["stringData"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	data, _ := obj["data"].(map[string]interface{})
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	for k, v := range stringData {
+		if value, ok := v.(string); ok {
+			data[k] = base64.StdEncoding.EncodeToString([]byte(value))
+		}
+	}
+	obj["data"] = data
+	delete(obj, "stringData")
+}
+
+// detectDrift compares the inputs of a resource to the live object, and returns the fields whose live values were
+// changed out of band. A field is considered to have drifted if its live value differs from the input value, and it
+// is owned by a field manager that is not in the given in-band managers, or it was removed from the live object.
+func detectDrift(inputs, live *unstructured.Unstructured, inBandManagers map[string]bool) ([]driftedField, error) {
+	owners, err := fieldOwners(live)
+	if err != nil {
+		return nil, err
+	}
+
+	// Normalize both objects through JSON so that numbers compare equal regardless of their Go types.
+	normalize := func(obj *unstructured.Unstructured) (interface{}, error) {
+		b, err := obj.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		var normalized interface{}
+		err = json.Unmarshal(b, &normalized)
+		return normalized, err
+	}
+	inputsObj, err := normalize(inputs)
+	if err != nil {
+		return nil, err
+	}
+	if inputs.GetAPIVersion() == "v1" && inputs.GetKind() == "Secret" {
+		mergeSecretStringData(inputsObj.(map[string]interface{}))
+	}
+	liveObj, err := normalize(live)
+	if err != nil {
+		return nil, err
+	}
+
+	var drifted []driftedField
+	var visit func(path []interface{}, input, live interface{}, liveExists bool)
+	visit = func(path []interface{}, input, live interface{}, liveExists bool) {
+		switch input := input.(type) {
+		case map[string]interface{}:
+			liveMap, _ := live.(map[string]interface{})
+			for k, v := range input {
+				liveValue, ok := liveMap[k]
+				visit(append(path[:len(path):len(path)], k), v, liveValue, ok)
+			}
+			return
+		case []interface{}:
+			liveList, _ := live.([]interface{})
+			for i, v := range input {
+				var liveValue interface{}
+				if i < len(liveList) {
+					liveValue = liveList[i]
+				}
+				visit(append(path[:len(path):len(path)], i), v, liveValue, i < len(liveList))
+			}
+			return
+		case nil:
+			return
+		}
+
+		if liveExists && reflect.DeepEqual(input, live) {
+			return
+		}
+
+		pathStr := formatPropertyPath(path)
+		owner, owned := owners[pathStr]
+		switch {
+		case !liveExists && !owned:
+			drifted = append(drifted, driftedField{
+				path: path,
+				diff: &pulumirpc.PropertyDiff{Kind: pulumirpc.PropertyDiff_ADD},
+			})
+		case owned && isOutOfBandManager(owner, inBandManagers):
+			drifted = append(drifted, driftedField{
+				path:    path,
+				manager: owner.Manager,
+				diff:    &pulumirpc.PropertyDiff{Kind: pulumirpc.PropertyDiff_UPDATE},
+			})
+		}
+	}
+	visit(nil, inputsObj, liveObj, true)
+
+	sort.Slice(drifted, func(i, j int) bool {
+		return formatPropertyPath(drifted[i].path) < formatPropertyPath(drifted[j].path)
+	})
+	return drifted, nil
+}
+
+// fieldOwners returns the field manager that owns each field of the live object, keyed by the property path of the
+// field. Fields in associative lists are resolved to the index of the list element in the live object.
+func fieldOwners(live *unstructured.Unstructured) (map[string]metav1.ManagedFieldsEntry, error) {
+	owners := map[string]metav1.ManagedFieldsEntry{}
+	for _, entry := range live.GetManagedFields() {
+		if entry.FieldsV1 == nil {
+			continue
+		}
+		set := &fieldpath.Set{}
+		if err := set.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return nil, err
+		}
+		set.Leaves().Iterate(func(p fieldpath.Path) {
+			if path, ok := resolveFieldPath(live.Object, p); ok {
+				owners[formatPropertyPath(path)] = entry
+			}
+		})
+	}
+	return owners, nil
+}
+
+// resolveFieldPath resolves a managed fields path against an object, and returns the equivalent property path.
+func resolveFieldPath(obj interface{}, p fieldpath.Path) ([]interface{}, bool) {
+	var path []interface{}
+	curr := obj
+	for _, element := range p {
+		switch {
+		case element.FieldName != nil:
+			m, ok := curr.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			curr = m[*element.FieldName]
+			path = append(path, *element.FieldName)
+		case element.Index != nil:
+			l, ok := curr.([]interface{})
+			if !ok || *element.Index >= len(l) {
+				return nil, false
+			}
+			curr = l[*element.Index]
+			path = append(path, *element.Index)
+		case element.Key != nil || element.Value != nil:
+			l, ok := curr.([]interface{})
+			if !ok {
+				return nil, false
+			}
+			index := -1
+			for i, item := range l {
+				if element.Value != nil && reflect.DeepEqual(normalizeValue((*element.Value).Unstructured()),
+					normalizeValue(item)) {
+					index = i
+					break
+				}
+				if element.Key != nil {
+					m, ok := item.(map[string]interface{})
+					if !ok {
+						continue
+					}
+					matches := true
+					for _, field := range *element.Key {
+						if !reflect.DeepEqual(normalizeValue(field.Value.Unstructured()), normalizeValue(m[field.Name])) {
+							matches = false
+							break
+						}
+					}
+					if matches {
+						index = i
+						break
+					}
+				}
+			}
+			if index < 0 {
+				return nil, false
+			}
+			curr = l[index]
+			path = append(path, index)
+		default:
+			return nil, false
+		}
+	}
+	return path, true
+}
+
+// normalizeValue converts numbers to float64 so that values decoded by different libraries compare equal.
+func normalizeValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case int:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"testing"
+
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func driftTestDeployment(replicas int64, image string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":      "nginx",
+			"namespace": "default",
+			"labels":    map[string]interface{}{"app": "nginx"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "sidecar", "image": "envoy"},
+						map[string]interface{}{"name": "nginx", "image": image},
+					},
+				},
+			},
+		},
+	}}
+}
+
+func TestDetectDrift(t *testing.T) {
+	live := driftTestDeployment(5, "nginx:1.20")
+	live.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{
+			Manager:   "pulumi-resource-kubernetes",
+			Operation: metav1.ManagedFieldsOperationUpdate,
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{".":{},"f:app":{}}},` +
+				`"f:spec":{"f:template":{"f:spec":{"f:containers":{` +
+				`"k:{\"name\":\"sidecar\"}":{".":{},"f:image":{},"f:name":{}},` +
+				`"k:{\"name\":\"nginx\"}":{".":{},"f:name":{}}}}}}}`)},
+		},
+		{
+			Manager:   "kube-controller-manager",
+			Operation: metav1.ManagedFieldsOperationUpdate,
+			FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:replicas":{}}}`)},
+		},
+		{
+			Manager:   "kubectl-edit",
+			Operation: metav1.ManagedFieldsOperationUpdate,
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(
+				`{"f:spec":{"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"nginx\"}":{"f:image":{}}}}}}}`)},
+		},
+	})
+
+	inputs := driftTestDeployment(2, "nginx:1.19")
+	inputs.SetAnnotations(map[string]string{"example.com/removed": "true"})
+
+	inBandManagers := map[string]bool{"pulumi-resource-kubernetes": true, "kube-controller-manager": true}
+	drifted, err := detectDrift(inputs, live, inBandManagers)
+	require.NoError(t, err)
+
+	require.Len(t, drifted, 2)
+	assert.Equal(t, `metadata.annotations["example.com/removed"]`, formatPropertyPath(drifted[0].path))
+	assert.Equal(t, pulumirpc.PropertyDiff_ADD, drifted[0].diff.Kind)
+	assert.Equal(t, "", drifted[0].manager)
+
+	// The replicas owned by the controller are not drift, but the image changed by `kubectl edit` is.
+	assert.Equal(t, "spec.template.spec.containers[1].image", formatPropertyPath(drifted[1].path))
+	assert.Equal(t, pulumirpc.PropertyDiff_UPDATE, drifted[1].diff.Kind)
+	assert.Equal(t, "kubectl-edit", drifted[1].manager)
+
+	// There is no drift if the live object matches the inputs.
+	drifted, err = detectDrift(driftTestDeployment(5, "nginx:1.20"), live, inBandManagers)
+	require.NoError(t, err)
+	assert.Empty(t, drifted)
+
+	// Changes by a manager that is not configured are drift.
+	drifted, err = detectDrift(driftTestDeployment(2, "nginx:1.20"), live,
+		map[string]bool{"pulumi-resource-kubernetes": true})
+	require.NoError(t, err)
+	require.Len(t, drifted, 1)
+	assert.Equal(t, "spec.replicas", formatPropertyPath(drifted[0].path))
+	assert.Equal(t, "kube-controller-manager", drifted[0].manager)
+}
+
+func TestDetectDriftSecretStringData(t *testing.T) {
+	live := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "credentials", "namespace": "default"},
+		"data":       map[string]interface{}{"password": "aHVudGVyMg=="},
+	}}
+	live.SetManagedFields([]metav1.ManagedFieldsEntry{{
+		Manager:   "pulumi-resource-kubernetes",
+		Operation: metav1.ManagedFieldsOperationUpdate,
+		FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:data":{".":{},"f:password":{}}}`)},
+	}})
+	inBandManagers := map[string]bool{"pulumi-resource-kubernetes": true}
+
+	// The API server stores `stringData` in `data`, so it is not reported as removed.
+	inputs := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "credentials", "namespace": "default"},
+		"stringData": map[string]interface{}{"password": "hunter2"},
+	}}
+	drifted, err := detectDrift(inputs, live, inBandManagers)
+	require.NoError(t, err)
+	assert.Empty(t, drifted)
+
+	// A key that was removed from the live object is drift.
+	inputs.Object["stringData"] = map[string]interface{}{"password": "hunter2", "username": "admin"}
+	drifted, err = detectDrift(inputs, live, inBandManagers)
+	require.NoError(t, err)
+	require.Len(t, drifted, 1)
+	assert.Equal(t, "data.username", formatPropertyPath(drifted[0].path))
+	assert.Equal(t, pulumirpc.PropertyDiff_ADD, drifted[0].diff.Kind)
+}
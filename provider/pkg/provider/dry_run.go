@@ -0,0 +1,291 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/clients"
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/openapi"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	logger "github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// admissionDeniedRegex matches the message of an error returned by the API server when an admission webhook rejects a
+// request, e.g., `admission webhook "validate.example.com" denied the request: replicas must be odd`.
+var admissionDeniedRegex = regexp.MustCompile(`admission webhook "([^"]+)" denied the request(?::\s*(.*))?`)
+
+// admissionDenial returns the name of the admission webhook that denied a request and its message, if the given error
+// is an admission webhook denial.
+func admissionDenial(err error) (webhook, message string, denied bool) {
+	se, isStatusError := err.(*errors.StatusError)
+	if !isStatusError {
+		return "", "", false
+	}
+	match := admissionDeniedRegex.FindStringSubmatch(se.ErrStatus.Message)
+	if match == nil {
+		return "", "", false
+	}
+	return match[1], match[2], true
+}
+
+// admissionDeniedReason returns the reason to report for an admission webhook denial, with the name of the webhook and
+// its message, if the given error is one.
+func admissionDeniedReason(err error) (string, bool) {
+	webhook, message, denied := admissionDenial(err)
+	if !denied {
+		return "", false
+	}
+	reason := fmt.Sprintf("admission webhook %s denied the request", webhook)
+	if message != "" {
+		reason += ": " + message
+	}
+	return reason, true
+}
+
+// admissionDeniedError returns an error that reports that an admission webhook denied the server-side dry run of the
+// given object, or nil if the given error is not an admission webhook denial.
+func admissionDeniedError(obj *unstructured.Unstructured, err error) error {
+	reason, denied := admissionDeniedReason(err)
+	if !denied {
+		return nil
+	}
+	return fmt.Errorf("update of resource %s would fail because %s", fqObjName(obj), reason)
+}
+
+// dryRunResult is the result of the server-side dry run of a resource, along with the inputs that were submitted.
+type dryRunResult struct {
+	inputs resource.PropertyMap
+	// live is the live object that an update was dry run against, or nil if the dry run was a create.
+	live *unstructured.Unstructured
+	// object is the object returned by the dry run.
+	object *unstructured.Unstructured
+}
+
+// checkDryRun submits the checked inputs of a resource to the API server in a server-side dry run, as a create if
+// there are no old inputs, or an update otherwise, and returns a CheckFailure if an admission webhook denies them.
+// Other errors are left for Diff, Create and Update to report. The result is kept, so that Diff and the preview of
+// the create or update do not run the dry run again.
+func (k *kubeProvider) checkDryRun(urn resource.URN, gvk schema.GroupVersionKind, oldInputs,
+	newInputs *unstructured.Unstructured, checkedInputs resource.PropertyMap,
+) *pulumirpc.CheckFailure {
+	if k.yamlRenderMode || hasComputedValue(newInputs) || hasComputedValue(oldInputs) || !k.supportsDryRun(gvk) {
+		return nil
+	}
+
+	// The inputs are submitted the same way as in Diff.
+	oldInputs, newInputs = oldInputs.DeepCopy(), newInputs.DeepCopy()
+	namespacedKind, err := k.isNamespacedKind(gvk)
+	if err != nil && !clients.IsNoNamespaceInfoErr(err) {
+		logger.V(3).Infof("skipping dry run of %s: %v", urn, err)
+		return nil
+	}
+	if err != nil || namespacedKind {
+		oldInputs.SetNamespace(canonicalNamespace(oldInputs.GetNamespace()))
+		newInputs.SetNamespace(canonicalNamespace(newInputs.GetNamespace()))
+	} else {
+		oldInputs.SetNamespace("")
+		newInputs.SetNamespace("")
+	}
+
+	var live, obj *unstructured.Unstructured
+	if len(oldInputs.Object) == 0 {
+		obj, err = k.dryRunCreate(newInputs)
+	} else {
+		if oldInputs.GroupVersionKind() != gvk {
+			return nil
+		}
+		var ignoreFields []openapi.FieldPath
+		if ignoreFields, err = k.ignoredFields(gvk, newInputs); err != nil {
+			return nil
+		}
+		live, obj, err = k.dryRunUpdate(openapi.WithoutFields(oldInputs, ignoreFields),
+			openapi.WithoutFields(newInputs, ignoreFields), ignoreFields)
+	}
+	if err != nil {
+		reason, denied := admissionDeniedReason(err)
+		if !denied {
+			logger.V(3).Infof("skipping dry run of %s: %v", urn, err)
+			return nil
+		}
+		return &pulumirpc.CheckFailure{Reason: reason}
+	}
+
+	k.storeDryRunResult(urn, checkedInputs, live, obj)
+	return nil
+}
+
+// dryRunCreate submits the creation of a resource to the API server in a server-side dry run, with the same
+// last-applied-configuration annotation as Create, and returns the object returned by the dry run.
+func (k *kubeProvider) dryRunCreate(newInputs *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	annotatedInputs, err := withLastAppliedConfig(newInputs)
+	if err != nil {
+		return nil, err
+	}
+	client, err := k.clientSet.ResourceClient(newInputs.GroupVersionKind(), newInputs.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	return client.Create(context.TODO(), annotatedInputs, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+}
+
+// storeDryRunResult remembers the result of the server-side dry run of the given inputs of a resource, so that Diff and
+// the preview of its create or update can use it rather than running the dry run again.
+func (k *kubeProvider) storeDryRunResult(urn resource.URN, inputs resource.PropertyMap, live,
+	obj *unstructured.Unstructured,
+) {
+	k.dryRunResultsMutex.Lock()
+	defer k.dryRunResultsMutex.Unlock()
+
+	if k.dryRunResults == nil {
+		k.dryRunResults = map[resource.URN]dryRunResult{}
+	}
+	k.dryRunResults[urn] = dryRunResult{inputs: inputs, live: live, object: obj}
+}
+
+// checkedDryRun returns the result of the server-side dry run of the update of a resource, if the dry run was for the
+// given inputs. The result is kept for the preview of the update.
+func (k *kubeProvider) checkedDryRun(urn resource.URN, inputs resource.PropertyMap) *dryRunResult {
+	k.dryRunResultsMutex.Lock()
+	defer k.dryRunResultsMutex.Unlock()
+
+	result, ok := k.dryRunResults[urn]
+	if !ok || result.live == nil || !sameInputs(result.inputs, inputs) {
+		return nil
+	}
+	return &result
+}
+
+// takeDryRunResult returns the object returned by the server-side dry run of a resource, if the dry run was for the
+// given inputs, and forgets it.
+func (k *kubeProvider) takeDryRunResult(urn resource.URN, inputs resource.PropertyMap) *unstructured.Unstructured {
+	k.dryRunResultsMutex.Lock()
+	defer k.dryRunResultsMutex.Unlock()
+
+	result, ok := k.dryRunResults[urn]
+	delete(k.dryRunResults, urn)
+	if !ok || !sameInputs(result.inputs, inputs) {
+		return nil
+	}
+	return result.object
+}
+
+// sameInputs returns true if the given inputs have the same values. Whether values are secret is not compared, since
+// the inputs returned by Check only keep secrets if they are enabled.
+func sameInputs(a, b resource.PropertyMap) bool {
+	return reflect.DeepEqual(propMapToUnstructured(a).Object, propMapToUnstructured(b).Object)
+}
+
+// serverSideDiffIgnored are the paths of fields that are changed by every write to the API server, and are therefore
+// left out of the output changes reported for a dry run.
+var serverSideDiffIgnored = map[string]bool{
+	"metadata.generation":                                  true,
+	"metadata.managedFields":                               true,
+	"metadata.resourceVersion":                             true,
+	`metadata.annotations["` + lastAppliedConfigKey + `"]`: true,
+}
+
+// addServerSideDiff adds the fields of a server-side dry-run patch that are not set in the inputs to the detailed diff.
+// These are the values that the API server or admission webhooks would default or mutate, and are recorded as output
+// changes so that they are shown in the preview. Fields that are already in the diff are not modified.
+func addServerSideDiff(
+	detailedDiff map[string]*pulumirpc.PropertyDiff, patch, live, newInputs map[string]interface{},
+) {
+	// Merge patches replace lists in full, so the elements of a patched list are complete values rather than patches,
+	// and any field that they do not contain has been removed.
+	var visit func(path []interface{}, v, old, input interface{}, inputExists, complete bool)
+	visit = func(path []interface{}, v, old, input interface{}, inputExists, complete bool) {
+		pathStr := formatPropertyPath(path)
+		if serverSideDiffIgnored[pathStr] {
+			return
+		}
+		if _, exists := detailedDiff[pathStr]; exists {
+			return
+		}
+
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if oldMap, ok := old.(map[string]interface{}); ok {
+				inputMap, _ := input.(map[string]interface{})
+				for key, value := range v {
+					inputValue, ok := inputMap[key]
+					visit(append(path[:len(path):len(path)], key), value, oldMap[key], inputValue, ok, complete)
+				}
+				if complete {
+					for key, oldValue := range oldMap {
+						if _, ok := v[key]; !ok {
+							inputValue, ok := inputMap[key]
+							visit(append(path[:len(path):len(path)], key), nil, oldValue, inputValue, ok, complete)
+						}
+					}
+				}
+				return
+			}
+		case []interface{}:
+			if oldList, ok := old.([]interface{}); ok {
+				inputList, _ := input.([]interface{})
+				for i := 0; i < len(v) || i < len(oldList); i++ {
+					var value, oldValue, inputValue interface{}
+					if i < len(v) {
+						value = v[i]
+					}
+					if i < len(oldList) {
+						oldValue = oldList[i]
+					}
+					if i < len(inputList) {
+						inputValue = inputList[i]
+					}
+					if value != nil || oldValue != nil {
+						visit(append(path[:len(path):len(path)], i), value, oldValue, inputValue, i < len(inputList), true)
+					}
+				}
+				return
+			}
+		}
+
+		// Values that are set in the inputs are reported by the input diff.
+		if inputExists {
+			return
+		}
+
+		var kind pulumirpc.PropertyDiff_Kind
+		switch {
+		case v == nil && old == nil:
+			return
+		case v == nil:
+			kind = pulumirpc.PropertyDiff_DELETE
+		case old == nil:
+			kind = pulumirpc.PropertyDiff_ADD
+		case reflect.DeepEqual(v, old) || equalNumbers(v, old):
+			return
+		default:
+			kind = pulumirpc.PropertyDiff_UPDATE
+		}
+		detailedDiff[pathStr] = &pulumirpc.PropertyDiff{Kind: kind, InputDiff: false}
+	}
+
+	for key, value := range patch {
+		inputValue, ok := newInputs[key]
+		visit([]interface{}{key}, value, live[key], inputValue, ok, false)
+	}
+}
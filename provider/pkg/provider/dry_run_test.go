@@ -0,0 +1,147 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestAdmissionDenial(t *testing.T) {
+	gr := schema.GroupResource{Group: "apps", Resource: "deployments"}
+
+	webhook, message, denied := admissionDenial(errors.NewForbidden(gr, "nginx", fmt.Errorf(
+		`admission webhook "validate.example.com" denied the request: replicas must be odd`)))
+	assert.True(t, denied)
+	assert.Equal(t, "validate.example.com", webhook)
+	assert.Equal(t, "replicas must be odd", message)
+
+	webhook, message, denied = admissionDenial(errors.NewBadRequest(
+		`admission webhook "policy.example.com" denied the request`))
+	assert.True(t, denied)
+	assert.Equal(t, "policy.example.com", webhook)
+	assert.Equal(t, "", message)
+
+	_, _, denied = admissionDenial(errors.NewNotFound(gr, "nginx"))
+	assert.False(t, denied)
+	_, _, denied = admissionDenial(nil)
+	assert.False(t, denied)
+}
+
+func TestDryRunResults(t *testing.T) {
+	k := &kubeProvider{}
+	urn := resource.URN("urn:pulumi:dev::test::kubernetes:apps/v1:Deployment::nginx")
+	inputs := resource.NewPropertyMapFromMap(map[string]interface{}{"spec": map[string]interface{}{"replicas": 1}})
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"replicas": 1}}}
+
+	live := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"replicas": 3}}}
+
+	// The result is only returned for the inputs of the dry run. Diff can use it any number of times, but the preview
+	// of the update only once.
+	k.storeDryRunResult(urn, inputs, live, obj)
+	checked := k.checkedDryRun(urn, inputs)
+	if assert.NotNil(t, checked) {
+		assert.Same(t, live, checked.live)
+		assert.Same(t, obj, checked.object)
+	}
+	assert.NotNil(t, k.checkedDryRun(urn, inputs))
+	assert.Same(t, obj, k.takeDryRunResult(urn, inputs))
+	assert.Nil(t, k.takeDryRunResult(urn, inputs))
+	assert.Nil(t, k.checkedDryRun(urn, inputs))
+
+	k.storeDryRunResult(urn, inputs, live, obj)
+	changed := resource.NewPropertyMapFromMap(map[string]interface{}{"spec": map[string]interface{}{"replicas": 2}})
+	assert.Nil(t, k.checkedDryRun(urn, changed))
+	assert.Nil(t, k.takeDryRunResult(urn, changed))
+	assert.Nil(t, k.takeDryRunResult(urn, inputs))
+
+	// Diff does not use the dry run of a create.
+	k.storeDryRunResult(urn, inputs, nil, obj)
+	assert.Nil(t, k.checkedDryRun(urn, inputs))
+	assert.Same(t, obj, k.takeDryRunResult(urn, inputs))
+
+	// Inputs match whether or not their values are secret.
+	secret := resource.PropertyMap{"data": resource.MakeSecret(resource.NewStringProperty("password"))}
+	plain := resource.PropertyMap{"data": resource.NewStringProperty("password")}
+	k.storeDryRunResult(urn, secret, live, obj)
+	assert.NotNil(t, k.checkedDryRun(urn, plain))
+}
+
+func TestAddServerSideDiff(t *testing.T) {
+	live := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":            "nginx",
+			"resourceVersion": "1",
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"containers": []interface{}{
+				map[string]interface{}{
+					"name":                   "nginx",
+					"image":                  "nginx:1.19",
+					"terminationMessagePath": "/dev/termination-log",
+				},
+			},
+		},
+	}
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"resourceVersion": "2",
+			"annotations":     map[string]interface{}{"sidecar.example.com/injected": "true"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(2),
+			"containers": []interface{}{
+				map[string]interface{}{
+					"name":            "nginx",
+					"image":           "nginx:1.20",
+					"imagePullPolicy": "IfNotPresent",
+				},
+				map[string]interface{}{"name": "sidecar", "image": "envoy"},
+			},
+		},
+	}
+	newInputs := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "nginx"},
+		"spec": map[string]interface{}{
+			"replicas": int64(2),
+			"containers": []interface{}{
+				map[string]interface{}{"name": "nginx", "image": "nginx:1.20"},
+			},
+		},
+	}
+
+	detailedDiff := map[string]*pulumirpc.PropertyDiff{
+		"spec.replicas":            {Kind: pulumirpc.PropertyDiff_UPDATE, InputDiff: true},
+		"spec.containers[0].image": {Kind: pulumirpc.PropertyDiff_UPDATE, InputDiff: true},
+	}
+	addServerSideDiff(detailedDiff, patch, live, newInputs)
+
+	assert.Equal(t, map[string]*pulumirpc.PropertyDiff{
+		"spec.replicas":                             {Kind: pulumirpc.PropertyDiff_UPDATE, InputDiff: true},
+		"spec.containers[0].image":                  {Kind: pulumirpc.PropertyDiff_UPDATE, InputDiff: true},
+		`metadata.annotations`:                      {Kind: pulumirpc.PropertyDiff_ADD},
+		"spec.containers[0].imagePullPolicy":        {Kind: pulumirpc.PropertyDiff_ADD},
+		"spec.containers[0].terminationMessagePath": {Kind: pulumirpc.PropertyDiff_DELETE},
+		"spec.containers[1]":                        {Kind: pulumirpc.PropertyDiff_ADD},
+	}, detailedDiff)
+}
@@ -0,0 +1,166 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// eventsOptions are the arguments of an events stream invoke. Events are selected either for the object with the given
+// kind and name, or for all objects of the given kind that match the label selector, e.g., a label that a program
+// derives from the URN of a resource.
+type eventsOptions struct {
+	namespace     string
+	apiVersion    string
+	kind          string
+	name          string
+	labelSelector labels.Selector
+	// eventType is the type of the Events to send, e.g., "Warning", or empty to send Events of all types.
+	eventType string
+}
+
+// parseEventsOptions returns the options of an events stream invoke.
+func parseEventsOptions(args resource.PropertyMap) (*eventsOptions, error) {
+	opts := &eventsOptions{}
+	for name, value := range map[string]*string{
+		"namespace":  &opts.namespace,
+		"apiVersion": &opts.apiVersion,
+		"kind":       &opts.kind,
+		"name":       &opts.name,
+		"type":       &opts.eventType,
+	} {
+		v := args[resource.PropertyKey(name)]
+		if !v.HasValue() {
+			continue
+		}
+		if !v.IsString() {
+			return nil, fmt.Errorf("events argument %q must be a string", name)
+		}
+		*value = v.StringValue()
+	}
+	if opts.kind == "" {
+		return nil, fmt.Errorf("missing required field 'kind' of type string")
+	}
+
+	if v := args["labelSelector"]; v.HasValue() {
+		if !v.IsString() {
+			return nil, fmt.Errorf("events argument \"labelSelector\" must be a string")
+		}
+		selector, err := labels.Parse(v.StringValue())
+		if err != nil {
+			return nil, fmt.Errorf("events argument \"labelSelector\" is invalid: %v", err)
+		}
+		opts.labelSelector = selector
+	}
+	switch {
+	case opts.name == "" && opts.labelSelector == nil:
+		return nil, fmt.Errorf(
+			"could not retrieve events because neither the object name nor a label selector was present")
+	case opts.name != "" && opts.labelSelector != nil:
+		return nil, fmt.Errorf("events arguments \"name\" and \"labelSelector\" cannot both be set")
+	case opts.labelSelector != nil && opts.apiVersion == "":
+		return nil, fmt.Errorf("events argument \"apiVersion\" is required with \"labelSelector\"")
+	}
+	return opts, nil
+}
+
+// fieldSelector returns the field selector for the Events of the involved object(s), in the same form as
+// `getLastWarningsForObject`. The namespace is not part of the selector, because Events are recorded in the namespace
+// of the object they are about, or in the "default" namespace for objects that are not namespaced.
+func (opts *eventsOptions) fieldSelector() string {
+	m := map[string]string{
+		"involvedObject.kind": opts.kind,
+	}
+	if opts.name != "" {
+		m["involvedObject.name"] = opts.name
+	}
+	if opts.apiVersion != "" {
+		m["involvedObject.apiVersion"] = opts.apiVersion
+	}
+	if opts.eventType != "" {
+		m["type"] = opts.eventType
+	}
+	return fields.Set(m).String()
+}
+
+// involvedObjects tracks the UIDs of the objects that match the label selector of an events stream invoke. Objects
+// that are created after the stream starts are found by listing the objects again when an Event refers to an unknown
+// object.
+type involvedObjects struct {
+	cl       dynamic.ResourceInterface
+	selector labels.Selector
+	// uids are the UIDs of the objects that match the selector, and others are the UIDs of objects that were found
+	// not to match it.
+	uids   map[types.UID]bool
+	others map[types.UID]bool
+}
+
+func newInvolvedObjects(cl dynamic.ResourceInterface, selector labels.Selector) *involvedObjects {
+	return &involvedObjects{cl: cl, selector: selector, uids: map[types.UID]bool{}, others: map[types.UID]bool{}}
+}
+
+// matches returns whether the watch event is for an Event of an object that matches the label selector.
+func (o *involvedObjects) matches(ctx context.Context, event map[string]interface{}) (bool, error) {
+	obj, ok := event["object"].(map[string]interface{})
+	if !ok {
+		return false, nil
+	}
+	uid, _, _ := unstructured.NestedString(obj, "involvedObject", "uid")
+	if uid == "" {
+		return false, nil
+	}
+	switch {
+	case o.uids[types.UID(uid)]:
+		return true, nil
+	case o.others[types.UID(uid)]:
+		return false, nil
+	}
+	if err := o.refresh(ctx); err != nil {
+		return false, err
+	}
+	if !o.uids[types.UID(uid)] {
+		o.others[types.UID(uid)] = true
+		return false, nil
+	}
+	return true, nil
+}
+
+// refresh lists the objects that match the label selector again. Objects that have been deleted are kept, so that the
+// Events that are recorded when they are deleted are still sent.
+func (o *involvedObjects) refresh(ctx context.Context) error {
+	opts := metav1.ListOptions{LabelSelector: o.selector.String(), Limit: defaultListPageSize}
+	for {
+		list, err := o.cl.List(ctx, opts)
+		if err != nil {
+			return err
+		}
+		for _, item := range list.Items {
+			o.uids[item.GetUID()] = true
+		}
+		if list.GetContinue() == "" {
+			return nil
+		}
+		opts.Continue = list.GetContinue()
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// uidListClient lists objects with the given UIDs.
+type uidListClient struct {
+	dynamic.ResourceInterface
+	uids  []string
+	lists int
+}
+
+func (c *uidListClient) List(_ context.Context, _ metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	c.lists++
+	list := &unstructured.UnstructuredList{}
+	for _, uid := range c.uids {
+		obj := unstructured.Unstructured{Object: map[string]interface{}{}}
+		obj.SetUID(types.UID(uid))
+		list.Items = append(list.Items, obj)
+	}
+	return list, nil
+}
+
+func TestParseEventsOptions(t *testing.T) {
+	opts, err := parseEventsOptions(resource.NewPropertyMapFromMap(map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"name":       "nginx",
+		"type":       "Warning",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t,
+		"involvedObject.apiVersion=apps/v1,involvedObject.kind=Deployment,involvedObject.name=nginx,type=Warning",
+		opts.fieldSelector())
+
+	opts, err = parseEventsOptions(resource.NewPropertyMapFromMap(map[string]interface{}{
+		"apiVersion":    "v1",
+		"kind":          "Pod",
+		"labelSelector": "app=nginx",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "involvedObject.apiVersion=v1,involvedObject.kind=Pod", opts.fieldSelector())
+	assert.Equal(t, "app=nginx", opts.labelSelector.String())
+
+	for _, args := range []map[string]interface{}{
+		{"name": "nginx"},
+		{"kind": "Pod"},
+		{"kind": "Pod", "name": 1},
+		{"kind": "Pod", "name": "nginx", "labelSelector": "app=nginx"},
+		{"kind": "Pod", "labelSelector": "app=nginx"},
+		{"apiVersion": "v1", "kind": "Pod", "labelSelector": "app in nginx"},
+	} {
+		_, err := parseEventsOptions(resource.NewPropertyMapFromMap(args))
+		assert.Error(t, err, args)
+	}
+}
+
+func TestInvolvedObjects(t *testing.T) {
+	event := func(uid string) map[string]interface{} {
+		return map[string]interface{}{
+			"type": "ADDED",
+			"object": map[string]interface{}{
+				"involvedObject": map[string]interface{}{"uid": uid},
+			},
+		}
+	}
+
+	cl := &uidListClient{uids: []string{"a"}}
+	involved := newInvolvedObjects(cl, labels.Everything())
+	ctx := context.Background()
+
+	matches, err := involved.matches(ctx, event("a"))
+	require.NoError(t, err)
+	assert.True(t, matches)
+	assert.Equal(t, 1, cl.lists)
+
+	// Known objects, and objects that are known not to match, are not listed again.
+	matches, err = involved.matches(ctx, event("b"))
+	require.NoError(t, err)
+	assert.False(t, matches)
+	matches, err = involved.matches(ctx, event("b"))
+	require.NoError(t, err)
+	assert.False(t, matches)
+	matches, err = involved.matches(ctx, event("a"))
+	require.NoError(t, err)
+	assert.True(t, matches)
+	assert.Equal(t, 2, cl.lists)
+
+	// Objects that are created later are found by listing again.
+	cl.uids = append(cl.uids, "c")
+	matches, err = involved.matches(ctx, event("c"))
+	require.NoError(t, err)
+	assert.True(t, matches)
+	assert.Equal(t, 3, cl.lists)
+}
@@ -0,0 +1,289 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/clients"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// execOptions returns the options of a PodExec resource, and the reader for its `stdin` input, if any.
+func execOptions(args resource.PropertyMap) (*corev1.PodExecOptions, io.Reader, error) {
+	opts := &corev1.PodExecOptions{}
+
+	command := args["command"]
+	if !command.HasValue() || !command.IsArray() || len(command.ArrayValue()) == 0 {
+		return nil, nil, fmt.Errorf("missing required field 'command' of type array of strings")
+	}
+	for _, arg := range command.ArrayValue() {
+		if !arg.IsString() {
+			return nil, nil, fmt.Errorf("PodExec input \"command\" must be an array of strings")
+		}
+		opts.Command = append(opts.Command, arg.StringValue())
+	}
+
+	if v := args["container"]; v.HasValue() {
+		if !v.IsString() {
+			return nil, nil, fmt.Errorf("PodExec input \"container\" must be a string")
+		}
+		opts.Container = v.StringValue()
+	}
+
+	var stdin io.Reader
+	if v := args["stdin"]; v.HasValue() {
+		if !v.IsString() {
+			return nil, nil, fmt.Errorf("PodExec input \"stdin\" must be a string")
+		}
+		stdin = strings.NewReader(v.StringValue())
+	}
+	return opts, stdin, nil
+}
+
+// exec runs a command in a container of a Pod for a PodExec resource. If the `waitFor` input is present, the command
+// is not run until the Pod satisfies the condition, e.g., `condition=Ready`. The `timeoutSeconds` input limits how
+// long it waits for the condition and for the command to exit. Returns the ID of the resource, which is the qualified
+// name of the Pod, and the result of the command.
+func (k *kubeProvider) exec(ctx context.Context, inputs resource.PropertyMap) (string, *clients.ExecResult, error) {
+	opts, stdin, err := execOptions(inputs)
+	if err != nil {
+		return "", nil, err
+	}
+
+	podArgs := resource.PropertyMap{
+		"apiVersion": resource.NewStringProperty("v1"),
+		"kind":       resource.NewStringProperty("Pod"),
+		"name":       inputs["pod"],
+	}
+	for _, key := range []resource.PropertyKey{"namespace", "waitFor", "timeoutSeconds"} {
+		if v, ok := inputs[key]; ok {
+			podArgs[key] = v
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if v := inputs["timeoutSeconds"]; v.HasValue() {
+		if !v.IsNumber() || v.NumberValue() <= 0 {
+			return "", nil, fmt.Errorf("field 'timeoutSeconds' must be a positive number")
+		}
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(v.NumberValue()*float64(time.Second)))
+		defer cancel()
+	}
+	go func() {
+		select {
+		case <-k.canceler.context.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	// Read the Pod first, so that a missing Pod is reported clearly rather than as a failed stream.
+	pod, err := k.getObject(ctx, podArgs)
+	if err != nil {
+		return "", nil, err
+	}
+
+	result, err := k.podClient.Exec(ctx, pod.GetNamespace(), pod.GetName(), opts, stdin)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", nil, fmt.Errorf("cancelled running command in Pod %s: %v", fqObjName(pod), ctx.Err())
+		}
+		return "", nil, fmt.Errorf("failed to run command in Pod %s: %v", fqObjName(pod), err)
+	}
+	return fqObjName(pod), result, nil
+}
+
+// podExecResultKeys are the outputs of a PodExec resource that are not inputs.
+var podExecResultKeys = []resource.PropertyKey{"stdout", "stderr", "exitCode"}
+
+// podExecOutputs returns the outputs of a PodExec resource, which are its inputs and the result of its command. If
+// the result is nil, i.e., during previews, the result is unknown.
+func podExecOutputs(inputs resource.PropertyMap, result *clients.ExecResult) resource.PropertyMap {
+	outputs := inputs.Copy()
+	if result == nil {
+		for _, key := range podExecResultKeys {
+			outputs[key] = resource.MakeComputed(resource.NewStringProperty(""))
+		}
+		return outputs
+	}
+	outputs["stdout"] = resource.NewStringProperty(result.Stdout)
+	outputs["stderr"] = resource.NewStringProperty(result.Stderr)
+	outputs["exitCode"] = resource.NewNumberProperty(float64(result.ExitCode))
+	return outputs
+}
+
+// podExecInputs returns the inputs of a PodExec resource from its outputs.
+func podExecInputs(outputs resource.PropertyMap) resource.PropertyMap {
+	inputs := outputs.Copy()
+	for _, key := range podExecResultKeys {
+		delete(inputs, key)
+	}
+	return inputs
+}
+
+// podExecProvider implements the PodExec resource, which runs a command in a container of a Pod when it is created,
+// and again whenever its inputs change. Unlike an invoke, the command is not run during previews, where its result is
+// unknown. Deleting the resource does not undo the effects of the command.
+type podExecProvider struct {
+	k *kubeProvider
+}
+
+var _ customResourceProvider = (*podExecProvider)(nil)
+
+func isPodExec(urn resource.URN) bool {
+	return urn.Type() == "kubernetes:index:PodExec"
+}
+
+func (r *podExecProvider) Check(ctx context.Context, req *pulumirpc.CheckRequest, _ bool) (*pulumirpc.CheckResponse, error) {
+	urn := resource.URN(req.GetUrn())
+	label := fmt.Sprintf("%s.Check(%s)", r.k.label(), urn)
+
+	news, err := plugin.UnmarshalProperties(req.GetNews(), plugin.MarshalOptions{
+		Label: fmt.Sprintf("%s.news", label), KeepUnknowns: true, SkipNulls: true, KeepSecrets: true,
+	})
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "check failed because malformed resource inputs: %+v", err)
+	}
+
+	var failures []*pulumirpc.CheckFailure
+	if v := news["pod"]; !v.IsComputed() && !v.IsString() {
+		failures = append(failures, &pulumirpc.CheckFailure{Property: "pod", Reason: "must be a string"})
+	}
+	if !news.ContainsUnknowns() {
+		if _, _, err := execOptions(news); err != nil {
+			failures = append(failures, &pulumirpc.CheckFailure{Reason: err.Error()})
+		}
+	}
+	return &pulumirpc.CheckResponse{Inputs: req.GetNews(), Failures: failures}, nil
+}
+
+func (r *podExecProvider) Diff(ctx context.Context, req *pulumirpc.DiffRequest) (*pulumirpc.DiffResponse, error) {
+	urn := resource.URN(req.GetUrn())
+	label := fmt.Sprintf("%s.Diff(%s)", r.k.label(), urn)
+
+	olds, err := plugin.UnmarshalProperties(req.GetOlds(), plugin.MarshalOptions{
+		Label: fmt.Sprintf("%s.olds", label), KeepUnknowns: true, SkipNulls: true, KeepSecrets: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	news, err := plugin.UnmarshalProperties(req.GetNews(), plugin.MarshalOptions{
+		Label: fmt.Sprintf("%s.news", label), KeepUnknowns: true, SkipNulls: true, KeepSecrets: true,
+	})
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "diff failed because malformed resource inputs")
+	}
+
+	// Any change to the inputs runs the command again.
+	diff := podExecInputs(olds).Diff(news)
+	if diff == nil {
+		return &pulumirpc.DiffResponse{Changes: pulumirpc.DiffResponse_DIFF_NONE}, nil
+	}
+	var changes []string
+	for _, key := range diff.Keys() {
+		if diff.Changed(key) {
+			changes = append(changes, string(key))
+		}
+	}
+	return &pulumirpc.DiffResponse{Changes: pulumirpc.DiffResponse_DIFF_SOME, Diffs: changes}, nil
+}
+
+func (r *podExecProvider) Create(ctx context.Context, req *pulumirpc.CreateRequest) (*pulumirpc.CreateResponse, error) {
+	urn := resource.URN(req.GetUrn())
+	label := fmt.Sprintf("%s.Create(%s)", r.k.label(), urn)
+
+	news, err := plugin.UnmarshalProperties(req.GetProperties(), plugin.MarshalOptions{
+		Label: fmt.Sprintf("%s.properties", label), KeepUnknowns: true, SkipNulls: true, KeepSecrets: true,
+	})
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "create failed because malformed resource inputs")
+	}
+
+	id, outputs, err := r.run(ctx, news, req.GetPreview())
+	if err != nil {
+		return nil, err
+	}
+	return &pulumirpc.CreateResponse{Id: id, Properties: outputs}, nil
+}
+
+// Read returns the last result of the command, as it cannot be read from the cluster.
+func (r *podExecProvider) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*pulumirpc.ReadResponse, error) {
+	if len(req.GetProperties().GetFields()) == 0 {
+		return nil, fmt.Errorf("PodExec resources cannot be imported")
+	}
+	return &pulumirpc.ReadResponse{Id: req.GetId(), Properties: req.GetProperties(), Inputs: req.GetInputs()}, nil
+}
+
+func (r *podExecProvider) Update(ctx context.Context, req *pulumirpc.UpdateRequest) (*pulumirpc.UpdateResponse, error) {
+	urn := resource.URN(req.GetUrn())
+	label := fmt.Sprintf("%s.Update(%s)", r.k.label(), urn)
+
+	news, err := plugin.UnmarshalProperties(req.GetNews(), plugin.MarshalOptions{
+		Label: fmt.Sprintf("%s.news", label), KeepUnknowns: true, SkipNulls: true, KeepSecrets: true,
+	})
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "update failed because malformed resource inputs")
+	}
+
+	_, outputs, err := r.run(ctx, news, req.GetPreview())
+	if err != nil {
+		return nil, err
+	}
+	return &pulumirpc.UpdateResponse{Properties: outputs}, nil
+}
+
+// Delete only removes the resource from the stack.
+func (r *podExecProvider) Delete(context.Context, *pulumirpc.DeleteRequest) (*empty.Empty, error) {
+	return &empty.Empty{}, nil
+}
+
+// run runs the command of a PodExec resource with the given inputs, unless this is a preview, and returns the ID and
+// the marshalled outputs of the resource.
+func (r *podExecProvider) run(
+	ctx context.Context, inputs resource.PropertyMap, preview bool,
+) (string, *structpb.Struct, error) {
+	var id string
+	var result *clients.ExecResult
+	if !preview {
+		if r.k.clusterUnreachable {
+			return "", nil, fmt.Errorf("can't run PodExec command with unreachable cluster. Reason: %q",
+				r.k.clusterUnreachableReason)
+		}
+		var err error
+		if id, result, err = r.k.exec(ctx, inputs); err != nil {
+			return "", nil, err
+		}
+	}
+
+	outputs, err := plugin.MarshalProperties(podExecOutputs(inputs, result), plugin.MarshalOptions{
+		KeepUnknowns: true, SkipNulls: true, KeepSecrets: r.k.enableSecrets,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return id, outputs, nil
+}
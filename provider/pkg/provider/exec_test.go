@@ -0,0 +1,117 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/clients"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecOptions(t *testing.T) {
+	opts, stdin, err := execOptions(resource.NewPropertyMapFromMap(map[string]interface{}{
+		"command":   []interface{}{"sh", "-c", "cat"},
+		"container": "app",
+		"stdin":     "hello",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sh", "-c", "cat"}, opts.Command)
+	assert.Equal(t, "app", opts.Container)
+	require.NotNil(t, stdin)
+	input, err := ioutil.ReadAll(stdin)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(input))
+
+	_, stdin, err = execOptions(resource.NewPropertyMapFromMap(map[string]interface{}{
+		"command": []interface{}{"true"},
+	}))
+	require.NoError(t, err)
+	assert.Nil(t, stdin)
+
+	for _, args := range []map[string]interface{}{
+		{},
+		{"command": "true"},
+		{"command": []interface{}{}},
+		{"command": []interface{}{"sleep", 1}},
+		{"command": []interface{}{"true"}, "container": 1},
+		{"command": []interface{}{"true"}, "stdin": true},
+	} {
+		_, _, err := execOptions(resource.NewPropertyMapFromMap(args))
+		assert.Error(t, err, args)
+	}
+}
+
+func TestPodExecProvider(t *testing.T) {
+	r := &podExecProvider{k: &kubeProvider{clusterUnreachable: true, clusterUnreachableReason: "no cluster"}}
+	urn := "urn:pulumi:dev::test::kubernetes:index:PodExec::migrate"
+	inputs := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"pod":     "db-0",
+		"command": []interface{}{"migrate", "up"},
+	})
+	marshal := func(pm resource.PropertyMap) *structpb.Struct {
+		s, err := plugin.MarshalProperties(pm, plugin.MarshalOptions{KeepUnknowns: true})
+		require.NoError(t, err)
+		return s
+	}
+	unmarshal := func(s *structpb.Struct) resource.PropertyMap {
+		pm, err := plugin.UnmarshalProperties(s, plugin.MarshalOptions{KeepUnknowns: true})
+		require.NoError(t, err)
+		return pm
+	}
+
+	checked, err := r.Check(context.Background(), &pulumirpc.CheckRequest{Urn: urn, News: marshal(inputs)}, false)
+	require.NoError(t, err)
+	assert.Empty(t, checked.GetFailures())
+	checked, err = r.Check(context.Background(), &pulumirpc.CheckRequest{
+		Urn: urn, News: marshal(resource.NewPropertyMapFromMap(map[string]interface{}{"command": "migrate"})),
+	}, false)
+	require.NoError(t, err)
+	assert.Len(t, checked.GetFailures(), 2)
+
+	// The command is not run during previews, so its result is unknown, and the cluster is not needed.
+	created, err := r.Create(context.Background(), &pulumirpc.CreateRequest{
+		Urn: urn, Properties: marshal(inputs), Preview: true,
+	})
+	require.NoError(t, err)
+	outputs := unmarshal(created.GetProperties())
+	for _, key := range podExecResultKeys {
+		assert.True(t, outputs[key].IsComputed(), key)
+	}
+	assert.Equal(t, inputs, podExecInputs(outputs))
+
+	_, err = r.Create(context.Background(), &pulumirpc.CreateRequest{Urn: urn, Properties: marshal(inputs)})
+	assert.Error(t, err)
+
+	// Any change to the inputs runs the command again.
+	olds := podExecOutputs(inputs, &clients.ExecResult{Stdout: "done"})
+	diff, err := r.Diff(context.Background(), &pulumirpc.DiffRequest{Urn: urn, Olds: marshal(olds), News: marshal(inputs)})
+	require.NoError(t, err)
+	assert.Equal(t, pulumirpc.DiffResponse_DIFF_NONE, diff.GetChanges())
+	changed := inputs.Copy()
+	changed["stdin"] = resource.NewStringProperty("--dry-run")
+	diff, err = r.Diff(context.Background(), &pulumirpc.DiffRequest{Urn: urn, Olds: marshal(olds), News: marshal(changed)})
+	require.NoError(t, err)
+	assert.Equal(t, pulumirpc.DiffResponse_DIFF_SOME, diff.GetChanges())
+	assert.Equal(t, []string{"stdin"}, diff.GetDiffs())
+	assert.Empty(t, diff.GetReplaces())
+}
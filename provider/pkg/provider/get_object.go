@@ -0,0 +1,234 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/clients"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const (
+	// defaultGetObjectTimeoutSeconds is how long a getObject invoke waits for its `waitFor` condition by default.
+	defaultGetObjectTimeoutSeconds = 300
+	// getObjectPollInterval is how often a getObject invoke reads the object while it waits for its condition.
+	getObjectPollInterval = 2 * time.Second
+)
+
+// waitCondition is a condition that an object must satisfy before a getObject invoke returns it. The syntax follows
+// `kubectl wait --for`: `exists` waits until the object exists, `condition=<type>[=<status>]` waits until the object
+// has a status condition of the given type with the status "True" (or the given status), and
+// `jsonpath={<path>}[=<value>]` waits until the field at the path has the given value, or is present if no value is
+// given.
+type waitCondition struct {
+	description string
+
+	conditionType   string
+	conditionStatus string
+
+	path     *jsonpath.JSONPath
+	value    string
+	hasValue bool
+}
+
+// parseWaitCondition parses a `waitFor` condition.
+func parseWaitCondition(s string) (*waitCondition, error) {
+	c := &waitCondition{description: s}
+	switch {
+	case s == "exists":
+		return c, nil
+	case strings.HasPrefix(s, "condition="):
+		parts := strings.SplitN(strings.TrimPrefix(s, "condition="), "=", 2)
+		if parts[0] == "" {
+			return nil, fmt.Errorf("invalid waitFor condition %q: missing condition type", s)
+		}
+		c.conditionType, c.conditionStatus = parts[0], "True"
+		if len(parts) == 2 {
+			c.conditionStatus = parts[1]
+		}
+		return c, nil
+	case strings.HasPrefix(s, "jsonpath="):
+		expr := strings.TrimPrefix(s, "jsonpath=")
+		var path string
+		if strings.HasPrefix(expr, "{") {
+			end := strings.Index(expr, "}")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid waitFor condition %q: unterminated '{'", s)
+			}
+			path, expr = expr[:end+1], expr[end+1:]
+			if expr != "" && !strings.HasPrefix(expr, "=") {
+				return nil, fmt.Errorf("invalid waitFor condition %q: expected '=' after the JSONPath", s)
+			}
+			expr = strings.TrimPrefix(expr, "=")
+			c.value, c.hasValue = expr, expr != ""
+		} else {
+			parts := strings.SplitN(expr, "=", 2)
+			path = fmt.Sprintf("{%s}", parts[0])
+			if len(parts) == 2 {
+				c.value, c.hasValue = parts[1], true
+			}
+		}
+		c.path = jsonpath.New("waitFor")
+		if err := c.path.Parse(path); err != nil {
+			return nil, fmt.Errorf("invalid waitFor condition %q: %v", s, err)
+		}
+		return c, nil
+	default:
+		return nil, fmt.Errorf(
+			"invalid waitFor condition %q: expected \"exists\", \"condition=<type>[=<status>]\" or "+
+				"\"jsonpath={<path>}[=<value>]\"", s)
+	}
+}
+
+// satisfied returns whether the object satisfies the condition.
+func (c *waitCondition) satisfied(obj *unstructured.Unstructured) bool {
+	switch {
+	case c.conditionType != "":
+		conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+		for _, raw := range conditions {
+			condition, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			conditionType, _ := condition["type"].(string)
+			status, _ := condition["status"].(string)
+			if strings.EqualFold(conditionType, c.conditionType) {
+				return strings.EqualFold(status, c.conditionStatus)
+			}
+		}
+		return false
+	case c.path != nil:
+		results, err := c.path.FindResults(obj.Object)
+		if err != nil || len(results) == 0 || len(results[0]) == 0 {
+			return false
+		}
+		if !c.hasValue {
+			return true
+		}
+		buf := new(bytes.Buffer)
+		if err = c.path.PrintResults(buf, results[0]); err != nil {
+			return false
+		}
+		return buf.String() == c.value
+	default:
+		return true
+	}
+}
+
+// getObject reads a live object for a getObject invoke. If the `waitFor` argument is present, the object is read
+// repeatedly until it satisfies the condition, or the timeout given by `timeoutSeconds` expires.
+func (k *kubeProvider) getObject(ctx context.Context, args resource.PropertyMap) (*unstructured.Unstructured, error) {
+	for _, required := range []resource.PropertyKey{"apiVersion", "kind", "name"} {
+		if !args[required].HasValue() || !args[required].IsString() {
+			return nil, fmt.Errorf("missing required field '%s' of type string", required)
+		}
+	}
+	gv, err := schema.ParseGroupVersion(args["apiVersion"].StringValue())
+	if err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetGroupVersionKind(gv.WithKind(args["kind"].StringValue()))
+	obj.SetName(args["name"].StringValue())
+
+	namespace := ""
+	if args["namespace"].HasValue() && args["namespace"].IsString() {
+		namespace = args["namespace"].StringValue()
+	}
+	if namespace == "" {
+		namespaced, err := k.isNamespacedKind(obj.GroupVersionKind())
+		if err != nil && !clients.IsNoNamespaceInfoErr(err) {
+			return nil, err
+		}
+		if namespaced || clients.IsNoNamespaceInfoErr(err) {
+			namespace = k.defaultNamespace
+			if namespace == "" {
+				namespace = "default"
+			}
+		}
+	}
+	obj.SetNamespace(namespace)
+
+	if !args["waitFor"].HasValue() {
+		return k.readLiveObject(obj)
+	}
+	if !args["waitFor"].IsString() {
+		return nil, fmt.Errorf("field 'waitFor' must be a string")
+	}
+	condition, err := parseWaitCondition(args["waitFor"].StringValue())
+	if err != nil {
+		return nil, err
+	}
+	timeout := time.Duration(defaultGetObjectTimeoutSeconds) * time.Second
+	if v := args["timeoutSeconds"]; v.HasValue() {
+		if !v.IsNumber() || v.NumberValue() <= 0 {
+			return nil, fmt.Errorf("field 'timeoutSeconds' must be a positive number")
+		}
+		timeout = time.Duration(v.NumberValue() * float64(time.Second))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(getObjectPollInterval)
+	defer ticker.Stop()
+	for {
+		live, err := k.readLiveObject(obj)
+		switch {
+		case err == nil && condition.satisfied(live):
+			return live, nil
+		case err != nil && !errors.IsNotFound(err):
+			return nil, err
+		}
+
+		select {
+		case <-k.canceler.context.Done():
+			return nil, fmt.Errorf("cancelled waiting for %s %s", obj.GetKind(), fqObjName(obj))
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for %s %s to satisfy condition %q",
+				obj.GetKind(), fqObjName(obj), condition.description)
+		case <-ticker.C:
+		}
+	}
+}
+
+// getObjectResult returns the properties that a getObject invoke returns for a live object. The managed fields are
+// omitted, as `kubectl get` does. The data of Secrets, and the last-applied-configuration annotation that contains a
+// copy of it, are marked as secret.
+func getObjectResult(live *unstructured.Unstructured) resource.PropertyMap {
+	live = live.DeepCopy()
+	live.SetManagedFields(nil)
+	object := resource.NewPropertyMapFromMap(live.Object)
+	if live.GetAPIVersion() == "v1" && live.GetKind() == "Secret" {
+		for _, key := range []resource.PropertyKey{"data", "stringData"} {
+			if v, ok := object[key]; ok {
+				object[key] = resource.MakeSecret(v)
+			}
+		}
+		if _, ok := live.GetAnnotations()[lastAppliedConfigKey]; ok {
+			annotations := object["metadata"].ObjectValue()["annotations"].ObjectValue()
+			annotations[lastAppliedConfigKey] = resource.MakeSecret(annotations[lastAppliedConfigKey])
+		}
+	}
+	return resource.PropertyMap{"result": resource.NewObjectProperty(object)}
+}
@@ -0,0 +1,116 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestWaitCondition(t *testing.T) {
+	pod := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]interface{}{"name": "nginx"},
+		"status": map[string]interface{}{
+			"phase": "Running",
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Initialized", "status": "True"},
+				map[string]interface{}{"type": "Ready", "status": "False"},
+			},
+		},
+	}}
+
+	tests := []struct {
+		condition string
+		satisfied bool
+	}{
+		{"exists", true},
+		{"condition=Initialized", true},
+		{"condition=ready", false},
+		{"condition=Ready=false", true},
+		{"condition=PodScheduled", false},
+		{"jsonpath={.status.phase}=Running", true},
+		{"jsonpath={.status.phase}=Pending", false},
+		{"jsonpath=.status.phase=Running", true},
+		{"jsonpath={.status.phase}", true},
+		{"jsonpath={.status.podIP}", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.condition, func(t *testing.T) {
+			c, err := parseWaitCondition(tt.condition)
+			require.NoError(t, err)
+			assert.Equal(t, tt.satisfied, c.satisfied(pod))
+		})
+	}
+
+	for _, condition := range []string{"ready", "condition=", "jsonpath={.status.phase", "jsonpath={.status}Running"} {
+		_, err := parseWaitCondition(condition)
+		assert.Error(t, err, condition)
+	}
+}
+
+func TestGetObjectResult(t *testing.T) {
+	managedFields := []interface{}{
+		map[string]interface{}{
+			"manager":    "kubectl",
+			"operation":  "Update",
+			"fieldsType": "FieldsV1",
+			"fieldsV1":   map[string]interface{}{"f:data": map[string]interface{}{"f:token": map[string]interface{}{}}},
+		},
+	}
+	secret := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]interface{}{
+			"name": "token",
+			"annotations": map[string]interface{}{
+				lastAppliedConfigKey: `{"apiVersion":"v1","kind":"Secret","stringData":{"token":"secret"}}`,
+				"team":               "web",
+			},
+			"managedFields": managedFields,
+		},
+		"data": map[string]interface{}{"token": "c2VjcmV0"},
+	}}
+	result := getObjectResult(secret)["result"].ObjectValue()
+	assert.True(t, result["data"].IsSecret())
+	assert.False(t, result["metadata"].IsSecret())
+	metadata := result["metadata"].ObjectValue()
+	assert.NotContains(t, metadata, resource.PropertyKey("managedFields"))
+	annotations := metadata["annotations"].ObjectValue()
+	assert.True(t, annotations[lastAppliedConfigKey].IsSecret())
+	assert.False(t, annotations["team"].IsSecret())
+
+	// The live object is not modified.
+	assert.Len(t, secret.GetManagedFields(), 1)
+
+	configMap := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":          "config",
+			"annotations":   map[string]interface{}{lastAppliedConfigKey: `{"data":{"key":"value"}}`},
+			"managedFields": managedFields,
+		},
+		"data": map[string]interface{}{"key": "value"},
+	}}
+	result = getObjectResult(configMap)["result"].ObjectValue()
+	assert.False(t, result.ContainsSecrets())
+	assert.NotContains(t, result["metadata"].ObjectValue(), resource.PropertyKey("managedFields"))
+}
@@ -0,0 +1,100 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/kinds"
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/metadata"
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/openapi"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// parseIgnoreFields parses the `ignoreFields` provider config, which is a JSON object that maps a resource type to a
+// list of field paths, e.g., `{"apps/v1/Deployment": ["/spec/replicas"]}`. Resource types are given as the apiVersion
+// and kind joined by a slash.
+func parseIgnoreFields(config string) (map[schema.GroupVersionKind][]openapi.FieldPath, error) {
+	var raw map[string][]string
+	if err := json.Unmarshal([]byte(config), &raw); err != nil {
+		return nil, fmt.Errorf("ignoreFields must be a JSON object mapping resource types to lists of field paths: %v",
+			err)
+	}
+
+	ignoreFields := map[schema.GroupVersionKind][]openapi.FieldPath{}
+	for key, paths := range raw {
+		i := strings.LastIndex(key, "/")
+		if i <= 0 || i == len(key)-1 {
+			return nil, fmt.Errorf("invalid resource type %q in ignoreFields: expected <apiVersion>/<kind>", key)
+		}
+		gv, err := schema.ParseGroupVersion(key[:i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid resource type %q in ignoreFields: %v", key, err)
+		}
+		gvk := gv.WithKind(key[i+1:])
+		for _, path := range paths {
+			fieldPath, err := openapi.ParseFieldPath(path)
+			if err != nil {
+				return nil, fmt.Errorf("invalid field path for %q in ignoreFields: %v", key, err)
+			}
+			ignoreFields[gvk] = append(ignoreFields[gvk], fieldPath)
+		}
+	}
+	return ignoreFields, nil
+}
+
+// parseIgnoreFieldsAnnotation parses the field paths in the `pulumi.com/ignoreFields` annotation of an object.
+func parseIgnoreFieldsAnnotation(obj *unstructured.Unstructured) ([]openapi.FieldPath, error) {
+	var fieldPaths []openapi.FieldPath
+	for _, path := range metadata.IgnoreFields(obj) {
+		fieldPath, err := openapi.ParseFieldPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid field path in %s annotation: %v", metadata.AnnotationIgnoreFields, err)
+		}
+		fieldPaths = append(fieldPaths, fieldPath)
+	}
+	return fieldPaths, nil
+}
+
+// ignoredFields returns the paths of the fields that are excluded from diffs and updates of the given object. These are
+// the paths configured on the provider for the object's type, and the paths in its `pulumi.com/ignoreFields`
+// annotation.
+//
+// Kinds that are not built in, such as custom resources, are updated with a JSON merge patch, which replaces lists as
+// a whole. Leaving a list element out of the patch would delete it from the live object, so paths that select list
+// elements of these kinds are rejected.
+func (k *kubeProvider) ignoredFields(gvk schema.GroupVersionKind, obj *unstructured.Unstructured,
+) ([]openapi.FieldPath, error) {
+	annotated, err := parseIgnoreFieldsAnnotation(obj)
+	if err != nil {
+		return nil, err
+	}
+	fieldPaths := append([]openapi.FieldPath{}, k.ignoreFields[gvk]...)
+	fieldPaths = append(fieldPaths, annotated...)
+
+	if known, _ := kinds.Kind(gvk.Kind).Namespaced(); !known {
+		for _, path := range fieldPaths {
+			if openapi.SelectsListElements(obj, path) {
+				return nil, fmt.Errorf("cannot ignore field %q of %s: it selects list elements, and the lists "+
+					"of resources that are not built-in Kubernetes kinds are replaced as a whole on update; "+
+					"ignore the entire list instead", path, gvk.Kind)
+			}
+		}
+	}
+	return fieldPaths, nil
+}
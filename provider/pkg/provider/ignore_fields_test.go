@@ -0,0 +1,125 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/metadata"
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestParseIgnoreFields(t *testing.T) {
+	ignoreFields, err := parseIgnoreFields(`{
+		"apps/v1/Deployment": ["/spec/replicas"],
+		"v1/Service": [".spec.clusterIP", ".spec.ports[*].nodePort"]
+	}`)
+	require.NoError(t, err)
+	assert.Len(t, ignoreFields[schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}], 1)
+	assert.Len(t, ignoreFields[schema.GroupVersionKind{Version: "v1", Kind: "Service"}], 2)
+
+	for _, config := range []string{
+		`["/spec/replicas"]`,
+		`{"Deployment": ["/spec/replicas"]}`,
+		`{"apps/v1/": ["/spec/replicas"]}`,
+		`{"apps/v1/Deployment": ["spec[?(@.replicas)]"]}`,
+	} {
+		_, err = parseIgnoreFields(config)
+		assert.Error(t, err, config)
+	}
+}
+
+func TestIgnoredFields(t *testing.T) {
+	deployment := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	replicas, err := openapi.ParseFieldPath("/spec/replicas")
+	require.NoError(t, err)
+	k := &kubeProvider{ignoreFields: map[schema.GroupVersionKind][]openapi.FieldPath{deployment: {replicas}}}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				metadata.AnnotationIgnoreFields: ".spec.template.spec.containers[*].imagePullPolicy",
+			},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "nginx", "imagePullPolicy": "Always"},
+					},
+				},
+			},
+		},
+	}}
+
+	ignoreFields, err := k.ignoredFields(deployment, obj)
+	require.NoError(t, err)
+	require.Len(t, ignoreFields, 2)
+	assert.Equal(t, map[string]interface{}{
+		"template": map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "nginx"},
+				},
+			},
+		},
+	}, openapi.WithoutFields(obj, ignoreFields).Object["spec"])
+
+	// The provider-level paths only apply to their resource type.
+	ignoreFields, err = k.ignoredFields(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"}, obj)
+	require.NoError(t, err)
+	assert.Len(t, ignoreFields, 1)
+
+	obj.SetAnnotations(map[string]string{metadata.AnnotationIgnoreFields: "/spec//replicas"})
+	_, err = k.ignoredFields(deployment, obj)
+	assert.Error(t, err)
+}
+
+func TestIgnoredFieldsMergePatch(t *testing.T) {
+	crontab := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "CronTab"}
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "CronTab",
+		"spec": map[string]interface{}{
+			"schedule": "* * * * */5",
+			"containers": []interface{}{
+				map[string]interface{}{"name": "cron", "image": "busybox"},
+			},
+		},
+	}}
+	k := &kubeProvider{}
+
+	// Custom resources are updated with a JSON merge patch, so fields and whole lists can be ignored, but not list
+	// elements.
+	for path, valid := range map[string]bool{
+		"/spec/schedule":              true,
+		"/spec/containers":            true,
+		"/spec/containers/0":          false,
+		".spec.containers[*].image":   false,
+		"/spec/containers/0/image":    false,
+		"/spec/initContainers/0/name": true,
+	} {
+		obj.SetAnnotations(map[string]string{metadata.AnnotationIgnoreFields: path})
+		_, err := k.ignoredFields(crontab, obj)
+		assert.Equal(t, valid, err == nil, path)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	clientapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// impersonation is the identity that the provider impersonates, from the `impersonate` provider config.
+type impersonation struct {
+	User   string   `json:"user"`
+	Groups []string `json:"groups"`
+	UID    string   `json:"uid"`
+}
+
+// parseImpersonation parses the `impersonate` provider config variable, which is the JSON encoding of the config.
+func parseImpersonation(value string) (*impersonation, error) {
+	var imp impersonation
+	if err := json.Unmarshal([]byte(value), &imp); err != nil {
+		return nil, fmt.Errorf("invalid impersonate config: %v", err)
+	}
+	if imp.User == "" && (len(imp.Groups) > 0 || imp.UID != "") {
+		return nil, fmt.Errorf("invalid impersonate config: groups and uid require a user")
+	}
+	return &imp, nil
+}
+
+// apply sets the impersonation of the client config overrides, so that every client config that is loaded through
+// them impersonates the user and groups. The UID is not part of a kubeconfig, so it is applied to the client config.
+func (imp *impersonation) apply(authInfo *clientapi.AuthInfo) {
+	authInfo.Impersonate = imp.User
+	authInfo.ImpersonateGroups = imp.Groups
+}
+
+// checkImpersonation validates the `impersonate` provider config. Values that are not known yet are not validated.
+func checkImpersonation(news resource.PropertyMap) []*pulumirpc.CheckFailure {
+	v := news["impersonate"]
+	if v.IsSecret() {
+		v = v.SecretValue().Element
+	}
+	if !v.HasValue() || v.ContainsUnknowns() {
+		return nil
+	}
+	fail := func(format string, args ...interface{}) []*pulumirpc.CheckFailure {
+		return []*pulumirpc.CheckFailure{{Property: "impersonate", Reason: fmt.Sprintf(format, args...)}}
+	}
+	if !v.IsObject() {
+		return fail("impersonate must be an object with user, groups and uid")
+	}
+
+	obj := v.ObjectValue()
+	for key, value := range obj {
+		switch key {
+		case "user", "uid":
+			if !value.IsString() {
+				return fail("impersonate.%s must be a string", key)
+			}
+		case "groups":
+			if !value.IsArray() {
+				return fail("impersonate.groups must be an array of strings")
+			}
+			for _, group := range value.ArrayValue() {
+				if !group.IsString() {
+					return fail("impersonate.groups must be an array of strings")
+				}
+			}
+		default:
+			return fail("unknown impersonate field %q, expected user, groups or uid", key)
+		}
+	}
+	if user := obj["user"]; !user.IsString() || user.StringValue() == "" {
+		if obj["groups"].HasValue() || obj["uid"].HasValue() {
+			return fail("impersonate.groups and impersonate.uid require impersonate.user to be set")
+		}
+	}
+	return nil
+}
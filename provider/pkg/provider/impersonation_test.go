@@ -0,0 +1,116 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func TestParseImpersonation(t *testing.T) {
+	imp, err := parseImpersonation(`{"user":"system:serviceaccount:tenant:deployer","groups":["tenants"],"uid":"1234"}`)
+	require.NoError(t, err)
+	assert.Equal(t, &impersonation{
+		User:   "system:serviceaccount:tenant:deployer",
+		Groups: []string{"tenants"},
+		UID:    "1234",
+	}, imp)
+
+	_, err = parseImpersonation(`{"groups":["tenants"]}`)
+	assert.EqualError(t, err, "invalid impersonate config: groups and uid require a user")
+	_, err = parseImpersonation(`"deployer"`)
+	assert.Error(t, err)
+}
+
+func TestImpersonationOverrides(t *testing.T) {
+	config, err := directCredentialsKubeconfig(map[string]string{
+		"kubernetes:config:server": "https://1.2.3.4:6443",
+		"kubernetes:config:token":  "token",
+	})
+	require.NoError(t, err)
+	overrides := &clientcmd.ConfigOverrides{}
+	imp := &impersonation{User: "deployer", Groups: []string{"tenants"}}
+	imp.apply(&overrides.AuthInfo)
+
+	restConfig, err := clientcmd.NewDefaultClientConfig(*config, overrides).ClientConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "deployer", restConfig.Impersonate.UserName)
+	assert.Equal(t, []string{"tenants"}, restConfig.Impersonate.Groups)
+	assert.Equal(t, "token", restConfig.BearerToken)
+}
+
+func TestCheckImpersonation(t *testing.T) {
+	tests := []struct {
+		name        string
+		impersonate resource.PropertyValue
+		reason      string
+	}{
+		{
+			name: "valid",
+			impersonate: resource.NewObjectProperty(resource.NewPropertyMapFromMap(map[string]interface{}{
+				"user":   "deployer",
+				"groups": []interface{}{"tenants"},
+				"uid":    "1234",
+			})),
+		},
+		{
+			name:        "computed",
+			impersonate: resource.MakeComputed(resource.NewObjectProperty(resource.PropertyMap{})),
+		},
+		{
+			name:        "not an object",
+			impersonate: resource.NewStringProperty("deployer"),
+			reason:      "impersonate must be an object with user, groups and uid",
+		},
+		{
+			name: "invalid groups",
+			impersonate: resource.NewObjectProperty(resource.NewPropertyMapFromMap(map[string]interface{}{
+				"user":   "deployer",
+				"groups": "tenants",
+			})),
+			reason: "impersonate.groups must be an array of strings",
+		},
+		{
+			name: "unknown field",
+			impersonate: resource.NewObjectProperty(resource.NewPropertyMapFromMap(map[string]interface{}{
+				"userName": "deployer",
+			})),
+			reason: `unknown impersonate field "userName", expected user, groups or uid`,
+		},
+		{
+			name: "uid without user",
+			impersonate: resource.NewObjectProperty(resource.NewPropertyMapFromMap(map[string]interface{}{
+				"uid": "1234",
+			})),
+			reason: "impersonate.groups and impersonate.uid require impersonate.user to be set",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := checkImpersonation(resource.PropertyMap{"impersonate": tt.impersonate})
+			if tt.reason == "" {
+				assert.Empty(t, failures)
+				return
+			}
+			require.Len(t, failures, 1)
+			assert.Equal(t, "impersonate", failures[0].Property)
+			assert.Equal(t, tt.reason, failures[0].Reason)
+		})
+	}
+}
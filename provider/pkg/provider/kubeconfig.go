@@ -19,11 +19,9 @@ type KubeConfig struct {
 
 // ToDiscoveryClient implemented interface method
 func (k *KubeConfig) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
-	// The more groups you have, the more discovery requests you need to make.
-	// given 25 groups (our groups + a few custom resources) with one-ish version each, discovery needs to make 50 requests
-	// double it just so we don't end up here again for a while.  This config is only used for discovery.
-	k.restConfig.Burst = 100
-
+	// The more groups you have, the more discovery requests you need to make. The discovery client raises the burst of
+	// its own copy of the config to 100, unless the provider config sets a shared rate limit, so the config that is
+	// shared with the other clients is not modified here.
 	return memcached.NewMemCacheClient(discovery.NewDiscoveryClientForConfigOrDie(k.restConfig)), nil
 }
 
@@ -0,0 +1,252 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/await/informers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// podLogBatchLines is the maximum number of log lines that are sent in a single podLogs message.
+	podLogBatchLines = 100
+	// podLogBatchInterval is the longest time that a log line is held back before it is sent, so that followed logs
+	// are still delivered promptly when they are written slowly.
+	podLogBatchInterval = 100 * time.Millisecond
+)
+
+// podLogBatch is a batch of consecutive log lines from one Pod.
+type podLogBatch struct {
+	pod   string
+	lines []string
+}
+
+// podLogsTarget returns the namespace of a podLogs stream invoke, and either the name of its Pod or the label selector
+// of its Pods.
+func podLogsTarget(args resource.PropertyMap) (namespace, name, labelSelector string, err error) {
+	namespace = "default"
+	for argName, value := range map[string]*string{
+		"namespace":     &namespace,
+		"name":          &name,
+		"labelSelector": &labelSelector,
+	} {
+		v := args[resource.PropertyKey(argName)]
+		if !v.HasValue() {
+			continue
+		}
+		if !v.IsString() {
+			return "", "", "", fmt.Errorf("podLogs argument %q must be a string", argName)
+		}
+		*value = v.StringValue()
+	}
+
+	switch {
+	case name == "" && labelSelector == "":
+		return "", "", "", fmt.Errorf(
+			"could not retrieve pod logs because neither the pod name nor a label selector was present")
+	case name != "" && labelSelector != "":
+		return "", "", "", fmt.Errorf("podLogs arguments \"name\" and \"labelSelector\" cannot both be set")
+	}
+	if labelSelector != "" {
+		if _, err = labels.Parse(labelSelector); err != nil {
+			return "", "", "", fmt.Errorf("podLogs argument \"labelSelector\" is invalid: %v", err)
+		}
+	}
+	return namespace, name, labelSelector, nil
+}
+
+// watchLogPods watches the Pods in the namespace that match the label selector, and sends each Pod on the returned
+// channel once its containers have started, so that its logs can be read. Pods that match the selector later, such
+// as the new Pods of a rollout, are sent as well. The Pods are watched until the context is cancelled.
+func (k *kubeProvider) watchLogPods(ctx context.Context, namespace, labelSelector string) (<-chan string, error) {
+	factory := informers.NewInformerFactory(k.clientSet,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptionsFunc(func(options *metav1.ListOptions) {
+			options.LabelSelector = labelSelector
+		}))
+	informer, err := informers.New(factory, informers.ForPods())
+	if err != nil {
+		return nil, err
+	}
+
+	// The handlers of an informer are called one at a time, so the started Pods need no lock.
+	pods := make(chan string)
+	started := map[types.UID]bool{}
+	send := func(obj interface{}) {
+		pod, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return
+		}
+		phase, _, _ := unstructured.NestedString(pod.Object, "status", "phase")
+		if phase == "" || phase == string(corev1.PodPending) || started[pod.GetUID()] {
+			return
+		}
+		started[pod.GetUID()] = true
+
+		select {
+		case pods <- pod.GetName():
+		case <-ctx.Done():
+		}
+	}
+	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    send,
+		UpdateFunc: func(_, obj interface{}) { send(obj) },
+		DeleteFunc: func(obj interface{}) {
+			if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = unknown.Obj
+			}
+			if pod, ok := obj.(*unstructured.Unstructured); ok {
+				delete(started, pod.GetUID())
+			}
+		},
+	})
+
+	factory.Start(ctx.Done())
+	return pods, nil
+}
+
+// podLogOptions returns the options of a podLogs stream invoke. Logs are followed unless `follow` is false.
+func podLogOptions(args resource.PropertyMap) (*corev1.PodLogOptions, error) {
+	opts := &corev1.PodLogOptions{Follow: true}
+
+	stringArg := func(name string) (string, error) {
+		v := args[resource.PropertyKey(name)]
+		if !v.HasValue() {
+			return "", nil
+		}
+		if !v.IsString() {
+			return "", fmt.Errorf("podLogs argument %q must be a string", name)
+		}
+		return v.StringValue(), nil
+	}
+	boolArg := func(name string, value *bool) error {
+		v := args[resource.PropertyKey(name)]
+		if !v.HasValue() {
+			return nil
+		}
+		if !v.IsBool() {
+			return fmt.Errorf("podLogs argument %q must be a boolean", name)
+		}
+		*value = v.BoolValue()
+		return nil
+	}
+	intArg := func(name string) (*int64, error) {
+		v := args[resource.PropertyKey(name)]
+		if !v.HasValue() {
+			return nil, nil
+		}
+		if !v.IsNumber() || v.NumberValue() < 0 || v.NumberValue() != float64(int64(v.NumberValue())) {
+			return nil, fmt.Errorf("podLogs argument %q must be a non-negative integer", name)
+		}
+		i := int64(v.NumberValue())
+		return &i, nil
+	}
+
+	var err error
+	if opts.Container, err = stringArg("container"); err != nil {
+		return nil, err
+	}
+	for name, value := range map[string]*bool{
+		"follow":     &opts.Follow,
+		"timestamps": &opts.Timestamps,
+		"previous":   &opts.Previous,
+	} {
+		if err = boolArg(name, value); err != nil {
+			return nil, err
+		}
+	}
+	if opts.SinceSeconds, err = intArg("sinceSeconds"); err != nil {
+		return nil, err
+	}
+	if opts.TailLines, err = intArg("tailLines"); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// batchLogLines reads log lines from the reader, and sends them in batches of up to `podLogBatchLines` lines. A
+// partial batch is sent once it has waited for `podLogBatchInterval`, or when the reader is exhausted. Returns when
+// the reader is exhausted or the context is cancelled.
+func batchLogLines(ctx context.Context, pod string, r io.Reader, batches chan<- podLogBatch) error {
+	lines := make(chan string)
+	scanErr := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-ctx.Done():
+				return
+			}
+		}
+		scanErr <- scanner.Err()
+	}()
+
+	var batch []string
+	flush := func() bool {
+		if len(batch) == 0 {
+			return true
+		}
+		select {
+		case batches <- podLogBatch{pod: pod, lines: batch}:
+			batch = nil
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	ticker := time.NewTicker(podLogBatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				if !flush() {
+					return nil
+				}
+				select {
+				case err := <-scanErr:
+					return err
+				default:
+					return nil
+				}
+			}
+			batch = append(batch, line)
+			if len(batch) >= podLogBatchLines && !flush() {
+				return nil
+			}
+		case <-ticker.C:
+			if !flush() {
+				return nil
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
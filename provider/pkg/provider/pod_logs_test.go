@@ -0,0 +1,172 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/clients"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+)
+
+func TestPodLogOptions(t *testing.T) {
+	opts, err := podLogOptions(resource.NewPropertyMapFromMap(map[string]interface{}{"name": "nginx"}))
+	require.NoError(t, err)
+	assert.Equal(t, &corev1.PodLogOptions{Follow: true}, opts)
+
+	opts, err = podLogOptions(resource.NewPropertyMapFromMap(map[string]interface{}{
+		"container":    "sidecar",
+		"follow":       false,
+		"sinceSeconds": 60,
+		"tailLines":    10,
+		"timestamps":   true,
+		"previous":     true,
+	}))
+	require.NoError(t, err)
+	sinceSeconds, tailLines := int64(60), int64(10)
+	assert.Equal(t, &corev1.PodLogOptions{
+		Container:    "sidecar",
+		SinceSeconds: &sinceSeconds,
+		TailLines:    &tailLines,
+		Timestamps:   true,
+		Previous:     true,
+	}, opts)
+
+	for _, args := range []map[string]interface{}{
+		{"container": 1},
+		{"follow": "true"},
+		{"tailLines": -1},
+		{"sinceSeconds": 1.5},
+	} {
+		_, err = podLogOptions(resource.NewPropertyMapFromMap(args))
+		assert.Error(t, err, args)
+	}
+}
+
+func TestPodLogsTarget(t *testing.T) {
+	namespace, name, labelSelector, err := podLogsTarget(resource.NewPropertyMapFromMap(map[string]interface{}{
+		"name": "nginx",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default", "nginx", ""}, []string{namespace, name, labelSelector})
+
+	namespace, name, labelSelector, err = podLogsTarget(resource.NewPropertyMapFromMap(map[string]interface{}{
+		"namespace":     "web",
+		"labelSelector": "app=nginx",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web", "", "app=nginx"}, []string{namespace, name, labelSelector})
+
+	for _, args := range []map[string]interface{}{
+		{},
+		{"name": 1},
+		{"name": "nginx", "namespace": true},
+		{"labelSelector": []interface{}{"app=nginx"}},
+		{"labelSelector": "app in (nginx"},
+		{"name": "nginx", "labelSelector": "app=nginx"},
+	} {
+		_, _, _, err = podLogsTarget(resource.NewPropertyMapFromMap(args))
+		assert.Error(t, err, args)
+	}
+}
+
+func TestWatchLogPods(t *testing.T) {
+	pod := func(name, phase string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "default",
+				"uid":       name,
+				"labels":    map[string]interface{}{"app": "nginx"},
+			},
+			"status": map[string]interface{}{"phase": phase},
+		}}
+	}
+	client := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{{Version: "v1", Resource: "pods"}: "PodList"},
+		pod("running", "Running"), pod("pending", "Pending"))
+	k := &kubeProvider{clientSet: &clients.DynamicClientSet{GenericClient: client}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pods, err := k.watchLogPods(ctx, "default", "app=nginx")
+	require.NoError(t, err)
+
+	receive := func() string {
+		select {
+		case name := <-pods:
+			return name
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a Pod")
+			return ""
+		}
+	}
+
+	// Pods are sent once their containers have started, and only once.
+	assert.Equal(t, "running", receive())
+	podsClient := client.Resource(schema.GroupVersionResource{Version: "v1", Resource: "pods"}).Namespace("default")
+	_, err = podsClient.Update(ctx, pod("running", "Succeeded"), metav1.UpdateOptions{})
+	require.NoError(t, err)
+	_, err = podsClient.Update(ctx, pod("pending", "Running"), metav1.UpdateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "pending", receive())
+
+	// Pods that are created later are sent as well.
+	_, err = podsClient.Create(ctx, pod("scaled", "Running"), metav1.CreateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "scaled", receive())
+}
+
+func TestBatchLogLines(t *testing.T) {
+	var lines []string
+	for i := 0; i < podLogBatchLines+5; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+
+	batches := make(chan podLogBatch)
+	done := make(chan error, 1)
+	go func() {
+		done <- batchLogLines(context.Background(), "nginx", strings.NewReader(strings.Join(lines, "\n")), batches)
+	}()
+
+	first := <-batches
+	assert.Equal(t, "nginx", first.pod)
+	assert.Equal(t, lines[:podLogBatchLines], first.lines)
+	second := <-batches
+	assert.Equal(t, lines[podLogBatchLines:], second.lines)
+	assert.NoError(t, <-done)
+
+	// Cancelling the context stops the batching without blocking on the unread batches.
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		done <- batchLogLines(ctx, "nginx", strings.NewReader(strings.Join(lines, "\n")), batches)
+	}()
+	cancel()
+	assert.NoError(t, <-done)
+}
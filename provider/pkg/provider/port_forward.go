@@ -0,0 +1,198 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// defaultPortForwardAddress is the local address that a portForward stream invoke listens on by default.
+const defaultPortForwardAddress = "127.0.0.1"
+
+// portForwardOptions are the arguments of a portForward stream invoke.
+type portForwardOptions struct {
+	namespace string
+	// kind is the kind of the target, either "Pod" or "Service".
+	kind string
+	name string
+	// port is the port of the Pod, or the port of the Service, that is forwarded to.
+	port int
+	// localPort is the local port to listen on, or 0 to choose a free port.
+	localPort int
+	address   string
+}
+
+// parsePortForwardOptions returns the options of a portForward stream invoke.
+func parsePortForwardOptions(args resource.PropertyMap) (*portForwardOptions, error) {
+	opts := &portForwardOptions{kind: "Pod", address: defaultPortForwardAddress}
+
+	stringArg := func(name string, value *string) error {
+		v := args[resource.PropertyKey(name)]
+		if !v.HasValue() {
+			return nil
+		}
+		if !v.IsString() {
+			return fmt.Errorf("portForward argument %q must be a string", name)
+		}
+		*value = v.StringValue()
+		return nil
+	}
+	portArg := func(name string, value *int, min int) error {
+		v := args[resource.PropertyKey(name)]
+		if !v.HasValue() {
+			return nil
+		}
+		if !v.IsNumber() || v.NumberValue() < float64(min) || v.NumberValue() > 65535 ||
+			v.NumberValue() != float64(int(v.NumberValue())) {
+			return fmt.Errorf("portForward argument %q must be a port number", name)
+		}
+		*value = int(v.NumberValue())
+		return nil
+	}
+
+	for name, value := range map[string]*string{
+		"namespace": &opts.namespace,
+		"kind":      &opts.kind,
+		"name":      &opts.name,
+		"address":   &opts.address,
+	} {
+		if err := stringArg(name, value); err != nil {
+			return nil, err
+		}
+	}
+	if opts.kind != "Pod" && opts.kind != "Service" {
+		return nil, fmt.Errorf("portForward argument \"kind\" must be \"Pod\" or \"Service\", got %q", opts.kind)
+	}
+	if opts.name == "" {
+		return nil, fmt.Errorf("missing required field 'name' of type string")
+	}
+	if !args["port"].HasValue() {
+		return nil, fmt.Errorf("missing required field 'port' of type number")
+	}
+	if err := portArg("port", &opts.port, 1); err != nil {
+		return nil, err
+	}
+	if err := portArg("localPort", &opts.localPort, 0); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// servicePodPort returns a ready Pod that backs the Service, and the port of that Pod that the given Service port
+// targets.
+func servicePodPort(svc *corev1.Service, pods []corev1.Pod, port int) (string, int, error) {
+	var servicePort *corev1.ServicePort
+	for i := range svc.Spec.Ports {
+		if int(svc.Spec.Ports[i].Port) == port {
+			servicePort = &svc.Spec.Ports[i]
+			break
+		}
+	}
+	if servicePort == nil {
+		return "", 0, fmt.Errorf("service %s/%s does not expose port %d", svc.Namespace, svc.Name, port)
+	}
+
+	var pod *corev1.Pod
+	for i := range pods {
+		if pods[i].DeletionTimestamp == nil && podReady(&pods[i]) {
+			pod = &pods[i]
+			break
+		}
+	}
+	if pod == nil {
+		return "", 0, fmt.Errorf("service %s/%s has no ready Pods", svc.Namespace, svc.Name)
+	}
+
+	switch {
+	case servicePort.TargetPort.Type == intstr.String:
+		for _, container := range pod.Spec.Containers {
+			for _, containerPort := range container.Ports {
+				if containerPort.Name == servicePort.TargetPort.StrVal {
+					return pod.Name, int(containerPort.ContainerPort), nil
+				}
+			}
+		}
+		return "", 0, fmt.Errorf("pod %s/%s has no container port named %q", pod.Namespace, pod.Name,
+			servicePort.TargetPort.StrVal)
+	case servicePort.TargetPort.IntVal != 0:
+		return pod.Name, int(servicePort.TargetPort.IntVal), nil
+	default:
+		// The target port defaults to the port of the Service.
+		return pod.Name, port, nil
+	}
+}
+
+// podReady returns whether the Pod is running and has the Ready condition.
+func podReady(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// portForwardTarget returns the name and port of the Pod that a portForward stream invoke forwards to. A Service is
+// resolved to one of its ready Pods, in the same way as `kubectl port-forward`.
+func (k *kubeProvider) portForwardTarget(ctx context.Context, opts *portForwardOptions) (string, int, error) {
+	if opts.kind == "Pod" {
+		return opts.name, opts.port, nil
+	}
+
+	serviceClient, err := k.clientSet.ResourceClient(corev1.SchemeGroupVersion.WithKind("Service"), opts.namespace)
+	if err != nil {
+		return "", 0, err
+	}
+	liveService, err := serviceClient.Get(ctx, opts.name, metav1.GetOptions{})
+	if err != nil {
+		return "", 0, err
+	}
+	var svc corev1.Service
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(liveService.Object, &svc); err != nil {
+		return "", 0, err
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return "", 0, fmt.Errorf("service %s/%s has no selector", svc.Namespace, svc.Name)
+	}
+
+	podClient, err := k.clientSet.ResourceClient(corev1.SchemeGroupVersion.WithKind("Pod"), opts.namespace)
+	if err != nil {
+		return "", 0, err
+	}
+	list, err := podClient.List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	pods := make([]corev1.Pod, len(list.Items))
+	for i, item := range list.Items {
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &pods[i]); err != nil {
+			return "", 0, err
+		}
+	}
+	return servicePodPort(&svc, pods, opts.port)
+}
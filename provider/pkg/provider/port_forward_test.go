@@ -0,0 +1,114 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestParsePortForwardOptions(t *testing.T) {
+	opts, err := parsePortForwardOptions(resource.NewPropertyMapFromMap(map[string]interface{}{
+		"name": "nginx",
+		"port": 80,
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, &portForwardOptions{kind: "Pod", name: "nginx", port: 80, address: "127.0.0.1"}, opts)
+
+	opts, err = parsePortForwardOptions(resource.NewPropertyMapFromMap(map[string]interface{}{
+		"namespace": "vault",
+		"kind":      "Service",
+		"name":      "vault",
+		"port":      8200,
+		"localPort": 18200,
+		"address":   "0.0.0.0",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, &portForwardOptions{
+		namespace: "vault", kind: "Service", name: "vault", port: 8200, localPort: 18200, address: "0.0.0.0",
+	}, opts)
+
+	for _, args := range []map[string]interface{}{
+		{"port": 80},
+		{"name": "nginx"},
+		{"name": "nginx", "port": 0},
+		{"name": "nginx", "port": 70000},
+		{"name": "nginx", "port": 80.5},
+		{"name": "nginx", "port": 80, "localPort": -1},
+		{"name": "nginx", "port": 80, "kind": "Deployment"},
+		{"name": "nginx", "port": "80"},
+	} {
+		_, err := parsePortForwardOptions(resource.NewPropertyMapFromMap(args))
+		assert.Error(t, err, args)
+	}
+}
+
+func TestServicePodPort(t *testing.T) {
+	pod := func(name string, ready bool) corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:  "app",
+				Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+			}}},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			},
+		}
+	}
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{
+			{Port: 80, TargetPort: intstr.FromString("http")},
+			{Port: 443, TargetPort: intstr.FromInt(8443)},
+			{Port: 9090},
+			{Port: 9091, TargetPort: intstr.FromString("metrics")},
+		}},
+	}
+	pods := []corev1.Pod{pod("app-1", false), pod("app-2", true)}
+
+	tests := []struct {
+		port    int
+		podPort int
+	}{
+		{80, 8080},
+		{443, 8443},
+		{9090, 9090},
+	}
+	for _, tt := range tests {
+		name, podPort, err := servicePodPort(svc, pods, tt.port)
+		require.NoError(t, err)
+		assert.Equal(t, "app-2", name)
+		assert.Equal(t, tt.podPort, podPort)
+	}
+
+	_, _, err := servicePodPort(svc, pods, 8080)
+	assert.Error(t, err)
+	_, _, err = servicePodPort(svc, pods, 9091)
+	assert.Error(t, err)
+	_, _, err = servicePodPort(svc, pods[:1], 80)
+	assert.Error(t, err)
+}
@@ -15,7 +15,6 @@
 package provider
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -28,8 +27,10 @@ import (
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	jsonpatch "github.com/evanphx/json-patch"
 	pbempty "github.com/golang/protobuf/ptypes/empty"
@@ -45,6 +46,7 @@ import (
 	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/logging"
 	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/metadata"
 	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/openapi"
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/policy"
 	"github.com/pulumi/pulumi/pkg/v3/resource/provider"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
@@ -61,12 +63,12 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
 	k8sresource "k8s.io/cli-runtime/pkg/resource"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	clientapi "k8s.io/client-go/tools/clientcmd/api"
 	k8sopenapi "k8s.io/kubectl/pkg/util/openapi"
-	"sigs.k8s.io/yaml"
 )
 
 // --------------------------------------------------------------------------
@@ -83,11 +85,19 @@ const (
 	streamInvokeList     = "kubernetes:kubernetes:list"
 	streamInvokeWatch    = "kubernetes:kubernetes:watch"
 	streamInvokePodLogs  = "kubernetes:kubernetes:podLogs"
+	streamInvokePortFwd  = "kubernetes:kubernetes:portForward"
+	streamInvokeEvents   = "kubernetes:kubernetes:events"
+	invokeGetObject      = "kubernetes:kubernetes:getObject"
+	invokeGetObjectIndex = "kubernetes:index:getObject"
 	invokeDecodeYaml     = "kubernetes:yaml:decode"
 	invokeHelmTemplate   = "kubernetes:helm:template"
 	invokeKustomize      = "kubernetes:kustomize:directory"
 	lastAppliedConfigKey = "kubectl.kubernetes.io/last-applied-configuration"
 	initialAPIVersionKey = "__initialApiVersion"
+
+	// defaultDiscoveryCacheTTL is how long discovery information that is cached on disk is used by default, the same
+	// as kubectl.
+	defaultDiscoveryCacheTTL = 10 * time.Minute
 )
 
 type cancellationContext struct {
@@ -118,9 +128,14 @@ type kubeProvider struct {
 	defaultNamespace string
 
 	enableDryRun                bool
+	enableDriftDetection        bool
 	enableSecrets               bool
 	suppressDeprecationWarnings bool
 	suppressHelmHookWarnings    bool
+	migrateAPIVersions          bool
+
+	// driftInBandManagers are the field managers whose changes are not reported as drift.
+	driftInBandManagers map[string]bool
 
 	suppressHelmReleaseBetaWarning bool
 	helmDriver                     string
@@ -129,9 +144,16 @@ type kubeProvider struct {
 	helmRepositoryConfigPath       string
 	helmRepositoryCache            string
 	helmReleaseProvider            customResourceProvider
+	podExecProvider                customResourceProvider
+
+	clientQPS            float32 // Requests per second shared by all clients, or 0 for the client-go default.
+	clientBurst          int     // Burst of requests shared by all clients, or 0 for the client-go default.
+	maxConcurrentWatches int     // Maximum number of open watches, or 0 for no limit.
+	discoveryCacheDir    string
+	discoveryCacheTTL    time.Duration // How long cached discovery information is used, or 0 for no on-disk cache.
 
 	yamlRenderMode bool
-	yamlDirectory  string
+	yamlRenderer   *yamlRenderer
 
 	clusterUnreachable       bool   // Kubernetes cluster is unreachable.
 	clusterUnreachableReason string // Detailed error message if cluster is unreachable.
@@ -141,10 +163,32 @@ type kubeProvider struct {
 	clientSet      *clients.DynamicClientSet
 	dryRunVerifier *k8sresource.DryRunVerifier
 	logClient      *clients.LogClient
-	k8sVersion     cluster.ServerVersion
+	podClient      *clients.PodClient
+	k8sVersion     cluster.ServerVersion // The version of the cluster; use serverVersion to look it up.
+	k8sVersionOnce sync.Once
+
+	// bundledSchema is the OpenAPI schema bundled for the configured `kubeVersion`, which is used in place of the
+	// schema from the API server if the cluster is unreachable.
+	bundledSchema *openapi.BundledSchema
+
+	// ignoreFields are the paths of fields that are excluded from diffs and updates, keyed by resource type.
+	ignoreFields map[schema.GroupVersionKind][]openapi.FieldPath
+
+	// policies are evaluated against every resource in Check.
+	policies policy.Policies
 
 	resources      k8sopenapi.Resources
 	resourcesMutex sync.RWMutex
+
+	// crds are the CRDs in the cluster of the custom resource kinds that have been validated, or nil for kinds without
+	// a CRD, and declaredCRDs are the CRDs that have been checked, created or updated by the provider.
+	crds         map[schema.GroupKind]*customResourceDefinition
+	declaredCRDs map[schema.GroupKind]*customResourceDefinition
+	crdsMutex    sync.Mutex
+
+	// dryRunResults are the results of the server-side dry runs of the latest Diff of each resource.
+	dryRunResults      map[resource.URN]dryRunResult
+	dryRunResultsMutex sync.Mutex
 }
 
 var _ pulumirpc.ResourceProviderServer = (*kubeProvider)(nil)
@@ -152,7 +196,7 @@ var _ pulumirpc.ResourceProviderServer = (*kubeProvider)(nil)
 func makeKubeProvider(
 	host *provider.HostClient, name, version string, pulumiSchema []byte,
 ) (pulumirpc.ResourceProviderServer, error) {
-	return &kubeProvider{
+	k := &kubeProvider{
 		host:                        host,
 		canceler:                    makeCancellationContext(),
 		name:                        name,
@@ -162,7 +206,9 @@ func makeKubeProvider(
 		enableDryRun:                false,
 		enableSecrets:               false,
 		suppressDeprecationWarnings: false,
-	}, nil
+	}
+	k.podExecProvider = &podExecProvider{k: k}
+	return k, nil
 }
 
 func (k *kubeProvider) getResources() (k8sopenapi.Resources, error) {
@@ -174,6 +220,10 @@ func (k *kubeProvider) getResources() (k8sopenapi.Resources, error) {
 		return rs, nil
 	}
 
+	if k.clusterUnreachable && k.bundledSchema != nil {
+		return k.bundledSchema.Resources, nil
+	}
+
 	k.resourcesMutex.Lock()
 	defer k.resourcesMutex.Unlock()
 
@@ -185,11 +235,43 @@ func (k *kubeProvider) getResources() (k8sopenapi.Resources, error) {
 	return k.resources, nil
 }
 
+// hasSchema returns true if an OpenAPI schema is available, either from the API server or bundled with the provider.
+func (k *kubeProvider) hasSchema() bool {
+	return !k.clusterUnreachable || k.bundledSchema != nil
+}
+
+// serverVersion returns the version of the cluster, or the configured `kubeVersion` if the cluster is unreachable. The
+// version is looked up the first time it's needed rather than in Configure, so that previews of programs that don't
+// need it don't wait for the API server.
+func (k *kubeProvider) serverVersion() cluster.ServerVersion {
+	k.k8sVersionOnce.Do(func() {
+		if !k.clusterUnreachable {
+			k.k8sVersion = cluster.TryGetServerVersion(k.clientSet.DiscoveryClientCached)
+		}
+	})
+	return k.k8sVersion
+}
+
+// isNamespacedKind returns whether the given kind is namespaced. If the cluster is unreachable, the scope of kinds
+// that are not known to the provider is looked up in the bundled schema.
+func (k *kubeProvider) isNamespacedKind(gvk schema.GroupVersionKind) (bool, error) {
+	if k.clusterUnreachable && k.bundledSchema != nil {
+		if known, namespaced := k.bundledSchema.IsNamespacedKind(gvk); known {
+			return namespaced, nil
+		}
+	}
+	return clients.IsNamespacedKind(gvk, k.clientSet)
+}
+
 func (k *kubeProvider) invalidateResources() {
 	k.resourcesMutex.Lock()
 	defer k.resourcesMutex.Unlock()
 
 	k.resources = nil
+
+	k.crdsMutex.Lock()
+	defer k.crdsMutex.Unlock()
+	k.crds = nil
 }
 
 // Call dynamically executes a method in the provider associated with a component resource.
@@ -241,6 +323,35 @@ func (k *kubeProvider) CheckConfig(ctx context.Context, req *pulumirpc.CheckRequ
 		return false
 	}
 
+	// Config that is not set is taken from environment variables, whose values are validated in the same way.
+	config := withConfigInputsEnv(news)
+
+	if kubeVersion := config["kubeVersion"]; kubeVersion.IsString() && kubeVersion.StringValue() != "" {
+		if _, err := cluster.ParseServerVersion(kubeVersion.StringValue()); err != nil {
+			return &pulumirpc.CheckResponse{Inputs: req.GetNews(), Failures: []*pulumirpc.CheckFailure{{
+				Property: "kubeVersion",
+				Reason:   err.Error(),
+			}}}, nil
+		}
+	}
+
+	if ignoreFields := config["ignoreFields"]; ignoreFields.IsString() && ignoreFields.StringValue() != "" {
+		if _, err := parseIgnoreFields(ignoreFields.StringValue()); err != nil {
+			return &pulumirpc.CheckResponse{Inputs: req.GetNews(), Failures: []*pulumirpc.CheckFailure{{
+				Property: "ignoreFields",
+				Reason:   err.Error(),
+			}}}, nil
+		}
+	}
+
+	if failures := checkImpersonation(config); len(failures) > 0 {
+		return &pulumirpc.CheckResponse{Inputs: req.GetNews(), Failures: failures}, nil
+	}
+
+	if failures := checkDirectCredentials(config); len(failures) > 0 {
+		return &pulumirpc.CheckResponse{Inputs: req.GetNews(), Failures: failures}, nil
+	}
+
 	renderYamlEnabled := truthyValue("renderYamlToDirectory", news)
 
 	errTemplate := `%q arg is not compatible with "renderYamlToDirectory" arg`
@@ -265,6 +376,12 @@ func (k *kubeProvider) CheckConfig(ctx context.Context, req *pulumirpc.CheckRequ
 				Reason:   fmt.Sprintf(errTemplate, "kubeconfig"),
 			})
 		}
+		if truthyValue("server", news) {
+			failures = append(failures, &pulumirpc.CheckFailure{
+				Property: "server",
+				Reason:   fmt.Sprintf(errTemplate, "server"),
+			})
+		}
 		if truthyValue("enableDryRun", news) {
 			failures = append(failures, &pulumirpc.CheckFailure{
 				Property: "enableDryRun",
@@ -272,11 +389,84 @@ func (k *kubeProvider) CheckConfig(ctx context.Context, req *pulumirpc.CheckRequ
 			})
 		}
 
+		if layout := config["renderYamlLayout"]; layout.IsString() {
+			if _, err := parseRenderLayout(layout.StringValue()); err != nil {
+				failures = append(failures, &pulumirpc.CheckFailure{
+					Property: "renderYamlLayout",
+					Reason:   err.Error(),
+				})
+			}
+		}
+
+		if unknowns := config["renderYamlUnknowns"]; unknowns.IsString() {
+			if _, err := parseRenderUnknowns(unknowns.StringValue()); err != nil {
+				failures = append(failures, &pulumirpc.CheckFailure{
+					Property: "renderYamlUnknowns",
+					Reason:   err.Error(),
+				})
+			}
+		}
+
+		if secrets := config["renderYamlSecrets"]; secrets.IsString() {
+			mode, err := parseRenderSecrets(secrets.StringValue())
+			if err != nil {
+				failures = append(failures, &pulumirpc.CheckFailure{
+					Property: "renderYamlSecrets",
+					Reason:   err.Error(),
+				})
+			} else {
+				var key string
+				if config["renderYamlSecretsKey"].IsString() {
+					key = config["renderYamlSecretsKey"].StringValue()
+				}
+				if _, err = newSecretEncrypter(mode, key); err != nil {
+					failures = append(failures, &pulumirpc.CheckFailure{
+						Property: "renderYamlSecretsKey",
+						Reason:   err.Error(),
+					})
+				}
+
+				// SOPS metadata and its MAC cover a whole file, so each encrypted Secret needs a file of its own.
+				layout := config["renderYamlLayout"]
+				if mode == renderSecretsSops && layout.IsString() && layout.StringValue() == string(renderLayoutSingle) {
+					failures = append(failures, &pulumirpc.CheckFailure{
+						Property: "renderYamlSecrets",
+						Reason: fmt.Sprintf("%q secrets are not compatible with the %q render layout",
+							renderSecretsSops, renderLayoutSingle),
+					})
+				}
+			}
+		}
+
 		if len(failures) > 0 {
 			return &pulumirpc.CheckResponse{Inputs: req.GetNews(), Failures: failures}, nil
 		}
 	}
 
+	// Direct credentials are secrets even if they are not marked as such by the program.
+	secrets, err := plugin.UnmarshalProperties(req.GetNews(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.news", label),
+		KeepUnknowns: true,
+		KeepSecrets:  true,
+		SkipNulls:    true,
+		RejectAssets: true,
+	})
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "CheckConfig failed because of malformed resource inputs")
+	}
+	if markDirectCredentialSecrets(secrets) {
+		inputs, err := plugin.MarshalProperties(secrets, plugin.MarshalOptions{
+			Label:        fmt.Sprintf("%s.inputs", label),
+			KeepUnknowns: true,
+			KeepSecrets:  true,
+			SkipNulls:    true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &pulumirpc.CheckResponse{Inputs: inputs}, nil
+	}
+
 	return &pulumirpc.CheckResponse{Inputs: req.GetNews()}, nil
 }
 
@@ -304,74 +494,24 @@ func (k *kubeProvider) DiffConfig(ctx context.Context, req *pulumirpc.DiffReques
 		return nil, pkgerrors.Wrapf(err, "DiffConfig failed because of malformed resource inputs")
 	}
 
-	// We can't tell for sure if a computed value has changed, so we make the conservative choice
-	// and force a replacement.
-	if news["kubeconfig"].IsComputed() {
-		return &pulumirpc.DiffResponse{
-			Changes:  pulumirpc.DiffResponse_DIFF_SOME,
-			Diffs:    []string{"kubeconfig"},
-			Replaces: []string{"kubeconfig"},
-		}, nil
-	}
-
-	var diffs, replaces []string
-
-	oldConfig, err := parseKubeconfigPropertyValue(olds["kubeconfig"])
-	if err != nil {
-		return nil, err
-	}
-	newConfig, err := parseKubeconfigPropertyValue(news["kubeconfig"])
+	d, err := diffProviderConfig(olds, news)
 	if err != nil {
 		return nil, err
 	}
+	logger.V(7).Infof("%s: diffs %v / replaces %v", label, d.diffs, d.replaces)
 
-	// Check for differences in provider overrides.
-	if !reflect.DeepEqual(oldConfig, newConfig) {
-		diffs = append(diffs, "kubeconfig")
-	}
-	if olds["context"] != news["context"] {
-		diffs = append(diffs, "context")
-	}
-	if olds["cluster"] != news["cluster"] {
-		diffs = append(diffs, "cluster")
-	}
-	if olds["namespace"] != news["namespace"] {
-		diffs = append(diffs, "namespace")
-	}
-	if olds["enableDryRun"] != news["enableDryRun"] {
-		diffs = append(diffs, "enableDryRun")
-	}
-	if olds["renderYamlToDirectory"] != news["renderYamlToDirectory"] {
-		diffs = append(diffs, "renderYamlToDirectory")
-
-		// If the render directory changes, all of the manifests will be replaced.
-		replaces = append(replaces, "renderYamlToDirectory")
-	}
-
-	// In general, it's not possible to tell from a kubeconfig if the k8s cluster it points to has
-	// changed. k8s clusters do not have a well defined identity, so the best we can do is check
-	// if the settings for the active cluster have changed. This is not a foolproof method; a trivial
-	// counterexample is changing the load balancer or DNS entry pointing to the same cluster.
-	//
-	// Given this limitation, we try to strike a reasonable balance by planning a replacement iff
-	// the active cluster in the kubeconfig changes. This could still plan an erroneous replacement,
-	// but should work for the majority of cases.
-	//
-	// The alternative of ignoring changes to the kubeconfig is untenable; if the k8s cluster has
-	// changed, any dependent resources must be recreated, and ignoring changes prevents that from
-	// happening.
-	oldActiveCluster := getActiveClusterFromConfig(oldConfig, olds)
-	activeCluster := getActiveClusterFromConfig(newConfig, news)
-	if !reflect.DeepEqual(oldActiveCluster, activeCluster) {
-		replaces = diffs
-	}
-	logger.V(7).Infof("%s: diffs %v / replaces %v", label, diffs, replaces)
-
-	if len(diffs) > 0 || len(replaces) > 0 {
+	if len(d.diffs) > 0 || len(d.replaces) > 0 {
+		// Explain whether the resources of the provider will be replaced, since the diff of the provider alone
+		// does not show why its resources are or are not replaced.
+		if k.host != nil {
+			_ = k.host.Log(ctx, diag.Info, urn, d.reason)
+		}
 		return &pulumirpc.DiffResponse{
-			Changes:  pulumirpc.DiffResponse_DIFF_SOME,
-			Diffs:    diffs,
-			Replaces: replaces,
+			Changes:         pulumirpc.DiffResponse_DIFF_SOME,
+			Diffs:           d.diffs,
+			Replaces:        d.replaces,
+			DetailedDiff:    d.detailedDiff(olds, news),
+			HasDetailedDiff: true,
 		}, nil
 	}
 
@@ -381,10 +521,11 @@ func (k *kubeProvider) DiffConfig(ctx context.Context, req *pulumirpc.DiffReques
 }
 
 // Configure configures the resource provider with "globals" that control its behavior.
-func (k *kubeProvider) Configure(_ context.Context, req *pulumirpc.ConfigureRequest) (*pulumirpc.ConfigureResponse, error) {
+func (k *kubeProvider) Configure(ctx context.Context, req *pulumirpc.ConfigureRequest) (*pulumirpc.ConfigureResponse, error) {
 	const trueStr = "true"
 
-	vars := req.GetVariables()
+	// Config that is not set is taken from environment variables.
+	vars := withConfigEnv(req.GetVariables())
 
 	//
 	// Set simple configuration settings.
@@ -407,137 +548,131 @@ func (k *kubeProvider) Configure(_ context.Context, req *pulumirpc.ConfigureRequ
 		CurrentContext: vars["kubernetes:config:context"],
 	}
 
-	enableDryRun := func() bool {
-		// If the provider flag is set, use that value to determine behavior. This will override the ENV var.
-		if enabled, exists := vars["kubernetes:config:enableDryRun"]; exists {
-			return enabled == trueStr
-		}
-		// If the provider flag is not set, fall back to the ENV var.
-		if enabled, exists := os.LookupEnv("PULUMI_K8S_ENABLE_DRY_RUN"); exists {
-			return enabled == trueStr
+	var impersonate *impersonation
+	if value := vars["kubernetes:config:impersonate"]; value != "" {
+		var err error
+		if impersonate, err = parseImpersonation(value); err != nil {
+			return nil, err
 		}
-		// Default to false.
-		return false
-	}
-	if enableDryRun() {
-		k.enableDryRun = true
+		impersonate.apply(&overrides.AuthInfo)
 	}
 
-	suppressDeprecationWarnings := func() bool {
-		// If the provider flag is set, use that value to determine behavior. This will override the ENV var.
-		if enabled, exists := vars["kubernetes:config:suppressDeprecationWarnings"]; exists {
-			return enabled == trueStr
-		}
-		// If the provider flag is not set, fall back to the ENV var.
-		if enabled, exists := os.LookupEnv("PULUMI_K8S_SUPPRESS_DEPRECATION_WARNINGS"); exists {
-			return enabled == trueStr
-		}
-		// Default to false.
-		return false
+	configValue := func(key string) (string, bool) {
+		value, exists := vars["kubernetes:config:"+key]
+		return value, exists
 	}
-	if suppressDeprecationWarnings() {
-		k.suppressDeprecationWarnings = true
+	configBool := func(key string) bool {
+		value, _ := configValue(key)
+		return value == trueStr
 	}
-
-	suppressHelmHookWarnings := func() bool {
-		// If the provider flag is set, use that value to determine behavior. This will override the ENV var.
-		if enabled, exists := vars["kubernetes:config:suppressHelmHookWarnings"]; exists {
-			return enabled == trueStr
-		}
-		// If the provider flag is not set, fall back to the ENV var.
-		if enabled, exists := os.LookupEnv("PULUMI_K8S_SUPPRESS_HELM_HOOK_WARNINGS"); exists {
-			return enabled == trueStr
+	configString := func(key, defaultValue string) string {
+		if value, exists := configValue(key); exists {
+			return value
 		}
-		// Default to false.
-		return false
-	}
-	if suppressHelmHookWarnings() {
-		k.suppressHelmHookWarnings = true
+		return defaultValue
 	}
 
-	renderYamlToDirectory := func() string {
-		// Read the config from the Provider.
-		if directory, exists := vars["kubernetes:config:renderYamlToDirectory"]; exists && directory != "" {
-			return directory
-		}
-		return ""
+	k.enableDryRun = configBool("enableDryRun")
+	k.enableDriftDetection = configBool("enableDriftDetection")
+	k.driftInBandManagers = map[string]bool{providerFieldManager(): true}
+	ignoredManagers := defaultDriftIgnoredFieldManagers
+	if config := configString("driftIgnoredFieldManagers", ""); config != "" {
+		ignoredManagers = strings.Split(config, ",")
 	}
-	k.yamlDirectory = renderYamlToDirectory()
-	k.yamlRenderMode = len(k.yamlDirectory) > 0
-
-	suppressHelmReleaseBetaWarning := func() bool {
-		if disabled, exists := vars["kubernetes:config:suppressHelmReleaseBetaWarning"]; exists {
-			return disabled == trueStr
-		}
-
-		// If the provider flag is not set, fall back to the ENV var.
-		if disabled, exists := os.LookupEnv("PULUMI_K8S_SUPPRESS_HELM_RELEASE_BETA_WARNING"); exists {
-			return disabled == trueStr
+	for _, manager := range ignoredManagers {
+		if manager = strings.TrimSpace(manager); manager != "" {
+			k.driftInBandManagers[manager] = true
 		}
-		return false
 	}
-	k.suppressHelmReleaseBetaWarning = suppressHelmReleaseBetaWarning()
 
-	helmDriver := func() string {
-		if driver, exists := vars["kubernetes:config:helmDriver"]; exists {
-			return driver
-		}
-		// If the provider flag is not set, fall back to the ENV var.
-		if driver, exists := os.LookupEnv("PULUMI_K8S_HELM_DRIVER"); exists {
-			return driver
+	if config := configString("ignoreFields", ""); config != "" {
+		parsed, err := parseIgnoreFields(config)
+		if err != nil {
+			return nil, err
 		}
-
-		return "secret"
+		k.ignoreFields = parsed
 	}
-	k.helmDriver = helmDriver() // TODO: Make sure this is in provider state
 
-	helmPluginsPath := func() string {
-		if pluginsPath, exists := vars["kubernetes:config:helmPluginsPath"]; exists {
-			return pluginsPath
+	k.suppressDeprecationWarnings = configBool("suppressDeprecationWarnings")
+	k.migrateAPIVersions = configBool("migrateApiVersions")
+	k.suppressHelmHookWarnings = configBool("suppressHelmHookWarnings")
+
+	yamlDirectory := configString("renderYamlToDirectory", "")
+	k.yamlRenderMode = len(yamlDirectory) > 0
+	if k.yamlRenderMode {
+		layout, err := parseRenderLayout(configString("renderYamlLayout", ""))
+		if err != nil {
+			return nil, err
 		}
-		// If the provider flag is not set, fall back to the ENV var.
-		if pluginsPath, exists := os.LookupEnv("PULUMI_K8S_HELM_PLUGINS_PATH"); exists {
-			return pluginsPath
+		unknowns, err := parseRenderUnknowns(configString("renderYamlUnknowns", ""))
+		if err != nil {
+			return nil, err
 		}
-		return helmpath.DataPath("plugins")
-	}
-	k.helmPluginsPath = helmPluginsPath()
-
-	helmRegistryConfigPath := func() string {
-		if registryPath, exists := vars["kubernetes:config:helmRegistryConfigPath"]; exists {
-			return registryPath
+		secretsMode, err := parseRenderSecrets(configString("renderYamlSecrets", ""))
+		if err != nil {
+			return nil, err
 		}
-		// If the provider flag is not set, fall back to the ENV var.
-		if registryPath, exists := os.LookupEnv("PULUMI_K8S_HELM_REGISTRY_CONFIG_PATH"); exists {
-			return registryPath
+		secrets, err := newSecretEncrypter(secretsMode, configString("renderYamlSecretsKey", ""))
+		if err != nil {
+			return nil, err
+		}
+		k.yamlRenderer = &yamlRenderer{
+			directory: yamlDirectory,
+			layout:    layout,
+			clean:     configBool("renderYamlClean"),
+			unknowns:  unknowns,
+			secrets:   secrets,
+		}
+	}
+
+	k.suppressHelmReleaseBetaWarning = configBool("suppressHelmReleaseBetaWarning")
+	k.helmDriver = configString("helmDriver", "secret") // TODO: Make sure this is in provider state
+	k.helmPluginsPath = configString("helmPluginsPath", helmpath.DataPath("plugins"))
+	k.helmRegistryConfigPath = configString("helmRegistryConfigPath", helmpath.ConfigPath("registry.json"))
+	k.helmRepositoryConfigPath = configString("helmRepositoryConfigPath", helmpath.ConfigPath("repositories.yaml"))
+	k.helmRepositoryCache = configString("helmRepositoryCache", helmpath.CachePath("repository"))
+
+	if limit := configString("clientQps", ""); limit != "" {
+		qps, err := strconv.ParseFloat(limit, 32)
+		if err != nil || qps <= 0 {
+			return nil, fmt.Errorf("invalid clientQps %q: must be a positive number", limit)
+		}
+		k.clientQPS = float32(qps)
+	}
+	for _, limit := range []struct {
+		key   string
+		value *int
+	}{
+		{"clientBurst", &k.clientBurst},
+		{"maxConcurrentWatches", &k.maxConcurrentWatches},
+	} {
+		if v := configString(limit.key, ""); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid %s %q: must be a positive integer", limit.key, v)
+			}
+			*limit.value = n
 		}
-		return helmpath.ConfigPath("registry.json")
 	}
-	k.helmRegistryConfigPath = helmRegistryConfigPath()
 
-	helmRepositoryConfigPath := func() string {
-		if repositoryConfigPath, exists := vars["kubernetes:config:helmRepositoryConfigPath"]; exists {
-			return repositoryConfigPath
-		}
+	k.discoveryCacheDir = configString("discoveryCacheDir", "")
 
-		if repositoryConfigPath, exists := os.LookupEnv("PULUMI_K8S_HELM_REPOSITORY_CONFIG_PATH"); exists {
-			return repositoryConfigPath
+	k.discoveryCacheTTL = defaultDiscoveryCacheTTL
+	if ttl := configString("discoveryCacheTtl", ""); ttl != "" {
+		seconds, err := strconv.Atoi(ttl)
+		if err != nil || seconds < 0 {
+			return nil, fmt.Errorf("invalid discoveryCacheTtl %q: must be a number of seconds", ttl)
 		}
-		return helmpath.ConfigPath("repositories.yaml")
+		k.discoveryCacheTTL = time.Duration(seconds) * time.Second
 	}
-	k.helmRepositoryConfigPath = helmRepositoryConfigPath()
-
-	helmRepositoryCache := func() string {
-		if repositoryCache, exists := vars["kubernetes:config:helmRepositoryCache"]; exists {
-			return repositoryCache
-		}
 
-		if repositoryCache, exists := os.LookupEnv("PULUMI_K8s_HELM_REPOSITORY_CACHE"); exists {
-			return repositoryCache
+	if dir := configString("policyDirectory", ""); dir != "" {
+		policies, err := policy.LoadDirectory(dir)
+		if err != nil {
+			return nil, fmt.Errorf("invalid policyDirectory: %v", err)
 		}
-		return helmpath.CachePath("repository")
+		k.policies = policies
 	}
-	k.helmRepositoryCache = helmRepositoryCache()
 
 	// Rather than erroring out on an invalid k8s config, mark the cluster as unreachable and conditionally bail out on
 	// operations that require a valid cluster. This will allow us to perform invoke operations using the default
@@ -554,7 +689,16 @@ func (k *kubeProvider) Configure(_ context.Context, req *pulumirpc.ConfigureRequ
 		usr, _ := user.Current()
 		return usr.HomeDir
 	}
-	if pathOrContents, ok := vars["kubernetes:config:kubeconfig"]; ok {
+	if _, ok := vars["kubernetes:config:server"]; ok {
+		// Direct credentials describe the cluster completely, so the ambient kubeconfig is not loaded.
+		config, err := directCredentialsKubeconfig(vars)
+		if err != nil {
+			k.clusterUnreachable = true
+			k.clusterUnreachableReason = fmt.Sprintf("invalid cluster credentials in provider config: %v", err)
+		} else {
+			kubeconfig = clientcmd.NewDefaultClientConfig(*config, &clientcmd.ConfigOverrides{AuthInfo: overrides.AuthInfo})
+		}
+	} else if pathOrContents, ok := vars["kubernetes:config:kubeconfig"]; ok {
 		var contents string
 
 		// Handle the '~' character if it is set in the config string. Normally, this would be expanded by the shell
@@ -610,9 +754,20 @@ func (k *kubeProvider) Configure(_ context.Context, req *pulumirpc.ConfigureRequ
 			k.clusterUnreachable = true
 			k.clusterUnreachableReason = fmt.Sprintf(
 				"unable to load Kubernetes client configuration from kubeconfig file: %v", err)
+		} else if config, err = clients.ShareCredentials(config); err != nil {
+			k.clusterUnreachable = true
+			k.clusterUnreachableReason = fmt.Sprintf(
+				"unable to obtain credentials for the Kubernetes cluster: %v", err)
 		} else {
 			warningConfig := rest.CopyConfig(config)
 			warningConfig.WarningHandler = rest.NoWarnings{}
+			if impersonate != nil && impersonate.UID != "" {
+				warningConfig = clients.ImpersonateUID(warningConfig, impersonate.UID)
+			}
+			if k.clientQPS != 0 || k.clientBurst != 0 {
+				// Every client is built from this config, including the Helm clients, so they all share the limit.
+				warningConfig = clients.LimitRate(warningConfig, k.clientQPS, k.clientBurst)
+			}
 			k.config = warningConfig
 			k.kubeconfig = kubeconfig
 
@@ -639,9 +794,23 @@ func (k *kubeProvider) Configure(_ context.Context, req *pulumirpc.ConfigureRequ
 
 	// These operations require a reachable cluster.
 	if !k.clusterUnreachable {
-		cs, err := clients.NewDynamicClientSet(k.config)
-		if err != nil {
-			return nil, err
+		var cs *clients.DynamicClientSet
+		if k.discoveryCacheDir != "" && k.discoveryCacheTTL > 0 {
+			var err error
+			cs, err = clients.NewDynamicClientSetWithDiskCache(k.config, k.discoveryCacheDir, k.discoveryCacheTTL)
+			if err != nil {
+				// Fall back to discovery without the on-disk cache, which reports errors when it's used.
+				logger.V(3).Infof("unable to use the discovery cache in %q: %v", k.discoveryCacheDir, err)
+			}
+		}
+		if cs == nil {
+			var err error
+			if cs, err = clients.NewDynamicClientSet(k.config); err != nil {
+				return nil, err
+			}
+		}
+		if k.maxConcurrentWatches != 0 {
+			cs.GenericClient = clients.LimitWatches(cs.GenericClient, k.maxConcurrentWatches)
 		}
 		k.clientSet = cs
 		k.dryRunVerifier = k8sresource.NewDryRunVerifier(cs.GenericClient, cs.DiscoveryClientCached)
@@ -650,8 +819,11 @@ func (k *kubeProvider) Configure(_ context.Context, req *pulumirpc.ConfigureRequ
 			return nil, err
 		}
 		k.logClient = lc
-
-		k.k8sVersion = cluster.TryGetServerVersion(cs.DiscoveryClientCached)
+		pc, err := clients.NewPodClient(k.config)
+		if err != nil {
+			return nil, err
+		}
+		k.podClient = pc
 
 		if _, err = k.getResources(); err != nil {
 			k.clusterUnreachable = true
@@ -660,6 +832,26 @@ func (k *kubeProvider) Configure(_ context.Context, req *pulumirpc.ConfigureRequ
 		}
 	}
 
+	// If the cluster is unreachable, use the OpenAPI schema bundled for the configured Kubernetes version so that
+	// resources can still be validated, e.g., in render mode or in CI.
+	if version := configString("kubeVersion", ""); version != "" && k.clusterUnreachable {
+		serverVersion, err := cluster.ParseServerVersion(version)
+		if err != nil {
+			return nil, err
+		}
+		k.k8sVersionOnce.Do(func() { k.k8sVersion = serverVersion })
+		k.bundledSchema, err = openapi.LoadBundledSchema(serverVersion)
+		if err != nil {
+			return nil, err
+		}
+		if k.bundledSchema.Version != serverVersion && k.host != nil {
+			_ = k.host.Log(ctx, diag.Warning, "", fmt.Sprintf(
+				"no OpenAPI schema is bundled for Kubernetes %s; resources are validated against the schema of "+
+					"Kubernetes %s instead, which may not match the resource types of the cluster",
+				serverVersion, k.bundledSchema.Version))
+		}
+	}
+
 	return &pulumirpc.ConfigureResponse{
 		AcceptSecrets:   true,
 		SupportsPreview: true,
@@ -683,6 +875,26 @@ func (k *kubeProvider) Invoke(ctx context.Context,
 	}
 
 	switch tok {
+	// The SDKs declare their functions in the index module, so getObject is also invoked with the token of the index
+	// module rather than the kubernetes module of the stream invokes.
+	case invokeGetObject, invokeGetObjectIndex:
+		if k.clusterUnreachable {
+			return nil, fmt.Errorf("configured Kubernetes cluster is unreachable: %s", k.clusterUnreachableReason)
+		}
+
+		live, err := k.getObject(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+
+		objProps, err := plugin.MarshalProperties(getObjectResult(live), plugin.MarshalOptions{
+			Label: label, KeepUnknowns: true, SkipNulls: true, KeepSecrets: k.enableSecrets,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &pulumirpc.InvokeResponse{Return: objProps}, nil
 	case invokeDecodeYaml:
 		var text, defaultNamespace string
 		if textArg := args["text"]; textArg.HasValue() && textArg.IsString() {
@@ -839,24 +1051,22 @@ func (k *kubeProvider) StreamInvoke(
 			return err
 		}
 
-		list, err := cl.List(context.TODO(), metav1.ListOptions{})
+		opts, err := listOptions(args)
 		if err != nil {
 			return err
 		}
 
 		//
-		// List resources. Send them one-by-one, asynchronously, to the client requesting them.
+		// List resources a page at a time. Send them one-by-one, asynchronously, to the client
+		// requesting them.
 		//
 
+		ctx, cancel := context.WithCancel(k.canceler.context)
+		defer cancel()
 		objects := make(chan map[string]interface{})
-		defer close(objects)
-		done := make(chan struct{})
-		defer close(done)
+		done := make(chan error, 1)
 		go func() {
-			for _, o := range list.Items {
-				objects <- o.Object
-			}
-			done <- struct{}{}
+			done <- listPages(ctx, cl, opts, objects)
 		}()
 
 		for {
@@ -868,12 +1078,12 @@ func (k *kubeProvider) StreamInvoke(
 				//
 
 				return nil
-			case <-done:
+			case err := <-done:
 				//
-				// Success. Return.
+				// Complete. Return the error if applicable.
 				//
 
-				return nil
+				return err
 			case o := <-objects:
 				//
 				// Publish resource from the list back to user.
@@ -933,112 +1143,351 @@ func (k *kubeProvider) StreamInvoke(
 			return err
 		}
 
-		watch, err := cl.Watch(context.TODO(), metav1.ListOptions{})
-		if err != nil {
+		opts, sendBookmarks, err := watchOptions(args)
+		if err != nil {
+			return err
+		}
+
+		//
+		// Watch for resource updates, and stream them back to the caller. The watch is resumed if the API
+		// server closes it.
+		//
+
+		ctx, cancel := context.WithCancel(k.canceler.context)
+		defer cancel()
+		events := make(chan map[string]interface{})
+		done := make(chan error, 1)
+		go func() {
+			done <- newResourceWatcher(cl, opts, sendBookmarks).run(ctx, events)
+		}()
+
+		for {
+			select {
+			case <-k.canceler.context.Done():
+				//
+				// `kubeProvider#Cancel` was called. Terminate the `StreamInvoke` RPC, free all
+				// resources, and exit without error.
+				//
+
+				return nil
+			case err := <-done:
+				//
+				// The watch failed. Return the error.
+				//
+
+				return err
+			case event := <-events:
+				//
+				// Kubernetes resource was updated. Publish resource update back to user.
+				//
+
+				resp, err := plugin.MarshalProperties(
+					resource.NewPropertyMapFromMap(event),
+					plugin.MarshalOptions{})
+				if err != nil {
+					return err
+				}
+
+				err = server.Send(&pulumirpc.InvokeResponse{Return: resp})
+				if err != nil {
+					return err
+				}
+			case <-server.Context().Done():
+				//
+				// gRPC stream was cancelled from the client that issued the `StreamInvoke` request
+				// to us. In this case, we terminate the `StreamInvoke` RPC, free all resources, and
+				// exit without error.
+				//
+				// Usually, this happens in the language provider, e.g., in the call to `cancel`
+				// below.
+				//
+				//     const deployments = await streamInvoke("kubernetes:kubernetes:watch", {
+				//         group: "apps", version: "v1", kind: "Deployment",
+				//     });
+				//     deployments.cancel();
+				//
+
+				return nil
+			}
+		}
+	case streamInvokePodLogs:
+		//
+		// Set up log stream for Pod.
+		//
+
+		if k.clusterUnreachable {
+			return fmt.Errorf("configured Kubernetes cluster is unreachable: %s", k.clusterUnreachableReason)
+		}
+
+		namespace, name, labelSelector, err := podLogsTarget(args)
+		if err != nil {
+			return err
+		}
+		opts, err := podLogOptions(args)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithCancel(k.canceler.context)
+		defer cancel()
+
+		//
+		// Enumerate logs by line, and send them back to the user in batches.
+		//
+
+		batches := make(chan podLogBatch)
+		done := make(chan error)
+		remaining := 0
+		streamLogs := func(pod string) {
+			remaining++
+			go func() {
+				podLogs, err := k.logClient.Logs(ctx, namespace, pod, opts)
+				if err == nil {
+					defer podLogs.Close()
+					err = batchLogLines(ctx, pod, podLogs, batches)
+				}
+				select {
+				case done <- err:
+				case <-ctx.Done():
+				}
+			}()
+		}
+
+		// Stream the logs of the named Pod, or multiplex the logs of the Pods that match the label selector. If the
+		// logs are followed, the Pods are watched, so that the logs of Pods that match the selector later are streamed
+		// as well, and the stream only ends when it is cancelled.
+		var pods <-chan string
+		switch {
+		case name != "":
+			streamLogs(name)
+		case opts.Follow:
+			if pods, err = k.watchLogPods(ctx, namespace, labelSelector); err != nil {
+				return err
+			}
+		default:
+			names, err := k.logClient.PodNames(ctx, namespace, labelSelector)
+			if err != nil {
+				return err
+			}
+			if len(names) == 0 {
+				return nil
+			}
+			for _, pod := range names {
+				streamLogs(pod)
+			}
+		}
+
+		for {
+			select {
+			case <-k.canceler.context.Done():
+				//
+				// `kubeProvider#Cancel` was called. Terminate the `StreamInvoke` RPC, free all
+				// resources, and exit without error.
+				//
+
+				return nil
+			case pod := <-pods:
+				//
+				// A Pod that matches the label selector has started. Stream its logs.
+				//
+
+				streamLogs(pod)
+			case err := <-done:
+				//
+				// The logs of a Pod are complete. Return the error if applicable, or once the logs of all
+				// Pods are complete, unless more Pods are watched.
+				//
+
+				remaining--
+				if err != nil || (remaining == 0 && pods == nil) {
+					return err
+				}
+			case batch := <-batches:
+				//
+				// Publish a batch of log lines back to user.
+				//
+
+				resp, err := plugin.MarshalProperties(
+					resource.NewPropertyMapFromMap(
+						map[string]interface{}{"pod": batch.pod, "lines": batch.lines}),
+					plugin.MarshalOptions{})
+				if err != nil {
+					return err
+				}
+
+				err = server.Send(&pulumirpc.InvokeResponse{Return: resp})
+				if err != nil {
+					return err
+				}
+			case <-server.Context().Done():
+				//
+				// gRPC stream was cancelled from the client that issued the `StreamInvoke` request
+				// to us. In this case, we terminate the `StreamInvoke` RPC, free all resources, and
+				// exit without error.
+				//
+				// Usually, this happens in the language provider, e.g., in the call to `cancel`
+				// below.
+				//
+				//     const podLogLines = await streamInvoke("kubernetes:kubernetes:podLogs", {
+				//         namespace: "default", name: "nginx-f94d8bc55-xftvs",
+				//     });
+				//     podLogLines.cancel();
+				//
+
+				return nil
+			}
+		}
+	case streamInvokePortFwd:
+		//
+		// Forward a local port to a Pod, or to a ready Pod of a Service.
+		//
+
+		if k.clusterUnreachable {
+			return fmt.Errorf("configured Kubernetes cluster is unreachable: %s", k.clusterUnreachableReason)
+		}
+
+		opts, err := parsePortForwardOptions(args)
+		if err != nil {
+			return err
+		}
+		if opts.namespace == "" {
+			opts.namespace = "default"
+			if k.defaultNamespace != "" {
+				opts.namespace = k.defaultNamespace
+			}
+		}
+
+		// Forwarding stops when either `kubeProvider#Cancel` is called or the gRPC stream is cancelled.
+		ctx, cancel := context.WithCancel(server.Context())
+		defer cancel()
+		go func() {
+			select {
+			case <-k.canceler.context.Done():
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		pod, port, err := k.portForwardTarget(ctx, opts)
+		if err != nil {
+			return err
+		}
+
+		ready := make(chan int)
+		done := make(chan error, 1)
+		go func() {
+			done <- k.podClient.PortForward(ctx, opts.namespace, pod, opts.address, opts.localPort, port, ready)
+		}()
+		// stopForwarding stops forwarding and waits for the local listener to be closed, so that the local port is
+		// free once the `StreamInvoke` RPC returns.
+		stopForwarding := func(err error) error {
+			cancel()
+			<-done
 			return err
 		}
 
-		//
-		// Watch for resource updates, and stream them back to the caller.
-		//
-
 		for {
 			select {
 			case <-k.canceler.context.Done():
 				//
-				// `kubeProvider#Cancel` was called. Terminate the `StreamInvoke` RPC, free all
-				// resources, and exit without error.
+				// `kubeProvider#Cancel` was called. Terminate the `StreamInvoke` RPC, stop forwarding,
+				// and exit without error.
 				//
 
-				watch.Stop()
-				return nil
-			case event := <-watch.ResultChan():
+				return stopForwarding(nil)
+			case err := <-done:
 				//
-				// Kubernetes resource was updated. Publish resource update back to user.
+				// The connection to the Pod failed or was lost.
+				//
+
+				return err
+			case localPort := <-ready:
+				//
+				// The local port is listening. Publish its address back to the user, and keep
+				// forwarding until the stream is cancelled.
 				//
 
 				resp, err := plugin.MarshalProperties(
-					resource.NewPropertyMapFromMap(
-						map[string]interface{}{
-							"type":   event.Type,
-							"object": event.Object.(*unstructured.Unstructured).Object,
-						}),
+					resource.NewPropertyMapFromMap(map[string]interface{}{
+						"address":   opts.address,
+						"localPort": localPort,
+						"pod":       pod,
+						"port":      port,
+					}),
 					plugin.MarshalOptions{})
 				if err != nil {
-					return err
+					return stopForwarding(err)
 				}
 
 				err = server.Send(&pulumirpc.InvokeResponse{Return: resp})
 				if err != nil {
-					return err
+					return stopForwarding(err)
 				}
 			case <-server.Context().Done():
 				//
 				// gRPC stream was cancelled from the client that issued the `StreamInvoke` request
-				// to us. In this case, we terminate the `StreamInvoke` RPC, free all resources, and
+				// to us. In this case, we terminate the `StreamInvoke` RPC, stop forwarding, and
 				// exit without error.
 				//
 				// Usually, this happens in the language provider, e.g., in the call to `cancel`
-				// below.
+				// below, once the program has finished talking to the service.
 				//
-				//     const deployments = await streamInvoke("kubernetes:kubernetes:watch", {
-				//         group: "apps", version: "v1", kind: "Deployment",
+				//     const forward = await streamInvoke("kubernetes:kubernetes:portForward", {
+				//         namespace: "vault", kind: "Service", name: "vault", port: 8200,
 				//     });
-				//     deployments.cancel();
+				//     forward.cancel();
 				//
 
-				watch.Stop()
-				return nil
+				return stopForwarding(nil)
 			}
 		}
-	case streamInvokePodLogs:
+	case streamInvokeEvents:
 		//
-		// Set up log stream for Pod.
+		// Set up a watch of the Events of an object, or of all objects that match a label selector.
 		//
 
 		if k.clusterUnreachable {
 			return fmt.Errorf("configured Kubernetes cluster is unreachable: %s", k.clusterUnreachableReason)
 		}
 
-		namespace := "default"
-		if args["namespace"].HasValue() {
-			namespace = args["namespace"].StringValue()
+		opts, err := parseEventsOptions(args)
+		if err != nil {
+			return err
 		}
-
-		if !args["name"].HasValue() {
-			return fmt.Errorf(
-				"could not retrieve pod logs because the pod name was not present")
+		if opts.namespace == "" {
+			opts.namespace = "default"
+			if k.defaultNamespace != "" {
+				opts.namespace = k.defaultNamespace
+			}
 		}
-		name := args["name"].StringValue()
 
-		podLogs, err := k.logClient.Logs(namespace, name)
+		eventsClient, err := k.clientSet.ResourceClient(
+			schema.GroupVersionKind{Version: "v1", Kind: "Event"}, opts.namespace)
 		if err != nil {
 			return err
 		}
-		defer podLogs.Close()
-
-		//
-		// Enumerate logs by line. Send back to the user.
-		//
-		// TODO: We send the logs back one-by-one, but we should probably batch them instead.
-		//
+		var involved *involvedObjects
+		if opts.labelSelector != nil {
+			gv, err := schema.ParseGroupVersion(opts.apiVersion)
+			if err != nil {
+				return err
+			}
+			objectsClient, err := k.clientSet.ResourceClient(gv.WithKind(opts.kind), opts.namespace)
+			if err != nil {
+				return err
+			}
+			involved = newInvolvedObjects(objectsClient, opts.labelSelector)
+		}
 
-		logLines := make(chan string)
-		defer close(logLines)
-		done := make(chan error)
-		defer close(done)
+		ctx, cancel := context.WithCancel(k.canceler.context)
+		defer cancel()
 
+		// Existing Events are sent first, as the watch starts without a resource version.
+		events := make(chan map[string]interface{})
+		done := make(chan error, 1)
 		go func() {
-			podLogLines := bufio.NewScanner(podLogs)
-			for podLogLines.Scan() {
-				logLines <- podLogLines.Text()
-			}
-
-			if err := podLogLines.Err(); err != nil {
-				done <- err
-			} else {
-				done <- nil
-			}
+			watcher := newResourceWatcher(eventsClient, metav1.ListOptions{FieldSelector: opts.fieldSelector()}, false)
+			done <- watcher.run(ctx, events)
 		}()
 
 		for {
@@ -1052,18 +1501,30 @@ func (k *kubeProvider) StreamInvoke(
 				return nil
 			case err := <-done:
 				//
-				// Complete. Return the error if applicable.
+				// The watch failed. Return the error.
 				//
 
 				return err
-			case line := <-logLines:
+			case event := <-events:
 				//
-				// Publish log line back to user.
+				// Publish a new or updated Event back to user. Events that expire are not reported.
 				//
 
+				if event["type"] == string(watch.Deleted) {
+					continue
+				}
+				if involved != nil {
+					matches, err := involved.matches(ctx, event)
+					if err != nil {
+						return err
+					}
+					if !matches {
+						continue
+					}
+				}
+
 				resp, err := plugin.MarshalProperties(
-					resource.NewPropertyMapFromMap(
-						map[string]interface{}{"lines": []string{line}}),
+					resource.NewPropertyMapFromMap(event),
 					plugin.MarshalOptions{})
 				if err != nil {
 					return err
@@ -1082,10 +1543,10 @@ func (k *kubeProvider) StreamInvoke(
 				// Usually, this happens in the language provider, e.g., in the call to `cancel`
 				// below.
 				//
-				//     const podLogLines = await streamInvoke("kubernetes:kubernetes:podLogs", {
-				//         namespace: "default", name: "nginx-f94d8bc55-xftvs",
+				//     const events = await streamInvoke("kubernetes:kubernetes:events", {
+				//         namespace: "default", apiVersion: "apps/v1", kind: "Deployment", name: "nginx",
 				//     });
-				//     podLogLines.cancel();
+				//     events.cancel();
 				//
 
 				return nil
@@ -1121,6 +1582,9 @@ func (k *kubeProvider) Check(ctx context.Context, req *pulumirpc.CheckRequest) (
 		}
 		return nil, fmt.Errorf("can't use Helm Release with unreachable cluster. Reason: %q", k.clusterUnreachableReason)
 	}
+	if isPodExec(urn) {
+		return k.podExecProvider.Check(ctx, req, false)
+	}
 
 	// Utilities for determining whether a resource's GVK exists.
 	gvkExists := func(gvk schema.GroupVersionKind) bool {
@@ -1176,6 +1640,13 @@ func (k *kubeProvider) Check(ctx context.Context, req *pulumirpc.CheckRequest) (
 
 	k.helmHookWarning(ctx, newInputs, urn)
 
+	if _, err := parseIgnoreFieldsAnnotation(newInputs); err != nil {
+		failures = append(failures, &pulumirpc.CheckFailure{
+			Property: "metadata.annotations",
+			Reason:   err.Error(),
+		})
+	}
+
 	annotatedInputs, err := legacyInitialAPIVersion(oldInputs, newInputs)
 	if err != nil {
 		return nil, pkgerrors.Wrapf(
@@ -1223,11 +1694,24 @@ func (k *kubeProvider) Check(ctx context.Context, req *pulumirpc.CheckRequest) (
 		return nil, err
 	}
 
-	// Skip the API version check if the cluster is unreachable.
-	if !k.clusterUnreachable {
-		if removed, version := kinds.RemovedAPIVersion(gvk, k.k8sVersion); removed {
+	// Skip the API version check if the cluster is unreachable and the Kubernetes version is not configured.
+	if k.hasSchema() {
+		if k.migrateAPIVersions {
+			migrated, err := kinds.MigrateAPIVersion(newInputs, k.serverVersion())
+			if err != nil {
+				_ = k.host.Log(ctx, diag.Warning, urn, fmt.Sprintf("unable to migrate apiVersion %q: %v",
+					newInputs.GetAPIVersion(), err))
+			} else if migrated != nil {
+				_ = k.host.Log(ctx, diag.Info, urn, fmt.Sprintf("migrated apiVersion %q to %q",
+					newInputs.GetAPIVersion(), migrated.GetAPIVersion()))
+				newInputs = migrated
+				gvk = migrated.GroupVersionKind()
+			}
+		}
+		serverVersion := k.serverVersion()
+		if removed, version := kinds.RemovedAPIVersion(gvk, serverVersion); removed {
 			_ = k.host.Log(ctx, diag.Warning, urn, (&kinds.RemovedAPIError{GVK: gvk, Version: version}).Error())
-		} else if !k.suppressDeprecationWarnings && kinds.DeprecatedAPIVersion(gvk, &k.k8sVersion) {
+		} else if !k.suppressDeprecationWarnings && kinds.DeprecatedAPIVersion(gvk, &serverVersion) {
 			_ = k.host.Log(ctx, diag.Warning, urn, gen.APIVersionComment(gvk))
 		}
 	}
@@ -1235,7 +1719,7 @@ func (k *kubeProvider) Check(ctx context.Context, req *pulumirpc.CheckRequest) (
 	// If a default namespace is set on the provider for this resource, check if the resource has Namespaced
 	// or Global scope. For namespaced resources, set the namespace to the default value if unset.
 	if k.defaultNamespace != "" && len(newInputs.GetNamespace()) == 0 {
-		namespacedKind, err := clients.IsNamespacedKind(gvk, k.clientSet)
+		namespacedKind, err := k.isNamespacedKind(gvk)
 		if err != nil {
 			if clients.IsNoNamespaceInfoErr(err) {
 				// This is probably a CustomResource without a registered CustomResourceDefinition.
@@ -1254,7 +1738,7 @@ func (k *kubeProvider) Check(ctx context.Context, req *pulumirpc.CheckRequest) (
 
 	// HACK: Do not validate against OpenAPI spec if there is a computed value. The OpenAPI spec
 	// does not know how to deal with the placeholder values for computed values.
-	if !hasComputedValue(newInputs) && !k.clusterUnreachable {
+	if !hasComputedValue(newInputs) && k.hasSchema() {
 		resources, err := k.getResources()
 		if err != nil {
 			return nil, pkgerrors.Wrapf(err, "Failed to fetch OpenAPI schema from the API server")
@@ -1290,9 +1774,49 @@ func (k *kubeProvider) Check(ctx context.Context, req *pulumirpc.CheckRequest) (
 		}
 	}
 
+	// Validate custom resources against the schemas of their CRDs, which are not part of the OpenAPI schema of the
+	// cluster unless they are published to it. CRDs that are declared in the stack are remembered, so that their custom
+	// resources are validated before they are created.
+	k.declareCRD(newInputs)
+	if !hasComputedValue(newInputs) {
+		crdFailures, warnings, err := k.checkCustomResource(newInputs)
+		if err != nil {
+			logger.V(3).Infof("skipping CRD validation of %s: %v", urn, err)
+		}
+		failures = append(failures, crdFailures...)
+		for _, warning := range warnings {
+			_ = k.host.Log(ctx, diag.Warning, urn, warning)
+		}
+	}
+
+	// Evaluate the policies of the provider. Violations of mandatory policies fail the check, and violations of
+	// advisory policies are warnings. Validations that depend on computed values cannot be decided, so they are
+	// evaluated again once the values are known, in Create or Update.
+	if len(k.policies) > 0 {
+		var oldObject *unstructured.Unstructured
+		if len(oldInputs.Object) > 0 {
+			oldObject = oldInputs
+		}
+		for _, violation := range k.policies.Evaluate(newInputs, oldObject) {
+			if violation.EnforcementLevel == policy.Advisory {
+				_ = k.host.Log(ctx, diag.Warning, urn, violation.String())
+				continue
+			}
+			failures = append(failures, &pulumirpc.CheckFailure{Reason: violation.String()})
+		}
+	}
+
 	checkedInputs := resource.NewPropertyMapFromMap(newInputs.Object)
 	annotateSecrets(checkedInputs, news)
 
+	// Submit the inputs in a server-side dry run, so that admission webhook denials are reported before the resource is
+	// created or updated.
+	if len(failures) == 0 {
+		if failure := k.checkDryRun(urn, gvk, oldInputs, newInputs, checkedInputs); failure != nil {
+			failures = append(failures, failure)
+		}
+	}
+
 	autonamedInputs, err := plugin.MarshalProperties(checkedInputs, plugin.MarshalOptions{
 		Label:        fmt.Sprintf("%s.autonamedInputs", label),
 		KeepUnknowns: true,
@@ -1304,15 +1828,50 @@ func (k *kubeProvider) Check(ctx context.Context, req *pulumirpc.CheckRequest) (
 	}
 
 	if k.yamlRenderMode {
-		if checkedInputs.ContainsSecrets() {
+		if checkedInputs.ContainsSecrets() && !k.yamlRenderer.encryptsSecret(newInputs) {
 			_ = k.host.Log(ctx, diag.Warning, urn, "rendered YAML will contain a secret value in plaintext")
 		}
+		if unresolved := computedValuePaths(newInputs); len(unresolved) > 0 {
+			if k.yamlRenderer.unknowns == renderUnknownsPlaceholder {
+				_ = k.host.Log(ctx, diag.Warning, urn, fmt.Sprintf(
+					"rendered YAML will be incomplete if these values are still unknown when it is rendered: %s. "+
+						"They will be rendered as %q and listed in %s",
+					strings.Join(unresolved, ", "), unresolvedPlaceholder, unresolvedReportFileName))
+			} else {
+				_ = k.host.Log(ctx, diag.Warning, urn, fmt.Sprintf(
+					"YAML will not be rendered while these values are unknown: %s",
+					strings.Join(unresolved, ", ")))
+			}
+		}
 	}
 
 	// Return new, possibly-autonamed inputs.
 	return &pulumirpc.CheckResponse{Inputs: autonamedInputs, Failures: failures}, nil
 }
 
+// enforcePolicies returns an error if the given object violates a mandatory policy. Check may not have been able to
+// decide the validations that depend on values that were computed when it ran, so they are evaluated again with the
+// resolved object before it is created or updated.
+func (k *kubeProvider) enforcePolicies(newInputs, oldInputs *unstructured.Unstructured) error {
+	if len(k.policies) == 0 {
+		return nil
+	}
+	if oldInputs != nil && len(oldInputs.Object) == 0 {
+		oldInputs = nil
+	}
+	var violations []string
+	for _, violation := range k.policies.Evaluate(newInputs, oldInputs) {
+		if violation.EnforcementLevel == policy.Mandatory {
+			violations = append(violations, violation.String())
+		}
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%s/%s violates mandatory policies: %s",
+			newInputs.GetNamespace(), newInputs.GetName(), strings.Join(violations, "; "))
+	}
+	return nil
+}
+
 // helmHookWarning logs a warning if a Chart contains unsupported hooks. The warning can be disabled by setting
 // the suppressHelmHookWarnings provider flag or related ENV var.
 func (k *kubeProvider) helmHookWarning(ctx context.Context, newInputs *unstructured.Unstructured, urn resource.URN) {
@@ -1364,6 +1923,9 @@ func (k *kubeProvider) Diff(ctx context.Context, req *pulumirpc.DiffRequest) (*p
 		}
 		return nil, fmt.Errorf("can't use Helm Release with unreachable cluster. Reason: %q", k.clusterUnreachableReason)
 	}
+	if isPodExec(urn) {
+		return k.podExecProvider.Diff(ctx, req)
+	}
 
 	label := fmt.Sprintf("%s.Diff(%s)", k.label(), urn)
 	logger.V(9).Infof("%s executing", label)
@@ -1391,14 +1953,18 @@ func (k *kubeProvider) Diff(ctx context.Context, req *pulumirpc.DiffRequest) (*p
 	}
 
 	newInputs := propMapToUnstructured(newResInputs)
-	oldInputs, _ := parseCheckpointObject(oldState)
+	oldInputs, oldLive := parseCheckpointObject(oldState)
 
 	gvk, err := k.gvkFromURN(urn)
 	if err != nil {
 		return nil, err
 	}
+	if k.migratedAPIVersion(gvk, newInputs) {
+		gvk = newInputs.GroupVersionKind()
+		withDefaultedSelector(oldInputs, oldLive)
+	}
 
-	namespacedKind, err := clients.IsNamespacedKind(gvk, k.clientSet)
+	namespacedKind, err := k.isNamespacedKind(gvk)
 	if err != nil {
 		if clients.IsNoNamespaceInfoErr(err) {
 			// This is probably a CustomResource without a registered CustomResourceDefinition.
@@ -1424,16 +1990,24 @@ func (k *kubeProvider) Diff(ctx context.Context, req *pulumirpc.DiffRequest) (*p
 		oldInputs.SetGroupVersionKind(gvk)
 	}
 
+	// Exclude ignored fields from the diff, e.g., fields that are set by controllers or mutating webhooks.
+	ignoreFields, err := k.ignoredFields(gvk, newInputs)
+	if err != nil {
+		return nil, err
+	}
+	diffOldInputs := openapi.WithoutFields(oldInputs, ignoreFields)
+	diffNewInputs := openapi.WithoutFields(newInputs, ignoreFields)
+
 	var patch []byte
 	var patchBase map[string]interface{}
 
 	// Always compute a client-side patch.
-	patch, err = k.inputPatch(oldInputs, newInputs)
+	patch, err = k.inputPatch(diffOldInputs, diffNewInputs)
 	if err != nil {
 		return nil, pkgerrors.Wrapf(
 			err, "Failed to check for changes in resource %s/%s", newInputs.GetNamespace(), newInputs.GetName())
 	}
-	patchBase = oldInputs.Object
+	patchBase = diffOldInputs.Object
 
 	patchObj := map[string]interface{}{}
 	if err = json.Unmarshal(patch, &patchObj); err != nil {
@@ -1443,11 +2017,25 @@ func (k *kubeProvider) Diff(ctx context.Context, req *pulumirpc.DiffRequest) (*p
 			newInputs.GetNamespace(), newInputs.GetName())
 	}
 
-	// Try to compute a server-side patch.
-	ssPatch, ssPatchBase, ssPatchOk := k.tryServerSidePatch(oldInputs, newInputs, gvk)
+	// Try to compute a server-side patch from the dry run of Check. If Check could not dry run the update, it is dry
+	// run here, and the result is kept for the preview of the update.
+	checked := k.checkedDryRun(urn, newResInputs)
+	ssPatch, ssPatchBase, ssDryRunObj, ssPatchOk, err := k.tryServerSidePatch(
+		diffOldInputs, diffNewInputs, gvk, ignoreFields, checked)
+	if err != nil {
+		if denied := admissionDeniedError(newInputs, err); denied != nil {
+			return nil, denied
+		}
+		return nil, pkgerrors.Wrapf(
+			err, "Failed to check for changes in resource %s/%s", newInputs.GetNamespace(), newInputs.GetName())
+	}
+	if ssPatchOk && checked == nil {
+		k.storeDryRunResult(urn, newResInputs, &unstructured.Unstructured{Object: ssPatchBase}, ssDryRunObj)
+	}
 
 	// If the server-side patch succeeded, then merge that patch into the client-side patch and override any conflicts
 	// with the server-side values.
+	ssPatchObj := map[string]interface{}{}
 	if ssPatchOk {
 		logger.V(1).Infof("calculated diffs for %s/%s using dry-run and inputs", newInputs.GetNamespace(), newInputs.GetName())
 		err = mergo.Merge(&patchBase, ssPatchBase, mergo.WithOverride)
@@ -1455,7 +2043,6 @@ func (k *kubeProvider) Diff(ctx context.Context, req *pulumirpc.DiffRequest) (*p
 			return nil, err
 		}
 
-		ssPatchObj := map[string]interface{}{}
 		if err = json.Unmarshal(ssPatch, &ssPatchObj); err != nil {
 			return nil, pkgerrors.Wrapf(
 				err, "Failed to check for changes in resource %s/%s because of an error serializing "+
@@ -1483,7 +2070,8 @@ func (k *kubeProvider) Diff(ctx context.Context, req *pulumirpc.DiffRequest) (*p
 		}
 
 		forceNewFields := forceNewProperties(gvk)
-		if detailedDiff, err = convertPatchToDiff(patchObj, patchBase, newInputs.Object, oldInputs.Object, forceNewFields...); err != nil {
+		if detailedDiff, err = convertPatchToDiff(
+			patchObj, patchBase, diffNewInputs.Object, diffOldInputs.Object, forceNewFields...); err != nil {
 			return nil, pkgerrors.Wrapf(
 				err, "Failed to check for changes in resource %s/%s because of an error "+
 					"converting JSON patch describing resource changes to a diff",
@@ -1493,6 +2081,12 @@ func (k *kubeProvider) Diff(ctx context.Context, req *pulumirpc.DiffRequest) (*p
 			v.InputDiff = true
 		}
 
+		// Show the values that the API server or admission webhooks would default or mutate as output changes, since
+		// they are not in the inputs and are therefore left out of the input diff.
+		if ssPatchOk {
+			addServerSideDiff(detailedDiff, ssPatchObj, ssPatchBase, diffNewInputs.Object)
+		}
+
 		for k, v := range detailedDiff {
 			switch v.Kind {
 			case pulumirpc.PropertyDiff_ADD_REPLACE, pulumirpc.PropertyDiff_DELETE_REPLACE, pulumirpc.PropertyDiff_UPDATE_REPLACE:
@@ -1501,6 +2095,58 @@ func (k *kubeProvider) Diff(ctx context.Context, req *pulumirpc.DiffRequest) (*p
 		}
 	}
 
+	// Compare the new inputs to the live object to detect changes that were made out of band. Errors are ignored,
+	// since the object may not exist yet or may have been deleted.
+	if k.enableDriftDetection && !k.clusterUnreachable && !hasComputedValue(newInputs) &&
+		oldInputs.GetName() == newInputs.GetName() && oldInputs.GetNamespace() == newInputs.GetNamespace() {
+		if live, err := k.readLiveObject(oldInputs); err == nil {
+			drifted, err := detectDrift(
+				diffNewInputs, openapi.WithoutFields(live, ignoreFields), k.driftInBandManagers)
+			if err != nil {
+				return nil, pkgerrors.Wrapf(err, "Failed to detect drift in resource %s/%s",
+					newInputs.GetNamespace(), newInputs.GetName())
+			}
+			if len(drifted) > 0 && detailedDiff == nil {
+				detailedDiff = map[string]*pulumirpc.PropertyDiff{}
+			}
+			changed := map[string]bool{}
+			for _, change := range changes {
+				changed[change] = true
+			}
+			for _, field := range drifted {
+				pathStr := formatPropertyPath(field.path)
+				if _, exists := detailedDiff[pathStr]; exists {
+					continue
+				}
+				detailedDiff[pathStr] = field.diff
+				hasChanges = pulumirpc.DiffResponse_DIFF_SOME
+				if topLevel := field.path[0].(string); !changed[topLevel] {
+					changed[topLevel] = true
+					changes = append(changes, topLevel)
+				}
+
+				manager := field.manager
+				if manager == "" {
+					manager = "an unknown field manager"
+				}
+				_ = k.host.Log(ctx, diag.Warning, urn, fmt.Sprintf(
+					"detected drift: %s was changed out of band by %s", pathStr, manager))
+			}
+		}
+	}
+
+	// Rendered Secrets are rendered again when the format or the key that their data is encrypted with changes.
+	if k.yamlRenderMode && k.yamlRenderer.rerendersSecret(newInputs, previousSecretsRendering(oldState)) {
+		hasChanges = pulumirpc.DiffResponse_DIFF_SOME
+		if detailedDiff == nil {
+			detailedDiff = map[string]*pulumirpc.PropertyDiff{}
+		}
+		if _, exists := detailedDiff[renderedSecretsKey]; !exists {
+			detailedDiff[renderedSecretsKey] = &pulumirpc.PropertyDiff{Kind: pulumirpc.PropertyDiff_UPDATE}
+			changes = append(changes, renderedSecretsKey)
+		}
+	}
+
 	if metadata.ReplaceUnready(newInputs) {
 		switch newInputs.GetKind() {
 		case "Job":
@@ -1571,6 +2217,9 @@ func (k *kubeProvider) Create(
 		}
 		return nil, fmt.Errorf("can't create Helm Release with unreachable cluster. Reason: %q", k.clusterUnreachableReason)
 	}
+	if isPodExec(urn) {
+		return k.podExecProvider.Create(ctx, req)
+	}
 
 	label := fmt.Sprintf("%s.Create(%s)", k.label(), urn)
 	logger.V(9).Infof("%s executing", label)
@@ -1594,6 +2243,9 @@ func (k *kubeProvider) Create(
 	}
 
 	newInputs := propMapToUnstructured(newResInputs)
+	// The inputs of a CRD may only be known once its dependencies are created, so it is remembered here as well as in
+	// Check.
+	k.declareCRD(newInputs)
 
 	// If this is a preview and the input values contain unknowns, return them as-is. This is compatible with
 	// prior behavior implemented by the Pulumi engine. Similarly, if the server does not support server-side
@@ -1605,6 +2257,15 @@ func (k *kubeProvider) Create(
 		return &pulumirpc.CreateResponse{Id: "", Properties: req.GetProperties()}, nil
 	}
 
+	if err := k.enforcePolicies(newInputs, nil); err != nil {
+		return nil, err
+	}
+
+	var unresolved []string
+	if k.yamlRenderMode {
+		newInputs, unresolved = k.yamlRenderer.resolveUnknowns(newInputs)
+	}
+
 	annotatedInputs, err := withLastAppliedConfig(newInputs)
 	if err != nil {
 		return nil, pkgerrors.Wrapf(
@@ -1616,21 +2277,26 @@ func (k *kubeProvider) Create(
 	initialAPIVersion := newInputs.GetAPIVersion()
 
 	if k.yamlRenderMode {
-		if newResInputs.ContainsSecrets() {
+		if newResInputs.ContainsSecrets() && !k.yamlRenderer.encryptsSecret(newInputs) {
 			_ = k.host.Log(ctx, diag.Warning, urn, fmt.Sprintf(
 				"rendered file %s contains a secret value in plaintext",
-				renderPathForResource(annotatedInputs, k.yamlDirectory)))
+				k.yamlRenderer.path(urn, annotatedInputs)))
 		}
-		err := renderYaml(annotatedInputs, k.yamlDirectory)
+		err := k.yamlRenderer.render(urn, annotatedInputs, unresolved)
 		if err != nil {
 			return nil, err
 		}
+		deferred := k.yamlRenderer.defers(unresolved)
 
 		obj := checkpointObject(newInputs, annotatedInputs, newResInputs, initialAPIVersion)
+		if isSecret(newInputs) && !deferred {
+			obj[renderedSecretsKey] = resource.NewStringProperty(secretsRendering(k.yamlRenderer.secrets))
+		}
 		inputsAndComputed, err := plugin.MarshalProperties(
 			obj, plugin.MarshalOptions{
-				Label:        fmt.Sprintf("%s.inputsAndComputed", label),
-				KeepUnknowns: true,
+				Label: fmt.Sprintf("%s.inputsAndComputed", label),
+				// The unknown values of a deferred resource are left out of its state until it is rendered.
+				KeepUnknowns: !deferred,
 				SkipNulls:    true,
 				KeepSecrets:  k.enableSecrets,
 			})
@@ -1638,8 +2304,14 @@ func (k *kubeProvider) Create(
 			return nil, err
 		}
 
-		_ = k.host.LogStatus(ctx, diag.Info, urn, fmt.Sprintf(
-			"rendered %s", renderPathForResource(annotatedInputs, k.yamlDirectory)))
+		if deferred {
+			_ = k.host.Log(ctx, diag.Warning, urn, fmt.Sprintf(
+				"rendering of %s is deferred until the following values are known: %s",
+				k.yamlRenderer.path(urn, annotatedInputs), strings.Join(unresolved, ", ")))
+		} else {
+			_ = k.host.LogStatus(ctx, diag.Info, urn, fmt.Sprintf(
+				"rendered %s", k.yamlRenderer.path(urn, annotatedInputs)))
+		}
 
 		return &pulumirpc.CreateResponse{
 			Id: fqObjName(annotatedInputs), Properties: inputsAndComputed,
@@ -1650,13 +2322,14 @@ func (k *kubeProvider) Create(
 	if err != nil {
 		return nil, pkgerrors.Wrapf(err, "Failed to fetch OpenAPI schema from the API server")
 	}
+	serverVersion := k.serverVersion()
 	config := await.CreateConfig{
 		ProviderConfig: await.ProviderConfig{
 			Context:           k.canceler.context,
 			Host:              k.host,
 			URN:               urn,
 			InitialAPIVersion: initialAPIVersion,
-			ClusterVersion:    &k.k8sVersion,
+			ClusterVersion:    &serverVersion,
 			ClientSet:         k.clientSet,
 			DedupLogger:       logging.NewLogger(k.canceler.context, k.host, urn),
 			Resources:         resources,
@@ -1665,7 +2338,15 @@ func (k *kubeProvider) Create(
 		Timeout: req.Timeout,
 		DryRun:  req.GetPreview(),
 	}
-	initialized, awaitErr := await.Creation(config)
+	// Apply create. The preview returns the result of the server-side dry run of Check if there is one, rather than
+	// running the dry run again.
+	var initialized *unstructured.Unstructured
+	var awaitErr error
+	if dryRunObj := k.takeDryRunResult(urn, newResInputs); req.GetPreview() && dryRunObj != nil {
+		initialized = dryRunObj
+	} else {
+		initialized, awaitErr = await.Creation(config)
+	}
 	if awaitErr != nil {
 		if req.GetPreview() {
 			failedPreview := false
@@ -1774,6 +2455,9 @@ func (k *kubeProvider) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*p
 		contract.Assertf(k.helmReleaseProvider != nil, "helmReleaseProvider not initialized.")
 		return k.helmReleaseProvider.Read(ctx, req)
 	}
+	if isPodExec(urn) {
+		return k.podExecProvider.Read(ctx, req)
+	}
 
 	// Obtain new properties, create a Kubernetes `unstructured.Unstructured` that we can pass to the
 	// validation routines.
@@ -2029,6 +2713,7 @@ func (k *kubeProvider) Update(
 		return nil, pkgerrors.Wrapf(err, "update failed because malformed resource inputs")
 	}
 	newInputs := propMapToUnstructured(newResInputs)
+	k.declareCRD(newInputs)
 
 	if isHelmRelease(urn) {
 		if !k.clusterUnreachable {
@@ -2036,6 +2721,9 @@ func (k *kubeProvider) Update(
 		}
 		return nil, fmt.Errorf("can't update Helm Release with unreachable cluster. Reason: %q", k.clusterUnreachableReason)
 	}
+	if isPodExec(urn) {
+		return k.podExecProvider.Update(ctx, req)
+	}
 	// Ignore old state; we'll get it from Kubernetes later.
 	oldInputs, _ := parseCheckpointObject(oldState)
 
@@ -2049,6 +2737,15 @@ func (k *kubeProvider) Update(
 		return &pulumirpc.UpdateResponse{Properties: req.News}, nil
 	}
 
+	if err := k.enforcePolicies(newInputs, oldInputs); err != nil {
+		return nil, err
+	}
+
+	var unresolved []string
+	if k.yamlRenderMode {
+		newInputs, unresolved = k.yamlRenderer.resolveUnknowns(newInputs)
+	}
+
 	annotatedInputs, err := withLastAppliedConfig(newInputs)
 	if err != nil {
 		return nil, pkgerrors.Wrapf(
@@ -2061,23 +2758,43 @@ func (k *kubeProvider) Update(
 	if err != nil {
 		return nil, err
 	}
+	if gvk, err := k.gvkFromURN(urn); err == nil && k.migratedAPIVersion(gvk, newInputs) {
+		// The resource is updated with the apiVersion that it was migrated to in Check, rather than the one it was
+		// created with.
+		initialAPIVersion = newInputs.GetAPIVersion()
+	}
 
 	if k.yamlRenderMode {
-		if newResInputs.ContainsSecrets() {
+		if newResInputs.ContainsSecrets() && !k.yamlRenderer.encryptsSecret(newInputs) {
 			_ = k.host.LogStatus(ctx, diag.Warning, urn, fmt.Sprintf(
 				"rendered file %s contains a secret value in plaintext",
-				renderPathForResource(annotatedInputs, k.yamlDirectory)))
+				k.yamlRenderer.path(urn, annotatedInputs)))
+		}
+		deferred := k.yamlRenderer.defers(unresolved)
+		previous := previousSecretsRendering(oldState)
+		if !deferred {
+			if err := k.yamlRenderer.removePreviousSecret(urn, annotatedInputs, previous); err != nil {
+				return nil, err
+			}
 		}
-		err := renderYaml(annotatedInputs, k.yamlDirectory)
+		err := k.yamlRenderer.render(urn, annotatedInputs, unresolved)
 		if err != nil {
 			return nil, err
 		}
 
 		obj := checkpointObject(newInputs, annotatedInputs, newResInputs, initialAPIVersion)
+		if isSecret(newInputs) {
+			// The Secret is only rendered with the current format and key once it is no longer deferred.
+			if !deferred {
+				previous = secretsRendering(k.yamlRenderer.secrets)
+			}
+			obj[renderedSecretsKey] = resource.NewStringProperty(previous)
+		}
 		inputsAndComputed, err := plugin.MarshalProperties(
 			obj, plugin.MarshalOptions{
-				Label:        fmt.Sprintf("%s.inputsAndComputed", label),
-				KeepUnknowns: true,
+				Label: fmt.Sprintf("%s.inputsAndComputed", label),
+				// The unknown values of a deferred resource are left out of its state until it is rendered.
+				KeepUnknowns: !deferred,
 				SkipNulls:    true,
 				KeepSecrets:  k.enableSecrets,
 			})
@@ -2085,8 +2802,14 @@ func (k *kubeProvider) Update(
 			return nil, err
 		}
 
-		_ = k.host.LogStatus(ctx, diag.Info, urn, fmt.Sprintf(
-			"rendered %s", renderPathForResource(annotatedInputs, k.yamlDirectory)))
+		if deferred {
+			_ = k.host.Log(ctx, diag.Warning, urn, fmt.Sprintf(
+				"rendering of %s is deferred until the following values are known: %s",
+				k.yamlRenderer.path(urn, annotatedInputs), strings.Join(unresolved, ", ")))
+		} else {
+			_ = k.host.LogStatus(ctx, diag.Info, urn, fmt.Sprintf(
+				"rendered %s", k.yamlRenderer.path(urn, annotatedInputs)))
+		}
 
 		return &pulumirpc.UpdateResponse{Properties: inputsAndComputed}, nil
 	}
@@ -2095,6 +2818,10 @@ func (k *kubeProvider) Update(
 	if err != nil {
 		return nil, pkgerrors.Wrapf(err, "Failed to fetch OpenAPI schema from the API server")
 	}
+	ignoreFields, err := k.ignoredFields(newInputs.GroupVersionKind(), newInputs)
+	if err != nil {
+		return nil, err
+	}
 	config := await.UpdateConfig{
 		ProviderConfig: await.ProviderConfig{
 			Context:           k.canceler.context,
@@ -2105,13 +2832,21 @@ func (k *kubeProvider) Update(
 			DedupLogger:       logging.NewLogger(k.canceler.context, k.host, urn),
 			Resources:         resources,
 		},
-		Previous: oldInputs,
-		Inputs:   annotatedInputs,
-		Timeout:  req.Timeout,
-		DryRun:   req.GetPreview(),
+		Previous:     oldInputs,
+		Inputs:       annotatedInputs,
+		Timeout:      req.Timeout,
+		DryRun:       req.GetPreview(),
+		IgnoreFields: ignoreFields,
+	}
+	// Apply update. The preview returns the result of the server-side dry run of Check or Diff if there is one, rather
+	// than running the dry run again.
+	var initialized *unstructured.Unstructured
+	var awaitErr error
+	if dryRunObj := k.takeDryRunResult(urn, newResInputs); req.GetPreview() && dryRunObj != nil {
+		initialized = dryRunObj
+	} else {
+		initialized, awaitErr = await.Update(config)
 	}
-	// Apply update.
-	initialized, awaitErr := await.Update(config)
 	if awaitErr != nil {
 		if req.GetPreview() && k.isDryRunDisabledError(err) {
 			logger.V(9).Infof("could not preview Update(%v): %v", urn, err)
@@ -2190,13 +2925,15 @@ func (k *kubeProvider) Delete(ctx context.Context, req *pulumirpc.DeleteRequest)
 		}
 		return nil, fmt.Errorf("can't delete Helm Release with unreachable cluster. Reason: %q", k.clusterUnreachableReason)
 	}
+	if isPodExec(urn) {
+		return k.podExecProvider.Delete(ctx, req)
+	}
 
 	_, current := parseCheckpointObject(oldState)
 	_, name := parseFqName(req.GetId())
 
 	if k.yamlRenderMode {
-		file := renderPathForResource(current, k.yamlDirectory)
-		err := os.Remove(file)
+		file, err := k.yamlRenderer.remove(urn, current)
 		if err != nil {
 			// Most of the time, errors will be because the file was already deleted. In this case,
 			// the operation succeeds. It's also possible that deletion fails due to file permission if
@@ -2301,6 +3038,35 @@ func (k *kubeProvider) label() string {
 	return fmt.Sprintf("Provider[%s]", k.name)
 }
 
+// migratedAPIVersion returns whether the given inputs of a resource of the given GVK were migrated to another
+// apiVersion in Check.
+func (k *kubeProvider) migratedAPIVersion(gvk schema.GroupVersionKind, inputs *unstructured.Unstructured) bool {
+	inputsGVK := inputs.GroupVersionKind()
+	return k.migrateAPIVersions && !inputsGVK.Empty() && inputsGVK != gvk
+}
+
+// withDefaultedSelector sets the selector of the old inputs of a workload that did not set one to the selector that
+// the API server defaulted it to. Workloads that are migrated to apps/v1 must set a selector, which is defaulted to
+// the labels of their pod template in the earlier apiVersions, so the selector that the migration sets is not a
+// change of the immutable selector, unless the labels of the pod template changed.
+func withDefaultedSelector(oldInputs, oldLive *unstructured.Unstructured) {
+	switch kinds.Kind(oldInputs.GetKind()) {
+	case kinds.DaemonSet, kinds.Deployment, kinds.ReplicaSet, kinds.StatefulSet:
+	default:
+		return
+	}
+	spec, ok := oldInputs.Object["spec"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	if _, ok := spec["selector"]; ok {
+		return
+	}
+	if selector, found, _ := unstructured.NestedFieldCopy(oldLive.Object, "spec", "selector"); found {
+		spec["selector"] = selector
+	}
+}
+
 func (k *kubeProvider) gvkFromURN(urn resource.URN) (schema.GroupVersionKind, error) {
 	if string(urn.Type().Package()) != k.providerPackage {
 		return schema.GroupVersionKind{}, fmt.Errorf("unrecognized resource type: %q for this provider",
@@ -2337,9 +3103,12 @@ func (k *kubeProvider) readLiveObject(obj *unstructured.Unstructured) (*unstruct
 	return rc.Get(context.TODO(), obj.GetName(), metav1.GetOptions{})
 }
 
-func (k *kubeProvider) serverSidePatch(oldInputs, newInputs *unstructured.Unstructured,
-) ([]byte, map[string]interface{}, error) {
-
+// dryRunUpdate submits the update of a resource from its old inputs to its new inputs to the API server in a
+// server-side dry run, and returns the live object and the object returned by the dry run. Ignored fields are left out
+// of the patch, as in the update itself. If the new object does not exist, e.g., because its name or namespace changed,
+// its creation is dry run instead.
+func (k *kubeProvider) dryRunUpdate(oldInputs, newInputs *unstructured.Unstructured, ignoreFields []openapi.FieldPath,
+) (*unstructured.Unstructured, *unstructured.Unstructured, error) {
 	client, err := k.clientSet.ResourceClient(oldInputs.GroupVersionKind(), oldInputs.GetNamespace())
 	if err != nil {
 		return nil, nil, err
@@ -2349,7 +3118,7 @@ func (k *kubeProvider) serverSidePatch(oldInputs, newInputs *unstructured.Unstru
 	if err != nil {
 		return nil, nil, err
 	}
-	liveInputs := parseLiveInputs(liveObject, oldInputs)
+	liveInputs := openapi.WithoutFields(parseLiveInputs(liveObject, oldInputs), ignoreFields)
 
 	resources, err := k.getResources()
 	if err != nil {
@@ -2389,22 +3158,46 @@ func (k *kubeProvider) serverSidePatch(oldInputs, newInputs *unstructured.Unstru
 	if err != nil {
 		return nil, nil, err
 	}
+	return liveObject, newObject, nil
+}
+
+// serverSidePatch computes the patch of an update from the live object to the object returned by its server-side dry
+// run. The result of the dry run in Check is used if there is one, and otherwise the update is dry run here.
+func (k *kubeProvider) serverSidePatch(oldInputs, newInputs *unstructured.Unstructured,
+	ignoreFields []openapi.FieldPath, checked *dryRunResult,
+) ([]byte, map[string]interface{}, *unstructured.Unstructured, error) {
+	var liveObject, newObject *unstructured.Unstructured
+	if checked != nil {
+		liveObject, newObject = checked.live, checked.object
+	} else {
+		var err error
+		if liveObject, newObject, err = k.dryRunUpdate(oldInputs, newInputs, ignoreFields); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	dryRunObj := newObject
+
+	// Ignored fields are removed from the live and dry-run objects, so that values set by the server do not show up
+	// in the diff.
+	liveObject = openapi.WithoutFields(liveObject, ignoreFields)
+	newObject = openapi.WithoutFields(newObject, ignoreFields)
 
 	liveJSON, err := liveObject.MarshalJSON()
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	newJSON, err := newObject.MarshalJSON()
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
-	patch, err = jsonpatch.CreateMergePatch(liveJSON, newJSON)
+	patch, err := jsonpatch.CreateMergePatch(liveJSON, newJSON)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
-	return patch, liveObject.Object, nil
+	return patch, liveObject.Object, dryRunObj, nil
 }
 
 // inputPatch calculates a patch on the client-side by comparing old inputs to the current inputs.
@@ -2449,38 +3242,49 @@ func (k *kubeProvider) isDryRunDisabledError(err error) bool {
 			strings.Contains(se.Status().Message, "does not support dry run"))
 }
 
-// tryServerSidePatch attempts to compute a server-side patch. Returns true iff the operation succeeded.
+// tryServerSidePatch computes the patch of an update with a server-side dry run, and returns it along with the live
+// object it applies to and the object returned by the dry run. It returns false if a server-side patch cannot be
+// computed for the update, in which case the patch is computed from the inputs, and an error if the dry run failed.
+// The result of the dry run in Check is used if there is one.
 func (k *kubeProvider) tryServerSidePatch(oldInputs, newInputs *unstructured.Unstructured, gvk schema.GroupVersionKind,
-) ([]byte, map[string]interface{}, bool) {
+	ignoreFields []openapi.FieldPath, checked *dryRunResult,
+) ([]byte, map[string]interface{}, *unstructured.Unstructured, bool, error) {
 	// If the resource's GVK changed, so compute patch using inputs.
 	if oldInputs.GroupVersionKind().String() != gvk.String() {
-		return nil, nil, false
+		return nil, nil, nil, false, nil
 	}
 	// If we can't dry-run the new GVK, computed the patch using inputs.
 	if !k.supportsDryRun(gvk) {
-		return nil, nil, false
+		return nil, nil, nil, false, nil
 	}
 	// TODO: Skipping server-side diff for resources with computed values is a hack. We will want to address this
 	// more granularly so that previews are as accurate as possible, but this is an easy workaround for a critical
 	// bug.
 	if hasComputedValue(newInputs) || hasComputedValue(oldInputs) {
-		return nil, nil, false
+		return nil, nil, nil, false, nil
 	}
 
-	ssPatch, ssPatchBase, err := k.serverSidePatch(oldInputs, newInputs)
+	ssPatch, ssPatchBase, dryRunObj, err := k.serverSidePatch(oldInputs, newInputs, ignoreFields, checked)
 	if k.isDryRunDisabledError(err) {
-		return nil, nil, false
+		return nil, nil, nil, false, nil
 	}
 	if se, isStatusError := err.(*errors.StatusError); isStatusError {
 		// If the resource field is immutable.
 		if se.Status().Code == http.StatusUnprocessableEntity ||
 			strings.Contains(se.ErrStatus.Message, "field is immutable") {
-			return nil, nil, false
+			return nil, nil, nil, false, nil
 		}
 	}
+	// If the live object does not exist, e.g., because it was deleted out of band.
+	if errors.IsNotFound(err) {
+		return nil, nil, nil, false, nil
+	}
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
 
 	// The server-side patch succeeded.
-	return ssPatch, ssPatchBase, true
+	return ssPatch, ssPatchBase, dryRunObj, true, nil
 }
 
 func mapReplStripSecrets(v resource.PropertyValue) (interface{}, bool) {
@@ -2746,6 +3550,27 @@ func makePatchSlice(path []interface{}, v interface{}) interface{} {
 	}
 }
 
+// formatPropertyPath formats the given path as a Pulumi property path, e.g. `spec.containers[0].image` or
+// `metadata.annotations["pulumi.com/skipAwait"]`.
+func formatPropertyPath(path []interface{}) string {
+	pathStr := ""
+	for _, v := range path {
+		switch v := v.(type) {
+		case string:
+			if strings.ContainsAny(v, `."[]`) {
+				pathStr = fmt.Sprintf(`%s["%s"]`, pathStr, strings.ReplaceAll(v, `"`, `\"`))
+			} else if pathStr != "" {
+				pathStr = fmt.Sprintf("%s.%s", pathStr, v)
+			} else {
+				pathStr = v
+			}
+		case int:
+			pathStr = fmt.Sprintf("%s[%d]", pathStr, v)
+		}
+	}
+	return pathStr
+}
+
 // equalNumbers returns true if both a and b are number values (int64 or float64). Note that if a this will fail if
 // either value is not representable as a float64.
 func equalNumbers(a, b interface{}) bool {
@@ -2859,23 +3684,7 @@ func (pc *patchConverter) addPatchValueToDiff(
 		}
 	}
 
-	pathStr := ""
-	for _, v := range path {
-		switch v := v.(type) {
-		case string:
-			if strings.ContainsAny(v, `."[]`) {
-				pathStr = fmt.Sprintf(`%s["%s"]`, pathStr, strings.ReplaceAll(v, `"`, `\"`))
-			} else if pathStr != "" {
-				pathStr = fmt.Sprintf("%s.%s", pathStr, v)
-			} else {
-				pathStr = v
-			}
-		case int:
-			pathStr = fmt.Sprintf("%s[%d]", pathStr, v)
-		}
-	}
-
-	pc.diff[pathStr] = &pulumirpc.PropertyDiff{Kind: diffKind, InputDiff: inputDiff}
+	pc.diff[formatPropertyPath(path)] = &pulumirpc.PropertyDiff{Kind: diffKind, InputDiff: inputDiff}
 	return nil
 }
 
@@ -2980,62 +3789,3 @@ func annotateSecrets(outs, ins resource.PropertyMap) {
 		}
 	}
 }
-
-// renderYaml marshals an Unstructured resource to YAML and writes it to the specified path on disk or returns an error.
-func renderYaml(resource *unstructured.Unstructured, yamlDirectory string) error {
-	jsonBytes, err := resource.MarshalJSON()
-	if err != nil {
-		return pkgerrors.Wrapf(err, "failed to render YAML file: %q", yamlDirectory)
-	}
-	yamlBytes, err := yaml.JSONToYAML(jsonBytes)
-	if err != nil {
-		return pkgerrors.Wrapf(err, "failed to render YAML file: %q", yamlDirectory)
-	}
-
-	crdDirectory := filepath.Join(yamlDirectory, "0-crd")
-	manifestDirectory := filepath.Join(yamlDirectory, "1-manifest")
-
-	if _, err := os.Stat(crdDirectory); os.IsNotExist(err) {
-		err = os.MkdirAll(crdDirectory, 0700)
-		if err != nil {
-			return pkgerrors.Wrapf(err, "failed to create directory for rendered YAML: %q", crdDirectory)
-		}
-	}
-	if _, err := os.Stat(manifestDirectory); os.IsNotExist(err) {
-		err = os.MkdirAll(manifestDirectory, 0700)
-		if err != nil {
-			return pkgerrors.Wrapf(err, "failed to create directory for rendered YAML: %q", manifestDirectory)
-		}
-	}
-
-	path := renderPathForResource(resource, yamlDirectory)
-	err = ioutil.WriteFile(path, yamlBytes, 0600)
-	if err != nil {
-		return pkgerrors.Wrapf(err, "failed to write YAML file: %q", path)
-	}
-
-	return nil
-}
-
-// renderPathForResource determines the appropriate YAML render path depending on the resource kind.
-func renderPathForResource(resource *unstructured.Unstructured, yamlDirectory string) string {
-	crdDirectory := filepath.Join(yamlDirectory, "0-crd")
-	manifestDirectory := filepath.Join(yamlDirectory, "1-manifest")
-
-	namespace := "default"
-	if "" != resource.GetNamespace() {
-		namespace = resource.GetNamespace()
-	}
-
-	fileName := fmt.Sprintf("%s-%s-%s.yaml", strings.ToLower(resource.GetKind()), namespace, resource.GetName())
-	filepath.Join(yamlDirectory, fileName)
-
-	var path string
-	if kinds.Kind(resource.GetKind()) == kinds.CustomResourceDefinition {
-		path = filepath.Join(crdDirectory, fileName)
-	} else {
-		path = filepath.Join(manifestDirectory, fileName)
-	}
-
-	return path
-}
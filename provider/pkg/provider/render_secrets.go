@@ -0,0 +1,381 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	ssv1alpha1 "github.com/bitnami-labs/sealed-secrets/pkg/apis/sealed-secrets/v1alpha1"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/kinds"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"go.mozilla.org/sops/v3"
+	sopsaes "go.mozilla.org/sops/v3/aes"
+	sopsage "go.mozilla.org/sops/v3/age"
+	"go.mozilla.org/sops/v3/pgp"
+	sopsyaml "go.mozilla.org/sops/v3/stores/yaml"
+	"go.mozilla.org/sops/v3/version"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+// renderSecrets controls how the data of Secret resources is written in render mode.
+type renderSecrets string
+
+const (
+	// renderSecretsPlaintext writes Secret data in plaintext. This is the default.
+	renderSecretsPlaintext renderSecrets = "plaintext"
+	// renderSecretsSops encrypts Secret data in the SOPS format for a set of age recipients or a PGP key.
+	renderSecretsSops renderSecrets = "sops"
+	// renderSecretsSealedSecrets renders Secrets as Bitnami SealedSecrets, sealed with the controller's certificate.
+	renderSecretsSealedSecrets renderSecrets = "sealedSecrets"
+)
+
+// parseRenderSecrets returns the renderSecrets mode for the given config value. An empty value selects the default.
+func parseRenderSecrets(value string) (renderSecrets, error) {
+	switch secrets := renderSecrets(value); secrets {
+	case "":
+		return renderSecretsPlaintext, nil
+	case renderSecretsPlaintext, renderSecretsSops, renderSecretsSealedSecrets:
+		return secrets, nil
+	default:
+		return "", fmt.Errorf("unknown render mode for secrets %q; expected one of %q, %q, or %q",
+			value, renderSecretsPlaintext, renderSecretsSops, renderSecretsSealedSecrets)
+	}
+}
+
+// secretEncrypter encrypts the data of a Secret before it is rendered.
+type secretEncrypter interface {
+	// encryptSecret returns the object to render in place of the given Secret.
+	encryptSecret(secret *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	// rendering returns a value that identifies the format and the key that Secrets are encrypted with.
+	rendering() string
+}
+
+// renderedSecretsKey is the key of the state of a rendered Secret that records how it was rendered, so that it is
+// rendered again when the `renderYamlSecrets` or `renderYamlSecretsKey` options change.
+const renderedSecretsKey = "__renderedSecrets"
+
+// secretsRendering returns a value that identifies how Secrets are rendered by the given encrypter.
+func secretsRendering(e secretEncrypter) string {
+	if e == nil {
+		return string(renderSecretsPlaintext)
+	}
+	return e.rendering()
+}
+
+// previousSecretsRendering returns the value that identifies how a Secret was rendered, from its state. Secrets
+// that were rendered before it was recorded were rendered in plaintext.
+func previousSecretsRendering(state resource.PropertyMap) string {
+	if v, ok := state[renderedSecretsKey]; ok && v.IsString() {
+		return v.StringValue()
+	}
+	return string(renderSecretsPlaintext)
+}
+
+// newSecretEncrypter returns the secretEncrypter for the given mode, or nil if Secrets are rendered in plaintext.
+//
+// For the sops mode, the key is a comma-separated list of age recipients, or the path to (or contents of) an
+// ASCII-armored PGP public key. For the sealedSecrets mode, the key is the path to (or contents of) the PEM-encoded
+// certificate of the sealed-secrets controller.
+func newSecretEncrypter(mode renderSecrets, key string) (secretEncrypter, error) {
+	switch mode {
+	case renderSecretsSops:
+		return newSopsEncrypter(key)
+	case renderSecretsSealedSecrets:
+		return newSealedSecretsEncrypter(key)
+	default:
+		return nil, nil
+	}
+}
+
+// isSecret returns true if the given object is a core/v1 Secret.
+func isSecret(obj *unstructured.Unstructured) bool {
+	return obj.GetAPIVersion() == "v1" && obj.GetKind() == string(kinds.Secret)
+}
+
+// encryptSecret returns the object to render in place of the given Secret. The last-applied-configuration annotation
+// is dropped, since it would otherwise contain the Secret data in plaintext.
+func encryptSecret(e secretEncrypter, secret *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	secret = secret.DeepCopy()
+	annotations := secret.GetAnnotations()
+	delete(annotations, lastAppliedConfigKey)
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	secret.SetAnnotations(annotations)
+
+	return e.encryptSecret(secret)
+}
+
+// readKeyMaterial returns the given value if it contains PEM or ASCII-armored key material, and otherwise reads the
+// key material from the file at the given path.
+func readKeyMaterial(value string) ([]byte, error) {
+	if strings.HasPrefix(strings.TrimSpace(value), "-----BEGIN ") {
+		return []byte(value), nil
+	}
+	b, err := ioutil.ReadFile(value)
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "failed to read key file %q", value)
+	}
+	return b, nil
+}
+
+// --------------------------------------------------------------------------
+// SOPS.
+// --------------------------------------------------------------------------
+
+// sopsEncryptedRegex selects the fields of a Secret that are encrypted. Everything else, including the metadata, is
+// left in plaintext so that the manifest can still be reviewed and diffed.
+const sopsEncryptedRegex = "^(data|stringData)$"
+
+// sopsEncrypter encrypts Secrets in the SOPS format, compatible with `sops --decrypt` and tools like KSOPS and Flux.
+type sopsEncrypter struct {
+	ageRecipients []string
+	pgpKey        *openpgp.Entity
+}
+
+func newSopsEncrypter(key string) (*sopsEncrypter, error) {
+	if key == "" {
+		return nil, fmt.Errorf("an age recipient or PGP public key is required to encrypt secrets with SOPS")
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(key), "age1") {
+		e := &sopsEncrypter{}
+		for _, recipient := range strings.Split(key, ",") {
+			recipient = strings.TrimSpace(recipient)
+			if _, err := sopsage.MasterKeyFromRecipient(recipient); err != nil {
+				return nil, pkgerrors.Wrapf(err, "invalid age recipient %q", recipient)
+			}
+			e.ageRecipients = append(e.ageRecipients, recipient)
+		}
+		return e, nil
+	}
+
+	b, err := readKeyMaterial(key)
+	if err != nil {
+		return nil, err
+	}
+	entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(b))
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "failed to parse PGP public key")
+	}
+	if len(entities) != 1 {
+		return nil, fmt.Errorf("expected a single PGP public key, found %d", len(entities))
+	}
+	return &sopsEncrypter{pgpKey: entities[0]}, nil
+}
+
+func (e *sopsEncrypter) rendering() string {
+	if e.pgpKey != nil {
+		return fmt.Sprintf("%s:%X", renderSecretsSops, e.pgpKey.PrimaryKey.Fingerprint)
+	}
+	recipients := append([]string{}, e.ageRecipients...)
+	sort.Strings(recipients)
+	return fmt.Sprintf("%s:%s", renderSecretsSops, strings.Join(recipients, ","))
+}
+
+// encryptSecret encrypts the Secret the same way as `sops --encrypt --encrypted-regex`. The tree is loaded from the
+// YAML that is rendered for the Secret, so that the MAC covers the values in the order that they are written.
+func (e *sopsEncrypter) encryptSecret(secret *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	jsonBytes, err := secret.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	yamlBytes, err := yaml.JSONToYAML(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	store := sopsyaml.Store{}
+	branches, err := store.LoadPlainFile(yamlBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err = rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	var group sops.KeyGroup
+	for _, recipient := range e.ageRecipients {
+		key, err := sopsage.MasterKeyFromRecipient(recipient)
+		if err != nil {
+			return nil, err
+		}
+		if err = key.Encrypt(dataKey); err != nil {
+			return nil, err
+		}
+		group = append(group, key)
+	}
+	if e.pgpKey != nil {
+		// SOPS only encrypts for PGP keys in the local GnuPG keyring, so the data key is encrypted for the given key
+		// here, and stored in the same format.
+		enc, err := pgpEncrypt(e.pgpKey, dataKey)
+		if err != nil {
+			return nil, err
+		}
+		group = append(group, &pgp.MasterKey{
+			Fingerprint:  fmt.Sprintf("%X", e.pgpKey.PrimaryKey.Fingerprint),
+			EncryptedKey: enc,
+			CreationDate: time.Now().UTC(),
+		})
+	}
+
+	tree := sops.Tree{
+		Branches: branches,
+		Metadata: sops.Metadata{
+			EncryptedRegex: sopsEncryptedRegex,
+			Version:        version.Version,
+			KeyGroups:      []sops.KeyGroup{group},
+		},
+	}
+	cipher := sopsaes.NewCipher()
+	mac, err := tree.Encrypt(dataKey, cipher)
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "failed to encrypt Secret %q", secret.GetName())
+	}
+	tree.Metadata.LastModified = time.Now().UTC()
+	tree.Metadata.MessageAuthenticationCode, err = cipher.Encrypt(mac, dataKey,
+		tree.Metadata.LastModified.Format(time.RFC3339))
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "failed to encrypt MAC of Secret %q", secret.GetName())
+	}
+
+	yamlBytes, err = store.EmitEncryptedFile(tree)
+	if err != nil {
+		return nil, err
+	}
+	if jsonBytes, err = yaml.YAMLToJSON(yamlBytes); err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{}
+	if err = obj.UnmarshalJSON(jsonBytes); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// pgpEncrypt encrypts the SOPS data key for the given PGP key, and returns the ASCII-armored message.
+func pgpEncrypt(key *openpgp.Entity, dataKey []byte) (string, error) {
+	var buf bytes.Buffer
+	armored, err := armor.Encode(&buf, "PGP MESSAGE", nil)
+	if err != nil {
+		return "", err
+	}
+	w, err := openpgp.Encrypt(armored, []*openpgp.Entity{key}, nil, &openpgp.FileHints{IsBinary: true}, nil)
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "failed to encrypt data key with PGP")
+	}
+	if _, err = w.Write(dataKey); err != nil {
+		return "", err
+	}
+	if err = w.Close(); err != nil {
+		return "", err
+	}
+	if err = armored.Close(); err != nil {
+		return "", err
+	}
+	return buf.String() + "\n", nil
+}
+
+// --------------------------------------------------------------------------
+// Sealed Secrets.
+// --------------------------------------------------------------------------
+
+const (
+	sealedSecretAPIVersion = "bitnami.com/v1alpha1"
+	sealedSecretKind       = "SealedSecret"
+)
+
+// sealedSecretsEncrypter renders Secrets as Bitnami SealedSecrets, which can only be decrypted by the sealed-secrets
+// controller in the cluster. Secrets are sealed the same way as `kubeseal`, with the scope given by their annotations,
+// which defaults to the strict scope.
+type sealedSecretsEncrypter struct {
+	publicKey *rsa.PublicKey
+}
+
+func newSealedSecretsEncrypter(key string) (*sealedSecretsEncrypter, error) {
+	if key == "" {
+		return nil, fmt.Errorf("the sealed-secrets controller certificate is required to render SealedSecrets")
+	}
+
+	b, err := readKeyMaterial(key)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("failed to parse sealed-secrets certificate: no PEM certificate found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "failed to parse sealed-secrets certificate")
+	}
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("sealed-secrets certificate does not contain an RSA public key")
+	}
+	return &sealedSecretsEncrypter{publicKey: publicKey}, nil
+}
+
+func (e *sealedSecretsEncrypter) rendering() string {
+	// The certificate of the controller is identified by the hash of its public key.
+	der, err := x509.MarshalPKIXPublicKey(e.publicKey)
+	if err != nil {
+		return string(renderSecretsSealedSecrets)
+	}
+	return fmt.Sprintf("%s:%x", renderSecretsSealedSecrets, sha256.Sum256(der))
+}
+
+func (e *sealedSecretsEncrypter) encryptSecret(secret *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	var s corev1.Secret
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(secret.Object, &s); err != nil {
+		return nil, pkgerrors.Wrapf(err, "failed to read Secret %q", secret.GetName())
+	}
+	if s.Namespace == "" {
+		s.Namespace = "default"
+	}
+
+	sealed, err := ssv1alpha1.NewSealedSecret(scheme.Codecs, e.publicKey, &s)
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "failed to seal Secret %q", secret.GetName())
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(sealed)
+	if err != nil {
+		return nil, err
+	}
+	unstructured.RemoveNestedField(obj, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(obj, "spec", "template", "metadata", "creationTimestamp")
+
+	result := &unstructured.Unstructured{Object: obj}
+	result.SetAPIVersion(sealedSecretAPIVersion)
+	result.SetKind(sealedSecretKind)
+	return result, nil
+}
@@ -0,0 +1,261 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"filippo.io/age"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	ssv1alpha1 "github.com/bitnami-labs/sealed-secrets/pkg/apis/sealed-secrets/v1alpha1"
+	sealedcrypto "github.com/bitnami-labs/sealed-secrets/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sopsaes "go.mozilla.org/sops/v3/aes"
+	"go.mozilla.org/sops/v3/decrypt"
+	"go.mozilla.org/sops/v3/pgp"
+	sopsyaml "go.mozilla.org/sops/v3/stores/yaml"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+func testSecret() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]interface{}{
+			"name":      "creds",
+			"namespace": "app",
+			"labels":    map[string]interface{}{"app": "web"},
+		},
+		"type":       "Opaque",
+		"data":       map[string]interface{}{"password": base64.StdEncoding.EncodeToString([]byte("hunter2"))},
+		"stringData": map[string]interface{}{"user": "admin"},
+	}}
+}
+
+func TestNewSopsEncrypter(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	e, err := newSopsEncrypter(identity.Recipient().String())
+	require.NoError(t, err)
+	assert.Equal(t, []string{identity.Recipient().String()}, e.ageRecipients)
+
+	recipient := identity.Recipient().String()
+	for _, key := range []string{
+		"",
+		"age1",
+		recipient[:len(recipient)-1] + "q",
+		recipient + "," + "age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8q",
+	} {
+		_, err = newSopsEncrypter(key)
+		assert.Error(t, err, key)
+	}
+}
+
+func TestSopsEncryptSecret(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	e, err := newSopsEncrypter(identity.Recipient().String())
+	require.NoError(t, err)
+
+	encrypted, err := encryptSecret(e, testSecret())
+	require.NoError(t, err)
+
+	// Only the data fields are encrypted.
+	assert.Equal(t, "creds", encrypted.GetName())
+	assert.Equal(t, map[string]string{"app": "web"}, encrypted.GetLabels())
+	assert.Equal(t, "Opaque", encrypted.Object["type"])
+	password, _, _ := unstructured.NestedString(encrypted.Object, "data", "password")
+	assert.True(t, strings.HasPrefix(password, "ENC[AES256_GCM,"), password)
+	encryptedRegex, _, _ := unstructured.NestedString(encrypted.Object, "sops", "encrypted_regex")
+	assert.Equal(t, sopsEncryptedRegex, encryptedRegex)
+
+	// The rendered manifest is decrypted by SOPS with the age identity, which also verifies the MAC.
+	keyFile := filepath.Join(t.TempDir(), "keys.txt")
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte(identity.String()+"\n"), 0600))
+	setEnv(t, map[string]string{"SOPS_AGE_KEY_FILE": keyFile})
+	cleartext, err := decrypt.Data(testRenderYAML(t, encrypted), "yaml")
+	require.NoError(t, err)
+	assert.Equal(t, testSecret().Object, testParseYAML(t, cleartext))
+}
+
+func TestSopsEncryptSecretPGP(t *testing.T) {
+	entity, err := openpgp.NewEntity("test", "", "test@example.com", nil)
+	require.NoError(t, err)
+	var publicKey bytes.Buffer
+	w, err := armor.Encode(&publicKey, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+
+	e, err := newSopsEncrypter(publicKey.String())
+	require.NoError(t, err)
+	encrypted, err := encryptSecret(e, testSecret())
+	require.NoError(t, err)
+
+	store := sopsyaml.Store{}
+	tree, err := store.LoadEncryptedFile(testRenderYAML(t, encrypted))
+	require.NoError(t, err)
+	require.Len(t, tree.Metadata.KeyGroups, 1)
+	require.Len(t, tree.Metadata.KeyGroups[0], 1)
+	key := tree.Metadata.KeyGroups[0][0].(*pgp.MasterKey)
+	assert.Equal(t, strings.ToUpper(entity.PrimaryKey.KeyIdString()), key.Fingerprint[len(key.Fingerprint)-16:])
+
+	// The data key can be recovered with the PGP private key, and decrypts the values and the MAC.
+	block, err := armor.Decode(strings.NewReader(key.EncryptedKey))
+	require.NoError(t, err)
+	md, err := openpgp.ReadMessage(block.Body, openpgp.EntityList{entity}, nil, nil)
+	require.NoError(t, err)
+	dataKey, err := ioutil.ReadAll(md.UnverifiedBody)
+	require.NoError(t, err)
+
+	cipher := sopsaes.NewCipher()
+	mac, err := tree.Decrypt(dataKey, cipher)
+	require.NoError(t, err)
+	decryptedMAC, err := cipher.Decrypt(tree.Metadata.MessageAuthenticationCode, dataKey,
+		tree.Metadata.LastModified.Format(time.RFC3339))
+	require.NoError(t, err)
+	assert.Equal(t, mac, decryptedMAC)
+
+	cleartext, err := store.EmitPlainFile(tree.Branches)
+	require.NoError(t, err)
+	assert.Equal(t, testSecret().Object, testParseYAML(t, cleartext))
+}
+
+// testSealedSecretsCertificate returns the PEM-encoded certificate of a sealed-secrets controller, and its private key.
+func testSealedSecretsCertificate(t *testing.T) (string, *rsa.PrivateKey) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sealed-secret"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), privateKey
+}
+
+func TestSealedSecretsEncryptSecret(t *testing.T) {
+	cert, privateKey := testSealedSecretsCertificate(t)
+
+	_, err := newSealedSecretsEncrypter("")
+	assert.Error(t, err)
+	e, err := newSealedSecretsEncrypter(cert)
+	require.NoError(t, err)
+
+	secret := testSecret()
+	secret.SetAnnotations(map[string]string{lastAppliedConfigKey: "{}"})
+	sealed, err := encryptSecret(e, secret)
+	require.NoError(t, err)
+
+	assert.Equal(t, sealedSecretAPIVersion, sealed.GetAPIVersion())
+	assert.Equal(t, sealedSecretKind, sealed.GetKind())
+	assert.Equal(t, "app", sealed.GetNamespace())
+	templateType, _, _ := unstructured.NestedString(sealed.Object, "spec", "template", "type")
+	assert.Equal(t, "Opaque", templateType)
+	_, found, _ := unstructured.NestedStringMap(sealed.Object, "spec", "template", "metadata", "annotations")
+	assert.False(t, found)
+
+	// The SealedSecret is unsealed by the sealed-secrets controller with the private key.
+	var s ssv1alpha1.SealedSecret
+	require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(sealed.Object, &s))
+	fingerprint, err := sealedcrypto.PublicKeyFingerprint(&privateKey.PublicKey)
+	require.NoError(t, err)
+	unsealed, err := s.Unseal(scheme.Codecs, map[string]*rsa.PrivateKey{fingerprint: privateKey})
+	require.NoError(t, err)
+	decrypted := map[string]string{}
+	for key, value := range unsealed.Data {
+		decrypted[key] = string(value)
+	}
+	assert.Equal(t, map[string]string{"password": "hunter2", "user": "admin"}, decrypted)
+
+	// The rendered file is named after the SealedSecret, whether the path is computed from the Secret or not.
+	r := &yamlRenderer{directory: "out", layout: renderLayoutDirectory, secrets: e}
+	assert.Equal(t, filepath.Join("out", "1-manifest", "sealedsecret-app-creds.yaml"), r.path(testURN, secret))
+	assert.Equal(t, r.path(testURN, secret), r.path(testURN, sealed))
+}
+
+// testRenderYAML returns the YAML that is rendered for the given object.
+func testRenderYAML(t *testing.T, obj *unstructured.Unstructured) []byte {
+	jsonBytes, err := obj.MarshalJSON()
+	require.NoError(t, err)
+	yamlBytes, err := yaml.JSONToYAML(jsonBytes)
+	require.NoError(t, err)
+	return yamlBytes
+}
+
+// testParseYAML parses a YAML manifest into an unstructured object.
+func testParseYAML(t *testing.T, b []byte) map[string]interface{} {
+	jsonBytes, err := yaml.YAMLToJSON(b)
+	require.NoError(t, err)
+	obj := &unstructured.Unstructured{}
+	require.NoError(t, obj.UnmarshalJSON(jsonBytes))
+	return obj.Object
+}
+
+func TestRenderPreviousSecret(t *testing.T) {
+	dir := t.TempDir()
+	cert, _ := testSealedSecretsCertificate(t)
+	e, err := newSealedSecretsEncrypter(cert)
+	require.NoError(t, err)
+	otherCert, _ := testSealedSecretsCertificate(t)
+	other, err := newSealedSecretsEncrypter(otherCert)
+	require.NoError(t, err)
+
+	plaintext := &yamlRenderer{directory: dir, layout: renderLayoutDirectory}
+	sealed := &yamlRenderer{directory: dir, layout: renderLayoutDirectory, secrets: e}
+	secret := testSecret()
+	secretPath := filepath.Join(dir, "1-manifest", "secret-app-creds.yaml")
+	sealedPath := filepath.Join(dir, "1-manifest", "sealedsecret-app-creds.yaml")
+
+	// Secrets are rendered again when the format or the key that they are encrypted with changes.
+	assert.Equal(t, "plaintext", secretsRendering(nil))
+	assert.True(t, sealed.rerendersSecret(secret, secretsRendering(nil)))
+	assert.True(t, sealed.rerendersSecret(secret, secretsRendering(other)))
+	assert.False(t, sealed.rerendersSecret(secret, secretsRendering(e)))
+	assert.False(t, sealed.rerendersSecret(renderTestObject("v1", "ConfigMap", "app", "creds"), "plaintext"))
+	assert.Equal(t, "plaintext", previousSecretsRendering(nil))
+
+	// The manifest of a Secret is removed when it is rendered as a SealedSecret instead, and the other way around.
+	require.NoError(t, plaintext.render(testURN, secret, nil))
+	assert.FileExists(t, secretPath)
+	require.NoError(t, sealed.removePreviousSecret(testURN, secret, secretsRendering(nil)))
+	require.NoError(t, sealed.render(testURN, secret, nil))
+	assert.NoFileExists(t, secretPath)
+	assert.FileExists(t, sealedPath)
+
+	require.NoError(t, plaintext.removePreviousSecret(testURN, secret, secretsRendering(e)))
+	require.NoError(t, plaintext.render(testURN, secret, nil))
+	assert.NoFileExists(t, sealedPath)
+	assert.FileExists(t, secretPath)
+}
@@ -0,0 +1,731 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/kinds"
+	"github.com/pulumi/pulumi-kubernetes/provider/v3/pkg/metadata"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	logger "github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	kustomizationFileName = "kustomization.yaml"
+
+	// unresolvedReportFileName is the name of the side-car report that lists the fields that were rendered with a
+	// placeholder because their values were unknown.
+	unresolvedReportFileName = "unresolved-fields.json"
+
+	// unresolvedPlaceholder is rendered in place of values that are unknown.
+	unresolvedPlaceholder = "<unresolved>"
+)
+
+// renderLayout controls how rendered manifests are arranged in the render directory.
+type renderLayout string
+
+const (
+	// renderLayoutDirectory writes one file per object, grouped into ordered subdirectories. This is the default.
+	renderLayoutDirectory renderLayout = "directory"
+	// renderLayoutComponent writes one file per object, grouped into a directory per component resource (e.g., a
+	// Chart or ConfigGroup) so that each component can be synced independently by GitOps tooling.
+	renderLayoutComponent renderLayout = "component"
+	// renderLayoutSingle writes every object to a single multi-document file named after the stack.
+	renderLayoutSingle renderLayout = "single"
+)
+
+// parseRenderLayout returns the renderLayout for the given config value. An empty value selects the default layout.
+func parseRenderLayout(value string) (renderLayout, error) {
+	switch layout := renderLayout(value); layout {
+	case "":
+		return renderLayoutDirectory, nil
+	case renderLayoutDirectory, renderLayoutComponent, renderLayoutSingle:
+		return layout, nil
+	default:
+		return "", fmt.Errorf("unknown render layout %q; expected one of %q, %q, or %q",
+			value, renderLayoutDirectory, renderLayoutComponent, renderLayoutSingle)
+	}
+}
+
+// renderUnknowns controls how resources with unknown input values are rendered.
+type renderUnknowns string
+
+const (
+	// renderUnknownsDefer skips writing a resource until all of its values are known, and lists it in a side-car
+	// report. This is the default.
+	renderUnknownsDefer renderUnknowns = "defer"
+	// renderUnknownsPlaceholder renders unknown values as a placeholder, and lists them in a side-car report.
+	renderUnknownsPlaceholder renderUnknowns = "placeholder"
+)
+
+// parseRenderUnknowns returns the renderUnknowns mode for the given config value. An empty value selects the default.
+func parseRenderUnknowns(value string) (renderUnknowns, error) {
+	switch unknowns := renderUnknowns(value); unknowns {
+	case "":
+		return renderUnknownsDefer, nil
+	case renderUnknownsDefer, renderUnknownsPlaceholder:
+		return unknowns, nil
+	default:
+		return "", fmt.Errorf("unknown render mode for unknown values %q; expected %q or %q",
+			value, renderUnknownsDefer, renderUnknownsPlaceholder)
+	}
+}
+
+// yamlRenderer writes resource manifests to a directory on disk rather than creating them on a cluster. This is used
+// when the `renderYamlToDirectory` option is set on the provider.
+type yamlRenderer struct {
+	directory string
+	layout    renderLayout
+
+	// clean strips the last-applied-configuration and Pulumi annotations from the rendered manifests, orders them so
+	// that they can be applied as-is with `kubectl apply -k`, and maintains a kustomization.yaml listing every file.
+	clean bool
+
+	// unknowns controls how values that are still unknown at render time are handled.
+	unknowns renderUnknowns
+
+	// secrets encrypts the data of Secrets before they are written. Secrets are written in plaintext if it is nil.
+	secrets secretEncrypter
+
+	// mu serializes writes to the render directory, since resources are rendered concurrently and the
+	// kustomization.yaml is regenerated after each change.
+	mu sync.Mutex
+}
+
+// resolveUnknowns prepares an object with unknown values for rendering, and returns it along with the paths of its
+// unknown values. If placeholders are enabled, the returned object is a copy with each unknown value replaced by a
+// placeholder. Otherwise, the object is returned as-is, and its rendering is deferred until its values are known.
+func (r *yamlRenderer) resolveUnknowns(obj *unstructured.Unstructured) (*unstructured.Unstructured, []string) {
+	unresolved := computedValuePaths(obj)
+	if len(unresolved) == 0 || r.unknowns != renderUnknownsPlaceholder {
+		return obj, unresolved
+	}
+	return replaceComputedValues(obj, unresolvedPlaceholder), unresolved
+}
+
+// defers returns true if a resource with the given unresolved paths is not written until its values are known.
+func (r *yamlRenderer) defers(unresolved []string) bool {
+	return len(unresolved) > 0 && r.unknowns != renderUnknownsPlaceholder
+}
+
+// render marshals an Unstructured resource to YAML and writes it to the appropriate path on disk or returns an error.
+// The unresolved paths are the fields that were rendered with a placeholder, and are recorded in the side-car report.
+// If the rendering of the resource is deferred, the manifest is not written, and any manifest that was rendered for it
+// before is kept; the resource is only recorded in the side-car report.
+func (r *yamlRenderer) render(urn resource.URN, obj *unstructured.Unstructured, unresolved []string) error {
+	if r.defers(unresolved) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.updateReport(r.path(urn, obj), obj, unresolved, true)
+	}
+
+	if r.clean {
+		obj = cleanRenderedObject(obj)
+	}
+	if r.encryptsSecret(obj) {
+		var err error
+		if obj, err = encryptSecret(r.secrets, obj); err != nil {
+			return err
+		}
+	}
+
+	jsonBytes, err := obj.MarshalJSON()
+	if err != nil {
+		return pkgerrors.Wrapf(err, "failed to render YAML file: %q", r.directory)
+	}
+	yamlBytes, err := yaml.JSONToYAML(jsonBytes)
+	if err != nil {
+		return pkgerrors.Wrapf(err, "failed to render YAML file: %q", r.directory)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	path := r.path(urn, obj)
+
+	directories := []string{filepath.Dir(path)}
+	if !r.clean && r.layout == renderLayoutDirectory {
+		// The CRD and manifest directories have always both been created, even if one of them is empty.
+		directories = []string{
+			filepath.Join(r.directory, "0-crd"),
+			filepath.Join(r.directory, "1-manifest"),
+		}
+	}
+	for _, directory := range directories {
+		if _, err := os.Stat(directory); os.IsNotExist(err) {
+			err = os.MkdirAll(directory, 0700)
+			if err != nil {
+				return pkgerrors.Wrapf(err, "failed to create directory for rendered YAML: %q", directory)
+			}
+		}
+	}
+
+	if r.layout == renderLayoutSingle {
+		yamlBytes, err = r.updateDocuments(path, obj, yamlBytes)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = ioutil.WriteFile(path, yamlBytes, 0600)
+	if err != nil {
+		return pkgerrors.Wrapf(err, "failed to write YAML file: %q", path)
+	}
+
+	if err = r.updateReport(path, obj, unresolved, false); err != nil {
+		return err
+	}
+
+	return r.writeKustomization()
+}
+
+// remove deletes the rendered manifest for the given resource, and returns the path of the file it was rendered to.
+func (r *yamlRenderer) remove(urn resource.URN, obj *unstructured.Unstructured) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	path := r.path(urn, obj)
+
+	if err := r.updateReport(path, obj, nil, false); err != nil {
+		return path, err
+	}
+
+	if r.layout == renderLayoutSingle {
+		yamlBytes, err := r.updateDocuments(path, obj, nil)
+		if err != nil {
+			return path, err
+		}
+		if len(yamlBytes) > 0 {
+			if err = ioutil.WriteFile(path, yamlBytes, 0600); err != nil {
+				return path, pkgerrors.Wrapf(err, "failed to write YAML file: %q", path)
+			}
+			return path, r.writeKustomization()
+		}
+	}
+
+	if err := os.Remove(path); err != nil {
+		return path, err
+	}
+
+	return path, r.writeKustomization()
+}
+
+// encryptsSecret returns true if the given object is a Secret whose data is encrypted before it is rendered.
+func (r *yamlRenderer) encryptsSecret(obj *unstructured.Unstructured) bool {
+	return r.secrets != nil && isSecret(obj)
+}
+
+// renderedIdentity returns an object with the kind that the given object is rendered as, so that the paths of
+// SealedSecrets are the same whether they are computed from the Secret or from the rendered object.
+func (r *yamlRenderer) renderedIdentity(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	_, sealed := r.secrets.(*sealedSecretsEncrypter)
+	return renderedIdentity(obj, sealed)
+}
+
+// renderedIdentity returns an object with the kind that the given object is rendered as, if Secrets are rendered as
+// SealedSecrets or not.
+func renderedIdentity(obj *unstructured.Unstructured, sealed bool) *unstructured.Unstructured {
+	if !sealed || !isSecret(obj) {
+		return obj
+	}
+
+	identity := &unstructured.Unstructured{}
+	identity.SetAPIVersion(sealedSecretAPIVersion)
+	identity.SetKind(sealedSecretKind)
+	identity.SetNamespace(obj.GetNamespace())
+	identity.SetName(obj.GetName())
+	return identity
+}
+
+// rerendersSecret returns true if the given object is a Secret that was rendered differently than Secrets are rendered
+// now, i.e., in another format or with another key.
+func (r *yamlRenderer) rerendersSecret(obj *unstructured.Unstructured, previous string) bool {
+	return isSecret(obj) && previous != secretsRendering(r.secrets)
+}
+
+// removePreviousSecret removes the manifest that a Secret was previously rendered to, if it was rendered as a Secret
+// and is now rendered as a SealedSecret, or the other way around, since the manifests of the two kinds have different
+// paths.
+func (r *yamlRenderer) removePreviousSecret(urn resource.URN, obj *unstructured.Unstructured, previous string) error {
+	_, sealed := r.secrets.(*sealedSecretsEncrypter)
+	wasSealed := strings.HasPrefix(previous, string(renderSecretsSealedSecrets))
+	if !isSecret(obj) || sealed == wasSealed {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	identity := renderedIdentity(obj, wasSealed)
+	path := r.identityPath(urn, identity)
+	if err := r.updateReport(path, identity, nil, false); err != nil {
+		return err
+	}
+
+	if r.layout == renderLayoutSingle {
+		// The manifest of a Secret has the same document key as the SealedSecret it is now rendered as, and is replaced
+		// when the SealedSecret is rendered, but the manifest of a SealedSecret must be removed.
+		if !wasSealed {
+			return nil
+		}
+		yamlBytes, err := r.updateDocuments(path, identity, nil)
+		if err != nil {
+			return err
+		}
+		if err = ioutil.WriteFile(path, yamlBytes, 0600); err != nil {
+			return pkgerrors.Wrapf(err, "failed to write YAML file: %q", path)
+		}
+		return nil
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return r.writeKustomization()
+}
+
+// path determines the appropriate YAML render path depending on the resource kind and the render layout.
+func (r *yamlRenderer) path(urn resource.URN, obj *unstructured.Unstructured) string {
+	return r.identityPath(urn, r.renderedIdentity(obj))
+}
+
+// identityPath returns the render path of an object that has the kind that it is rendered as.
+func (r *yamlRenderer) identityPath(urn resource.URN, obj *unstructured.Unstructured) string {
+	switch r.layout {
+	case renderLayoutSingle:
+		return filepath.Join(r.directory, fmt.Sprintf("%s.yaml", urn.Stack()))
+	case renderLayoutComponent:
+		return filepath.Join(r.directory, renderComponent(urn, obj), r.orderDirectory(obj),
+			renderComponentFileName(urn, obj))
+	default:
+		return filepath.Join(r.directory, r.orderDirectory(obj), renderFileName(obj))
+	}
+}
+
+// renderFileName returns the name of the file that a resource is rendered to in the per-object layouts.
+func renderFileName(obj *unstructured.Unstructured) string {
+	namespace := "default"
+	if "" != obj.GetNamespace() {
+		namespace = obj.GetNamespace()
+	}
+
+	return fmt.Sprintf("%s-%s-%s.yaml", strings.ToLower(obj.GetKind()), namespace, obj.GetName())
+}
+
+// renderComponentFileName returns the name of the file that a resource is rendered to in the component layout.
+//
+// Components of the same type share a directory unless their objects carry an instance label, so the file is named
+// after the URN name of the resource rather than after the object. URN names are unique among the resources of a type,
+// so the files of two components never collide, even if they render objects with the same name.
+func renderComponentFileName(urn resource.URN, obj *unstructured.Unstructured) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		default:
+			return '-'
+		}
+	}, string(urn.Name()))
+
+	return fmt.Sprintf("%s-%s.yaml", strings.ToLower(obj.GetKind()), name)
+}
+
+// renderComponent returns the name of the component directory for a resource in the component layout.
+//
+// The URN records the types of a resource's parents, but not their names. The directory is therefore named after the
+// innermost component type in the parent chain (e.g., "chart" or "configgroup"). Objects rendered by a Helm chart
+// conventionally carry the release name in the `app.kubernetes.io/instance` label, which is the Chart resource name
+// unless overridden, so it is appended when present to keep separate Charts apart. Resources that do not belong to a
+// component are written to a directory named after the stack.
+func renderComponent(urn resource.URN, obj *unstructured.Unstructured) string {
+	var parents []string
+	for _, typ := range strings.Split(string(urn.QualifiedType()), resource.URNTypeDelimiter) {
+		if typ != "pulumi:pulumi:Stack" {
+			parents = append(parents, typ)
+		}
+	}
+	if len(parents) < 2 {
+		return string(urn.Stack())
+	}
+
+	parentType := tokens.Type(parents[len(parents)-2])
+	component := strings.ToLower(string(parentType.Name()))
+	if instance := obj.GetLabels()["app.kubernetes.io/instance"]; instance != "" {
+		component = fmt.Sprintf("%s-%s", component, instance)
+	}
+	return component
+}
+
+// updateDocuments replaces the document for the given resource in the multi-document file at path, and returns the
+// new contents of the file. The document is removed if rendered is nil. Documents are sorted in the order they must be
+// applied, so the file contents do not depend on the order in which resources were rendered.
+func (r *yamlRenderer) updateDocuments(path string, obj *unstructured.Unstructured, rendered []byte,
+) ([]byte, error) {
+	documents := map[string][]byte{}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, pkgerrors.Wrapf(err, "failed to read YAML file: %q", path)
+	}
+	reader := k8syaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(b)))
+	for {
+		document, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, pkgerrors.Wrapf(err, "failed to read YAML file: %q", path)
+		}
+		existing := &unstructured.Unstructured{}
+		if err = yaml.Unmarshal(document, &existing.Object); err != nil {
+			return nil, pkgerrors.Wrapf(err, "failed to parse YAML file: %q", path)
+		}
+		if len(existing.Object) == 0 {
+			continue
+		}
+		documents[r.documentKey(existing)] = bytes.TrimPrefix(document, []byte("---\n"))
+	}
+
+	if rendered == nil {
+		delete(documents, r.documentKey(obj))
+	} else {
+		documents[r.documentKey(obj)] = rendered
+	}
+
+	var keys []string
+	for key := range documents {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	for _, key := range keys {
+		buf.WriteString("---\n")
+		buf.Write(documents[key])
+	}
+	return buf.Bytes(), nil
+}
+
+// documentKey returns a key that identifies a resource in a multi-document file, and sorts in the order that the
+// documents must be applied.
+func (r *yamlRenderer) documentKey(obj *unstructured.Unstructured) string {
+	obj = r.renderedIdentity(obj)
+	return filepath.ToSlash(filepath.Join(r.orderDirectory(obj), renderFileName(obj)))
+}
+
+// orderDirectory returns the subdirectory for the given resource. The subdirectories are prefixed with a number so
+// that a lexical walk of the render directory visits them in the order they must be applied.
+func (r *yamlRenderer) orderDirectory(obj *unstructured.Unstructured) string {
+	kind := kinds.Kind(obj.GetKind())
+	if !r.clean {
+		if kind == kinds.CustomResourceDefinition {
+			return "0-crd"
+		}
+		return "1-manifest"
+	}
+
+	switch kind {
+	case kinds.CustomResourceDefinition:
+		return "0-crd"
+	case kinds.Namespace:
+		// Namespaces must exist before any of the namespaced resources inside them are applied.
+		return "1-namespace"
+	case kinds.MutatingWebhookConfiguration, kinds.ValidatingWebhookConfiguration:
+		// Webhooks are applied last, since a webhook that is registered before its backing Service is running will
+		// reject every other resource that it matches.
+		return "3-webhook"
+	default:
+		return "2-manifest"
+	}
+}
+
+// unresolvedFields is an entry in the side-car report that lists the fields of a rendered object that were rendered
+// with a placeholder, or the unknown fields of an object whose rendering was deferred.
+type unresolvedFields struct {
+	File       string   `json:"file"`
+	APIVersion string   `json:"apiVersion"`
+	Kind       string   `json:"kind"`
+	Namespace  string   `json:"namespace,omitempty"`
+	Name       string   `json:"name"`
+	Fields     []string `json:"fields"`
+	// Deferred is set if the file was not written because the fields are unknown.
+	Deferred bool `json:"deferred,omitempty"`
+}
+
+// updateReport replaces the side-car report entry for the given object with the given unresolved fields, or removes
+// it if there are none. The report is deleted once it is empty. It must be called with r.mu held.
+func (r *yamlRenderer) updateReport(path string, obj *unstructured.Unstructured, unresolved []string,
+	deferred bool) error {
+	reportPath := filepath.Join(r.directory, unresolvedReportFileName)
+	file, err := filepath.Rel(r.directory, path)
+	if err != nil {
+		return err
+	}
+	file = filepath.ToSlash(file)
+
+	var entries []unresolvedFields
+	b, err := ioutil.ReadFile(reportPath)
+	switch {
+	case os.IsNotExist(err):
+		if len(unresolved) == 0 {
+			return nil
+		}
+	case err != nil:
+		return pkgerrors.Wrapf(err, "failed to read unresolved fields report: %q", reportPath)
+	default:
+		if err = json.Unmarshal(b, &entries); err != nil {
+			return pkgerrors.Wrapf(err, "failed to parse unresolved fields report: %q", reportPath)
+		}
+	}
+
+	var updated []unresolvedFields
+	for _, entry := range entries {
+		if entry.File != file || entry.Kind != obj.GetKind() || entry.Namespace != obj.GetNamespace() ||
+			entry.Name != obj.GetName() {
+			updated = append(updated, entry)
+		}
+	}
+	if len(unresolved) > 0 {
+		updated = append(updated, unresolvedFields{
+			File:       file,
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Namespace:  obj.GetNamespace(),
+			Name:       obj.GetName(),
+			Fields:     unresolved,
+			Deferred:   deferred,
+		})
+	}
+
+	if len(updated) == 0 {
+		if err = os.Remove(reportPath); err != nil && !os.IsNotExist(err) {
+			return pkgerrors.Wrapf(err, "failed to remove unresolved fields report: %q", reportPath)
+		}
+		return nil
+	}
+
+	sort.Slice(updated, func(i, j int) bool {
+		a, b := updated[i], updated[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	b, err = json.MarshalIndent(updated, "", "    ")
+	if err != nil {
+		return pkgerrors.Wrapf(err, "failed to render unresolved fields report")
+	}
+	if err = ioutil.WriteFile(reportPath, b, 0600); err != nil {
+		return pkgerrors.Wrapf(err, "failed to write unresolved fields report: %q", reportPath)
+	}
+
+	return nil
+}
+
+// writeKustomization regenerates the kustomization.yaml at the root of the render directory so that it lists every
+// rendered file. In the component layout, each component directory gets its own kustomization.yaml, and the root
+// kustomization.yaml lists the components. It must be called with r.mu held.
+func (r *yamlRenderer) writeKustomization() error {
+	if !r.clean {
+		return nil
+	}
+
+	var files []string
+	err := filepath.Walk(r.directory, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".yaml" || info.Name() == kustomizationFileName {
+			return nil
+		}
+		rel, err := filepath.Rel(r.directory, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return pkgerrors.Wrapf(err, "failed to list rendered YAML files in %q", r.directory)
+	}
+
+	if r.layout != renderLayoutComponent {
+		return writeKustomizationFile(r.directory, files)
+	}
+
+	components := map[string][]string{}
+	for _, file := range files {
+		parts := strings.SplitN(file, "/", 2)
+		if len(parts) < 2 {
+			// Every rendered file is in the directory of its component, so files at the top level were not written
+			// by the renderer and are left out of the kustomization.
+			logger.V(3).Infof("skipping %q in %q: not in a component directory", file, r.directory)
+			continue
+		}
+		components[parts[0]] = append(components[parts[0]], parts[1])
+	}
+
+	// Remove the directories of components that no longer contain any rendered files. Only the components that the
+	// root kustomization.yaml lists were written by the renderer, so other directories are left alone.
+	previous, err := readKustomizationResources(r.directory)
+	if err != nil {
+		return err
+	}
+	for _, component := range previous {
+		if _, ok := components[component]; !ok {
+			if err = removeComponentDirectory(filepath.Join(r.directory, component)); err != nil {
+				return err
+			}
+		}
+	}
+
+	var names []string
+	for component, componentFiles := range components {
+		if err = writeKustomizationFile(filepath.Join(r.directory, component), componentFiles); err != nil {
+			return err
+		}
+		names = append(names, component)
+	}
+	return writeKustomizationFile(r.directory, names)
+}
+
+// writeKustomizationFile writes a kustomization.yaml to the given directory that lists the given resources.
+func writeKustomizationFile(directory string, resources []string) error {
+	sort.Strings(resources)
+
+	kustomization := types.Kustomization{
+		TypeMeta: types.TypeMeta{
+			APIVersion: types.KustomizationVersion,
+			Kind:       types.KustomizationKind,
+		},
+		Resources: resources,
+	}
+	yamlBytes, err := yaml.Marshal(kustomization)
+	if err != nil {
+		return pkgerrors.Wrapf(err, "failed to render %s", kustomizationFileName)
+	}
+
+	path := filepath.Join(directory, kustomizationFileName)
+	if err = ioutil.WriteFile(path, yamlBytes, 0600); err != nil {
+		return pkgerrors.Wrapf(err, "failed to write YAML file: %q", path)
+	}
+
+	return nil
+}
+
+// readKustomizationResources returns the resources that the kustomization.yaml in the given directory lists, or nil if
+// there is no kustomization.yaml.
+func readKustomizationResources(directory string) ([]string, error) {
+	path := filepath.Join(directory, kustomizationFileName)
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "failed to read YAML file: %q", path)
+	}
+
+	var kustomization types.Kustomization
+	if err = yaml.Unmarshal(b, &kustomization); err != nil {
+		return nil, pkgerrors.Wrapf(err, "failed to parse YAML file: %q", path)
+	}
+	return kustomization.Resources, nil
+}
+
+// removeComponentDirectory removes the kustomization.yaml of a component that no longer contains any rendered files,
+// along with the directories that are left empty. Files that were not written by the renderer are kept.
+func removeComponentDirectory(directory string) error {
+	path := filepath.Join(directory, kustomizationFileName)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return pkgerrors.Wrapf(err, "failed to remove YAML file: %q", path)
+	}
+
+	var directories []string
+	err := filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			directories = append(directories, path)
+		}
+		return nil
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return pkgerrors.Wrapf(err, "failed to list component directory %q", directory)
+	}
+
+	// Subdirectories are walked after their parents, so they are removed first.
+	for i := len(directories) - 1; i >= 0; i-- {
+		entries, err := ioutil.ReadDir(directories[i])
+		if err != nil {
+			return pkgerrors.Wrapf(err, "failed to list component directory %q", directories[i])
+		}
+		if len(entries) > 0 {
+			continue
+		}
+		if err = os.Remove(directories[i]); err != nil {
+			return pkgerrors.Wrapf(err, "failed to remove empty component directory %q", directories[i])
+		}
+	}
+	return nil
+}
+
+// cleanRenderedObject returns a copy of the given object without the last-applied-configuration annotation or any
+// annotations that only have meaning to the Pulumi provider.
+func cleanRenderedObject(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	obj = obj.DeepCopy()
+
+	annotations := obj.GetAnnotations()
+	for key := range annotations {
+		if key == lastAppliedConfigKey || strings.HasPrefix(key, metadata.AnnotationPrefix) {
+			delete(annotations, key)
+		}
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	obj.SetAnnotations(annotations)
+
+	return obj
+}
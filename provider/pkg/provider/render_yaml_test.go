@@ -0,0 +1,304 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+var testURN = resource.URN("urn:pulumi:dev::render::kubernetes:apps/v1:Deployment::nginx")
+
+func renderTestObject(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name": name,
+			"annotations": map[string]interface{}{
+				lastAppliedConfigKey:   "{}",
+				"pulumi.com/autonamed": "true",
+				"example.com/keep":     "yes",
+			},
+		},
+	}}
+	if namespace != "" {
+		obj.SetNamespace(namespace)
+	}
+	return obj
+}
+
+func TestRenderPath(t *testing.T) {
+	crd := renderTestObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "", "foos.example.com")
+	ns := renderTestObject("v1", "Namespace", "", "app")
+	deployment := renderTestObject("apps/v1", "Deployment", "app", "nginx")
+	webhook := renderTestObject(
+		"admissionregistration.k8s.io/v1", "ValidatingWebhookConfiguration", "", "policy")
+
+	r := &yamlRenderer{directory: "out", layout: renderLayoutDirectory}
+	assert.Equal(t, filepath.Join("out", "0-crd", "customresourcedefinition-default-foos.example.com.yaml"),
+		r.path(testURN, crd))
+	assert.Equal(t, filepath.Join("out", "1-manifest", "namespace-default-app.yaml"), r.path(testURN, ns))
+	assert.Equal(t, filepath.Join("out", "1-manifest", "deployment-app-nginx.yaml"), r.path(testURN, deployment))
+	assert.Equal(t, filepath.Join("out", "1-manifest", "validatingwebhookconfiguration-default-policy.yaml"),
+		r.path(testURN, webhook))
+
+	r = &yamlRenderer{directory: "out", layout: renderLayoutDirectory, clean: true}
+	assert.Equal(t, filepath.Join("out", "0-crd", "customresourcedefinition-default-foos.example.com.yaml"),
+		r.path(testURN, crd))
+	assert.Equal(t, filepath.Join("out", "1-namespace", "namespace-default-app.yaml"), r.path(testURN, ns))
+	assert.Equal(t, filepath.Join("out", "2-manifest", "deployment-app-nginx.yaml"), r.path(testURN, deployment))
+	assert.Equal(t, filepath.Join("out", "3-webhook", "validatingwebhookconfiguration-default-policy.yaml"),
+		r.path(testURN, webhook))
+}
+
+func TestRenderClean(t *testing.T) {
+	dir, err := ioutil.TempDir("", "render-yaml-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	r := &yamlRenderer{directory: dir, layout: renderLayoutDirectory, clean: true}
+	deployment := renderTestObject("apps/v1", "Deployment", "app", "nginx")
+	webhook := renderTestObject(
+		"admissionregistration.k8s.io/v1", "ValidatingWebhookConfiguration", "", "policy")
+	require.NoError(t, r.render(testURN, webhook, nil))
+	require.NoError(t, r.render(testURN, deployment, nil))
+	require.NoError(t, r.render(testURN, renderTestObject("v1", "Namespace", "", "app"), nil))
+
+	// Internal annotations are stripped, and user annotations are kept.
+	b, err := ioutil.ReadFile(r.path(testURN, deployment))
+	require.NoError(t, err)
+	rendered := &unstructured.Unstructured{}
+	require.NoError(t, yaml.Unmarshal(b, &rendered.Object))
+	assert.Equal(t, map[string]string{"example.com/keep": "yes"}, rendered.GetAnnotations())
+
+	// The input object is not modified.
+	assert.Len(t, deployment.GetAnnotations(), 3)
+
+	readKustomization := func() []string {
+		b, err := ioutil.ReadFile(filepath.Join(dir, kustomizationFileName))
+		require.NoError(t, err)
+		var kustomization struct {
+			Kind      string   `json:"kind"`
+			Resources []string `json:"resources"`
+		}
+		require.NoError(t, yaml.Unmarshal(b, &kustomization))
+		assert.Equal(t, "Kustomization", kustomization.Kind)
+		return kustomization.Resources
+	}
+	assert.Equal(t, []string{
+		"1-namespace/namespace-default-app.yaml",
+		"2-manifest/deployment-app-nginx.yaml",
+		"3-webhook/validatingwebhookconfiguration-default-policy.yaml",
+	}, readKustomization())
+
+	path, err := r.remove(testURN, webhook)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "3-webhook", "validatingwebhookconfiguration-default-policy.yaml"), path)
+	assert.Equal(t, []string{
+		"1-namespace/namespace-default-app.yaml",
+		"2-manifest/deployment-app-nginx.yaml",
+	}, readKustomization())
+}
+
+func TestRenderDefault(t *testing.T) {
+	dir, err := ioutil.TempDir("", "render-yaml-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	r := &yamlRenderer{directory: dir, layout: renderLayoutDirectory}
+	deployment := renderTestObject("apps/v1", "Deployment", "app", "nginx")
+	require.NoError(t, r.render(testURN, deployment, nil))
+
+	b, err := ioutil.ReadFile(r.path(testURN, deployment))
+	require.NoError(t, err)
+	rendered := &unstructured.Unstructured{}
+	require.NoError(t, yaml.Unmarshal(b, &rendered.Object))
+	assert.Equal(t, deployment.GetAnnotations(), rendered.GetAnnotations())
+
+	assert.DirExists(t, filepath.Join(dir, "0-crd"))
+	assert.NoFileExists(t, filepath.Join(dir, kustomizationFileName))
+}
+
+func TestRenderComponentLayout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "render-yaml-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	chartURN := resource.URN(
+		"urn:pulumi:dev::render::kubernetes:helm.sh/v3:Chart$kubernetes:apps/v1:Deployment::default/nginx")
+	groupURN := resource.URN(
+		"urn:pulumi:dev::render::kubernetes:yaml:ConfigGroup$kubernetes:yaml:ConfigFile$kubernetes:v1:Service::svc")
+
+	chartDeployment := renderTestObject("apps/v1", "Deployment", "app", "nginx")
+	chartDeployment.SetLabels(map[string]string{"app.kubernetes.io/instance": "web"})
+	groupService := renderTestObject("v1", "Service", "app", "svc")
+	ns := renderTestObject("v1", "Namespace", "", "app")
+
+	r := &yamlRenderer{directory: dir, layout: renderLayoutComponent, clean: true}
+	assert.Equal(t, filepath.Join(dir, "chart-web", "2-manifest", "deployment-default-nginx.yaml"),
+		r.path(chartURN, chartDeployment))
+	assert.Equal(t, filepath.Join(dir, "configfile", "2-manifest", "service-svc.yaml"),
+		r.path(groupURN, groupService))
+	assert.Equal(t, filepath.Join(dir, "dev", "1-namespace", "namespace-nginx.yaml"), r.path(testURN, ns))
+
+	// Two ConfigFiles that render a Service with the same name are written to different files.
+	otherGroupURN := resource.URN(
+		"urn:pulumi:dev::render::kubernetes:yaml:ConfigGroup$kubernetes:yaml:ConfigFile$kubernetes:v1:Service::other-svc")
+	assert.NotEqual(t, r.path(groupURN, groupService), r.path(otherGroupURN, groupService))
+
+	// Directories and top-level files that were not written by the renderer are kept.
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "docs"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "values.yaml"), []byte("replicas: 1\n"), 0600))
+
+	require.NoError(t, r.render(chartURN, chartDeployment, nil))
+	require.NoError(t, r.render(groupURN, groupService, nil))
+
+	readResources := func(path string) []string {
+		b, err := ioutil.ReadFile(filepath.Join(path, kustomizationFileName))
+		require.NoError(t, err)
+		var kustomization struct {
+			Resources []string `json:"resources"`
+		}
+		require.NoError(t, yaml.Unmarshal(b, &kustomization))
+		return kustomization.Resources
+	}
+	assert.Equal(t, []string{"chart-web", "configfile"}, readResources(dir))
+	assert.Equal(t, []string{"2-manifest/deployment-default-nginx.yaml"},
+		readResources(filepath.Join(dir, "chart-web")))
+
+	_, err = r.remove(chartURN, chartDeployment)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"configfile"}, readResources(dir))
+	assert.NoDirExists(t, filepath.Join(dir, "chart-web"))
+	assert.DirExists(t, filepath.Join(dir, "docs"))
+	assert.FileExists(t, filepath.Join(dir, "values.yaml"))
+}
+
+func TestRenderSingleLayout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "render-yaml-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	r := &yamlRenderer{directory: dir, layout: renderLayoutSingle}
+	path := filepath.Join(dir, "dev.yaml")
+	assert.Equal(t, path, r.path(testURN, renderTestObject("v1", "Namespace", "", "app")))
+
+	deployment := renderTestObject("apps/v1", "Deployment", "app", "nginx")
+	crd := renderTestObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "", "foos.example.com")
+	require.NoError(t, r.render(testURN, deployment, nil))
+	require.NoError(t, r.render(testURN, crd, nil))
+
+	// Re-rendering an object replaces its document.
+	deployment.SetLabels(map[string]string{"app": "nginx"})
+	require.NoError(t, r.render(testURN, deployment, nil))
+
+	readNames := func() []string {
+		b, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		var names []string
+		for _, document := range strings.Split(string(b), "---\n") {
+			if document == "" {
+				continue
+			}
+			obj := &unstructured.Unstructured{}
+			require.NoError(t, yaml.Unmarshal([]byte(document), &obj.Object))
+			names = append(names, obj.GetName())
+		}
+		return names
+	}
+	assert.Equal(t, []string{"foos.example.com", "nginx"}, readNames())
+
+	_, err = r.remove(testURN, crd)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nginx"}, readNames())
+
+	_, err = r.remove(testURN, deployment)
+	require.NoError(t, err)
+	assert.NoFileExists(t, path)
+}
+
+func TestRenderUnknowns(t *testing.T) {
+	dir, err := ioutil.TempDir("", "render-yaml-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	deployment := renderTestObject("apps/v1", "Deployment", "app", "nginx")
+	deployment.Object["spec"] = map[string]interface{}{"replicas": resource.Computed{}}
+
+	// Rendering is deferred by default: the manifest is not written, and the resource is listed in the report.
+	r := &yamlRenderer{directory: dir, layout: renderLayoutDirectory, unknowns: renderUnknownsDefer}
+	resolved, unresolved := r.resolveUnknowns(deployment)
+	assert.Equal(t, []string{"spec.replicas"}, unresolved)
+	assert.True(t, r.defers(unresolved))
+	require.NoError(t, r.render(testURN, resolved, unresolved))
+	assert.NoFileExists(t, r.path(testURN, resolved))
+
+	reportPath := filepath.Join(dir, unresolvedReportFileName)
+	var report []unresolvedFields
+	readReport := func() {
+		b, err := ioutil.ReadFile(reportPath)
+		require.NoError(t, err)
+		report = nil
+		require.NoError(t, json.Unmarshal(b, &report))
+	}
+	readReport()
+	assert.Equal(t, []unresolvedFields{{
+		File:       "1-manifest/deployment-app-nginx.yaml",
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Namespace:  "app",
+		Name:       "nginx",
+		Fields:     []string{"spec.replicas"},
+		Deferred:   true,
+	}}, report)
+
+	r = &yamlRenderer{directory: dir, layout: renderLayoutDirectory, unknowns: renderUnknownsPlaceholder}
+	resolved, unresolved = r.resolveUnknowns(deployment)
+	assert.Equal(t, []string{"spec.replicas"}, unresolved)
+	assert.False(t, r.defers(unresolved))
+	require.NoError(t, r.render(testURN, resolved, unresolved))
+
+	b, err := ioutil.ReadFile(r.path(testURN, resolved))
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "replicas: <unresolved>")
+
+	readReport()
+	assert.Equal(t, []unresolvedFields{{
+		File:       "1-manifest/deployment-app-nginx.yaml",
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Namespace:  "app",
+		Name:       "nginx",
+		Fields:     []string{"spec.replicas"},
+	}}, report)
+
+	// Once the values are known, the resource is removed from the report.
+	deployment.Object["spec"] = map[string]interface{}{"replicas": int64(1)}
+	resolved, unresolved = r.resolveUnknowns(deployment)
+	assert.Empty(t, unresolved)
+	require.NoError(t, r.render(testURN, resolved, unresolved))
+	assert.NoFileExists(t, reportPath)
+}
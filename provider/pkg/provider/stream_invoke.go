@@ -0,0 +1,359 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+// defaultListPageSize is the number of objects that are requested from the API server per page if a list stream invoke
+// does not specify a `limit`.
+const defaultListPageSize = 500
+
+// selectorListOptions returns the list options for the `labelSelector` and `fieldSelector` arguments of a list or
+// watch stream invoke.
+func selectorListOptions(invoke string, args resource.PropertyMap) (metav1.ListOptions, error) {
+	var opts metav1.ListOptions
+	if v := args["labelSelector"]; v.HasValue() {
+		if !v.IsString() {
+			return opts, fmt.Errorf("%s argument \"labelSelector\" must be a string", invoke)
+		}
+		if _, err := labels.Parse(v.StringValue()); err != nil {
+			return opts, fmt.Errorf("%s argument \"labelSelector\" is invalid: %v", invoke, err)
+		}
+		opts.LabelSelector = v.StringValue()
+	}
+	if v := args["fieldSelector"]; v.HasValue() {
+		if !v.IsString() {
+			return opts, fmt.Errorf("%s argument \"fieldSelector\" must be a string", invoke)
+		}
+		if _, err := fields.ParseSelector(v.StringValue()); err != nil {
+			return opts, fmt.Errorf("%s argument \"fieldSelector\" is invalid: %v", invoke, err)
+		}
+		opts.FieldSelector = v.StringValue()
+	}
+	return opts, nil
+}
+
+// listOptions returns the list options of a list stream invoke. The `limit` argument is the number of objects that
+// are requested per page.
+func listOptions(args resource.PropertyMap) (metav1.ListOptions, error) {
+	opts, err := selectorListOptions("list", args)
+	if err != nil {
+		return opts, err
+	}
+	opts.Limit = defaultListPageSize
+	if v := args["limit"]; v.HasValue() {
+		if !v.IsNumber() || v.NumberValue() < 1 || v.NumberValue() != float64(int64(v.NumberValue())) {
+			return opts, fmt.Errorf("list argument \"limit\" must be a positive integer")
+		}
+		opts.Limit = int64(v.NumberValue())
+	}
+	return opts, nil
+}
+
+// listPages lists the objects of a resource type one page at a time, following the `continue` token of each page, and
+// sends each object on the given channel. Only one page is held in memory at a time. Returns when all pages have been
+// listed, or when the context is cancelled.
+func listPages(
+	ctx context.Context, cl dynamic.ResourceInterface, opts metav1.ListOptions, objects chan<- map[string]interface{},
+) error {
+	for {
+		list, err := cl.List(ctx, opts)
+		if err != nil {
+			return err
+		}
+		for _, o := range list.Items {
+			select {
+			case objects <- o.Object:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+		if list.GetContinue() == "" {
+			return nil
+		}
+		opts.Continue = list.GetContinue()
+	}
+}
+
+// watchOptions returns the list options of a watch stream invoke, including the `resourceVersion` to resume from, and
+// whether bookmark events are sent to the caller.
+func watchOptions(args resource.PropertyMap) (metav1.ListOptions, bool, error) {
+	opts, err := selectorListOptions("watch", args)
+	if err != nil {
+		return opts, false, err
+	}
+	if v := args["resourceVersion"]; v.HasValue() {
+		if !v.IsString() {
+			return opts, false, fmt.Errorf("watch argument \"resourceVersion\" must be a string")
+		}
+		opts.ResourceVersion = v.StringValue()
+	}
+	sendBookmarks := false
+	if v := args["allowBookmarks"]; v.HasValue() {
+		if !v.IsBool() {
+			return opts, false, fmt.Errorf("watch argument \"allowBookmarks\" must be a boolean")
+		}
+		sendBookmarks = v.BoolValue()
+	}
+	return opts, sendBookmarks, nil
+}
+
+// watchRetryBackoff is the backoff between attempts to watch or list a resource type again after a transient error.
+var watchRetryBackoff = wait.Backoff{
+	Duration: 500 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    math.MaxInt32,
+	Cap:      30 * time.Second,
+}
+
+// resourceWatcher watches a resource type, and resumes the watch from the last resource version it has seen when the
+// API server closes it, or when it fails with a transient error. Only if that resource version has expired are the
+// objects listed again, and the differences from the objects that were already sent are sent as events, so that the
+// caller does not miss any changes.
+type resourceWatcher struct {
+	cl            dynamic.ResourceInterface
+	opts          metav1.ListOptions
+	sendBookmarks bool
+	backoff       wait.Backoff
+
+	// known are the identities and last resource versions of the objects that were sent, keyed by namespace and name.
+	// Only these are kept rather than the objects, which may be large, since they are enough to skip repeated events
+	// and to send a DELETED event for an object that is found to be deleted when the objects are listed again.
+	known map[string]*unstructured.Unstructured
+}
+
+func newResourceWatcher(cl dynamic.ResourceInterface, opts metav1.ListOptions, sendBookmarks bool) *resourceWatcher {
+	return &resourceWatcher{
+		cl:            cl,
+		opts:          opts,
+		sendBookmarks: sendBookmarks,
+		backoff:       watchRetryBackoff,
+		known:         map[string]*unstructured.Unstructured{},
+	}
+}
+
+// run sends watch events on the given channel until the context is cancelled, or the watch fails with an error that
+// is not transient.
+func (w *resourceWatcher) run(ctx context.Context, events chan<- map[string]interface{}) error {
+	resourceVersion := w.opts.ResourceVersion
+	backoff := w.backoff
+	relist := false
+	for ctx.Err() == nil {
+		var err error
+		if relist {
+			var listed string
+			if listed, err = w.relist(ctx, events); err == nil {
+				resourceVersion, relist = listed, false
+			}
+		} else {
+			opts := w.opts
+			opts.ResourceVersion = resourceVersion
+			// Bookmarks are always requested, so that the watch can be resumed from a recent resource version.
+			opts.AllowWatchBookmarks = true
+
+			var watcher watch.Interface
+			if watcher, err = w.cl.Watch(ctx, opts); err == nil {
+				var received string
+				received, err = w.receive(ctx, watcher, resourceVersion, events)
+				watcher.Stop()
+				if received != resourceVersion {
+					// The watch made progress, so the next error is retried without delay.
+					resourceVersion, backoff = received, w.backoff
+				}
+			}
+		}
+
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err == nil:
+			continue
+		case isGone(err):
+			// The resource version has expired, so the changes since then can only be found by listing the objects.
+			relist = true
+			continue
+		case !isTransientWatchError(err):
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(backoff.Step()):
+		}
+	}
+	return nil
+}
+
+// isGone returns true if the error is a 410 Gone response, which the API server returns for a watch or list from a
+// resource version that has expired.
+func isGone(err error) bool {
+	status, isStatus := err.(errors.APIStatus)
+	return isStatus && status.Status().Code == http.StatusGone
+}
+
+// isTransientWatchError returns true if a watch or list that failed with the given error may succeed if it is
+// retried, i.e., the error is not caused by the request itself, or by the permissions of the client.
+func isTransientWatchError(err error) bool {
+	switch {
+	case errors.IsBadRequest(err), errors.IsForbidden(err), errors.IsUnauthorized(err), errors.IsNotFound(err),
+		errors.IsMethodNotSupported(err), errors.IsInvalid(err), errors.IsNotAcceptable(err),
+		errors.IsUnsupportedMediaType(err):
+		return false
+	}
+	return true
+}
+
+// receive sends the events of a watch until its result channel is closed, and returns the last resource version
+// that it has seen. Error events are returned as errors.
+func (w *resourceWatcher) receive(
+	ctx context.Context, watcher watch.Interface, resourceVersion string, events chan<- map[string]interface{},
+) (string, error) {
+	for {
+		var event watch.Event
+		var ok bool
+		select {
+		case <-ctx.Done():
+			return resourceVersion, nil
+		case event, ok = <-watcher.ResultChan():
+		}
+		if !ok {
+			// The API server closed the watch, e.g., because it timed out.
+			return resourceVersion, nil
+		}
+		if event.Type == watch.Error {
+			return resourceVersion, errors.FromObject(event.Object)
+		}
+		obj, isUnstructured := event.Object.(*unstructured.Unstructured)
+		if !isUnstructured {
+			return resourceVersion, fmt.Errorf("unexpected object of type %T in watch event", event.Object)
+		}
+		resourceVersion = obj.GetResourceVersion()
+
+		key := obj.GetNamespace() + "/" + obj.GetName()
+		switch event.Type {
+		case watch.Bookmark:
+			if !w.sendBookmarks {
+				continue
+			}
+		case watch.Deleted:
+			delete(w.known, key)
+		default:
+			// A watch without a resource version starts with an ADDED event for each existing object, which must
+			// not be repeated if the watch is restarted before any other event is received.
+			if known, exists := w.known[key]; exists && known.GetResourceVersion() == obj.GetResourceVersion() {
+				continue
+			}
+			w.known[key] = knownObject(obj)
+		}
+		if !sendWatchEvent(ctx, events, event.Type, obj) {
+			return resourceVersion, nil
+		}
+	}
+}
+
+// relist lists the objects again after the resource version of the watch has expired. Objects that are new or have
+// changed since they were last sent are sent as ADDED or MODIFIED events, and objects that no longer exist are sent as
+// DELETED events. Returns the resource version of the list.
+func (w *resourceWatcher) relist(ctx context.Context, events chan<- map[string]interface{}) (string, error) {
+	opts := metav1.ListOptions{
+		LabelSelector: w.opts.LabelSelector,
+		FieldSelector: w.opts.FieldSelector,
+		Limit:         defaultListPageSize,
+	}
+	listed := map[string]bool{}
+	var resourceVersion string
+	for {
+		list, err := w.cl.List(ctx, opts)
+		if err != nil {
+			return "", err
+		}
+		resourceVersion = list.GetResourceVersion()
+		for i := range list.Items {
+			obj := &list.Items[i]
+			key := obj.GetNamespace() + "/" + obj.GetName()
+			listed[key] = true
+
+			eventType := watch.Added
+			if known, exists := w.known[key]; exists {
+				if known.GetResourceVersion() == obj.GetResourceVersion() {
+					continue
+				}
+				eventType = watch.Modified
+			}
+			w.known[key] = knownObject(obj)
+			if !sendWatchEvent(ctx, events, eventType, obj) {
+				return "", ctx.Err()
+			}
+		}
+		if list.GetContinue() == "" {
+			break
+		}
+		opts.Continue = list.GetContinue()
+	}
+
+	for key, obj := range w.known {
+		if listed[key] {
+			continue
+		}
+		delete(w.known, key)
+		if !sendWatchEvent(ctx, events, watch.Deleted, obj) {
+			return "", ctx.Err()
+		}
+	}
+	return resourceVersion, nil
+}
+
+// knownObject returns the identity and resource version of an object, which is what a resourceWatcher keeps of the
+// objects that it has sent.
+func knownObject(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	known := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	known.SetAPIVersion(obj.GetAPIVersion())
+	known.SetKind(obj.GetKind())
+	known.SetNamespace(obj.GetNamespace())
+	known.SetName(obj.GetName())
+	known.SetUID(obj.GetUID())
+	known.SetResourceVersion(obj.GetResourceVersion())
+	return known
+}
+
+// sendWatchEvent sends a watch event on the given channel, and returns false if the context was cancelled first.
+func sendWatchEvent(
+	ctx context.Context, events chan<- map[string]interface{}, eventType watch.EventType,
+	obj *unstructured.Unstructured,
+) bool {
+	select {
+	case events <- map[string]interface{}{"type": string(eventType), "object": obj.Object}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+// pagedResourceClient serves a fixed number of objects in pages of the requested size.
+type pagedResourceClient struct {
+	dynamic.ResourceInterface
+	objects  int
+	requests []metav1.ListOptions
+}
+
+func (c *pagedResourceClient) List(_ context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	c.requests = append(c.requests, opts)
+	start := 0
+	if opts.Continue != "" {
+		start, _ = strconv.Atoi(opts.Continue)
+	}
+	list := &unstructured.UnstructuredList{}
+	for i := start; i < c.objects && i < start+int(opts.Limit); i++ {
+		list.Items = append(list.Items, unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": fmt.Sprintf("pod-%d", i)},
+		}})
+	}
+	if next := start + int(opts.Limit); next < c.objects {
+		list.SetContinue(strconv.Itoa(next))
+	}
+	return list, nil
+}
+
+func TestListOptions(t *testing.T) {
+	opts, err := listOptions(resource.PropertyMap{})
+	require.NoError(t, err)
+	assert.Equal(t, metav1.ListOptions{Limit: defaultListPageSize}, opts)
+
+	opts, err = listOptions(resource.NewPropertyMapFromMap(map[string]interface{}{
+		"labelSelector": "app=nginx,tier in (frontend)",
+		"fieldSelector": "status.phase=Running",
+		"limit":         50,
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, metav1.ListOptions{
+		LabelSelector: "app=nginx,tier in (frontend)",
+		FieldSelector: "status.phase=Running",
+		Limit:         50,
+	}, opts)
+
+	for _, args := range []map[string]interface{}{
+		{"labelSelector": "app in nginx"},
+		{"fieldSelector": "status.phase"},
+		{"labelSelector": 1},
+		{"limit": 0},
+		{"limit": 2.5},
+	} {
+		_, err = listOptions(resource.NewPropertyMapFromMap(args))
+		assert.Error(t, err, args)
+	}
+}
+
+func TestListPages(t *testing.T) {
+	cl := &pagedResourceClient{objects: 5}
+	objects := make(chan map[string]interface{})
+	done := make(chan error, 1)
+	go func() {
+		done <- listPages(context.Background(), cl, metav1.ListOptions{Limit: 2}, objects)
+	}()
+
+	var names []string
+	for len(names) < 5 {
+		names = append(names, (&unstructured.Unstructured{Object: <-objects}).GetName())
+	}
+	require.NoError(t, <-done)
+	assert.Equal(t, []string{"pod-0", "pod-1", "pod-2", "pod-3", "pod-4"}, names)
+
+	require.Len(t, cl.requests, 3)
+	assert.Equal(t, "", cl.requests[0].Continue)
+	assert.Equal(t, "2", cl.requests[1].Continue)
+	assert.Equal(t, "4", cl.requests[2].Continue)
+
+	// Cancelling the context stops the listing without blocking on the unread objects.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.NoError(t, listPages(ctx, cl, metav1.ListOptions{Limit: 2}, objects))
+}
+
+// scriptedWatchClient serves a sequence of watches with predefined events, and a fixed list of objects. The list
+// fails with each of listErrors before it succeeds.
+type scriptedWatchClient struct {
+	dynamic.ResourceInterface
+	watches    [][]watch.Event
+	list       *unstructured.UnstructuredList
+	listErrors []error
+	requests   []metav1.ListOptions
+	lists      int
+}
+
+func (c *scriptedWatchClient) Watch(_ context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	c.requests = append(c.requests, opts)
+	if len(c.watches) == 0 {
+		return nil, errors.NewBadRequest("unexpected watch")
+	}
+	events := c.watches[0]
+	c.watches = c.watches[1:]
+
+	w := watch.NewFakeWithChanSize(len(events), false)
+	for _, event := range events {
+		w.Action(event.Type, event.Object)
+	}
+	w.Stop()
+	return w, nil
+}
+
+func (c *scriptedWatchClient) List(context.Context, metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	c.lists++
+	if len(c.listErrors) > 0 {
+		err := c.listErrors[0]
+		c.listErrors = c.listErrors[1:]
+		return nil, err
+	}
+	return c.list, nil
+}
+
+func watchTestPod(name, resourceVersion string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name":            name,
+			"namespace":       "default",
+			"uid":             "uid-" + name,
+			"resourceVersion": resourceVersion,
+		},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{map[string]interface{}{"name": "nginx", "image": "nginx"}},
+		},
+	}}
+}
+
+func TestWatchOptions(t *testing.T) {
+	opts, sendBookmarks, err := watchOptions(resource.NewPropertyMapFromMap(map[string]interface{}{
+		"labelSelector":   "app=nginx",
+		"resourceVersion": "12345",
+		"allowBookmarks":  true,
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, metav1.ListOptions{LabelSelector: "app=nginx", ResourceVersion: "12345"}, opts)
+	assert.True(t, sendBookmarks)
+
+	_, _, err = watchOptions(resource.NewPropertyMapFromMap(map[string]interface{}{"resourceVersion": 12345}))
+	assert.Error(t, err)
+}
+
+func TestResourceWatcher(t *testing.T) {
+	gone := errors.NewResourceExpired("too old resource version: 2 (3)").ErrStatus
+	internal := errors.NewInternalError(fmt.Errorf("etcd is unavailable")).ErrStatus
+	forbidden := errors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", fmt.Errorf("denied")).ErrStatus
+
+	list := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{
+		*watchTestPod("a", "3"), *watchTestPod("b", "4"),
+	}}
+	list.SetResourceVersion("5")
+
+	cl := &scriptedWatchClient{
+		watches: [][]watch.Event{
+			{
+				{Type: watch.Added, Object: watchTestPod("a", "1")},
+				{Type: watch.Added, Object: watchTestPod("c", "1")},
+				{Type: watch.Bookmark, Object: watchTestPod("", "2")},
+			},
+			{{Type: watch.Error, Object: &gone}},
+			{
+				{Type: watch.Deleted, Object: watchTestPod("b", "6")},
+				{Type: watch.Error, Object: &internal},
+			},
+			{{Type: watch.Error, Object: &gone}},
+			{{Type: watch.Error, Object: &forbidden}},
+		},
+		list:       list,
+		listErrors: []error{errors.NewServiceUnavailable("etcd is unavailable")},
+	}
+
+	events := make(chan map[string]interface{}, 10)
+	watcher := newResourceWatcher(cl, metav1.ListOptions{LabelSelector: "app=nginx"}, true)
+	watcher.backoff = wait.Backoff{Duration: time.Millisecond, Steps: math.MaxInt32}
+	err := watcher.run(context.Background(), events)
+	close(events)
+	assert.True(t, errors.IsForbidden(err))
+
+	var received []string
+	var relistDeleted *unstructured.Unstructured
+	for event := range events {
+		obj := &unstructured.Unstructured{Object: event["object"].(map[string]interface{})}
+		received = append(received, fmt.Sprintf("%s %s@%s", event["type"], obj.GetName(), obj.GetResourceVersion()))
+		if event["type"] == string(watch.Deleted) && obj.GetName() == "c" {
+			relistDeleted = obj
+		}
+	}
+	assert.Equal(t, []string{
+		"ADDED a@1",
+		"ADDED c@1",
+		"BOOKMARK @2",
+		"MODIFIED a@3",
+		"ADDED b@4",
+		"DELETED c@1",
+		"DELETED b@6",
+		"ADDED b@4",
+	}, received)
+
+	// Only the identity and resource version of the objects are kept, and sent for the objects that are found to be
+	// deleted when the objects are listed again.
+	assert.Equal(t, map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name":            "c",
+			"namespace":       "default",
+			"uid":             "uid-c",
+			"resourceVersion": "1",
+		},
+	}, relistDeleted.Object)
+
+	// The objects are only listed again when the resource version has expired, and a transient error of the list
+	// is retried.
+	assert.Equal(t, 3, cl.lists)
+
+	// Each watch resumes from the last resource version that was seen, and keeps the selectors.
+	require.Len(t, cl.requests, 5)
+	for i, resourceVersion := range []string{"", "2", "5", "6", "5"} {
+		assert.Equal(t, resourceVersion, cl.requests[i].ResourceVersion)
+		assert.Equal(t, "app=nginx", cl.requests[i].LabelSelector)
+		assert.True(t, cl.requests[i].AllowWatchBookmarks)
+	}
+}
+
+func TestIsTransientWatchError(t *testing.T) {
+	gr := schema.GroupResource{Resource: "pods"}
+	assert.True(t, isTransientWatchError(fmt.Errorf("connection reset by peer")))
+	assert.True(t, isTransientWatchError(errors.NewInternalError(fmt.Errorf("etcd is unavailable"))))
+	assert.True(t, isTransientWatchError(errors.NewTooManyRequests("slow down", 1)))
+	assert.False(t, isTransientWatchError(errors.NewForbidden(gr, "", fmt.Errorf("denied"))))
+	assert.False(t, isTransientWatchError(errors.NewNotFound(gr, "")))
+
+	assert.True(t, isGone(errors.NewResourceExpired("too old resource version")))
+	assert.True(t, isGone(errors.NewGone("gone")))
+	assert.False(t, isGone(errors.NewInternalError(fmt.Errorf("etcd is unavailable"))))
+}
@@ -5,6 +5,7 @@ package provider
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
@@ -46,6 +47,71 @@ func hasComputedValue(obj *unstructured.Unstructured) bool {
 	return false
 }
 
+// computedValuePaths returns the property paths of every computed value in the given object, sorted.
+func computedValuePaths(obj *unstructured.Unstructured) []string {
+	if obj == nil || obj.Object == nil {
+		return nil
+	}
+
+	var paths []string
+	var visit func(path []interface{}, v interface{})
+	visit = func(path []interface{}, v interface{}) {
+		switch field := v.(type) {
+		case resource.Computed:
+			paths = append(paths, formatPropertyPath(path))
+		case map[string]interface{}:
+			for k, v := range field {
+				visit(append(path[:len(path):len(path)], k), v)
+			}
+		case []interface{}:
+			for i, v := range field {
+				visit(append(path[:len(path):len(path)], i), v)
+			}
+		case []map[string]interface{}:
+			for i, v := range field {
+				visit(append(path[:len(path):len(path)], i), v)
+			}
+		}
+	}
+	visit(nil, obj.Object)
+
+	sort.Strings(paths)
+	return paths
+}
+
+// replaceComputedValues returns a copy of the given object with every computed value replaced by the placeholder.
+func replaceComputedValues(obj *unstructured.Unstructured, placeholder string) *unstructured.Unstructured {
+	var replace func(v interface{}) interface{}
+	replace = func(v interface{}) interface{} {
+		switch field := v.(type) {
+		case resource.Computed:
+			return placeholder
+		case map[string]interface{}:
+			m := make(map[string]interface{}, len(field))
+			for k, v := range field {
+				m[k] = replace(v)
+			}
+			return m
+		case []interface{}:
+			a := make([]interface{}, len(field))
+			for i, v := range field {
+				a[i] = replace(v)
+			}
+			return a
+		case []map[string]interface{}:
+			a := make([]interface{}, len(field))
+			for i, v := range field {
+				a[i] = replace(v)
+			}
+			return a
+		default:
+			return v
+		}
+	}
+
+	return &unstructured.Unstructured{Object: replace(obj.Object).(map[string]interface{})}
+}
+
 // --------------------------------------------------------------------------
 // Names and namespaces.
 // --------------------------------------------------------------------------
@@ -107,7 +173,11 @@ func parseKubeconfigPropertyValue(kubeconfig resource.PropertyValue) (*clientapi
 }
 
 // getActiveClusterFromConfig gets the current cluster from a kubeconfig, accounting for provider overrides.
+// If the overrides describe a cluster with direct credentials, that cluster is used instead of the kubeconfig.
 func getActiveClusterFromConfig(config *clientapi.Config, overrides resource.PropertyMap) *clientapi.Cluster {
+	if cluster := directCredentialsCluster(overrides); cluster != nil {
+		return cluster
+	}
 	if config == nil || len(config.Clusters) == 0 {
 		return &clientapi.Cluster{}
 	}
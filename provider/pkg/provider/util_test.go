@@ -166,6 +166,39 @@ func TestHasComputedValue(t *testing.T) {
 	}
 }
 
+func TestComputedValues(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"field1": 1,
+		"field2": resource.Computed{},
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				"example.com/hash": resource.Computed{},
+			},
+		},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "nginx"},
+				map[string]interface{}{"image": resource.Computed{}},
+			},
+		},
+	}}
+
+	assert.Nil(t, computedValuePaths(nil))
+	assert.Equal(t, []string{
+		"field2",
+		`metadata.annotations["example.com/hash"]`,
+		"spec.containers[1].image",
+	}, computedValuePaths(obj))
+
+	replaced := replaceComputedValues(obj, "<unresolved>")
+	assert.False(t, hasComputedValue(replaced))
+	assert.Equal(t, "<unresolved>", replaced.Object["field2"])
+	assert.Equal(t, map[string]string{"example.com/hash": "<unresolved>"}, replaced.GetAnnotations())
+
+	// The original object is not modified.
+	assert.Equal(t, resource.Computed{}, obj.Object["field2"])
+}
+
 func TestFqName(t *testing.T) {
 	obj := &unstructured.Unstructured{
 		Object: map[string]interface{}{
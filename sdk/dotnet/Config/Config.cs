@@ -32,6 +32,76 @@ namespace Pulumi.Kubernetes
 
         private static readonly Pulumi.Config __config = new Pulumi.Config("kubernetes");
 
+        private static readonly __Value<string?> _certificateAuthorityData = new __Value<string?>(() => __config.Get("certificateAuthorityData"));
+        /// <summary>
+        /// PEM-encoded certificate authority certificates for the API server given by `server`, either as PEM text or base64-encoded as in a kubeconfig.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `certificateAuthorityData` parameter.
+        /// 2. The `PULUMI_K8S_CERTIFICATE_AUTHORITY_DATA` environment variable, unless `kubeconfig`, `context` or `cluster` is set.
+        /// </summary>
+        public static string? CertificateAuthorityData
+        {
+            get => _certificateAuthorityData.Get();
+            set => _certificateAuthorityData.Set(value);
+        }
+
+        private static readonly __Value<int?> _clientBurst = new __Value<int?>(() => __config.GetInt32("clientBurst"));
+        /// <summary>
+        /// The maximum burst of requests that the provider sends to the Kubernetes API server above `clientQps`, shared by all of its clients. Defaults to 10.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `clientBurst` parameter.
+        /// 2. The `PULUMI_K8S_CLIENT_BURST` environment variable.
+        /// </summary>
+        public static int? ClientBurst
+        {
+            get => _clientBurst.Get();
+            set => _clientBurst.Set(value);
+        }
+
+        private static readonly __Value<string?> _clientCertificateData = new __Value<string?>(() => __config.Get("clientCertificateData"));
+        /// <summary>
+        /// A PEM-encoded client certificate to authenticate to the API server given by `server`, either as PEM text or base64-encoded as in a kubeconfig. Requires `clientKeyData`.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `clientCertificateData` parameter.
+        /// 2. The `PULUMI_K8S_CLIENT_CERTIFICATE_DATA` environment variable, unless `kubeconfig`, `context` or `cluster` is set.
+        /// </summary>
+        public static string? ClientCertificateData
+        {
+            get => _clientCertificateData.Get();
+            set => _clientCertificateData.Set(value);
+        }
+
+        private static readonly __Value<string?> _clientKeyData = new __Value<string?>(() => __config.Get("clientKeyData"));
+        /// <summary>
+        /// The PEM-encoded private key of the client certificate given by `clientCertificateData`, either as PEM text or base64-encoded as in a kubeconfig.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `clientKeyData` parameter.
+        /// 2. The `PULUMI_K8S_CLIENT_KEY_DATA` environment variable, unless `kubeconfig`, `context` or `cluster` is set.
+        /// </summary>
+        public static string? ClientKeyData
+        {
+            get => _clientKeyData.Get();
+            set => _clientKeyData.Set(value);
+        }
+
+        private static readonly __Value<double?> _clientQps = new __Value<double?>(() => __config.GetDouble("clientQps"));
+        /// <summary>
+        /// The maximum number of queries per second that the provider sends to the Kubernetes API server, shared by all of its clients. Defaults to 5.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `clientQps` parameter.
+        /// 2. The `PULUMI_K8S_CLIENT_QPS` environment variable.
+        /// </summary>
+        public static double? ClientQps
+        {
+            get => _clientQps.Get();
+            set => _clientQps.Set(value);
+        }
+
         private static readonly __Value<string?> _cluster = new __Value<string?>(() => __config.Get("cluster"));
         /// <summary>
         /// If present, the name of the kubeconfig cluster to use.
@@ -52,6 +122,62 @@ namespace Pulumi.Kubernetes
             set => _context.Set(value);
         }
 
+        private static readonly __Value<string?> _discoveryCacheDir = new __Value<string?>(() => __config.Get("discoveryCacheDir"));
+        /// <summary>
+        /// The directory in which the provider caches the discovery information and OpenAPI schema of the Kubernetes API server, shared by all provider processes. If this is not set, discovery information is only cached in memory.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `discoveryCacheDir` parameter.
+        /// 2. The `PULUMI_K8S_DISCOVERY_CACHE_DIR` environment variable.
+        /// </summary>
+        public static string? DiscoveryCacheDir
+        {
+            get => _discoveryCacheDir.Get();
+            set => _discoveryCacheDir.Set(value);
+        }
+
+        private static readonly __Value<int?> _discoveryCacheTtl = new __Value<int?>(() => __config.GetInt32("discoveryCacheTtl"));
+        /// <summary>
+        /// The number of seconds for which the cached API groups and resources of the Kubernetes API server are used, before they are discovered again. The cached OpenAPI schema is revalidated on every use. Only used if `discoveryCacheDir` is set. Set to 0 to disable the on-disk cache. Defaults to 600.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `discoveryCacheTtl` parameter.
+        /// 2. The `PULUMI_K8S_DISCOVERY_CACHE_TTL` environment variable.
+        /// </summary>
+        public static int? DiscoveryCacheTtl
+        {
+            get => _discoveryCacheTtl.Get();
+            set => _discoveryCacheTtl.Set(value);
+        }
+
+        private static readonly __Value<string?> _driftIgnoredFieldManagers = new __Value<string?>(() => __config.Get("driftIgnoredFieldManagers"));
+        /// <summary>
+        /// BETA FEATURE - A comma-separated list of the field managers whose changes are not reported as drift when `enableDriftDetection` is set, such as the controllers that own the fields they update. Changes by any other field manager than the provider are reported as drift. Defaults to `kube-controller-manager,kube-scheduler,kubelet`.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `driftIgnoredFieldManagers` parameter.
+        /// 2. The `PULUMI_K8S_DRIFT_IGNORED_FIELD_MANAGERS` environment variable.
+        /// </summary>
+        public static string? DriftIgnoredFieldManagers
+        {
+            get => _driftIgnoredFieldManagers.Get();
+            set => _driftIgnoredFieldManagers.Set(value);
+        }
+
+        private static readonly __Value<bool?> _enableDriftDetection = new __Value<bool?>(() => __config.GetBoolean("enableDriftDetection"));
+        /// <summary>
+        /// BETA FEATURE - If present and set to true, compare the inputs of each resource to its live state during diffs, and report fields that were changed out of band (e.g., with `kubectl edit`) as drift. Fields owned by the field managers in `driftIgnoredFieldManagers`, such as `spec.replicas` managed by a HorizontalPodAutoscaler, are not reported.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `enableDriftDetection` parameter.
+        /// 2. The `PULUMI_K8S_ENABLE_DRIFT_DETECTION` environment variable.
+        /// </summary>
+        public static bool? EnableDriftDetection
+        {
+            get => _enableDriftDetection.Get();
+            set => _enableDriftDetection.Set(value);
+        }
+
         private static readonly __Value<bool?> _enableDryRun = new __Value<bool?>(() => __config.GetBoolean("enableDryRun"));
         /// <summary>
         /// BETA FEATURE - If present and set to true, enable server-side diff calculations.
@@ -117,6 +243,62 @@ namespace Pulumi.Kubernetes
             set => _helmRepositoryConfigPath.Set(value);
         }
 
+        private static readonly __Value<string?> _ignoreFields = new __Value<string?>(() => __config.Get("ignoreFields"));
+        /// <summary>
+        /// BETA FEATURE - A JSON object mapping resource types to lists of fields that are excluded from diffs and updates, e.g., `{"apps/v1/Deployment": ["/spec/replicas"]}`. Resource types are given as `&lt;apiVersion&gt;/&lt;kind&gt;`, and fields as JSON pointers or JSONPaths, where `*` matches every map key or list element. This is useful for fields that are set by controllers or mutating webhooks, such as injected sidecar containers or `caBundle` values. Fields can also be ignored for a single resource with the `pulumi.com/ignoreFields` annotation.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `ignoreFields` parameter.
+        /// 2. The `PULUMI_K8S_IGNORE_FIELDS` environment variable.
+        /// </summary>
+        public static string? IgnoreFields
+        {
+            get => _ignoreFields.Get();
+            set => _ignoreFields.Set(value);
+        }
+
+        private static readonly __Value<string?> _impersonate = new __Value<string?>(() => __config.Get("impersonate"));
+        /// <summary>
+        /// The user, groups and UID that the provider impersonates in every request to the Kubernetes API server, including the requests of Helm releases, e.g., to manage the resources of a tenant with the identity of its service account. The identity is a JSON object with the `user`, `groups` and `uid` to impersonate.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `impersonate` parameter.
+        /// 2. The `PULUMI_K8S_IMPERSONATE` environment variable.
+        /// </summary>
+        public static string? Impersonate
+        {
+            get => _impersonate.Get();
+            set => _impersonate.Set(value);
+        }
+
+        private static readonly __Value<bool?> _insecureSkipTlsVerify = new __Value<bool?>(() => __config.GetBoolean("insecureSkipTlsVerify"));
+        /// <summary>
+        /// If present and set to true, the certificate of the API server given by `server` is not verified. This makes the connection insecure, and cannot be combined with `certificateAuthorityData`.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `insecureSkipTlsVerify` parameter.
+        /// 2. The `PULUMI_K8S_INSECURE_SKIP_TLS_VERIFY` environment variable, unless `kubeconfig`, `context` or `cluster` is set.
+        /// </summary>
+        public static bool? InsecureSkipTlsVerify
+        {
+            get => _insecureSkipTlsVerify.Get();
+            set => _insecureSkipTlsVerify.Set(value);
+        }
+
+        private static readonly __Value<string?> _kubeVersion = new __Value<string?>(() => __config.Get("kubeVersion"));
+        /// <summary>
+        /// The Kubernetes version to validate resources against when the cluster is unreachable, e.g., in render mode or in CI. When set, resources are validated and scoped using the OpenAPI schema bundled with the provider for that version.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `kubeVersion` parameter.
+        /// 2. The `PULUMI_K8S_KUBE_VERSION` environment variable.
+        /// </summary>
+        public static string? KubeVersion
+        {
+            get => _kubeVersion.Get();
+            set => _kubeVersion.Set(value);
+        }
+
         private static readonly __Value<string?> _kubeconfig = new __Value<string?>(() => __config.Get("kubeconfig"));
         /// <summary>
         /// The contents of a kubeconfig file or the path to a kubeconfig file. If this is set, this config will be used instead of $KUBECONFIG.
@@ -127,6 +309,34 @@ namespace Pulumi.Kubernetes
             set => _kubeconfig.Set(value);
         }
 
+        private static readonly __Value<int?> _maxConcurrentWatches = new __Value<int?>(() => __config.GetInt32("maxConcurrentWatches"));
+        /// <summary>
+        /// The maximum number of watches that the provider keeps open at the same time, e.g., while it waits for resources to become ready. A watch that would exceed the limit waits up to 10 seconds for another watch to end. If none does, the objects of the resource are listed again periodically until a watch is free, so that waiting for resources that need several watches, e.g., 4 for a Deployment, still completes. Unlimited by default.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `maxConcurrentWatches` parameter.
+        /// 2. The `PULUMI_K8S_MAX_CONCURRENT_WATCHES` environment variable.
+        /// </summary>
+        public static int? MaxConcurrentWatches
+        {
+            get => _maxConcurrentWatches.Get();
+            set => _maxConcurrentWatches.Set(value);
+        }
+
+        private static readonly __Value<bool?> _migrateApiVersions = new __Value<bool?>(() => __config.GetBoolean("migrateApiVersions"));
+        /// <summary>
+        /// If present and set to true, resources that use a deprecated or removed apiVersion are migrated to the newest apiVersion of their kind that the cluster supports, along with the changes of their fields that can be made automatically, such as the backends of an Ingress. Resources that cannot be migrated automatically are left unchanged with a warning.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `migrateApiVersions` parameter.
+        /// 2. The `PULUMI_K8S_MIGRATE_API_VERSIONS` environment variable.
+        /// </summary>
+        public static bool? MigrateApiVersions
+        {
+            get => _migrateApiVersions.Get();
+            set => _migrateApiVersions.Set(value);
+        }
+
         private static readonly __Value<string?> _namespace = new __Value<string?>(() => __config.Get("namespace"));
         /// <summary>
         /// If present, the default namespace to use. This flag is ignored for cluster-scoped resources.
@@ -142,6 +352,98 @@ namespace Pulumi.Kubernetes
             set => _namespace.Set(value);
         }
 
+        private static readonly __Value<string?> _policyDirectory = new __Value<string?>(() => __config.Get("policyDirectory"));
+        /// <summary>
+        /// A directory of policies that are evaluated against every resource in `Check`. Each YAML or JSON file in the directory contains one or more policies, whose validations are CEL expressions of the resource as `object` and of its previous inputs as `oldObject`. Violations of `mandatory` policies (the default) fail the check, violations of `advisory` policies are reported as warnings, and `disabled` policies are not evaluated. Validations that depend on values that are unknown in a preview are evaluated again when the resource is created or updated. Rego policies are not supported.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `policyDirectory` parameter.
+        /// 2. The `PULUMI_K8S_POLICY_DIRECTORY` environment variable.
+        /// </summary>
+        public static string? PolicyDirectory
+        {
+            get => _policyDirectory.Get();
+            set => _policyDirectory.Set(value);
+        }
+
+        private static readonly __Value<string?> _proxyUrl = new __Value<string?>(() => __config.Get("proxyUrl"));
+        /// <summary>
+        /// The URL of an http, https or socks5 proxy to connect to the API server given by `server` through.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `proxyUrl` parameter.
+        /// 2. The `PULUMI_K8S_PROXY_URL` environment variable, unless `kubeconfig`, `context` or `cluster` is set.
+        /// </summary>
+        public static string? ProxyUrl
+        {
+            get => _proxyUrl.Get();
+            set => _proxyUrl.Set(value);
+        }
+
+        private static readonly __Value<bool?> _renderYamlClean = new __Value<bool?>(() => __config.GetBoolean("renderYamlClean"));
+        /// <summary>
+        /// BETA FEATURE - If present and set to true, and `renderYamlToDirectory` is set, the rendered manifests are cleaned up
+        /// so that they can be applied directly with `kubectl apply -k` or consumed by GitOps tooling. The
+        /// `last-applied-configuration` and Pulumi-internal annotations are removed, the manifests are ordered so that
+        /// CustomResourceDefinitions and Namespaces are applied first and webhook configurations last, and a `kustomization.yaml`
+        /// listing every rendered file is written to the root of the directory.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `renderYamlClean` parameter.
+        /// 2. The `PULUMI_K8S_RENDER_YAML_CLEAN` environment variable.
+        /// </summary>
+        public static bool? RenderYamlClean
+        {
+            get => _renderYamlClean.Get();
+            set => _renderYamlClean.Set(value);
+        }
+
+        private static readonly __Value<string?> _renderYamlLayout = new __Value<string?>(() => __config.Get("renderYamlLayout"));
+        /// <summary>
+        /// BETA FEATURE - If present, and `renderYamlToDirectory` is set, controls how the rendered manifests are arranged in the
+        /// directory. Values are:
+        /// - `directory` (default): one file per resource, in the `0-crd` and `1-manifest` subdirectories.
+        /// - `component`: one file per resource, in a subdirectory per component resource (e.g., a Chart or ConfigGroup).
+        /// - `single`: a single multi-document file per stack, named `&lt;stack&gt;.yaml`.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `renderYamlLayout` parameter.
+        /// 2. The `PULUMI_K8S_RENDER_YAML_LAYOUT` environment variable.
+        /// </summary>
+        public static string? RenderYamlLayout
+        {
+            get => _renderYamlLayout.Get();
+            set => _renderYamlLayout.Set(value);
+        }
+
+        private static readonly __Value<string?> _renderYamlSecrets = new __Value<string?>(() => __config.Get("renderYamlSecrets"));
+        /// <summary>
+        /// Controls how the data of Secrets is written when `renderYamlToDirectory` is set. `plaintext` (the default) writes it unencrypted. `sops` encrypts the `data` and `stringData` fields in the SOPS format. `sealedSecrets` renders each Secret as a Bitnami SealedSecret.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `renderYamlSecrets` parameter.
+        /// 2. The `PULUMI_K8S_RENDER_YAML_SECRETS` environment variable.
+        /// </summary>
+        public static string? RenderYamlSecrets
+        {
+            get => _renderYamlSecrets.Get();
+            set => _renderYamlSecrets.Set(value);
+        }
+
+        private static readonly __Value<string?> _renderYamlSecretsKey = new __Value<string?>(() => __config.Get("renderYamlSecretsKey"));
+        /// <summary>
+        /// The key used to encrypt Secrets when `renderYamlSecrets` is set. For `sops`, a comma-separated list of age recipients, or the path to (or contents of) an ASCII-armored PGP public key. For `sealedSecrets`, the path to (or contents of) the PEM-encoded certificate of the sealed-secrets controller.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `renderYamlSecretsKey` parameter.
+        /// 2. The `PULUMI_K8S_RENDER_YAML_SECRETS_KEY` environment variable.
+        /// </summary>
+        public static string? RenderYamlSecretsKey
+        {
+            get => _renderYamlSecretsKey.Get();
+            set => _renderYamlSecretsKey.Set(value);
+        }
+
         private static readonly __Value<string?> _renderYamlToDirectory = new __Value<string?>(() => __config.Get("renderYamlToDirectory"));
         /// <summary>
         /// BETA FEATURE - If present, render resource manifests to this directory. In this mode, resources will not
@@ -159,6 +461,34 @@ namespace Pulumi.Kubernetes
             set => _renderYamlToDirectory.Set(value);
         }
 
+        private static readonly __Value<string?> _renderYamlUnknowns = new __Value<string?>(() => __config.Get("renderYamlUnknowns"));
+        /// <summary>
+        /// Controls how resources with unknown values are rendered when `renderYamlToDirectory` is set. `defer` (the default) does not write a resource until all of its values are known, and lists its unknown values in `unresolved-fields.json` in the render directory. `placeholder` renders unknown values as `&lt;unresolved&gt;` and lists them in `unresolved-fields.json` in the render directory.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `renderYamlUnknowns` parameter.
+        /// 2. The `PULUMI_K8S_RENDER_YAML_UNKNOWNS` environment variable.
+        /// </summary>
+        public static string? RenderYamlUnknowns
+        {
+            get => _renderYamlUnknowns.Get();
+            set => _renderYamlUnknowns.Set(value);
+        }
+
+        private static readonly __Value<string?> _server = new __Value<string?>(() => __config.Get("server"));
+        /// <summary>
+        /// The address of the Kubernetes API server, e.g., `https://1.2.3.4:6443`. If this is set, the provider connects to this server with the credentials given by `certificateAuthorityData`, `token`, `clientCertificateData` and `clientKeyData`, and no kubeconfig is loaded. It cannot be combined with `kubeconfig`, `context` or `cluster`.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `server` parameter.
+        /// 2. The `PULUMI_K8S_SERVER` environment variable, unless `kubeconfig`, `context` or `cluster` is set.
+        /// </summary>
+        public static string? Server
+        {
+            get => _server.Get();
+            set => _server.Set(value);
+        }
+
         private static readonly __Value<bool?> _suppressDeprecationWarnings = new __Value<bool?>(() => __config.GetBoolean("suppressDeprecationWarnings"));
         /// <summary>
         /// If present and set to true, suppress apiVersion deprecation warnings from the CLI.
@@ -197,5 +527,19 @@ namespace Pulumi.Kubernetes
             set => _suppressHelmReleaseBetaWarning.Set(value);
         }
 
+        private static readonly __Value<string?> _token = new __Value<string?>(() => __config.Get("token"));
+        /// <summary>
+        /// A bearer token to authenticate to the API server given by `server`.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `token` parameter.
+        /// 2. The `PULUMI_K8S_TOKEN` environment variable, unless `kubeconfig`, `context` or `cluster` is set.
+        /// </summary>
+        public static string? Token
+        {
+            get => _token.Get();
+            set => _token.Set(value);
+        }
+
     }
 }
@@ -0,0 +1,80 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+using System;
+using System.Collections.Generic;
+using System.Collections.Immutable;
+using System.Threading.Tasks;
+using Pulumi.Serialization;
+
+namespace Pulumi.Kubernetes
+{
+    public static class GetObject
+    {
+        /// <summary>
+        /// Reads a live object from the cluster, optionally waiting until it satisfies a condition, e.g., to use a value that a controller sets on a resource. The data of Secrets, and their last-applied-configuration annotation, are returned as secrets. Managed fields are omitted.
+        /// </summary>
+        public static Task<GetObjectResult> InvokeAsync(GetObjectArgs args, InvokeOptions? options = null)
+            => Pulumi.Deployment.Instance.InvokeAsync<GetObjectResult>("kubernetes:index:getObject", args ?? new GetObjectArgs(), options.WithVersion());
+    }
+
+
+    public class GetObjectArgs : Pulumi.InvokeArgs
+    {
+        /// <summary>
+        /// The apiVersion of the object, e.g., `apps/v1`.
+        /// </summary>
+        [Input("apiVersion", required: true)]
+        public string ApiVersion { get; set; } = null!;
+
+        /// <summary>
+        /// The kind of the object, e.g., `Deployment`.
+        /// </summary>
+        [Input("kind", required: true)]
+        public string Kind { get; set; } = null!;
+
+        /// <summary>
+        /// The name of the object.
+        /// </summary>
+        [Input("name", required: true)]
+        public string Name { get; set; } = null!;
+
+        /// <summary>
+        /// The namespace of the object. Defaults to the namespace of the provider for namespaced kinds.
+        /// </summary>
+        [Input("namespace")]
+        public string? Namespace { get; set; }
+
+        /// <summary>
+        /// How long to wait for the `waitFor` condition, in seconds. Defaults to 300.
+        /// </summary>
+        [Input("timeoutSeconds")]
+        public int? TimeoutSeconds { get; set; }
+
+        /// <summary>
+        /// A condition that the object must satisfy before it is returned, in the syntax of `kubectl wait --for`: `exists`, `condition=&lt;type&gt;[=&lt;status&gt;]` or `jsonpath={&lt;path&gt;}[=&lt;value&gt;]`. If not set, the object is read once.
+        /// </summary>
+        [Input("waitFor")]
+        public string? WaitFor { get; set; }
+
+        public GetObjectArgs()
+        {
+        }
+    }
+
+
+    [OutputType]
+    public sealed class GetObjectResult
+    {
+        /// <summary>
+        /// The live object.
+        /// </summary>
+        public readonly ImmutableDictionary<string, object> Result;
+
+        [OutputConstructor]
+        private GetObjectResult(ImmutableDictionary<string, object> result)
+        {
+            Result = result;
+        }
+    }
+}
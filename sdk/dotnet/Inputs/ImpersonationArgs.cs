@@ -0,0 +1,46 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+using System;
+using System.Collections.Generic;
+using System.Collections.Immutable;
+using System.Threading.Tasks;
+using Pulumi.Serialization;
+
+namespace Pulumi.Kubernetes.Types.Inputs.
+{
+
+    /// <summary>
+    /// The identity that the provider impersonates in every request to the Kubernetes API server.
+    /// </summary>
+    public class ImpersonationArgs : Pulumi.ResourceArgs
+    {
+        [Input("groups")]
+        private InputList<string>? _groups;
+
+        /// <summary>
+        /// The groups to impersonate. Requires `user` to be set.
+        /// </summary>
+        public InputList<string> Groups
+        {
+            get => _groups ?? (_groups = new InputList<string>());
+            set => _groups = value;
+        }
+
+        /// <summary>
+        /// The UID to impersonate. Requires `user` to be set, and Kubernetes v1.22 or later.
+        /// </summary>
+        [Input("uid")]
+        public Input<string>? Uid { get; set; }
+
+        /// <summary>
+        /// The user to impersonate, e.g., `system:serviceaccount:&lt;namespace&gt;:&lt;name&gt;`.
+        /// </summary>
+        [Input("user")]
+        public Input<string>? User { get; set; }
+
+        public ImpersonationArgs()
+        {
+        }
+    }
+}
@@ -0,0 +1,182 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+using System;
+using System.Collections.Generic;
+using System.Collections.Immutable;
+using System.Threading.Tasks;
+using Pulumi.Serialization;
+
+namespace Pulumi.Kubernetes
+{
+    /// <summary>
+    /// PodExec runs a command in a container of a Pod when it is created, and again whenever its inputs change, e.g., to run database migrations or smoke tests as part of a stack. The command is not run during previews, where its result is unknown. Deleting a PodExec does not undo the effects of its command.
+    /// </summary>
+    [KubernetesResourceType("kubernetes:index:PodExec")]
+    public partial class PodExec : KubernetesResource
+    {
+        /// <summary>
+        /// The command to run, and its arguments. The command is not run in a shell.
+        /// </summary>
+        [Output("command")]
+        public Output<ImmutableArray<string>> Command { get; private set; } = null!;
+
+        /// <summary>
+        /// The container to run the command in. Defaults to the only container of the Pod.
+        /// </summary>
+        [Output("container")]
+        public Output<string> Container { get; private set; } = null!;
+
+        /// <summary>
+        /// The exit code of the command.
+        /// </summary>
+        [Output("exitCode")]
+        public Output<int> ExitCode { get; private set; } = null!;
+
+        /// <summary>
+        /// The namespace of the Pod. Defaults to the namespace of the provider.
+        /// </summary>
+        [Output("namespace")]
+        public Output<string> Namespace { get; private set; } = null!;
+
+        /// <summary>
+        /// The name of the Pod to run the command in.
+        /// </summary>
+        [Output("pod")]
+        public Output<string> Pod { get; private set; } = null!;
+
+        /// <summary>
+        /// The standard error of the command.
+        /// </summary>
+        [Output("stderr")]
+        public Output<string> Stderr { get; private set; } = null!;
+
+        /// <summary>
+        /// The input of the command.
+        /// </summary>
+        [Output("stdin")]
+        public Output<string> Stdin { get; private set; } = null!;
+
+        /// <summary>
+        /// The standard output of the command.
+        /// </summary>
+        [Output("stdout")]
+        public Output<string> Stdout { get; private set; } = null!;
+
+        /// <summary>
+        /// How long to wait for the `waitFor` condition and for the command to exit, in seconds.
+        /// </summary>
+        [Output("timeoutSeconds")]
+        public Output<int> TimeoutSeconds { get; private set; } = null!;
+
+        /// <summary>
+        /// A condition that the Pod must satisfy before the command is run, in the syntax of `kubectl wait --for`, e.g., `condition=Ready`.
+        /// </summary>
+        [Output("waitFor")]
+        public Output<string> WaitFor { get; private set; } = null!;
+
+
+        /// <summary>
+        /// Create a PodExec resource with the given unique name, arguments, and options.
+        /// </summary>
+        ///
+        /// <param name="name">The unique name of the resource</param>
+        /// <param name="args">The arguments used to populate this resource's properties</param>
+        /// <param name="options">A bag of options that control this resource's behavior</param>
+        public PodExec(string name, Pulumi.Kubernetes.Types.Inputs..PodExecArgs? args = null, CustomResourceOptions? options = null)
+            : base("kubernetes:index:PodExec", name, args ?? new PodExecArgs(), MakeResourceOptions(options, ""))
+        {
+        }
+        internal PodExec(string name, ImmutableDictionary<string, object?> dictionary, CustomResourceOptions? options = null)
+            : base("kubernetes:index:PodExec", name, new DictionaryResourceArgs(dictionary), MakeResourceOptions(options, ""))
+        {
+        }
+
+        private PodExec(string name, Input<string> id, CustomResourceOptions? options = null)
+            : base("kubernetes:index:PodExec", name, null, MakeResourceOptions(options, id))
+        {
+        }
+
+        private static CustomResourceOptions MakeResourceOptions(CustomResourceOptions? options, Input<string>? id)
+        {
+            var defaultOptions = new CustomResourceOptions
+            {
+                Version = Utilities.Version,
+            };
+            var merged = CustomResourceOptions.Merge(defaultOptions, options);
+            // Override the ID if one was specified for consistency with other language SDKs.
+            merged.Id = id ?? merged.Id;
+            return merged;
+        }
+        /// <summary>
+        /// Get an existing PodExec resource's state with the given name, ID, and optional extra
+        /// properties used to qualify the lookup.
+        /// </summary>
+        ///
+        /// <param name="name">The unique name of the resulting resource.</param>
+        /// <param name="id">The unique provider ID of the resource to lookup.</param>
+        /// <param name="options">A bag of options that control this resource's behavior</param>
+        public static PodExec Get(string name, Input<string> id, CustomResourceOptions? options = null)
+        {
+            return new PodExec(name, id, options);
+        }
+    }
+}
+namespace Pulumi.Kubernetes.Types.Inputs.
+{
+
+    public class PodExecArgs : Pulumi.ResourceArgs
+    {
+        [Input("command", required: true)]
+        private InputList<string>? _command;
+
+        /// <summary>
+        /// The command to run, and its arguments. The command is not run in a shell.
+        /// </summary>
+        public InputList<string> Command
+        {
+            get => _command ?? (_command = new InputList<string>());
+            set => _command = value;
+        }
+
+        /// <summary>
+        /// The container to run the command in. Defaults to the only container of the Pod.
+        /// </summary>
+        [Input("container")]
+        public Input<string>? Container { get; set; }
+
+        /// <summary>
+        /// The namespace of the Pod. Defaults to the namespace of the provider.
+        /// </summary>
+        [Input("namespace")]
+        public Input<string>? Namespace { get; set; }
+
+        /// <summary>
+        /// The name of the Pod to run the command in.
+        /// </summary>
+        [Input("pod", required: true)]
+        public Input<string> Pod { get; set; } = null!;
+
+        /// <summary>
+        /// The input of the command.
+        /// </summary>
+        [Input("stdin")]
+        public Input<string>? Stdin { get; set; }
+
+        /// <summary>
+        /// How long to wait for the `waitFor` condition and for the command to exit, in seconds.
+        /// </summary>
+        [Input("timeoutSeconds")]
+        public Input<int>? TimeoutSeconds { get; set; }
+
+        /// <summary>
+        /// A condition that the Pod must satisfy before the command is run, in the syntax of `kubectl wait --for`, e.g., `condition=Ready`.
+        /// </summary>
+        [Input("waitFor")]
+        public Input<string>? WaitFor { get; set; }
+
+        public PodExecArgs()
+        {
+        }
+    }
+}
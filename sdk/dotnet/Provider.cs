@@ -46,6 +46,46 @@ namespace Pulumi.Kubernetes
 
     public sealed class ProviderArgs : Pulumi.ResourceArgs
     {
+        /// <summary>
+        /// PEM-encoded certificate authority certificates for the API server given by `server`, either as PEM text or base64-encoded as in a kubeconfig.
+        /// </summary>
+        [Input("certificateAuthorityData")]
+        public Input<string>? CertificateAuthorityData { get; set; }
+
+        /// <summary>
+        /// The maximum burst of requests that the provider sends to the Kubernetes API server above `clientQps`, shared by all of its clients. Defaults to 10.
+        /// </summary>
+        [Input("clientBurst", json: true)]
+        public Input<int>? ClientBurst { get; set; }
+
+        /// <summary>
+        /// A PEM-encoded client certificate to authenticate to the API server given by `server`, either as PEM text or base64-encoded as in a kubeconfig. Requires `clientKeyData`.
+        /// </summary>
+        [Input("clientCertificateData")]
+        public Input<string>? ClientCertificateData { get; set; }
+
+        [Input("clientKeyData")]
+        private Input<string>? _clientKeyData;
+
+        /// <summary>
+        /// The PEM-encoded private key of the client certificate given by `clientCertificateData`, either as PEM text or base64-encoded as in a kubeconfig.
+        /// </summary>
+        public Input<string>? ClientKeyData
+        {
+            get => _clientKeyData;
+            set
+            {
+                var emptySecret = Output.CreateSecret(0);
+                _clientKeyData = Output.Tuple<Input<string>?, int>(value, emptySecret).Apply(t => t.Item1);
+            }
+        }
+
+        /// <summary>
+        /// The maximum number of queries per second that the provider sends to the Kubernetes API server, shared by all of its clients. Defaults to 5.
+        /// </summary>
+        [Input("clientQps", json: true)]
+        public Input<double>? ClientQps { get; set; }
+
         /// <summary>
         /// If present, the name of the kubeconfig cluster to use.
         /// </summary>
@@ -58,6 +98,34 @@ namespace Pulumi.Kubernetes
         [Input("context")]
         public Input<string>? Context { get; set; }
 
+        /// <summary>
+        /// The directory in which the provider caches the discovery information and OpenAPI schema of the Kubernetes API server, shared by all provider processes. If this is not set, discovery information is only cached in memory.
+        /// </summary>
+        [Input("discoveryCacheDir")]
+        public Input<string>? DiscoveryCacheDir { get; set; }
+
+        /// <summary>
+        /// The number of seconds for which the cached API groups and resources of the Kubernetes API server are used, before they are discovered again. The cached OpenAPI schema is revalidated on every use. Only used if `discoveryCacheDir` is set. Set to 0 to disable the on-disk cache. Defaults to 600.
+        /// </summary>
+        [Input("discoveryCacheTtl", json: true)]
+        public Input<int>? DiscoveryCacheTtl { get; set; }
+
+        /// <summary>
+        /// BETA FEATURE - A comma-separated list of the field managers whose changes are not reported as drift when `enableDriftDetection` is set, such as the controllers that own the fields they update. Changes by any other field manager than the provider are reported as drift. Defaults to `kube-controller-manager,kube-scheduler,kubelet`.
+        /// </summary>
+        [Input("driftIgnoredFieldManagers")]
+        public Input<string>? DriftIgnoredFieldManagers { get; set; }
+
+        /// <summary>
+        /// BETA FEATURE - If present and set to true, compare the inputs of each resource to its live state during diffs, and report fields that were changed out of band (e.g., with `kubectl edit`) as drift. Fields owned by the field managers in `driftIgnoredFieldManagers`, such as `spec.replicas` managed by a HorizontalPodAutoscaler, are not reported.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `enableDriftDetection` parameter.
+        /// 2. The `PULUMI_K8S_ENABLE_DRIFT_DETECTION` environment variable.
+        /// </summary>
+        [Input("enableDriftDetection", json: true)]
+        public Input<bool>? EnableDriftDetection { get; set; }
+
         /// <summary>
         /// BETA FEATURE - If present and set to true, enable server-side diff calculations.
         /// This feature is in developer preview, and is disabled by default.
@@ -95,12 +163,52 @@ namespace Pulumi.Kubernetes
         [Input("helmRepositoryConfigPath")]
         public Input<string>? HelmRepositoryConfigPath { get; set; }
 
+        /// <summary>
+        /// BETA FEATURE - A JSON object mapping resource types to lists of fields that are excluded from diffs and updates, e.g., `{"apps/v1/Deployment": ["/spec/replicas"]}`. Resource types are given as `&lt;apiVersion&gt;/&lt;kind&gt;`, and fields as JSON pointers or JSONPaths, where `*` matches every map key or list element. This is useful for fields that are set by controllers or mutating webhooks, such as injected sidecar containers or `caBundle` values. Fields can also be ignored for a single resource with the `pulumi.com/ignoreFields` annotation.
+        /// </summary>
+        [Input("ignoreFields")]
+        public Input<string>? IgnoreFields { get; set; }
+
+        /// <summary>
+        /// The user, groups and UID that the provider impersonates in every request to the Kubernetes API server, including the requests of Helm releases, e.g., to manage the resources of a tenant with the identity of its service account. If not set, the JSON encoding of the identity is read from the `PULUMI_K8S_IMPERSONATE` environment variable.
+        /// </summary>
+        [Input("impersonate", json: true)]
+        public Input<Pulumi.Kubernetes.Types.Inputs..ImpersonationArgs>? Impersonate { get; set; }
+
+        /// <summary>
+        /// If present and set to true, the certificate of the API server given by `server` is not verified. This makes the connection insecure, and cannot be combined with `certificateAuthorityData`.
+        /// </summary>
+        [Input("insecureSkipTlsVerify", json: true)]
+        public Input<bool>? InsecureSkipTlsVerify { get; set; }
+
+        /// <summary>
+        /// The Kubernetes version to validate resources against when the cluster is unreachable, e.g., in render mode or in CI. When set, resources are validated and scoped using the OpenAPI schema bundled with the provider for that version.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `kubeVersion` parameter.
+        /// 2. The `PULUMI_K8S_KUBE_VERSION` environment variable.
+        /// </summary>
+        [Input("kubeVersion")]
+        public Input<string>? KubeVersion { get; set; }
+
         /// <summary>
         /// The contents of a kubeconfig file or the path to a kubeconfig file.
         /// </summary>
         [Input("kubeconfig")]
         public Input<string>? KubeConfig { get; set; }
 
+        /// <summary>
+        /// The maximum number of watches that the provider keeps open at the same time, e.g., while it waits for resources to become ready. A watch that would exceed the limit waits up to 10 seconds for another watch to end. If none does, the objects of the resource are listed again periodically until a watch is free, so that waiting for resources that need several watches, e.g., 4 for a Deployment, still completes. Unlimited by default.
+        /// </summary>
+        [Input("maxConcurrentWatches", json: true)]
+        public Input<int>? MaxConcurrentWatches { get; set; }
+
+        /// <summary>
+        /// If present and set to true, resources that use a deprecated or removed apiVersion are migrated to the newest apiVersion of their kind that the cluster supports, along with the changes of their fields that can be made automatically, such as the backends of an Ingress. Resources that cannot be migrated automatically are left unchanged with a warning.
+        /// </summary>
+        [Input("migrateApiVersions", json: true)]
+        public Input<bool>? MigrateApiVersions { get; set; }
+
         /// <summary>
         /// If present, the default namespace to use. This flag is ignored for cluster-scoped resources.
         /// 
@@ -112,6 +220,50 @@ namespace Pulumi.Kubernetes
         [Input("namespace")]
         public Input<string>? Namespace { get; set; }
 
+        /// <summary>
+        /// A directory of policies that are evaluated against every resource in `Check`. Each YAML or JSON file in the directory contains one or more policies, whose validations are CEL expressions of the resource as `object` and of its previous inputs as `oldObject`. Violations of `mandatory` policies (the default) fail the check, violations of `advisory` policies are reported as warnings, and `disabled` policies are not evaluated. Validations that depend on values that are unknown in a preview are evaluated again when the resource is created or updated. Rego policies are not supported.
+        /// </summary>
+        [Input("policyDirectory")]
+        public Input<string>? PolicyDirectory { get; set; }
+
+        /// <summary>
+        /// The URL of an http, https or socks5 proxy to connect to the API server given by `server` through.
+        /// </summary>
+        [Input("proxyUrl")]
+        public Input<string>? ProxyUrl { get; set; }
+
+        /// <summary>
+        /// BETA FEATURE - If present and set to true, and `renderYamlToDirectory` is set, the rendered manifests are cleaned up
+        /// so that they can be applied directly with `kubectl apply -k` or consumed by GitOps tooling. The
+        /// `last-applied-configuration` and Pulumi-internal annotations are removed, the manifests are ordered so that
+        /// CustomResourceDefinitions and Namespaces are applied first and webhook configurations last, and a `kustomization.yaml`
+        /// listing every rendered file is written to the root of the directory.
+        /// </summary>
+        [Input("renderYamlClean", json: true)]
+        public Input<bool>? RenderYamlClean { get; set; }
+
+        /// <summary>
+        /// BETA FEATURE - If present, and `renderYamlToDirectory` is set, controls how the rendered manifests are arranged in the
+        /// directory. Values are:
+        /// - `directory` (default): one file per resource, in the `0-crd` and `1-manifest` subdirectories.
+        /// - `component`: one file per resource, in a subdirectory per component resource (e.g., a Chart or ConfigGroup).
+        /// - `single`: a single multi-document file per stack, named `&lt;stack&gt;.yaml`.
+        /// </summary>
+        [Input("renderYamlLayout")]
+        public Input<string>? RenderYamlLayout { get; set; }
+
+        /// <summary>
+        /// Controls how the data of Secrets is written when `renderYamlToDirectory` is set. `plaintext` (the default) writes it unencrypted. `sops` encrypts the `data` and `stringData` fields in the SOPS format. `sealedSecrets` renders each Secret as a Bitnami SealedSecret.
+        /// </summary>
+        [Input("renderYamlSecrets")]
+        public Input<string>? RenderYamlSecrets { get; set; }
+
+        /// <summary>
+        /// The key used to encrypt Secrets when `renderYamlSecrets` is set. For `sops`, a comma-separated list of age recipients, or the path to (or contents of) an ASCII-armored PGP public key. For `sealedSecrets`, the path to (or contents of) the PEM-encoded certificate of the sealed-secrets controller.
+        /// </summary>
+        [Input("renderYamlSecretsKey")]
+        public Input<string>? RenderYamlSecretsKey { get; set; }
+
         /// <summary>
         /// BETA FEATURE - If present, render resource manifests to this directory. In this mode, resources will not
         /// be created on a Kubernetes cluster, but the rendered manifests will be kept in sync with changes
@@ -125,6 +277,18 @@ namespace Pulumi.Kubernetes
         [Input("renderYamlToDirectory")]
         public Input<string>? RenderYamlToDirectory { get; set; }
 
+        /// <summary>
+        /// Controls how resources with unknown values are rendered when `renderYamlToDirectory` is set. `defer` (the default) does not write a resource until all of its values are known, and lists its unknown values in `unresolved-fields.json` in the render directory. `placeholder` renders unknown values as `&lt;unresolved&gt;` and lists them in `unresolved-fields.json` in the render directory.
+        /// </summary>
+        [Input("renderYamlUnknowns")]
+        public Input<string>? RenderYamlUnknowns { get; set; }
+
+        /// <summary>
+        /// The address of the Kubernetes API server, e.g., `https://1.2.3.4:6443`. If this is set, the provider connects to this server with the credentials given by `certificateAuthorityData`, `token`, `clientCertificateData` and `clientKeyData`, and no kubeconfig is loaded. It cannot be combined with `kubeconfig`, `context` or `cluster`.
+        /// </summary>
+        [Input("server")]
+        public Input<string>? Server { get; set; }
+
         /// <summary>
         /// If present and set to true, suppress apiVersion deprecation warnings from the CLI.
         /// </summary>
@@ -143,15 +307,47 @@ namespace Pulumi.Kubernetes
         [Input("suppressHelmReleaseBetaWarning", json: true)]
         public Input<bool>? SuppressHelmReleaseBetaWarning { get; set; }
 
+        [Input("token")]
+        private Input<string>? _token;
+
+        /// <summary>
+        /// A bearer token to authenticate to the API server given by `server`.
+        /// </summary>
+        public Input<string>? Token
+        {
+            get => _token;
+            set
+            {
+                var emptySecret = Output.CreateSecret(0);
+                _token = Output.Tuple<Input<string>?, int>(value, emptySecret).Apply(t => t.Item1);
+            }
+        }
+
         public ProviderArgs()
         {
+            ClientBurst = Utilities.GetEnvInt32("PULUMI_K8S_CLIENT_BURST");
+            ClientQps = Utilities.GetEnvDouble("PULUMI_K8S_CLIENT_QPS");
+            DiscoveryCacheDir = Utilities.GetEnv("PULUMI_K8S_DISCOVERY_CACHE_DIR");
+            DiscoveryCacheTtl = Utilities.GetEnvInt32("PULUMI_K8S_DISCOVERY_CACHE_TTL");
+            DriftIgnoredFieldManagers = Utilities.GetEnv("PULUMI_K8S_DRIFT_IGNORED_FIELD_MANAGERS");
+            EnableDriftDetection = Utilities.GetEnvBoolean("PULUMI_K8S_ENABLE_DRIFT_DETECTION");
             EnableDryRun = Utilities.GetEnvBoolean("PULUMI_K8S_ENABLE_DRY_RUN");
             HelmDriver = Utilities.GetEnv("PULUMI_K8S_HELM_DRIVER");
             HelmPluginsPath = Utilities.GetEnv("PULUMI_K8S_HELM_PLUGINS_PATH");
             HelmRegistryConfigPath = Utilities.GetEnv("PULUMI_K8S_HELM_REGISTRY_CONFIG_PATH");
             HelmRepositoryCache = Utilities.GetEnv("PULUMI_K8s_HELM_REPOSITORY_CACHE");
             HelmRepositoryConfigPath = Utilities.GetEnv("PULUMI_K8S_HELM_REPOSITORY_CONFIG_PATH");
+            IgnoreFields = Utilities.GetEnv("PULUMI_K8S_IGNORE_FIELDS");
+            KubeVersion = Utilities.GetEnv("PULUMI_K8S_KUBE_VERSION");
             KubeConfig = Utilities.GetEnv("KUBECONFIG");
+            MaxConcurrentWatches = Utilities.GetEnvInt32("PULUMI_K8S_MAX_CONCURRENT_WATCHES");
+            MigrateApiVersions = Utilities.GetEnvBoolean("PULUMI_K8S_MIGRATE_API_VERSIONS");
+            PolicyDirectory = Utilities.GetEnv("PULUMI_K8S_POLICY_DIRECTORY");
+            RenderYamlClean = Utilities.GetEnvBoolean("PULUMI_K8S_RENDER_YAML_CLEAN");
+            RenderYamlLayout = Utilities.GetEnv("PULUMI_K8S_RENDER_YAML_LAYOUT");
+            RenderYamlSecrets = Utilities.GetEnv("PULUMI_K8S_RENDER_YAML_SECRETS");
+            RenderYamlSecretsKey = Utilities.GetEnv("PULUMI_K8S_RENDER_YAML_SECRETS_KEY");
+            RenderYamlUnknowns = Utilities.GetEnv("PULUMI_K8S_RENDER_YAML_UNKNOWNS");
             SuppressDeprecationWarnings = Utilities.GetEnvBoolean("PULUMI_K8S_SUPPRESS_DEPRECATION_WARNINGS");
             SuppressHelmHookWarnings = Utilities.GetEnvBoolean("PULUMI_K8S_SUPPRESS_HELM_HOOK_WARNINGS");
             SuppressHelmReleaseBetaWarning = Utilities.GetEnvBoolean("PULUMI_K8S_SUPPRESS_HELM_RELEASE_BETA_WARNING");
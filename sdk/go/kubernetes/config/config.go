@@ -8,6 +8,51 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
 )
 
+// PEM-encoded certificate authority certificates for the API server given by `server`, either as PEM text or base64-encoded as in a kubeconfig.
+//
+// This config can be specified in the following ways, using this precedence:
+// 1. This `certificateAuthorityData` parameter.
+// 2. The `PULUMI_K8S_CERTIFICATE_AUTHORITY_DATA` environment variable, unless `kubeconfig`, `context` or `cluster` is set.
+func GetCertificateAuthorityData(ctx *pulumi.Context) string {
+	return config.Get(ctx, "kubernetes:certificateAuthorityData")
+}
+
+// The maximum burst of requests that the provider sends to the Kubernetes API server above `clientQps`, shared by all of its clients. Defaults to 10.
+//
+// This config can be specified in the following ways, using this precedence:
+// 1. This `clientBurst` parameter.
+// 2. The `PULUMI_K8S_CLIENT_BURST` environment variable.
+func GetClientBurst(ctx *pulumi.Context) int {
+	return config.GetInt(ctx, "kubernetes:clientBurst")
+}
+
+// A PEM-encoded client certificate to authenticate to the API server given by `server`, either as PEM text or base64-encoded as in a kubeconfig. Requires `clientKeyData`.
+//
+// This config can be specified in the following ways, using this precedence:
+// 1. This `clientCertificateData` parameter.
+// 2. The `PULUMI_K8S_CLIENT_CERTIFICATE_DATA` environment variable, unless `kubeconfig`, `context` or `cluster` is set.
+func GetClientCertificateData(ctx *pulumi.Context) string {
+	return config.Get(ctx, "kubernetes:clientCertificateData")
+}
+
+// The PEM-encoded private key of the client certificate given by `clientCertificateData`, either as PEM text or base64-encoded as in a kubeconfig.
+//
+// This config can be specified in the following ways, using this precedence:
+// 1. This `clientKeyData` parameter.
+// 2. The `PULUMI_K8S_CLIENT_KEY_DATA` environment variable, unless `kubeconfig`, `context` or `cluster` is set.
+func GetClientKeyData(ctx *pulumi.Context) string {
+	return config.Get(ctx, "kubernetes:clientKeyData")
+}
+
+// The maximum number of queries per second that the provider sends to the Kubernetes API server, shared by all of its clients. Defaults to 5.
+//
+// This config can be specified in the following ways, using this precedence:
+// 1. This `clientQps` parameter.
+// 2. The `PULUMI_K8S_CLIENT_QPS` environment variable.
+func GetClientQps(ctx *pulumi.Context) float64 {
+	return config.GetFloat64(ctx, "kubernetes:clientQps")
+}
+
 // If present, the name of the kubeconfig cluster to use.
 func GetCluster(ctx *pulumi.Context) string {
 	return config.Get(ctx, "kubernetes:cluster")
@@ -18,6 +63,42 @@ func GetContext(ctx *pulumi.Context) string {
 	return config.Get(ctx, "kubernetes:context")
 }
 
+// The directory in which the provider caches the discovery information and OpenAPI schema of the Kubernetes API server, shared by all provider processes. If this is not set, discovery information is only cached in memory.
+//
+// This config can be specified in the following ways, using this precedence:
+// 1. This `discoveryCacheDir` parameter.
+// 2. The `PULUMI_K8S_DISCOVERY_CACHE_DIR` environment variable.
+func GetDiscoveryCacheDir(ctx *pulumi.Context) string {
+	return config.Get(ctx, "kubernetes:discoveryCacheDir")
+}
+
+// The number of seconds for which the cached API groups and resources of the Kubernetes API server are used, before they are discovered again. The cached OpenAPI schema is revalidated on every use. Only used if `discoveryCacheDir` is set. Set to 0 to disable the on-disk cache. Defaults to 600.
+//
+// This config can be specified in the following ways, using this precedence:
+// 1. This `discoveryCacheTtl` parameter.
+// 2. The `PULUMI_K8S_DISCOVERY_CACHE_TTL` environment variable.
+func GetDiscoveryCacheTtl(ctx *pulumi.Context) int {
+	return config.GetInt(ctx, "kubernetes:discoveryCacheTtl")
+}
+
+// BETA FEATURE - A comma-separated list of the field managers whose changes are not reported as drift when `enableDriftDetection` is set, such as the controllers that own the fields they update. Changes by any other field manager than the provider are reported as drift. Defaults to `kube-controller-manager,kube-scheduler,kubelet`.
+//
+// This config can be specified in the following ways, using this precedence:
+// 1. This `driftIgnoredFieldManagers` parameter.
+// 2. The `PULUMI_K8S_DRIFT_IGNORED_FIELD_MANAGERS` environment variable.
+func GetDriftIgnoredFieldManagers(ctx *pulumi.Context) string {
+	return config.Get(ctx, "kubernetes:driftIgnoredFieldManagers")
+}
+
+// BETA FEATURE - If present and set to true, compare the inputs of each resource to its live state during diffs, and report fields that were changed out of band (e.g., with `kubectl edit`) as drift. Fields owned by the field managers in `driftIgnoredFieldManagers`, such as `spec.replicas` managed by a HorizontalPodAutoscaler, are not reported.
+//
+// This config can be specified in the following ways, using this precedence:
+// 1. This `enableDriftDetection` parameter.
+// 2. The `PULUMI_K8S_ENABLE_DRIFT_DETECTION` environment variable.
+func GetEnableDriftDetection(ctx *pulumi.Context) bool {
+	return config.GetBool(ctx, "kubernetes:enableDriftDetection")
+}
+
 // BETA FEATURE - If present and set to true, enable server-side diff calculations.
 // This feature is in developer preview, and is disabled by default.
 //
@@ -53,11 +134,65 @@ func GetHelmRepositoryConfigPath(ctx *pulumi.Context) string {
 	return config.Get(ctx, "kubernetes:helmRepositoryConfigPath")
 }
 
+// BETA FEATURE - A JSON object mapping resource types to lists of fields that are excluded from diffs and updates, e.g., `{"apps/v1/Deployment": ["/spec/replicas"]}`. Resource types are given as `<apiVersion>/<kind>`, and fields as JSON pointers or JSONPaths, where `*` matches every map key or list element. This is useful for fields that are set by controllers or mutating webhooks, such as injected sidecar containers or `caBundle` values. Fields can also be ignored for a single resource with the `pulumi.com/ignoreFields` annotation.
+//
+// This config can be specified in the following ways, using this precedence:
+// 1. This `ignoreFields` parameter.
+// 2. The `PULUMI_K8S_IGNORE_FIELDS` environment variable.
+func GetIgnoreFields(ctx *pulumi.Context) string {
+	return config.Get(ctx, "kubernetes:ignoreFields")
+}
+
+// The user, groups and UID that the provider impersonates in every request to the Kubernetes API server, including the requests of Helm releases, e.g., to manage the resources of a tenant with the identity of its service account. The identity is a JSON object with the `user`, `groups` and `uid` to impersonate.
+//
+// This config can be specified in the following ways, using this precedence:
+// 1. This `impersonate` parameter.
+// 2. The `PULUMI_K8S_IMPERSONATE` environment variable.
+func GetImpersonate(ctx *pulumi.Context) string {
+	return config.Get(ctx, "kubernetes:impersonate")
+}
+
+// If present and set to true, the certificate of the API server given by `server` is not verified. This makes the connection insecure, and cannot be combined with `certificateAuthorityData`.
+//
+// This config can be specified in the following ways, using this precedence:
+// 1. This `insecureSkipTlsVerify` parameter.
+// 2. The `PULUMI_K8S_INSECURE_SKIP_TLS_VERIFY` environment variable, unless `kubeconfig`, `context` or `cluster` is set.
+func GetInsecureSkipTlsVerify(ctx *pulumi.Context) bool {
+	return config.GetBool(ctx, "kubernetes:insecureSkipTlsVerify")
+}
+
+// The Kubernetes version to validate resources against when the cluster is unreachable, e.g., in render mode or in CI. When set, resources are validated and scoped using the OpenAPI schema bundled with the provider for that version.
+//
+// This config can be specified in the following ways, using this precedence:
+// 1. This `kubeVersion` parameter.
+// 2. The `PULUMI_K8S_KUBE_VERSION` environment variable.
+func GetKubeVersion(ctx *pulumi.Context) string {
+	return config.Get(ctx, "kubernetes:kubeVersion")
+}
+
 // The contents of a kubeconfig file or the path to a kubeconfig file. If this is set, this config will be used instead of $KUBECONFIG.
 func GetKubeconfig(ctx *pulumi.Context) string {
 	return config.Get(ctx, "kubernetes:kubeconfig")
 }
 
+// The maximum number of watches that the provider keeps open at the same time, e.g., while it waits for resources to become ready. A watch that would exceed the limit waits up to 10 seconds for another watch to end. If none does, the objects of the resource are listed again periodically until a watch is free, so that waiting for resources that need several watches, e.g., 4 for a Deployment, still completes. Unlimited by default.
+//
+// This config can be specified in the following ways, using this precedence:
+// 1. This `maxConcurrentWatches` parameter.
+// 2. The `PULUMI_K8S_MAX_CONCURRENT_WATCHES` environment variable.
+func GetMaxConcurrentWatches(ctx *pulumi.Context) int {
+	return config.GetInt(ctx, "kubernetes:maxConcurrentWatches")
+}
+
+// If present and set to true, resources that use a deprecated or removed apiVersion are migrated to the newest apiVersion of their kind that the cluster supports, along with the changes of their fields that can be made automatically, such as the backends of an Ingress. Resources that cannot be migrated automatically are left unchanged with a warning.
+//
+// This config can be specified in the following ways, using this precedence:
+// 1. This `migrateApiVersions` parameter.
+// 2. The `PULUMI_K8S_MIGRATE_API_VERSIONS` environment variable.
+func GetMigrateApiVersions(ctx *pulumi.Context) bool {
+	return config.GetBool(ctx, "kubernetes:migrateApiVersions")
+}
+
 // If present, the default namespace to use. This flag is ignored for cluster-scoped resources.
 //
 // A namespace can be specified in multiple places, and the precedence is as follows:
@@ -68,6 +203,68 @@ func GetNamespace(ctx *pulumi.Context) string {
 	return config.Get(ctx, "kubernetes:namespace")
 }
 
+// A directory of policies that are evaluated against every resource in `Check`. Each YAML or JSON file in the directory contains one or more policies, whose validations are CEL expressions of the resource as `object` and of its previous inputs as `oldObject`. Violations of `mandatory` policies (the default) fail the check, violations of `advisory` policies are reported as warnings, and `disabled` policies are not evaluated. Validations that depend on values that are unknown in a preview are evaluated again when the resource is created or updated. Rego policies are not supported.
+//
+// This config can be specified in the following ways, using this precedence:
+// 1. This `policyDirectory` parameter.
+// 2. The `PULUMI_K8S_POLICY_DIRECTORY` environment variable.
+func GetPolicyDirectory(ctx *pulumi.Context) string {
+	return config.Get(ctx, "kubernetes:policyDirectory")
+}
+
+// The URL of an http, https or socks5 proxy to connect to the API server given by `server` through.
+//
+// This config can be specified in the following ways, using this precedence:
+// 1. This `proxyUrl` parameter.
+// 2. The `PULUMI_K8S_PROXY_URL` environment variable, unless `kubeconfig`, `context` or `cluster` is set.
+func GetProxyUrl(ctx *pulumi.Context) string {
+	return config.Get(ctx, "kubernetes:proxyUrl")
+}
+
+// BETA FEATURE - If present and set to true, and `renderYamlToDirectory` is set, the rendered manifests are cleaned up
+// so that they can be applied directly with `kubectl apply -k` or consumed by GitOps tooling. The
+// `last-applied-configuration` and Pulumi-internal annotations are removed, the manifests are ordered so that
+// CustomResourceDefinitions and Namespaces are applied first and webhook configurations last, and a `kustomization.yaml`
+// listing every rendered file is written to the root of the directory.
+//
+// This config can be specified in the following ways, using this precedence:
+// 1. This `renderYamlClean` parameter.
+// 2. The `PULUMI_K8S_RENDER_YAML_CLEAN` environment variable.
+func GetRenderYamlClean(ctx *pulumi.Context) bool {
+	return config.GetBool(ctx, "kubernetes:renderYamlClean")
+}
+
+// BETA FEATURE - If present, and `renderYamlToDirectory` is set, controls how the rendered manifests are arranged in the
+// directory. Values are:
+// - `directory` (default): one file per resource, in the `0-crd` and `1-manifest` subdirectories.
+// - `component`: one file per resource, in a subdirectory per component resource (e.g., a Chart or ConfigGroup).
+// - `single`: a single multi-document file per stack, named `<stack>.yaml`.
+//
+// This config can be specified in the following ways, using this precedence:
+// 1. This `renderYamlLayout` parameter.
+// 2. The `PULUMI_K8S_RENDER_YAML_LAYOUT` environment variable.
+func GetRenderYamlLayout(ctx *pulumi.Context) string {
+	return config.Get(ctx, "kubernetes:renderYamlLayout")
+}
+
+// Controls how the data of Secrets is written when `renderYamlToDirectory` is set. `plaintext` (the default) writes it unencrypted. `sops` encrypts the `data` and `stringData` fields in the SOPS format. `sealedSecrets` renders each Secret as a Bitnami SealedSecret.
+//
+// This config can be specified in the following ways, using this precedence:
+// 1. This `renderYamlSecrets` parameter.
+// 2. The `PULUMI_K8S_RENDER_YAML_SECRETS` environment variable.
+func GetRenderYamlSecrets(ctx *pulumi.Context) string {
+	return config.Get(ctx, "kubernetes:renderYamlSecrets")
+}
+
+// The key used to encrypt Secrets when `renderYamlSecrets` is set. For `sops`, a comma-separated list of age recipients, or the path to (or contents of) an ASCII-armored PGP public key. For `sealedSecrets`, the path to (or contents of) the PEM-encoded certificate of the sealed-secrets controller.
+//
+// This config can be specified in the following ways, using this precedence:
+// 1. This `renderYamlSecretsKey` parameter.
+// 2. The `PULUMI_K8S_RENDER_YAML_SECRETS_KEY` environment variable.
+func GetRenderYamlSecretsKey(ctx *pulumi.Context) string {
+	return config.Get(ctx, "kubernetes:renderYamlSecretsKey")
+}
+
 // BETA FEATURE - If present, render resource manifests to this directory. In this mode, resources will not
 // be created on a Kubernetes cluster, but the rendered manifests will be kept in sync with changes
 // to the Pulumi program. This feature is in developer preview, and is disabled by default.
@@ -80,6 +277,24 @@ func GetRenderYamlToDirectory(ctx *pulumi.Context) string {
 	return config.Get(ctx, "kubernetes:renderYamlToDirectory")
 }
 
+// Controls how resources with unknown values are rendered when `renderYamlToDirectory` is set. `defer` (the default) does not write a resource until all of its values are known, and lists its unknown values in `unresolved-fields.json` in the render directory. `placeholder` renders unknown values as `<unresolved>` and lists them in `unresolved-fields.json` in the render directory.
+//
+// This config can be specified in the following ways, using this precedence:
+// 1. This `renderYamlUnknowns` parameter.
+// 2. The `PULUMI_K8S_RENDER_YAML_UNKNOWNS` environment variable.
+func GetRenderYamlUnknowns(ctx *pulumi.Context) string {
+	return config.Get(ctx, "kubernetes:renderYamlUnknowns")
+}
+
+// The address of the Kubernetes API server, e.g., `https://1.2.3.4:6443`. If this is set, the provider connects to this server with the credentials given by `certificateAuthorityData`, `token`, `clientCertificateData` and `clientKeyData`, and no kubeconfig is loaded. It cannot be combined with `kubeconfig`, `context` or `cluster`.
+//
+// This config can be specified in the following ways, using this precedence:
+// 1. This `server` parameter.
+// 2. The `PULUMI_K8S_SERVER` environment variable, unless `kubeconfig`, `context` or `cluster` is set.
+func GetServer(ctx *pulumi.Context) string {
+	return config.Get(ctx, "kubernetes:server")
+}
+
 // If present and set to true, suppress apiVersion deprecation warnings from the CLI.
 //
 // This config can be specified in the following ways, using this precedence:
@@ -102,3 +317,12 @@ func GetSuppressHelmHookWarnings(ctx *pulumi.Context) bool {
 func GetSuppressHelmReleaseBetaWarning(ctx *pulumi.Context) bool {
 	return config.GetBool(ctx, "kubernetes:suppressHelmReleaseBetaWarning")
 }
+
+// A bearer token to authenticate to the API server given by `server`.
+//
+// This config can be specified in the following ways, using this precedence:
+// 1. This `token` parameter.
+// 2. The `PULUMI_K8S_TOKEN` environment variable, unless `kubeconfig`, `context` or `cluster` is set.
+func GetToken(ctx *pulumi.Context) string {
+	return config.Get(ctx, "kubernetes:token")
+}
@@ -0,0 +1,38 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+package kubernetes
+
+import (
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Reads a live object from the cluster, optionally waiting until it satisfies a condition, e.g., to use a value that a controller sets on a resource. The data of Secrets, and their last-applied-configuration annotation, are returned as secrets. Managed fields are omitted.
+func GetObject(ctx *pulumi.Context, args *GetObjectArgs, opts ...pulumi.InvokeOption) (*GetObjectResult, error) {
+	var rv GetObjectResult
+	err := ctx.Invoke("kubernetes:index:getObject", args, &rv, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &rv, nil
+}
+
+type GetObjectArgs struct {
+	// The apiVersion of the object, e.g., `apps/v1`.
+	ApiVersion string `pulumi:"apiVersion"`
+	// The kind of the object, e.g., `Deployment`.
+	Kind string `pulumi:"kind"`
+	// The name of the object.
+	Name string `pulumi:"name"`
+	// The namespace of the object. Defaults to the namespace of the provider for namespaced kinds.
+	Namespace *string `pulumi:"namespace"`
+	// How long to wait for the `waitFor` condition, in seconds. Defaults to 300.
+	TimeoutSeconds *int `pulumi:"timeoutSeconds"`
+	// A condition that the object must satisfy before it is returned, in the syntax of `kubectl wait --for`: `exists`, `condition=<type>[=<status>]` or `jsonpath={<path>}[=<value>]`. If not set, the object is read once.
+	WaitFor *string `pulumi:"waitFor"`
+}
+
+type GetObjectResult struct {
+	// The live object.
+	Result map[string]interface{} `pulumi:"result"`
+}
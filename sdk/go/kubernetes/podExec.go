@@ -0,0 +1,308 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+package kubernetes
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// PodExec runs a command in a container of a Pod when it is created, and again whenever its inputs change, e.g., to run database migrations or smoke tests as part of a stack. The command is not run during previews, where its result is unknown. Deleting a PodExec does not undo the effects of its command.
+type PodExec struct {
+	pulumi.CustomResourceState
+
+	// The command to run, and its arguments. The command is not run in a shell.
+	Command pulumi.StringArrayOutput `pulumi:"command"`
+	// The container to run the command in. Defaults to the only container of the Pod.
+	Container pulumi.StringPtrOutput `pulumi:"container"`
+	// The exit code of the command.
+	ExitCode pulumi.IntOutput `pulumi:"exitCode"`
+	// The namespace of the Pod. Defaults to the namespace of the provider.
+	Namespace pulumi.StringPtrOutput `pulumi:"namespace"`
+	// The name of the Pod to run the command in.
+	Pod pulumi.StringOutput `pulumi:"pod"`
+	// The standard error of the command.
+	Stderr pulumi.StringOutput `pulumi:"stderr"`
+	// The input of the command.
+	Stdin pulumi.StringPtrOutput `pulumi:"stdin"`
+	// The standard output of the command.
+	Stdout pulumi.StringOutput `pulumi:"stdout"`
+	// How long to wait for the `waitFor` condition and for the command to exit, in seconds.
+	TimeoutSeconds pulumi.IntPtrOutput `pulumi:"timeoutSeconds"`
+	// A condition that the Pod must satisfy before the command is run, in the syntax of `kubectl wait --for`, e.g., `condition=Ready`.
+	WaitFor pulumi.StringPtrOutput `pulumi:"waitFor"`
+}
+
+// NewPodExec registers a new resource with the given unique name, arguments, and options.
+func NewPodExec(ctx *pulumi.Context,
+	name string, args *PodExecArgs, opts ...pulumi.ResourceOption) (*PodExec, error) {
+	if args == nil {
+		return nil, errors.New("missing one or more required arguments")
+	}
+
+	if args.Command == nil {
+		return nil, errors.New("invalid value for required argument 'Command'")
+	}
+	if args.Pod == nil {
+		return nil, errors.New("invalid value for required argument 'Pod'")
+	}
+	var resource PodExec
+	err := ctx.RegisterResource("kubernetes:index:PodExec", name, args, &resource, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// GetPodExec gets an existing PodExec resource's state with the given name, ID, and optional
+// state properties that are used to uniquely qualify the lookup (nil if not required).
+func GetPodExec(ctx *pulumi.Context,
+	name string, id pulumi.IDInput, state *PodExecState, opts ...pulumi.ResourceOption) (*PodExec, error) {
+	var resource PodExec
+	err := ctx.ReadResource("kubernetes:index:PodExec", name, id, state, &resource, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// Input properties used for looking up and filtering PodExec resources.
+type podExecState struct {
+}
+
+type PodExecState struct {
+}
+
+func (PodExecState) ElementType() reflect.Type {
+	return reflect.TypeOf((*podExecState)(nil)).Elem()
+}
+
+type podExecArgs struct {
+	// The command to run, and its arguments. The command is not run in a shell.
+	Command []string `pulumi:"command"`
+	// The container to run the command in. Defaults to the only container of the Pod.
+	Container *string `pulumi:"container"`
+	// The namespace of the Pod. Defaults to the namespace of the provider.
+	Namespace *string `pulumi:"namespace"`
+	// The name of the Pod to run the command in.
+	Pod string `pulumi:"pod"`
+	// The input of the command.
+	Stdin *string `pulumi:"stdin"`
+	// How long to wait for the `waitFor` condition and for the command to exit, in seconds.
+	TimeoutSeconds *int `pulumi:"timeoutSeconds"`
+	// A condition that the Pod must satisfy before the command is run, in the syntax of `kubectl wait --for`, e.g., `condition=Ready`.
+	WaitFor *string `pulumi:"waitFor"`
+}
+
+// The set of arguments for constructing a PodExec resource.
+type PodExecArgs struct {
+	// The command to run, and its arguments. The command is not run in a shell.
+	Command pulumi.StringArrayInput
+	// The container to run the command in. Defaults to the only container of the Pod.
+	Container pulumi.StringPtrInput
+	// The namespace of the Pod. Defaults to the namespace of the provider.
+	Namespace pulumi.StringPtrInput
+	// The name of the Pod to run the command in.
+	Pod pulumi.StringInput
+	// The input of the command.
+	Stdin pulumi.StringPtrInput
+	// How long to wait for the `waitFor` condition and for the command to exit, in seconds.
+	TimeoutSeconds pulumi.IntPtrInput
+	// A condition that the Pod must satisfy before the command is run, in the syntax of `kubectl wait --for`, e.g., `condition=Ready`.
+	WaitFor pulumi.StringPtrInput
+}
+
+func (PodExecArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*podExecArgs)(nil)).Elem()
+}
+
+type PodExecInput interface {
+	pulumi.Input
+
+	ToPodExecOutput() PodExecOutput
+	ToPodExecOutputWithContext(ctx context.Context) PodExecOutput
+}
+
+func (*PodExec) ElementType() reflect.Type {
+	return reflect.TypeOf((*PodExec)(nil))
+}
+
+func (i *PodExec) ToPodExecOutput() PodExecOutput {
+	return i.ToPodExecOutputWithContext(context.Background())
+}
+
+func (i *PodExec) ToPodExecOutputWithContext(ctx context.Context) PodExecOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(PodExecOutput)
+}
+
+func (i *PodExec) ToPodExecPtrOutput() PodExecPtrOutput {
+	return i.ToPodExecPtrOutputWithContext(context.Background())
+}
+
+func (i *PodExec) ToPodExecPtrOutputWithContext(ctx context.Context) PodExecPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(PodExecPtrOutput)
+}
+
+type PodExecPtrInput interface {
+	pulumi.Input
+
+	ToPodExecPtrOutput() PodExecPtrOutput
+	ToPodExecPtrOutputWithContext(ctx context.Context) PodExecPtrOutput
+}
+
+type podExecPtrType PodExecArgs
+
+func (*podExecPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**PodExec)(nil))
+}
+
+func (i *podExecPtrType) ToPodExecPtrOutput() PodExecPtrOutput {
+	return i.ToPodExecPtrOutputWithContext(context.Background())
+}
+
+func (i *podExecPtrType) ToPodExecPtrOutputWithContext(ctx context.Context) PodExecPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(PodExecPtrOutput)
+}
+
+// PodExecArrayInput is an input type that accepts PodExecArray and PodExecArrayOutput values.
+// You can construct a concrete instance of `PodExecArrayInput` via:
+//
+//          PodExecArray{ PodExecArgs{...} }
+type PodExecArrayInput interface {
+	pulumi.Input
+
+	ToPodExecArrayOutput() PodExecArrayOutput
+	ToPodExecArrayOutputWithContext(context.Context) PodExecArrayOutput
+}
+
+type PodExecArray []PodExecInput
+
+func (PodExecArray) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]*PodExec)(nil)).Elem()
+}
+
+func (i PodExecArray) ToPodExecArrayOutput() PodExecArrayOutput {
+	return i.ToPodExecArrayOutputWithContext(context.Background())
+}
+
+func (i PodExecArray) ToPodExecArrayOutputWithContext(ctx context.Context) PodExecArrayOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(PodExecArrayOutput)
+}
+
+// PodExecMapInput is an input type that accepts PodExecMap and PodExecMapOutput values.
+// You can construct a concrete instance of `PodExecMapInput` via:
+//
+//          PodExecMap{ "key": PodExecArgs{...} }
+type PodExecMapInput interface {
+	pulumi.Input
+
+	ToPodExecMapOutput() PodExecMapOutput
+	ToPodExecMapOutputWithContext(context.Context) PodExecMapOutput
+}
+
+type PodExecMap map[string]PodExecInput
+
+func (PodExecMap) ElementType() reflect.Type {
+	return reflect.TypeOf((*map[string]*PodExec)(nil)).Elem()
+}
+
+func (i PodExecMap) ToPodExecMapOutput() PodExecMapOutput {
+	return i.ToPodExecMapOutputWithContext(context.Background())
+}
+
+func (i PodExecMap) ToPodExecMapOutputWithContext(ctx context.Context) PodExecMapOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(PodExecMapOutput)
+}
+
+type PodExecOutput struct {
+	*pulumi.OutputState
+}
+
+func (PodExecOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*PodExec)(nil))
+}
+
+func (o PodExecOutput) ToPodExecOutput() PodExecOutput {
+	return o
+}
+
+func (o PodExecOutput) ToPodExecOutputWithContext(ctx context.Context) PodExecOutput {
+	return o
+}
+
+func (o PodExecOutput) ToPodExecPtrOutput() PodExecPtrOutput {
+	return o.ToPodExecPtrOutputWithContext(context.Background())
+}
+
+func (o PodExecOutput) ToPodExecPtrOutputWithContext(ctx context.Context) PodExecPtrOutput {
+	return o.ApplyT(func(v PodExec) *PodExec {
+		return &v
+	}).(PodExecPtrOutput)
+}
+
+type PodExecPtrOutput struct {
+	*pulumi.OutputState
+}
+
+func (PodExecPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**PodExec)(nil))
+}
+
+func (o PodExecPtrOutput) ToPodExecPtrOutput() PodExecPtrOutput {
+	return o
+}
+
+func (o PodExecPtrOutput) ToPodExecPtrOutputWithContext(ctx context.Context) PodExecPtrOutput {
+	return o
+}
+
+type PodExecArrayOutput struct{ *pulumi.OutputState }
+
+func (PodExecArrayOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]PodExec)(nil))
+}
+
+func (o PodExecArrayOutput) ToPodExecArrayOutput() PodExecArrayOutput {
+	return o
+}
+
+func (o PodExecArrayOutput) ToPodExecArrayOutputWithContext(ctx context.Context) PodExecArrayOutput {
+	return o
+}
+
+func (o PodExecArrayOutput) Index(i pulumi.IntInput) PodExecOutput {
+	return pulumi.All(o, i).ApplyT(func(vs []interface{}) PodExec {
+		return vs[0].([]PodExec)[vs[1].(int)]
+	}).(PodExecOutput)
+}
+
+type PodExecMapOutput struct{ *pulumi.OutputState }
+
+func (PodExecMapOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*map[string]PodExec)(nil))
+}
+
+func (o PodExecMapOutput) ToPodExecMapOutput() PodExecMapOutput {
+	return o
+}
+
+func (o PodExecMapOutput) ToPodExecMapOutputWithContext(ctx context.Context) PodExecMapOutput {
+	return o
+}
+
+func (o PodExecMapOutput) MapIndex(k pulumi.StringInput) PodExecOutput {
+	return pulumi.All(o, k).ApplyT(func(vs []interface{}) PodExec {
+		return vs[0].(map[string]PodExec)[vs[1].(string)]
+	}).(PodExecOutput)
+}
+
+func init() {
+	pulumi.RegisterOutputType(PodExecOutput{})
+	pulumi.RegisterOutputType(PodExecPtrOutput{})
+	pulumi.RegisterOutputType(PodExecArrayOutput{})
+	pulumi.RegisterOutputType(PodExecMapOutput{})
+}
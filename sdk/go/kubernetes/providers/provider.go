@@ -24,6 +24,24 @@ func NewProvider(ctx *pulumi.Context,
 		args = &ProviderArgs{}
 	}
 
+	if args.ClientBurst == nil {
+		args.ClientBurst = pulumi.IntPtr(getEnvOrDefault(0, parseEnvInt, "PULUMI_K8S_CLIENT_BURST").(int))
+	}
+	if args.ClientQps == nil {
+		args.ClientQps = pulumi.Float64Ptr(getEnvOrDefault(0.0, parseEnvFloat, "PULUMI_K8S_CLIENT_QPS").(float64))
+	}
+	if args.DiscoveryCacheDir == nil {
+		args.DiscoveryCacheDir = pulumi.StringPtr(getEnvOrDefault("", nil, "PULUMI_K8S_DISCOVERY_CACHE_DIR").(string))
+	}
+	if args.DiscoveryCacheTtl == nil {
+		args.DiscoveryCacheTtl = pulumi.IntPtr(getEnvOrDefault(0, parseEnvInt, "PULUMI_K8S_DISCOVERY_CACHE_TTL").(int))
+	}
+	if args.DriftIgnoredFieldManagers == nil {
+		args.DriftIgnoredFieldManagers = pulumi.StringPtr(getEnvOrDefault("", nil, "PULUMI_K8S_DRIFT_IGNORED_FIELD_MANAGERS").(string))
+	}
+	if args.EnableDriftDetection == nil {
+		args.EnableDriftDetection = pulumi.BoolPtr(getEnvOrDefault(false, parseEnvBool, "PULUMI_K8S_ENABLE_DRIFT_DETECTION").(bool))
+	}
 	if args.EnableDryRun == nil {
 		args.EnableDryRun = pulumi.BoolPtr(getEnvOrDefault(false, parseEnvBool, "PULUMI_K8S_ENABLE_DRY_RUN").(bool))
 	}
@@ -42,9 +60,39 @@ func NewProvider(ctx *pulumi.Context,
 	if args.HelmRepositoryConfigPath == nil {
 		args.HelmRepositoryConfigPath = pulumi.StringPtr(getEnvOrDefault("", nil, "PULUMI_K8S_HELM_REPOSITORY_CONFIG_PATH").(string))
 	}
+	if args.IgnoreFields == nil {
+		args.IgnoreFields = pulumi.StringPtr(getEnvOrDefault("", nil, "PULUMI_K8S_IGNORE_FIELDS").(string))
+	}
+	if args.KubeVersion == nil {
+		args.KubeVersion = pulumi.StringPtr(getEnvOrDefault("", nil, "PULUMI_K8S_KUBE_VERSION").(string))
+	}
 	if args.Kubeconfig == nil {
 		args.Kubeconfig = pulumi.StringPtr(getEnvOrDefault("", nil, "KUBECONFIG").(string))
 	}
+	if args.MaxConcurrentWatches == nil {
+		args.MaxConcurrentWatches = pulumi.IntPtr(getEnvOrDefault(0, parseEnvInt, "PULUMI_K8S_MAX_CONCURRENT_WATCHES").(int))
+	}
+	if args.MigrateApiVersions == nil {
+		args.MigrateApiVersions = pulumi.BoolPtr(getEnvOrDefault(false, parseEnvBool, "PULUMI_K8S_MIGRATE_API_VERSIONS").(bool))
+	}
+	if args.PolicyDirectory == nil {
+		args.PolicyDirectory = pulumi.StringPtr(getEnvOrDefault("", nil, "PULUMI_K8S_POLICY_DIRECTORY").(string))
+	}
+	if args.RenderYamlClean == nil {
+		args.RenderYamlClean = pulumi.BoolPtr(getEnvOrDefault(false, parseEnvBool, "PULUMI_K8S_RENDER_YAML_CLEAN").(bool))
+	}
+	if args.RenderYamlLayout == nil {
+		args.RenderYamlLayout = pulumi.StringPtr(getEnvOrDefault("", nil, "PULUMI_K8S_RENDER_YAML_LAYOUT").(string))
+	}
+	if args.RenderYamlSecrets == nil {
+		args.RenderYamlSecrets = pulumi.StringPtr(getEnvOrDefault("", nil, "PULUMI_K8S_RENDER_YAML_SECRETS").(string))
+	}
+	if args.RenderYamlSecretsKey == nil {
+		args.RenderYamlSecretsKey = pulumi.StringPtr(getEnvOrDefault("", nil, "PULUMI_K8S_RENDER_YAML_SECRETS_KEY").(string))
+	}
+	if args.RenderYamlUnknowns == nil {
+		args.RenderYamlUnknowns = pulumi.StringPtr(getEnvOrDefault("", nil, "PULUMI_K8S_RENDER_YAML_UNKNOWNS").(string))
+	}
 	if args.SuppressDeprecationWarnings == nil {
 		args.SuppressDeprecationWarnings = pulumi.BoolPtr(getEnvOrDefault(false, parseEnvBool, "PULUMI_K8S_SUPPRESS_DEPRECATION_WARNINGS").(bool))
 	}
@@ -64,10 +112,32 @@ func NewProvider(ctx *pulumi.Context,
 
 // Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
 type providerArgs struct {
+	// PEM-encoded certificate authority certificates for the API server given by `server`, either as PEM text or base64-encoded as in a kubeconfig.
+	CertificateAuthorityData *string `pulumi:"certificateAuthorityData"`
+	// The maximum burst of requests that the provider sends to the Kubernetes API server above `clientQps`, shared by all of its clients. Defaults to 10.
+	ClientBurst *int `pulumi:"clientBurst"`
+	// A PEM-encoded client certificate to authenticate to the API server given by `server`, either as PEM text or base64-encoded as in a kubeconfig. Requires `clientKeyData`.
+	ClientCertificateData *string `pulumi:"clientCertificateData"`
+	// The PEM-encoded private key of the client certificate given by `clientCertificateData`, either as PEM text or base64-encoded as in a kubeconfig.
+	ClientKeyData *string `pulumi:"clientKeyData"`
+	// The maximum number of queries per second that the provider sends to the Kubernetes API server, shared by all of its clients. Defaults to 5.
+	ClientQps *float64 `pulumi:"clientQps"`
 	// If present, the name of the kubeconfig cluster to use.
 	Cluster *string `pulumi:"cluster"`
 	// If present, the name of the kubeconfig context to use.
 	Context *string `pulumi:"context"`
+	// The directory in which the provider caches the discovery information and OpenAPI schema of the Kubernetes API server, shared by all provider processes. If this is not set, discovery information is only cached in memory.
+	DiscoveryCacheDir *string `pulumi:"discoveryCacheDir"`
+	// The number of seconds for which the cached API groups and resources of the Kubernetes API server are used, before they are discovered again. The cached OpenAPI schema is revalidated on every use. Only used if `discoveryCacheDir` is set. Set to 0 to disable the on-disk cache. Defaults to 600.
+	DiscoveryCacheTtl *int `pulumi:"discoveryCacheTtl"`
+	// BETA FEATURE - A comma-separated list of the field managers whose changes are not reported as drift when `enableDriftDetection` is set, such as the controllers that own the fields they update. Changes by any other field manager than the provider are reported as drift. Defaults to `kube-controller-manager,kube-scheduler,kubelet`.
+	DriftIgnoredFieldManagers *string `pulumi:"driftIgnoredFieldManagers"`
+	// BETA FEATURE - If present and set to true, compare the inputs of each resource to its live state during diffs, and report fields that were changed out of band (e.g., with `kubectl edit`) as drift. Fields owned by the field managers in `driftIgnoredFieldManagers`, such as `spec.replicas` managed by a HorizontalPodAutoscaler, are not reported.
+	//
+	// This config can be specified in the following ways, using this precedence:
+	// 1. This `enableDriftDetection` parameter.
+	// 2. The `PULUMI_K8S_ENABLE_DRIFT_DETECTION` environment variable.
+	EnableDriftDetection *bool `pulumi:"enableDriftDetection"`
 	// BETA FEATURE - If present and set to true, enable server-side diff calculations.
 	// This feature is in developer preview, and is disabled by default.
 	EnableDryRun *bool `pulumi:"enableDryRun"`
@@ -81,8 +151,24 @@ type providerArgs struct {
 	HelmRepositoryCache *string `pulumi:"helmRepositoryCache"`
 	// BETA FEATURE - Used for supporting Helm Release resource (Beta). The path to the file containing repository names and URLs.
 	HelmRepositoryConfigPath *string `pulumi:"helmRepositoryConfigPath"`
+	// BETA FEATURE - A JSON object mapping resource types to lists of fields that are excluded from diffs and updates, e.g., `{"apps/v1/Deployment": ["/spec/replicas"]}`. Resource types are given as `<apiVersion>/<kind>`, and fields as JSON pointers or JSONPaths, where `*` matches every map key or list element. This is useful for fields that are set by controllers or mutating webhooks, such as injected sidecar containers or `caBundle` values. Fields can also be ignored for a single resource with the `pulumi.com/ignoreFields` annotation.
+	IgnoreFields *string `pulumi:"ignoreFields"`
+	// The user, groups and UID that the provider impersonates in every request to the Kubernetes API server, including the requests of Helm releases, e.g., to manage the resources of a tenant with the identity of its service account. If not set, the JSON encoding of the identity is read from the `PULUMI_K8S_IMPERSONATE` environment variable.
+	Impersonate *Impersonation `pulumi:"impersonate"`
+	// If present and set to true, the certificate of the API server given by `server` is not verified. This makes the connection insecure, and cannot be combined with `certificateAuthorityData`.
+	InsecureSkipTlsVerify *bool `pulumi:"insecureSkipTlsVerify"`
+	// The Kubernetes version to validate resources against when the cluster is unreachable, e.g., in render mode or in CI. When set, resources are validated and scoped using the OpenAPI schema bundled with the provider for that version.
+	//
+	// This config can be specified in the following ways, using this precedence:
+	// 1. This `kubeVersion` parameter.
+	// 2. The `PULUMI_K8S_KUBE_VERSION` environment variable.
+	KubeVersion *string `pulumi:"kubeVersion"`
 	// The contents of a kubeconfig file or the path to a kubeconfig file.
 	Kubeconfig *string `pulumi:"kubeconfig"`
+	// The maximum number of watches that the provider keeps open at the same time, e.g., while it waits for resources to become ready. A watch that would exceed the limit waits up to 10 seconds for another watch to end. If none does, the objects of the resource are listed again periodically until a watch is free, so that waiting for resources that need several watches, e.g., 4 for a Deployment, still completes. Unlimited by default.
+	MaxConcurrentWatches *int `pulumi:"maxConcurrentWatches"`
+	// If present and set to true, resources that use a deprecated or removed apiVersion are migrated to the newest apiVersion of their kind that the cluster supports, along with the changes of their fields that can be made automatically, such as the backends of an Ingress. Resources that cannot be migrated automatically are left unchanged with a warning.
+	MigrateApiVersions *bool `pulumi:"migrateApiVersions"`
 	// If present, the default namespace to use. This flag is ignored for cluster-scoped resources.
 	//
 	// A namespace can be specified in multiple places, and the precedence is as follows:
@@ -90,6 +176,26 @@ type providerArgs struct {
 	// 2. This `namespace` parameter.
 	// 3. `namespace` set for the active context in the kubeconfig.
 	Namespace *string `pulumi:"namespace"`
+	// A directory of policies that are evaluated against every resource in `Check`. Each YAML or JSON file in the directory contains one or more policies, whose validations are CEL expressions of the resource as `object` and of its previous inputs as `oldObject`. Violations of `mandatory` policies (the default) fail the check, violations of `advisory` policies are reported as warnings, and `disabled` policies are not evaluated. Validations that depend on values that are unknown in a preview are evaluated again when the resource is created or updated. Rego policies are not supported.
+	PolicyDirectory *string `pulumi:"policyDirectory"`
+	// The URL of an http, https or socks5 proxy to connect to the API server given by `server` through.
+	ProxyUrl *string `pulumi:"proxyUrl"`
+	// BETA FEATURE - If present and set to true, and `renderYamlToDirectory` is set, the rendered manifests are cleaned up
+	// so that they can be applied directly with `kubectl apply -k` or consumed by GitOps tooling. The
+	// `last-applied-configuration` and Pulumi-internal annotations are removed, the manifests are ordered so that
+	// CustomResourceDefinitions and Namespaces are applied first and webhook configurations last, and a `kustomization.yaml`
+	// listing every rendered file is written to the root of the directory.
+	RenderYamlClean *bool `pulumi:"renderYamlClean"`
+	// BETA FEATURE - If present, and `renderYamlToDirectory` is set, controls how the rendered manifests are arranged in the
+	// directory. Values are:
+	// - `directory` (default): one file per resource, in the `0-crd` and `1-manifest` subdirectories.
+	// - `component`: one file per resource, in a subdirectory per component resource (e.g., a Chart or ConfigGroup).
+	// - `single`: a single multi-document file per stack, named `<stack>.yaml`.
+	RenderYamlLayout *string `pulumi:"renderYamlLayout"`
+	// Controls how the data of Secrets is written when `renderYamlToDirectory` is set. `plaintext` (the default) writes it unencrypted. `sops` encrypts the `data` and `stringData` fields in the SOPS format. `sealedSecrets` renders each Secret as a Bitnami SealedSecret.
+	RenderYamlSecrets *string `pulumi:"renderYamlSecrets"`
+	// The key used to encrypt Secrets when `renderYamlSecrets` is set. For `sops`, a comma-separated list of age recipients, or the path to (or contents of) an ASCII-armored PGP public key. For `sealedSecrets`, the path to (or contents of) the PEM-encoded certificate of the sealed-secrets controller.
+	RenderYamlSecretsKey *string `pulumi:"renderYamlSecretsKey"`
 	// BETA FEATURE - If present, render resource manifests to this directory. In this mode, resources will not
 	// be created on a Kubernetes cluster, but the rendered manifests will be kept in sync with changes
 	// to the Pulumi program. This feature is in developer preview, and is disabled by default.
@@ -99,21 +205,49 @@ type providerArgs struct {
 	// and may result in an error if they are referenced by other resources. Also note that any secret values
 	// used in these resources will be rendered in plaintext to the resulting YAML.
 	RenderYamlToDirectory *string `pulumi:"renderYamlToDirectory"`
+	// Controls how resources with unknown values are rendered when `renderYamlToDirectory` is set. `defer` (the default) does not write a resource until all of its values are known, and lists its unknown values in `unresolved-fields.json` in the render directory. `placeholder` renders unknown values as `<unresolved>` and lists them in `unresolved-fields.json` in the render directory.
+	RenderYamlUnknowns *string `pulumi:"renderYamlUnknowns"`
+	// The address of the Kubernetes API server, e.g., `https://1.2.3.4:6443`. If this is set, the provider connects to this server with the credentials given by `certificateAuthorityData`, `token`, `clientCertificateData` and `clientKeyData`, and no kubeconfig is loaded. It cannot be combined with `kubeconfig`, `context` or `cluster`.
+	Server *string `pulumi:"server"`
 	// If present and set to true, suppress apiVersion deprecation warnings from the CLI.
 	SuppressDeprecationWarnings *bool `pulumi:"suppressDeprecationWarnings"`
 	// If present and set to true, suppress unsupported Helm hook warnings from the CLI.
 	SuppressHelmHookWarnings *bool `pulumi:"suppressHelmHookWarnings"`
 	// While Helm Release provider is in beta, by default 'pulumi up' will log a warning if the resource is used. If present and set to "true", this warning is omitted.
 	SuppressHelmReleaseBetaWarning *bool `pulumi:"suppressHelmReleaseBetaWarning"`
+	// A bearer token to authenticate to the API server given by `server`.
+	Token *string `pulumi:"token"`
 }
 
 // The set of arguments for constructing a Provider resource.
 // Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
 type ProviderArgs struct {
+	// PEM-encoded certificate authority certificates for the API server given by `server`, either as PEM text or base64-encoded as in a kubeconfig.
+	CertificateAuthorityData pulumi.StringPtrInput
+	// The maximum burst of requests that the provider sends to the Kubernetes API server above `clientQps`, shared by all of its clients. Defaults to 10.
+	ClientBurst pulumi.IntPtrInput
+	// A PEM-encoded client certificate to authenticate to the API server given by `server`, either as PEM text or base64-encoded as in a kubeconfig. Requires `clientKeyData`.
+	ClientCertificateData pulumi.StringPtrInput
+	// The PEM-encoded private key of the client certificate given by `clientCertificateData`, either as PEM text or base64-encoded as in a kubeconfig.
+	ClientKeyData pulumi.StringPtrInput
+	// The maximum number of queries per second that the provider sends to the Kubernetes API server, shared by all of its clients. Defaults to 5.
+	ClientQps pulumi.Float64PtrInput
 	// If present, the name of the kubeconfig cluster to use.
 	Cluster pulumi.StringPtrInput
 	// If present, the name of the kubeconfig context to use.
 	Context pulumi.StringPtrInput
+	// The directory in which the provider caches the discovery information and OpenAPI schema of the Kubernetes API server, shared by all provider processes. If this is not set, discovery information is only cached in memory.
+	DiscoveryCacheDir pulumi.StringPtrInput
+	// The number of seconds for which the cached API groups and resources of the Kubernetes API server are used, before they are discovered again. The cached OpenAPI schema is revalidated on every use. Only used if `discoveryCacheDir` is set. Set to 0 to disable the on-disk cache. Defaults to 600.
+	DiscoveryCacheTtl pulumi.IntPtrInput
+	// BETA FEATURE - A comma-separated list of the field managers whose changes are not reported as drift when `enableDriftDetection` is set, such as the controllers that own the fields they update. Changes by any other field manager than the provider are reported as drift. Defaults to `kube-controller-manager,kube-scheduler,kubelet`.
+	DriftIgnoredFieldManagers pulumi.StringPtrInput
+	// BETA FEATURE - If present and set to true, compare the inputs of each resource to its live state during diffs, and report fields that were changed out of band (e.g., with `kubectl edit`) as drift. Fields owned by the field managers in `driftIgnoredFieldManagers`, such as `spec.replicas` managed by a HorizontalPodAutoscaler, are not reported.
+	//
+	// This config can be specified in the following ways, using this precedence:
+	// 1. This `enableDriftDetection` parameter.
+	// 2. The `PULUMI_K8S_ENABLE_DRIFT_DETECTION` environment variable.
+	EnableDriftDetection pulumi.BoolPtrInput
 	// BETA FEATURE - If present and set to true, enable server-side diff calculations.
 	// This feature is in developer preview, and is disabled by default.
 	EnableDryRun pulumi.BoolPtrInput
@@ -127,8 +261,24 @@ type ProviderArgs struct {
 	HelmRepositoryCache pulumi.StringPtrInput
 	// BETA FEATURE - Used for supporting Helm Release resource (Beta). The path to the file containing repository names and URLs.
 	HelmRepositoryConfigPath pulumi.StringPtrInput
+	// BETA FEATURE - A JSON object mapping resource types to lists of fields that are excluded from diffs and updates, e.g., `{"apps/v1/Deployment": ["/spec/replicas"]}`. Resource types are given as `<apiVersion>/<kind>`, and fields as JSON pointers or JSONPaths, where `*` matches every map key or list element. This is useful for fields that are set by controllers or mutating webhooks, such as injected sidecar containers or `caBundle` values. Fields can also be ignored for a single resource with the `pulumi.com/ignoreFields` annotation.
+	IgnoreFields pulumi.StringPtrInput
+	// The user, groups and UID that the provider impersonates in every request to the Kubernetes API server, including the requests of Helm releases, e.g., to manage the resources of a tenant with the identity of its service account. If not set, the JSON encoding of the identity is read from the `PULUMI_K8S_IMPERSONATE` environment variable.
+	Impersonate ImpersonationPtrInput
+	// If present and set to true, the certificate of the API server given by `server` is not verified. This makes the connection insecure, and cannot be combined with `certificateAuthorityData`.
+	InsecureSkipTlsVerify pulumi.BoolPtrInput
+	// The Kubernetes version to validate resources against when the cluster is unreachable, e.g., in render mode or in CI. When set, resources are validated and scoped using the OpenAPI schema bundled with the provider for that version.
+	//
+	// This config can be specified in the following ways, using this precedence:
+	// 1. This `kubeVersion` parameter.
+	// 2. The `PULUMI_K8S_KUBE_VERSION` environment variable.
+	KubeVersion pulumi.StringPtrInput
 	// The contents of a kubeconfig file or the path to a kubeconfig file.
 	Kubeconfig pulumi.StringPtrInput
+	// The maximum number of watches that the provider keeps open at the same time, e.g., while it waits for resources to become ready. A watch that would exceed the limit waits up to 10 seconds for another watch to end. If none does, the objects of the resource are listed again periodically until a watch is free, so that waiting for resources that need several watches, e.g., 4 for a Deployment, still completes. Unlimited by default.
+	MaxConcurrentWatches pulumi.IntPtrInput
+	// If present and set to true, resources that use a deprecated or removed apiVersion are migrated to the newest apiVersion of their kind that the cluster supports, along with the changes of their fields that can be made automatically, such as the backends of an Ingress. Resources that cannot be migrated automatically are left unchanged with a warning.
+	MigrateApiVersions pulumi.BoolPtrInput
 	// If present, the default namespace to use. This flag is ignored for cluster-scoped resources.
 	//
 	// A namespace can be specified in multiple places, and the precedence is as follows:
@@ -136,6 +286,26 @@ type ProviderArgs struct {
 	// 2. This `namespace` parameter.
 	// 3. `namespace` set for the active context in the kubeconfig.
 	Namespace pulumi.StringPtrInput
+	// A directory of policies that are evaluated against every resource in `Check`. Each YAML or JSON file in the directory contains one or more policies, whose validations are CEL expressions of the resource as `object` and of its previous inputs as `oldObject`. Violations of `mandatory` policies (the default) fail the check, violations of `advisory` policies are reported as warnings, and `disabled` policies are not evaluated. Validations that depend on values that are unknown in a preview are evaluated again when the resource is created or updated. Rego policies are not supported.
+	PolicyDirectory pulumi.StringPtrInput
+	// The URL of an http, https or socks5 proxy to connect to the API server given by `server` through.
+	ProxyUrl pulumi.StringPtrInput
+	// BETA FEATURE - If present and set to true, and `renderYamlToDirectory` is set, the rendered manifests are cleaned up
+	// so that they can be applied directly with `kubectl apply -k` or consumed by GitOps tooling. The
+	// `last-applied-configuration` and Pulumi-internal annotations are removed, the manifests are ordered so that
+	// CustomResourceDefinitions and Namespaces are applied first and webhook configurations last, and a `kustomization.yaml`
+	// listing every rendered file is written to the root of the directory.
+	RenderYamlClean pulumi.BoolPtrInput
+	// BETA FEATURE - If present, and `renderYamlToDirectory` is set, controls how the rendered manifests are arranged in the
+	// directory. Values are:
+	// - `directory` (default): one file per resource, in the `0-crd` and `1-manifest` subdirectories.
+	// - `component`: one file per resource, in a subdirectory per component resource (e.g., a Chart or ConfigGroup).
+	// - `single`: a single multi-document file per stack, named `<stack>.yaml`.
+	RenderYamlLayout pulumi.StringPtrInput
+	// Controls how the data of Secrets is written when `renderYamlToDirectory` is set. `plaintext` (the default) writes it unencrypted. `sops` encrypts the `data` and `stringData` fields in the SOPS format. `sealedSecrets` renders each Secret as a Bitnami SealedSecret.
+	RenderYamlSecrets pulumi.StringPtrInput
+	// The key used to encrypt Secrets when `renderYamlSecrets` is set. For `sops`, a comma-separated list of age recipients, or the path to (or contents of) an ASCII-armored PGP public key. For `sealedSecrets`, the path to (or contents of) the PEM-encoded certificate of the sealed-secrets controller.
+	RenderYamlSecretsKey pulumi.StringPtrInput
 	// BETA FEATURE - If present, render resource manifests to this directory. In this mode, resources will not
 	// be created on a Kubernetes cluster, but the rendered manifests will be kept in sync with changes
 	// to the Pulumi program. This feature is in developer preview, and is disabled by default.
@@ -145,12 +315,18 @@ type ProviderArgs struct {
 	// and may result in an error if they are referenced by other resources. Also note that any secret values
 	// used in these resources will be rendered in plaintext to the resulting YAML.
 	RenderYamlToDirectory pulumi.StringPtrInput
+	// Controls how resources with unknown values are rendered when `renderYamlToDirectory` is set. `defer` (the default) does not write a resource until all of its values are known, and lists its unknown values in `unresolved-fields.json` in the render directory. `placeholder` renders unknown values as `<unresolved>` and lists them in `unresolved-fields.json` in the render directory.
+	RenderYamlUnknowns pulumi.StringPtrInput
+	// The address of the Kubernetes API server, e.g., `https://1.2.3.4:6443`. If this is set, the provider connects to this server with the credentials given by `certificateAuthorityData`, `token`, `clientCertificateData` and `clientKeyData`, and no kubeconfig is loaded. It cannot be combined with `kubeconfig`, `context` or `cluster`.
+	Server pulumi.StringPtrInput
 	// If present and set to true, suppress apiVersion deprecation warnings from the CLI.
 	SuppressDeprecationWarnings pulumi.BoolPtrInput
 	// If present and set to true, suppress unsupported Helm hook warnings from the CLI.
 	SuppressHelmHookWarnings pulumi.BoolPtrInput
 	// While Helm Release provider is in beta, by default 'pulumi up' will log a warning if the resource is used. If present and set to "true", this warning is omitted.
 	SuppressHelmReleaseBetaWarning pulumi.BoolPtrInput
+	// A bearer token to authenticate to the API server given by `server`.
+	Token pulumi.StringPtrInput
 }
 
 // Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
@@ -0,0 +1,220 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+package kubernetes
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// The identity that the provider impersonates in every request to the Kubernetes API server.
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+type Impersonation struct {
+	// The groups to impersonate. Requires `user` to be set.
+	Groups []string `pulumi:"groups"`
+	// The UID to impersonate. Requires `user` to be set, and Kubernetes v1.22 or later.
+	Uid *string `pulumi:"uid"`
+	// The user to impersonate, e.g., `system:serviceaccount:<namespace>:<name>`.
+	User *string `pulumi:"user"`
+}
+
+// ImpersonationInput is an input type that accepts ImpersonationArgs and ImpersonationOutput values.
+// You can construct a concrete instance of `ImpersonationInput` via:
+//
+//          ImpersonationArgs{...}
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+type ImpersonationInput interface {
+	pulumi.Input
+
+	ToImpersonationOutput() ImpersonationOutput
+	ToImpersonationOutputWithContext(context.Context) ImpersonationOutput
+}
+
+// The identity that the provider impersonates in every request to the Kubernetes API server.
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+type ImpersonationArgs struct {
+	// The groups to impersonate. Requires `user` to be set.
+	Groups pulumi.StringArrayInput `pulumi:"groups"`
+	// The UID to impersonate. Requires `user` to be set, and Kubernetes v1.22 or later.
+	Uid pulumi.StringPtrInput `pulumi:"uid"`
+	// The user to impersonate, e.g., `system:serviceaccount:<namespace>:<name>`.
+	User pulumi.StringPtrInput `pulumi:"user"`
+}
+
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func (ImpersonationArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*Impersonation)(nil)).Elem()
+}
+
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func (i ImpersonationArgs) ToImpersonationOutput() ImpersonationOutput {
+	return i.ToImpersonationOutputWithContext(context.Background())
+}
+
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func (i ImpersonationArgs) ToImpersonationOutputWithContext(ctx context.Context) ImpersonationOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ImpersonationOutput)
+}
+
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func (i ImpersonationArgs) ToImpersonationPtrOutput() ImpersonationPtrOutput {
+	return i.ToImpersonationPtrOutputWithContext(context.Background())
+}
+
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func (i ImpersonationArgs) ToImpersonationPtrOutputWithContext(ctx context.Context) ImpersonationPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ImpersonationOutput).ToImpersonationPtrOutputWithContext(ctx)
+}
+
+// ImpersonationPtrInput is an input type that accepts ImpersonationArgs, ImpersonationPtr and ImpersonationPtrOutput values.
+// You can construct a concrete instance of `ImpersonationPtrInput` via:
+//
+//                  ImpersonationArgs{...}
+//
+//          or:
+//
+//                  nil
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+type ImpersonationPtrInput interface {
+	pulumi.Input
+
+	ToImpersonationPtrOutput() ImpersonationPtrOutput
+	ToImpersonationPtrOutputWithContext(context.Context) ImpersonationPtrOutput
+}
+
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+type impersonationPtrType ImpersonationArgs
+
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func ImpersonationPtr(v *ImpersonationArgs) ImpersonationPtrInput {
+	return (*impersonationPtrType)(v)
+}
+
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func (*impersonationPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**Impersonation)(nil)).Elem()
+}
+
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func (i *impersonationPtrType) ToImpersonationPtrOutput() ImpersonationPtrOutput {
+	return i.ToImpersonationPtrOutputWithContext(context.Background())
+}
+
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func (i *impersonationPtrType) ToImpersonationPtrOutputWithContext(ctx context.Context) ImpersonationPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ImpersonationPtrOutput)
+}
+
+// The identity that the provider impersonates in every request to the Kubernetes API server.
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+type ImpersonationOutput struct{ *pulumi.OutputState }
+
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func (ImpersonationOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*Impersonation)(nil)).Elem()
+}
+
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func (o ImpersonationOutput) ToImpersonationOutput() ImpersonationOutput {
+	return o
+}
+
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func (o ImpersonationOutput) ToImpersonationOutputWithContext(ctx context.Context) ImpersonationOutput {
+	return o
+}
+
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func (o ImpersonationOutput) ToImpersonationPtrOutput() ImpersonationPtrOutput {
+	return o.ToImpersonationPtrOutputWithContext(context.Background())
+}
+
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func (o ImpersonationOutput) ToImpersonationPtrOutputWithContext(ctx context.Context) ImpersonationPtrOutput {
+	return o.ApplyT(func(v Impersonation) *Impersonation {
+		return &v
+	}).(ImpersonationPtrOutput)
+}
+
+// The groups to impersonate. Requires `user` to be set.
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func (o ImpersonationOutput) Groups() pulumi.StringArrayOutput {
+	return o.ApplyT(func(v Impersonation) []string { return v.Groups }).(pulumi.StringArrayOutput)
+}
+
+// The UID to impersonate. Requires `user` to be set, and Kubernetes v1.22 or later.
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func (o ImpersonationOutput) Uid() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Impersonation) *string { return v.Uid }).(pulumi.StringPtrOutput)
+}
+
+// The user to impersonate, e.g., `system:serviceaccount:<namespace>:<name>`.
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func (o ImpersonationOutput) User() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Impersonation) *string { return v.User }).(pulumi.StringPtrOutput)
+}
+
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+type ImpersonationPtrOutput struct{ *pulumi.OutputState }
+
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func (ImpersonationPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**Impersonation)(nil)).Elem()
+}
+
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func (o ImpersonationPtrOutput) ToImpersonationPtrOutput() ImpersonationPtrOutput {
+	return o
+}
+
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func (o ImpersonationPtrOutput) ToImpersonationPtrOutputWithContext(ctx context.Context) ImpersonationPtrOutput {
+	return o
+}
+
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func (o ImpersonationPtrOutput) Elem() ImpersonationOutput {
+	return o.ApplyT(func(v *Impersonation) Impersonation { return *v }).(ImpersonationOutput)
+}
+
+// The groups to impersonate. Requires `user` to be set.
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func (o ImpersonationPtrOutput) Groups() pulumi.StringArrayOutput {
+	return o.ApplyT(func(v *Impersonation) []string {
+		if v == nil {
+			return nil
+		}
+		return v.Groups
+	}).(pulumi.StringArrayOutput)
+}
+
+// The UID to impersonate. Requires `user` to be set, and Kubernetes v1.22 or later.
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func (o ImpersonationPtrOutput) Uid() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Impersonation) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Uid
+	}).(pulumi.StringPtrOutput)
+}
+
+// The user to impersonate, e.g., `system:serviceaccount:<namespace>:<name>`.
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func (o ImpersonationPtrOutput) User() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Impersonation) *string {
+		if v == nil {
+			return nil
+		}
+		return v.User
+	}).(pulumi.StringPtrOutput)
+}
+
+// Deprecated: Use `github.com/pulumi/pulumi-kubernetes/sdk/v3/go/kubernetes` instead
+func init() {
+	pulumi.RegisterOutputType(ImpersonationOutput{})
+	pulumi.RegisterOutputType(ImpersonationPtrOutput{})
+}
@@ -1,29 +1,188 @@
-// Copyright 2016-2020, Pulumi Corporation.
-//
-// Licensed under the Apache License, Version 2.0 (the "License");
-// you may not use this file except in compliance with the License.
-// You may obtain a copy of the License at
-//
-//     http://www.apache.org/licenses/LICENSE-2.0
-//
-// Unless required by applicable law or agreed to in writing, software
-// distributed under the License is distributed on an "AS IS" BASIS,
-// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
-// See the License for the specific language governing permissions and
-// limitations under the License.
-
 // *** WARNING: this file was generated by pulumigen. ***
 // *** Do not edit by hand unless you're certain you know what you are doing! ***
 
 package kubernetes
 
 import (
+	"context"
 	"reflect"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 )
 
-// UntypedArgs is an untyped interface that is required for YAML and CustomResource support.
-type UntypedArgs map[string]interface{}
+// The identity that the provider impersonates in every request to the Kubernetes API server.
+type Impersonation struct {
+	// The groups to impersonate. Requires `user` to be set.
+	Groups []string `pulumi:"groups"`
+	// The UID to impersonate. Requires `user` to be set, and Kubernetes v1.22 or later.
+	Uid *string `pulumi:"uid"`
+	// The user to impersonate, e.g., `system:serviceaccount:<namespace>:<name>`.
+	User *string `pulumi:"user"`
+}
+
+// ImpersonationInput is an input type that accepts ImpersonationArgs and ImpersonationOutput values.
+// You can construct a concrete instance of `ImpersonationInput` via:
+//
+//          ImpersonationArgs{...}
+type ImpersonationInput interface {
+	pulumi.Input
+
+	ToImpersonationOutput() ImpersonationOutput
+	ToImpersonationOutputWithContext(context.Context) ImpersonationOutput
+}
+
+// The identity that the provider impersonates in every request to the Kubernetes API server.
+type ImpersonationArgs struct {
+	// The groups to impersonate. Requires `user` to be set.
+	Groups pulumi.StringArrayInput `pulumi:"groups"`
+	// The UID to impersonate. Requires `user` to be set, and Kubernetes v1.22 or later.
+	Uid pulumi.StringPtrInput `pulumi:"uid"`
+	// The user to impersonate, e.g., `system:serviceaccount:<namespace>:<name>`.
+	User pulumi.StringPtrInput `pulumi:"user"`
+}
+
+func (ImpersonationArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*Impersonation)(nil)).Elem()
+}
+
+func (i ImpersonationArgs) ToImpersonationOutput() ImpersonationOutput {
+	return i.ToImpersonationOutputWithContext(context.Background())
+}
+
+func (i ImpersonationArgs) ToImpersonationOutputWithContext(ctx context.Context) ImpersonationOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ImpersonationOutput)
+}
+
+func (i ImpersonationArgs) ToImpersonationPtrOutput() ImpersonationPtrOutput {
+	return i.ToImpersonationPtrOutputWithContext(context.Background())
+}
+
+func (i ImpersonationArgs) ToImpersonationPtrOutputWithContext(ctx context.Context) ImpersonationPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ImpersonationOutput).ToImpersonationPtrOutputWithContext(ctx)
+}
+
+// ImpersonationPtrInput is an input type that accepts ImpersonationArgs, ImpersonationPtr and ImpersonationPtrOutput values.
+// You can construct a concrete instance of `ImpersonationPtrInput` via:
+//
+//                  ImpersonationArgs{...}
+//
+//          or:
+//
+//                  nil
+type ImpersonationPtrInput interface {
+	pulumi.Input
+
+	ToImpersonationPtrOutput() ImpersonationPtrOutput
+	ToImpersonationPtrOutputWithContext(context.Context) ImpersonationPtrOutput
+}
+
+type impersonationPtrType ImpersonationArgs
+
+func ImpersonationPtr(v *ImpersonationArgs) ImpersonationPtrInput {
+	return (*impersonationPtrType)(v)
+}
+
+func (*impersonationPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**Impersonation)(nil)).Elem()
+}
+
+func (i *impersonationPtrType) ToImpersonationPtrOutput() ImpersonationPtrOutput {
+	return i.ToImpersonationPtrOutputWithContext(context.Background())
+}
+
+func (i *impersonationPtrType) ToImpersonationPtrOutputWithContext(ctx context.Context) ImpersonationPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ImpersonationPtrOutput)
+}
+
+// The identity that the provider impersonates in every request to the Kubernetes API server.
+type ImpersonationOutput struct{ *pulumi.OutputState }
+
+func (ImpersonationOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*Impersonation)(nil)).Elem()
+}
+
+func (o ImpersonationOutput) ToImpersonationOutput() ImpersonationOutput {
+	return o
+}
+
+func (o ImpersonationOutput) ToImpersonationOutputWithContext(ctx context.Context) ImpersonationOutput {
+	return o
+}
+
+func (o ImpersonationOutput) ToImpersonationPtrOutput() ImpersonationPtrOutput {
+	return o.ToImpersonationPtrOutputWithContext(context.Background())
+}
+
+func (o ImpersonationOutput) ToImpersonationPtrOutputWithContext(ctx context.Context) ImpersonationPtrOutput {
+	return o.ApplyT(func(v Impersonation) *Impersonation {
+		return &v
+	}).(ImpersonationPtrOutput)
+}
+
+// The groups to impersonate. Requires `user` to be set.
+func (o ImpersonationOutput) Groups() pulumi.StringArrayOutput {
+	return o.ApplyT(func(v Impersonation) []string { return v.Groups }).(pulumi.StringArrayOutput)
+}
+
+// The UID to impersonate. Requires `user` to be set, and Kubernetes v1.22 or later.
+func (o ImpersonationOutput) Uid() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Impersonation) *string { return v.Uid }).(pulumi.StringPtrOutput)
+}
+
+// The user to impersonate, e.g., `system:serviceaccount:<namespace>:<name>`.
+func (o ImpersonationOutput) User() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Impersonation) *string { return v.User }).(pulumi.StringPtrOutput)
+}
+
+type ImpersonationPtrOutput struct{ *pulumi.OutputState }
+
+func (ImpersonationPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**Impersonation)(nil)).Elem()
+}
+
+func (o ImpersonationPtrOutput) ToImpersonationPtrOutput() ImpersonationPtrOutput {
+	return o
+}
+
+func (o ImpersonationPtrOutput) ToImpersonationPtrOutputWithContext(ctx context.Context) ImpersonationPtrOutput {
+	return o
+}
+
+func (o ImpersonationPtrOutput) Elem() ImpersonationOutput {
+	return o.ApplyT(func(v *Impersonation) Impersonation { return *v }).(ImpersonationOutput)
+}
+
+// The groups to impersonate. Requires `user` to be set.
+func (o ImpersonationPtrOutput) Groups() pulumi.StringArrayOutput {
+	return o.ApplyT(func(v *Impersonation) []string {
+		if v == nil {
+			return nil
+		}
+		return v.Groups
+	}).(pulumi.StringArrayOutput)
+}
+
+// The UID to impersonate. Requires `user` to be set, and Kubernetes v1.22 or later.
+func (o ImpersonationPtrOutput) Uid() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Impersonation) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Uid
+	}).(pulumi.StringPtrOutput)
+}
+
+// The user to impersonate, e.g., `system:serviceaccount:<namespace>:<name>`.
+func (o ImpersonationPtrOutput) User() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Impersonation) *string {
+		if v == nil {
+			return nil
+		}
+		return v.User
+	}).(pulumi.StringPtrOutput)
+}
 
-func (UntypedArgs) ElementType() reflect.Type {
-	return reflect.TypeOf((*map[string]interface{})(nil)).Elem()
+func init() {
+	pulumi.RegisterOutputType(ImpersonationOutput{})
+	pulumi.RegisterOutputType(ImpersonationPtrOutput{})
 }
@@ -0,0 +1,29 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+package kubernetes
+
+import (
+	"reflect"
+)
+
+// UntypedArgs is an untyped interface that is required for YAML and CustomResource support.
+type UntypedArgs map[string]interface{}
+
+func (UntypedArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*map[string]interface{})(nil)).Elem()
+}
@@ -0,0 +1,61 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+import * as pulumi from "@pulumi/pulumi";
+import { input as inputs, output as outputs, enums } from "./types";
+import * as utilities from "./utilities";
+
+/**
+ * Reads a live object from the cluster, optionally waiting until it satisfies a condition, e.g., to use a value that a controller sets on a resource. The data of Secrets, and their last-applied-configuration annotation, are returned as secrets. Managed fields are omitted.
+ */
+export function getObject(args: GetObjectArgs, opts?: pulumi.InvokeOptions): Promise<GetObjectResult> {
+    if (!opts) {
+        opts = {}
+    }
+
+    if (!opts.version) {
+        opts.version = utilities.getVersion();
+    }
+    return pulumi.runtime.invoke("kubernetes:index:getObject", {
+        "apiVersion": args.apiVersion,
+        "kind": args.kind,
+        "name": args.name,
+        "namespace": args.namespace,
+        "timeoutSeconds": args.timeoutSeconds,
+        "waitFor": args.waitFor,
+    }, opts);
+}
+
+export interface GetObjectArgs {
+    /**
+     * The apiVersion of the object, e.g., `apps/v1`.
+     */
+    apiVersion: string;
+    /**
+     * The kind of the object, e.g., `Deployment`.
+     */
+    kind: string;
+    /**
+     * The name of the object.
+     */
+    name: string;
+    /**
+     * The namespace of the object. Defaults to the namespace of the provider for namespaced kinds.
+     */
+    namespace?: string;
+    /**
+     * How long to wait for the `waitFor` condition, in seconds. Defaults to 300.
+     */
+    timeoutSeconds?: number;
+    /**
+     * A condition that the object must satisfy before it is returned, in the syntax of `kubectl wait --for`: `exists`, `condition=<type>[=<status>]` or `jsonpath={<path>}[=<value>]`. If not set, the object is read once.
+     */
+    waitFor?: string;
+}
+
+export interface GetObjectResult {
+    /**
+     * The live object.
+     */
+    readonly result: {[key: string]: any};
+}
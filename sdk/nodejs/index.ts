@@ -5,6 +5,8 @@ import * as pulumi from "@pulumi/pulumi";
 import * as utilities from "./utilities";
 
 // Export members:
+export * from "./getObject";
+export * from "./podExec";
 export * from "./provider";
 
 // Export sub-modules:
@@ -68,6 +70,22 @@ export {
     yaml,
 };
 
+// Import resources to register:
+import { PodExec } from "./podExec";
+
+const _module = {
+    version: utilities.getVersion(),
+    construct: (name: string, type: string, urn: string): pulumi.Resource => {
+        switch (type) {
+            case "kubernetes:index:PodExec":
+                return new PodExec(name, <any>undefined, { urn })
+            default:
+                throw new Error(`unknown resource type ${type}`);
+        }
+    },
+};
+pulumi.runtime.registerResourceModule("kubernetes", "index", _module)
+
 import { Provider } from "./provider";
 
 pulumi.runtime.registerResourcePackage("kubernetes", {
@@ -0,0 +1,156 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+import * as pulumi from "@pulumi/pulumi";
+import * as utilities from "./utilities";
+
+/**
+ * PodExec runs a command in a container of a Pod when it is created, and again whenever its inputs change, e.g., to run database migrations or smoke tests as part of a stack. The command is not run during previews, where its result is unknown. Deleting a PodExec does not undo the effects of its command.
+ */
+export class PodExec extends pulumi.CustomResource {
+    /**
+     * Get an existing PodExec resource's state with the given name, ID, and optional extra
+     * properties used to qualify the lookup.
+     *
+     * @param name The _unique_ name of the resulting resource.
+     * @param id The _unique_ provider ID of the resource to lookup.
+     * @param opts Optional settings to control the behavior of the CustomResource.
+     */
+    public static get(name: string, id: pulumi.Input<pulumi.ID>, opts?: pulumi.CustomResourceOptions): PodExec {
+        return new PodExec(name, undefined as any, { ...opts, id: id });
+    }
+
+    /** @internal */
+    public static readonly __pulumiType = 'kubernetes:index:PodExec';
+
+    /**
+     * Returns true if the given object is an instance of PodExec.  This is designed to work even
+     * when multiple copies of the Pulumi SDK have been loaded into the same process.
+     */
+    public static isInstance(obj: any): obj is PodExec {
+        if (obj === undefined || obj === null) {
+            return false;
+        }
+        return obj['__pulumiType'] === PodExec.__pulumiType;
+    }
+
+    /**
+     * The command to run, and its arguments. The command is not run in a shell.
+     */
+    public readonly command!: pulumi.Output<string[]>;
+    /**
+     * The container to run the command in. Defaults to the only container of the Pod.
+     */
+    public readonly container!: pulumi.Output<string>;
+    /**
+     * The exit code of the command.
+     */
+    public /*out*/ readonly exitCode!: pulumi.Output<number>;
+    /**
+     * The namespace of the Pod. Defaults to the namespace of the provider.
+     */
+    public readonly namespace!: pulumi.Output<string>;
+    /**
+     * The name of the Pod to run the command in.
+     */
+    public readonly pod!: pulumi.Output<string>;
+    /**
+     * The standard error of the command.
+     */
+    public /*out*/ readonly stderr!: pulumi.Output<string>;
+    /**
+     * The input of the command.
+     */
+    public readonly stdin!: pulumi.Output<string>;
+    /**
+     * The standard output of the command.
+     */
+    public /*out*/ readonly stdout!: pulumi.Output<string>;
+    /**
+     * How long to wait for the `waitFor` condition and for the command to exit, in seconds.
+     */
+    public readonly timeoutSeconds!: pulumi.Output<number>;
+    /**
+     * A condition that the Pod must satisfy before the command is run, in the syntax of `kubectl wait --for`, e.g., `condition=Ready`.
+     */
+    public readonly waitFor!: pulumi.Output<string>;
+
+    /**
+     * Create a PodExec resource with the given unique name, arguments, and options.
+     *
+     * @param name The _unique_ name of the resource.
+     * @param args The arguments to use to populate this resource's properties.
+     * @param opts A bag of options that control this resource's behavior.
+     */
+    constructor(name: string, args?: PodExecArgs, opts?: pulumi.CustomResourceOptions) {
+        let inputs: pulumi.Inputs = {};
+        opts = opts || {};
+        if (!opts.id) {
+            if ((!args || args.command === undefined) && !opts.urn) {
+                throw new Error("Missing required property 'command'");
+            }
+            if ((!args || args.pod === undefined) && !opts.urn) {
+                throw new Error("Missing required property 'pod'");
+            }
+            inputs["command"] = args ? args.command : undefined;
+            inputs["container"] = args ? args.container : undefined;
+            inputs["namespace"] = args ? args.namespace : undefined;
+            inputs["pod"] = args ? args.pod : undefined;
+            inputs["stdin"] = args ? args.stdin : undefined;
+            inputs["timeoutSeconds"] = args ? args.timeoutSeconds : undefined;
+            inputs["waitFor"] = args ? args.waitFor : undefined;
+            inputs["exitCode"] = undefined /*out*/;
+            inputs["stderr"] = undefined /*out*/;
+            inputs["stdout"] = undefined /*out*/;
+        } else {
+            inputs["command"] = undefined /*out*/;
+            inputs["container"] = undefined /*out*/;
+            inputs["exitCode"] = undefined /*out*/;
+            inputs["namespace"] = undefined /*out*/;
+            inputs["pod"] = undefined /*out*/;
+            inputs["stderr"] = undefined /*out*/;
+            inputs["stdin"] = undefined /*out*/;
+            inputs["stdout"] = undefined /*out*/;
+            inputs["timeoutSeconds"] = undefined /*out*/;
+            inputs["waitFor"] = undefined /*out*/;
+        }
+        if (!opts.version) {
+            opts = pulumi.mergeOptions(opts, { version: utilities.getVersion()});
+        }
+        super(PodExec.__pulumiType, name, inputs, opts);
+    }
+}
+
+/**
+ * The set of arguments for constructing a PodExec resource.
+ */
+export interface PodExecArgs {
+    /**
+     * The command to run, and its arguments. The command is not run in a shell.
+     */
+    command: pulumi.Input<pulumi.Input<string>[]>;
+    /**
+     * The container to run the command in. Defaults to the only container of the Pod.
+     */
+    container?: pulumi.Input<string>;
+    /**
+     * The namespace of the Pod. Defaults to the namespace of the provider.
+     */
+    namespace?: pulumi.Input<string>;
+    /**
+     * The name of the Pod to run the command in.
+     */
+    pod: pulumi.Input<string>;
+    /**
+     * The input of the command.
+     */
+    stdin?: pulumi.Input<string>;
+    /**
+     * How long to wait for the `waitFor` condition and for the command to exit, in seconds.
+     */
+    timeoutSeconds?: pulumi.Input<number>;
+    /**
+     * A condition that the Pod must satisfy before the command is run, in the syntax of `kubectl wait --for`, e.g., `condition=Ready`.
+     */
+    waitFor?: pulumi.Input<string>;
+}
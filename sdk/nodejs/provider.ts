@@ -2,6 +2,7 @@
 // *** Do not edit by hand unless you're certain you know what you are doing! ***
 
 import * as pulumi from "@pulumi/pulumi";
+import { input as inputs, output as outputs, enums } from "./types";
 import * as utilities from "./utilities";
 
 /**
@@ -34,20 +35,44 @@ export class Provider extends pulumi.ProviderResource {
         let inputs: pulumi.Inputs = {};
         opts = opts || {};
         {
+            inputs["certificateAuthorityData"] = args ? args.certificateAuthorityData : undefined;
+            inputs["clientBurst"] = pulumi.output((args ? args.clientBurst : undefined) ?? <any>utilities.getEnvNumber("PULUMI_K8S_CLIENT_BURST")).apply(JSON.stringify);
+            inputs["clientCertificateData"] = args ? args.clientCertificateData : undefined;
+            inputs["clientKeyData"] = args?.clientKeyData ? pulumi.secret(args.clientKeyData) : undefined;
+            inputs["clientQps"] = pulumi.output((args ? args.clientQps : undefined) ?? <any>utilities.getEnvNumber("PULUMI_K8S_CLIENT_QPS")).apply(JSON.stringify);
             inputs["cluster"] = args ? args.cluster : undefined;
             inputs["context"] = args ? args.context : undefined;
+            inputs["discoveryCacheDir"] = (args ? args.discoveryCacheDir : undefined) ?? utilities.getEnv("PULUMI_K8S_DISCOVERY_CACHE_DIR");
+            inputs["discoveryCacheTtl"] = pulumi.output((args ? args.discoveryCacheTtl : undefined) ?? <any>utilities.getEnvNumber("PULUMI_K8S_DISCOVERY_CACHE_TTL")).apply(JSON.stringify);
+            inputs["driftIgnoredFieldManagers"] = (args ? args.driftIgnoredFieldManagers : undefined) ?? utilities.getEnv("PULUMI_K8S_DRIFT_IGNORED_FIELD_MANAGERS");
+            inputs["enableDriftDetection"] = pulumi.output((args ? args.enableDriftDetection : undefined) ?? <any>utilities.getEnvBoolean("PULUMI_K8S_ENABLE_DRIFT_DETECTION")).apply(JSON.stringify);
             inputs["enableDryRun"] = pulumi.output((args ? args.enableDryRun : undefined) ?? <any>utilities.getEnvBoolean("PULUMI_K8S_ENABLE_DRY_RUN")).apply(JSON.stringify);
             inputs["helmDriver"] = (args ? args.helmDriver : undefined) ?? utilities.getEnv("PULUMI_K8S_HELM_DRIVER");
             inputs["helmPluginsPath"] = (args ? args.helmPluginsPath : undefined) ?? utilities.getEnv("PULUMI_K8S_HELM_PLUGINS_PATH");
             inputs["helmRegistryConfigPath"] = (args ? args.helmRegistryConfigPath : undefined) ?? utilities.getEnv("PULUMI_K8S_HELM_REGISTRY_CONFIG_PATH");
             inputs["helmRepositoryCache"] = (args ? args.helmRepositoryCache : undefined) ?? utilities.getEnv("PULUMI_K8s_HELM_REPOSITORY_CACHE");
             inputs["helmRepositoryConfigPath"] = (args ? args.helmRepositoryConfigPath : undefined) ?? utilities.getEnv("PULUMI_K8S_HELM_REPOSITORY_CONFIG_PATH");
+            inputs["ignoreFields"] = (args ? args.ignoreFields : undefined) ?? utilities.getEnv("PULUMI_K8S_IGNORE_FIELDS");
+            inputs["impersonate"] = pulumi.output(args ? args.impersonate : undefined).apply(JSON.stringify);
+            inputs["insecureSkipTlsVerify"] = pulumi.output(args ? args.insecureSkipTlsVerify : undefined).apply(JSON.stringify);
+            inputs["kubeVersion"] = (args ? args.kubeVersion : undefined) ?? utilities.getEnv("PULUMI_K8S_KUBE_VERSION");
             inputs["kubeconfig"] = (args ? args.kubeconfig : undefined) ?? utilities.getEnv("KUBECONFIG");
+            inputs["maxConcurrentWatches"] = pulumi.output((args ? args.maxConcurrentWatches : undefined) ?? <any>utilities.getEnvNumber("PULUMI_K8S_MAX_CONCURRENT_WATCHES")).apply(JSON.stringify);
+            inputs["migrateApiVersions"] = pulumi.output((args ? args.migrateApiVersions : undefined) ?? <any>utilities.getEnvBoolean("PULUMI_K8S_MIGRATE_API_VERSIONS")).apply(JSON.stringify);
             inputs["namespace"] = args ? args.namespace : undefined;
+            inputs["policyDirectory"] = (args ? args.policyDirectory : undefined) ?? utilities.getEnv("PULUMI_K8S_POLICY_DIRECTORY");
+            inputs["proxyUrl"] = args ? args.proxyUrl : undefined;
+            inputs["renderYamlClean"] = pulumi.output((args ? args.renderYamlClean : undefined) ?? <any>utilities.getEnvBoolean("PULUMI_K8S_RENDER_YAML_CLEAN")).apply(JSON.stringify);
+            inputs["renderYamlLayout"] = (args ? args.renderYamlLayout : undefined) ?? utilities.getEnv("PULUMI_K8S_RENDER_YAML_LAYOUT");
+            inputs["renderYamlSecrets"] = (args ? args.renderYamlSecrets : undefined) ?? utilities.getEnv("PULUMI_K8S_RENDER_YAML_SECRETS");
+            inputs["renderYamlSecretsKey"] = (args ? args.renderYamlSecretsKey : undefined) ?? utilities.getEnv("PULUMI_K8S_RENDER_YAML_SECRETS_KEY");
             inputs["renderYamlToDirectory"] = args ? args.renderYamlToDirectory : undefined;
+            inputs["renderYamlUnknowns"] = (args ? args.renderYamlUnknowns : undefined) ?? utilities.getEnv("PULUMI_K8S_RENDER_YAML_UNKNOWNS");
+            inputs["server"] = args ? args.server : undefined;
             inputs["suppressDeprecationWarnings"] = pulumi.output((args ? args.suppressDeprecationWarnings : undefined) ?? <any>utilities.getEnvBoolean("PULUMI_K8S_SUPPRESS_DEPRECATION_WARNINGS")).apply(JSON.stringify);
             inputs["suppressHelmHookWarnings"] = pulumi.output((args ? args.suppressHelmHookWarnings : undefined) ?? <any>utilities.getEnvBoolean("PULUMI_K8S_SUPPRESS_HELM_HOOK_WARNINGS")).apply(JSON.stringify);
             inputs["suppressHelmReleaseBetaWarning"] = pulumi.output((args ? args.suppressHelmReleaseBetaWarning : undefined) ?? <any>utilities.getEnvBoolean("PULUMI_K8S_SUPPRESS_HELM_RELEASE_BETA_WARNING")).apply(JSON.stringify);
+            inputs["token"] = args?.token ? pulumi.secret(args.token) : undefined;
         }
         if (!opts.version) {
             opts = pulumi.mergeOptions(opts, { version: utilities.getVersion()});
@@ -60,6 +85,26 @@ export class Provider extends pulumi.ProviderResource {
  * The set of arguments for constructing a Provider resource.
  */
 export interface ProviderArgs {
+    /**
+     * PEM-encoded certificate authority certificates for the API server given by `server`, either as PEM text or base64-encoded as in a kubeconfig.
+     */
+    certificateAuthorityData?: pulumi.Input<string>;
+    /**
+     * The maximum burst of requests that the provider sends to the Kubernetes API server above `clientQps`, shared by all of its clients. Defaults to 10.
+     */
+    clientBurst?: pulumi.Input<number>;
+    /**
+     * A PEM-encoded client certificate to authenticate to the API server given by `server`, either as PEM text or base64-encoded as in a kubeconfig. Requires `clientKeyData`.
+     */
+    clientCertificateData?: pulumi.Input<string>;
+    /**
+     * The PEM-encoded private key of the client certificate given by `clientCertificateData`, either as PEM text or base64-encoded as in a kubeconfig.
+     */
+    clientKeyData?: pulumi.Input<string>;
+    /**
+     * The maximum number of queries per second that the provider sends to the Kubernetes API server, shared by all of its clients. Defaults to 5.
+     */
+    clientQps?: pulumi.Input<number>;
     /**
      * If present, the name of the kubeconfig cluster to use.
      */
@@ -68,6 +113,26 @@ export interface ProviderArgs {
      * If present, the name of the kubeconfig context to use.
      */
     context?: pulumi.Input<string>;
+    /**
+     * The directory in which the provider caches the discovery information and OpenAPI schema of the Kubernetes API server, shared by all provider processes. If this is not set, discovery information is only cached in memory.
+     */
+    discoveryCacheDir?: pulumi.Input<string>;
+    /**
+     * The number of seconds for which the cached API groups and resources of the Kubernetes API server are used, before they are discovered again. The cached OpenAPI schema is revalidated on every use. Only used if `discoveryCacheDir` is set. Set to 0 to disable the on-disk cache. Defaults to 600.
+     */
+    discoveryCacheTtl?: pulumi.Input<number>;
+    /**
+     * BETA FEATURE - A comma-separated list of the field managers whose changes are not reported as drift when `enableDriftDetection` is set, such as the controllers that own the fields they update. Changes by any other field manager than the provider are reported as drift. Defaults to `kube-controller-manager,kube-scheduler,kubelet`.
+     */
+    driftIgnoredFieldManagers?: pulumi.Input<string>;
+    /**
+     * BETA FEATURE - If present and set to true, compare the inputs of each resource to its live state during diffs, and report fields that were changed out of band (e.g., with `kubectl edit`) as drift. Fields owned by the field managers in `driftIgnoredFieldManagers`, such as `spec.replicas` managed by a HorizontalPodAutoscaler, are not reported.
+     *
+     * This config can be specified in the following ways, using this precedence:
+     * 1. This `enableDriftDetection` parameter.
+     * 2. The `PULUMI_K8S_ENABLE_DRIFT_DETECTION` environment variable.
+     */
+    enableDriftDetection?: pulumi.Input<boolean>;
     /**
      * BETA FEATURE - If present and set to true, enable server-side diff calculations.
      * This feature is in developer preview, and is disabled by default.
@@ -93,10 +158,38 @@ export interface ProviderArgs {
      * BETA FEATURE - Used for supporting Helm Release resource (Beta). The path to the file containing repository names and URLs.
      */
     helmRepositoryConfigPath?: pulumi.Input<string>;
+    /**
+     * BETA FEATURE - A JSON object mapping resource types to lists of fields that are excluded from diffs and updates, e.g., `{"apps/v1/Deployment": ["/spec/replicas"]}`. Resource types are given as `<apiVersion>/<kind>`, and fields as JSON pointers or JSONPaths, where `*` matches every map key or list element. This is useful for fields that are set by controllers or mutating webhooks, such as injected sidecar containers or `caBundle` values. Fields can also be ignored for a single resource with the `pulumi.com/ignoreFields` annotation.
+     */
+    ignoreFields?: pulumi.Input<string>;
+    /**
+     * The user, groups and UID that the provider impersonates in every request to the Kubernetes API server, including the requests of Helm releases, e.g., to manage the resources of a tenant with the identity of its service account. If not set, the JSON encoding of the identity is read from the `PULUMI_K8S_IMPERSONATE` environment variable.
+     */
+    impersonate?: pulumi.Input<inputs.Impersonation>;
+    /**
+     * If present and set to true, the certificate of the API server given by `server` is not verified. This makes the connection insecure, and cannot be combined with `certificateAuthorityData`.
+     */
+    insecureSkipTlsVerify?: pulumi.Input<boolean>;
+    /**
+     * The Kubernetes version to validate resources against when the cluster is unreachable, e.g., in render mode or in CI. When set, resources are validated and scoped using the OpenAPI schema bundled with the provider for that version.
+     *
+     * This config can be specified in the following ways, using this precedence:
+     * 1. This `kubeVersion` parameter.
+     * 2. The `PULUMI_K8S_KUBE_VERSION` environment variable.
+     */
+    kubeVersion?: pulumi.Input<string>;
     /**
      * The contents of a kubeconfig file or the path to a kubeconfig file.
      */
     kubeconfig?: pulumi.Input<string>;
+    /**
+     * The maximum number of watches that the provider keeps open at the same time, e.g., while it waits for resources to become ready. A watch that would exceed the limit waits up to 10 seconds for another watch to end. If none does, the objects of the resource are listed again periodically until a watch is free, so that waiting for resources that need several watches, e.g., 4 for a Deployment, still completes. Unlimited by default.
+     */
+    maxConcurrentWatches?: pulumi.Input<number>;
+    /**
+     * If present and set to true, resources that use a deprecated or removed apiVersion are migrated to the newest apiVersion of their kind that the cluster supports, along with the changes of their fields that can be made automatically, such as the backends of an Ingress. Resources that cannot be migrated automatically are left unchanged with a warning.
+     */
+    migrateApiVersions?: pulumi.Input<boolean>;
     /**
      * If present, the default namespace to use. This flag is ignored for cluster-scoped resources.
      *
@@ -106,6 +199,38 @@ export interface ProviderArgs {
      * 3. `namespace` set for the active context in the kubeconfig.
      */
     namespace?: pulumi.Input<string>;
+    /**
+     * A directory of policies that are evaluated against every resource in `Check`. Each YAML or JSON file in the directory contains one or more policies, whose validations are CEL expressions of the resource as `object` and of its previous inputs as `oldObject`. Violations of `mandatory` policies (the default) fail the check, violations of `advisory` policies are reported as warnings, and `disabled` policies are not evaluated. Validations that depend on values that are unknown in a preview are evaluated again when the resource is created or updated. Rego policies are not supported.
+     */
+    policyDirectory?: pulumi.Input<string>;
+    /**
+     * The URL of an http, https or socks5 proxy to connect to the API server given by `server` through.
+     */
+    proxyUrl?: pulumi.Input<string>;
+    /**
+     * BETA FEATURE - If present and set to true, and `renderYamlToDirectory` is set, the rendered manifests are cleaned up
+     * so that they can be applied directly with `kubectl apply -k` or consumed by GitOps tooling. The
+     * `last-applied-configuration` and Pulumi-internal annotations are removed, the manifests are ordered so that
+     * CustomResourceDefinitions and Namespaces are applied first and webhook configurations last, and a `kustomization.yaml`
+     * listing every rendered file is written to the root of the directory.
+     */
+    renderYamlClean?: pulumi.Input<boolean>;
+    /**
+     * BETA FEATURE - If present, and `renderYamlToDirectory` is set, controls how the rendered manifests are arranged in the
+     * directory. Values are:
+     * - `directory` (default): one file per resource, in the `0-crd` and `1-manifest` subdirectories.
+     * - `component`: one file per resource, in a subdirectory per component resource (e.g., a Chart or ConfigGroup).
+     * - `single`: a single multi-document file per stack, named `<stack>.yaml`.
+     */
+    renderYamlLayout?: pulumi.Input<string>;
+    /**
+     * Controls how the data of Secrets is written when `renderYamlToDirectory` is set. `plaintext` (the default) writes it unencrypted. `sops` encrypts the `data` and `stringData` fields in the SOPS format. `sealedSecrets` renders each Secret as a Bitnami SealedSecret.
+     */
+    renderYamlSecrets?: pulumi.Input<string>;
+    /**
+     * The key used to encrypt Secrets when `renderYamlSecrets` is set. For `sops`, a comma-separated list of age recipients, or the path to (or contents of) an ASCII-armored PGP public key. For `sealedSecrets`, the path to (or contents of) the PEM-encoded certificate of the sealed-secrets controller.
+     */
+    renderYamlSecretsKey?: pulumi.Input<string>;
     /**
      * BETA FEATURE - If present, render resource manifests to this directory. In this mode, resources will not
      * be created on a Kubernetes cluster, but the rendered manifests will be kept in sync with changes
@@ -117,6 +242,14 @@ export interface ProviderArgs {
      * used in these resources will be rendered in plaintext to the resulting YAML.
      */
     renderYamlToDirectory?: pulumi.Input<string>;
+    /**
+     * Controls how resources with unknown values are rendered when `renderYamlToDirectory` is set. `defer` (the default) does not write a resource until all of its values are known, and lists its unknown values in `unresolved-fields.json` in the render directory. `placeholder` renders unknown values as `<unresolved>` and lists them in `unresolved-fields.json` in the render directory.
+     */
+    renderYamlUnknowns?: pulumi.Input<string>;
+    /**
+     * The address of the Kubernetes API server, e.g., `https://1.2.3.4:6443`. If this is set, the provider connects to this server with the credentials given by `certificateAuthorityData`, `token`, `clientCertificateData` and `clientKeyData`, and no kubeconfig is loaded. It cannot be combined with `kubeconfig`, `context` or `cluster`.
+     */
+    server?: pulumi.Input<string>;
     /**
      * If present and set to true, suppress apiVersion deprecation warnings from the CLI.
      */
@@ -129,4 +262,8 @@ export interface ProviderArgs {
      * While Helm Release provider is in beta, by default 'pulumi up' will log a warning if the resource is used. If present and set to "true", this warning is omitted.
      */
     suppressHelmReleaseBetaWarning?: pulumi.Input<boolean>;
+    /**
+     * A bearer token to authenticate to the API server given by `server`.
+     */
+    token?: pulumi.Input<string>;
 }
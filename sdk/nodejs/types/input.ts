@@ -4,6 +4,23 @@
 import * as pulumi from "@pulumi/pulumi";
 import { input as inputs, output as outputs, enums } from "../types";
 
+/**
+ * The identity that the provider impersonates in every request to the Kubernetes API server.
+ */
+export interface Impersonation {
+    /**
+     * The groups to impersonate. Requires `user` to be set.
+     */
+    groups?: pulumi.Input<pulumi.Input<string>[]>;
+    /**
+     * The UID to impersonate. Requires `user` to be set, and Kubernetes v1.22 or later.
+     */
+    uid?: pulumi.Input<string>;
+    /**
+     * The user to impersonate, e.g., `system:serviceaccount:<namespace>:<name>`.
+     */
+    user?: pulumi.Input<string>;
+}
 export namespace admissionregistration {
     export namespace v1 {
         /**
@@ -0,0 +1,327 @@
+# coding=utf-8
+# *** WARNING: this file was generated by pulumigen. ***
+# *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+import warnings
+import pulumi
+import pulumi.runtime
+from typing import Any, Mapping, Optional, Sequence, Union, overload
+from . import _utilities
+
+__all__ = ['PodExecArgs', 'PodExec']
+
+@pulumi.input_type
+class PodExecArgs:
+    def __init__(__self__, *,
+                 command: pulumi.Input[Sequence[pulumi.Input[str]]],
+                 pod: pulumi.Input[str],
+                 container: Optional[pulumi.Input[str]] = None,
+                 namespace: Optional[pulumi.Input[str]] = None,
+                 stdin: Optional[pulumi.Input[str]] = None,
+                 timeout_seconds: Optional[pulumi.Input[int]] = None,
+                 wait_for: Optional[pulumi.Input[str]] = None):
+        """
+        The set of arguments for constructing a PodExec resource.
+        :param pulumi.Input[Sequence[pulumi.Input[str]]] command: The command to run, and its arguments. The command is not run in a shell.
+        :param pulumi.Input[str] pod: The name of the Pod to run the command in.
+        :param pulumi.Input[str] container: The container to run the command in. Defaults to the only container of the Pod.
+        :param pulumi.Input[str] namespace: The namespace of the Pod. Defaults to the namespace of the provider.
+        :param pulumi.Input[str] stdin: The input of the command.
+        :param pulumi.Input[int] timeout_seconds: How long to wait for the `waitFor` condition and for the command to exit, in seconds.
+        :param pulumi.Input[str] wait_for: A condition that the Pod must satisfy before the command is run, in the syntax of `kubectl wait --for`, e.g., `condition=Ready`.
+        """
+        pulumi.set(__self__, "command", command)
+        pulumi.set(__self__, "pod", pod)
+        if container is not None:
+            pulumi.set(__self__, "container", container)
+        if namespace is not None:
+            pulumi.set(__self__, "namespace", namespace)
+        if stdin is not None:
+            pulumi.set(__self__, "stdin", stdin)
+        if timeout_seconds is not None:
+            pulumi.set(__self__, "timeout_seconds", timeout_seconds)
+        if wait_for is not None:
+            pulumi.set(__self__, "wait_for", wait_for)
+
+    @property
+    @pulumi.getter
+    def command(self) -> pulumi.Input[Sequence[pulumi.Input[str]]]:
+        """
+        The command to run, and its arguments. The command is not run in a shell.
+        """
+        return pulumi.get(self, "command")
+
+    @command.setter
+    def command(self, value: pulumi.Input[Sequence[pulumi.Input[str]]]):
+        pulumi.set(self, "command", value)
+
+    @property
+    @pulumi.getter
+    def pod(self) -> pulumi.Input[str]:
+        """
+        The name of the Pod to run the command in.
+        """
+        return pulumi.get(self, "pod")
+
+    @pod.setter
+    def pod(self, value: pulumi.Input[str]):
+        pulumi.set(self, "pod", value)
+
+    @property
+    @pulumi.getter
+    def container(self) -> Optional[pulumi.Input[str]]:
+        """
+        The container to run the command in. Defaults to the only container of the Pod.
+        """
+        return pulumi.get(self, "container")
+
+    @container.setter
+    def container(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "container", value)
+
+    @property
+    @pulumi.getter
+    def namespace(self) -> Optional[pulumi.Input[str]]:
+        """
+        The namespace of the Pod. Defaults to the namespace of the provider.
+        """
+        return pulumi.get(self, "namespace")
+
+    @namespace.setter
+    def namespace(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "namespace", value)
+
+    @property
+    @pulumi.getter
+    def stdin(self) -> Optional[pulumi.Input[str]]:
+        """
+        The input of the command.
+        """
+        return pulumi.get(self, "stdin")
+
+    @stdin.setter
+    def stdin(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "stdin", value)
+
+    @property
+    @pulumi.getter(name="timeoutSeconds")
+    def timeout_seconds(self) -> Optional[pulumi.Input[int]]:
+        """
+        How long to wait for the `waitFor` condition and for the command to exit, in seconds.
+        """
+        return pulumi.get(self, "timeout_seconds")
+
+    @timeout_seconds.setter
+    def timeout_seconds(self, value: Optional[pulumi.Input[int]]):
+        pulumi.set(self, "timeout_seconds", value)
+
+    @property
+    @pulumi.getter(name="waitFor")
+    def wait_for(self) -> Optional[pulumi.Input[str]]:
+        """
+        A condition that the Pod must satisfy before the command is run, in the syntax of `kubectl wait --for`, e.g., `condition=Ready`.
+        """
+        return pulumi.get(self, "wait_for")
+
+    @wait_for.setter
+    def wait_for(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "wait_for", value)
+
+
+class PodExec(pulumi.CustomResource):
+    @overload
+    def __init__(__self__,
+                 resource_name: str,
+                 opts: Optional[pulumi.ResourceOptions] = None,
+                 command: Optional[pulumi.Input[Sequence[pulumi.Input[str]]]] = None,
+                 container: Optional[pulumi.Input[str]] = None,
+                 namespace: Optional[pulumi.Input[str]] = None,
+                 pod: Optional[pulumi.Input[str]] = None,
+                 stdin: Optional[pulumi.Input[str]] = None,
+                 timeout_seconds: Optional[pulumi.Input[int]] = None,
+                 wait_for: Optional[pulumi.Input[str]] = None,
+                 __props__=None):
+        """
+        PodExec runs a command in a container of a Pod when it is created, and again whenever its inputs change, e.g., to run database migrations or smoke tests as part of a stack. The command is not run during previews, where its result is unknown. Deleting a PodExec does not undo the effects of its command.
+
+        :param str resource_name: The name of the resource.
+        :param pulumi.ResourceOptions opts: Options for the resource.
+        :param pulumi.Input[Sequence[pulumi.Input[str]]] command: The command to run, and its arguments. The command is not run in a shell.
+        :param pulumi.Input[str] container: The container to run the command in. Defaults to the only container of the Pod.
+        :param pulumi.Input[str] namespace: The namespace of the Pod. Defaults to the namespace of the provider.
+        :param pulumi.Input[str] pod: The name of the Pod to run the command in.
+        :param pulumi.Input[str] stdin: The input of the command.
+        :param pulumi.Input[int] timeout_seconds: How long to wait for the `waitFor` condition and for the command to exit, in seconds.
+        :param pulumi.Input[str] wait_for: A condition that the Pod must satisfy before the command is run, in the syntax of `kubectl wait --for`, e.g., `condition=Ready`.
+        """
+        ...
+    @overload
+    def __init__(__self__,
+                 resource_name: str,
+                 args: PodExecArgs,
+                 opts: Optional[pulumi.ResourceOptions] = None):
+        """
+        PodExec runs a command in a container of a Pod when it is created, and again whenever its inputs change, e.g., to run database migrations or smoke tests as part of a stack. The command is not run during previews, where its result is unknown. Deleting a PodExec does not undo the effects of its command.
+
+        :param str resource_name: The name of the resource.
+        :param PodExecArgs args: The arguments to use to populate this resource's properties.
+        :param pulumi.ResourceOptions opts: Options for the resource.
+        """
+        ...
+    def __init__(__self__, resource_name: str, *args, **kwargs):
+        resource_args, opts = _utilities.get_resource_args_opts(PodExecArgs, pulumi.ResourceOptions, *args, **kwargs)
+        if resource_args is not None:
+            __self__._internal_init(resource_name, opts, **resource_args.__dict__)
+        else:
+            __self__._internal_init(resource_name, *args, **kwargs)
+
+    def _internal_init(__self__,
+                 resource_name: str,
+                 opts: Optional[pulumi.ResourceOptions] = None,
+                 command: Optional[pulumi.Input[Sequence[pulumi.Input[str]]]] = None,
+                 container: Optional[pulumi.Input[str]] = None,
+                 namespace: Optional[pulumi.Input[str]] = None,
+                 pod: Optional[pulumi.Input[str]] = None,
+                 stdin: Optional[pulumi.Input[str]] = None,
+                 timeout_seconds: Optional[pulumi.Input[int]] = None,
+                 wait_for: Optional[pulumi.Input[str]] = None,
+                 __props__=None):
+        if opts is None:
+            opts = pulumi.ResourceOptions()
+        if not isinstance(opts, pulumi.ResourceOptions):
+            raise TypeError('Expected resource options to be a ResourceOptions instance')
+        if opts.version is None:
+            opts.version = _utilities.get_version()
+        if opts.id is None:
+            if __props__ is not None:
+                raise TypeError('__props__ is only valid when passed in combination with a valid opts.id to get an existing resource')
+            __props__ = PodExecArgs.__new__(PodExecArgs)
+
+            if command is None and not opts.urn:
+                raise TypeError("Missing required property 'command'")
+            __props__.__dict__["command"] = command
+            __props__.__dict__["container"] = container
+            __props__.__dict__["namespace"] = namespace
+            if pod is None and not opts.urn:
+                raise TypeError("Missing required property 'pod'")
+            __props__.__dict__["pod"] = pod
+            __props__.__dict__["stdin"] = stdin
+            __props__.__dict__["timeout_seconds"] = timeout_seconds
+            __props__.__dict__["wait_for"] = wait_for
+            __props__.__dict__["exit_code"] = None
+            __props__.__dict__["stderr"] = None
+            __props__.__dict__["stdout"] = None
+        super(PodExec, __self__).__init__(
+            'kubernetes:index:PodExec',
+            resource_name,
+            __props__,
+            opts)
+
+    @staticmethod
+    def get(resource_name: str,
+            id: pulumi.Input[str],
+            opts: Optional[pulumi.ResourceOptions] = None) -> 'PodExec':
+        """
+        Get an existing PodExec resource's state with the given name, id, and optional extra
+        properties used to qualify the lookup.
+
+        :param str resource_name: The unique name of the resulting resource.
+        :param pulumi.Input[str] id: The unique provider ID of the resource to lookup.
+        :param pulumi.ResourceOptions opts: Options for the resource.
+        """
+        opts = pulumi.ResourceOptions.merge(opts, pulumi.ResourceOptions(id=id))
+
+        __props__ = PodExecArgs.__new__(PodExecArgs)
+
+        __props__.__dict__["command"] = None
+        __props__.__dict__["container"] = None
+        __props__.__dict__["exit_code"] = None
+        __props__.__dict__["namespace"] = None
+        __props__.__dict__["pod"] = None
+        __props__.__dict__["stderr"] = None
+        __props__.__dict__["stdin"] = None
+        __props__.__dict__["stdout"] = None
+        __props__.__dict__["timeout_seconds"] = None
+        __props__.__dict__["wait_for"] = None
+        return PodExec(resource_name, opts=opts, __props__=__props__)
+
+    @property
+    @pulumi.getter
+    def command(self) -> pulumi.Output[Sequence[str]]:
+        """
+        The command to run, and its arguments. The command is not run in a shell.
+        """
+        return pulumi.get(self, "command")
+
+    @property
+    @pulumi.getter
+    def container(self) -> pulumi.Output[Optional[str]]:
+        """
+        The container to run the command in. Defaults to the only container of the Pod.
+        """
+        return pulumi.get(self, "container")
+
+    @property
+    @pulumi.getter(name="exitCode")
+    def exit_code(self) -> pulumi.Output[int]:
+        """
+        The exit code of the command.
+        """
+        return pulumi.get(self, "exit_code")
+
+    @property
+    @pulumi.getter
+    def namespace(self) -> pulumi.Output[Optional[str]]:
+        """
+        The namespace of the Pod. Defaults to the namespace of the provider.
+        """
+        return pulumi.get(self, "namespace")
+
+    @property
+    @pulumi.getter
+    def pod(self) -> pulumi.Output[str]:
+        """
+        The name of the Pod to run the command in.
+        """
+        return pulumi.get(self, "pod")
+
+    @property
+    @pulumi.getter
+    def stderr(self) -> pulumi.Output[str]:
+        """
+        The standard error of the command.
+        """
+        return pulumi.get(self, "stderr")
+
+    @property
+    @pulumi.getter
+    def stdin(self) -> pulumi.Output[Optional[str]]:
+        """
+        The input of the command.
+        """
+        return pulumi.get(self, "stdin")
+
+    @property
+    @pulumi.getter
+    def stdout(self) -> pulumi.Output[str]:
+        """
+        The standard output of the command.
+        """
+        return pulumi.get(self, "stdout")
+
+    @property
+    @pulumi.getter(name="timeoutSeconds")
+    def timeout_seconds(self) -> pulumi.Output[Optional[int]]:
+        """
+        How long to wait for the `waitFor` condition and for the command to exit, in seconds.
+        """
+        return pulumi.get(self, "timeout_seconds")
+
+    @property
+    @pulumi.getter(name="waitFor")
+    def wait_for(self) -> pulumi.Output[Optional[str]]:
+        """
+        A condition that the Pod must satisfy before the command is run, in the syntax of `kubectl wait --for`, e.g., `condition=Ready`.
+        """
+        return pulumi.get(self, "wait_for")
+
@@ -5,9 +5,12 @@
 from . import _utilities
 import typing
 # Export this package's modules as members:
+from .get_object import *
 from .kustomize import *
+from .PodExec import *
 from .provider import *
 from .yaml import *
+from ._inputs import *
 
 # Make subpackages available:
 if typing.TYPE_CHECKING:
@@ -434,6 +437,14 @@ _utilities.register(
    "kubernetes:helm.sh/v3:Release": "Release"
   }
  },
+ {
+  "pkg": "kubernetes",
+  "mod": "index",
+  "fqn": "pulumi_kubernetes",
+  "classes": {
+   "kubernetes:index:PodExec": "PodExec"
+  }
+ },
  {
   "pkg": "kubernetes",
   "mod": "meta/v1",
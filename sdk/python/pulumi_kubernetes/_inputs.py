@@ -0,0 +1,70 @@
+# coding=utf-8
+# *** WARNING: this file was generated by pulumigen. ***
+# *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+import warnings
+import pulumi
+import pulumi.runtime
+from typing import Any, Mapping, Optional, Sequence, Union, overload
+from . import _utilities
+
+__all__ = [
+    'ImpersonationArgs',
+]
+
+@pulumi.input_type
+class ImpersonationArgs:
+    def __init__(__self__, *,
+                 groups: Optional[pulumi.Input[Sequence[pulumi.Input[str]]]] = None,
+                 uid: Optional[pulumi.Input[str]] = None,
+                 user: Optional[pulumi.Input[str]] = None):
+        """
+        The identity that the provider impersonates in every request to the Kubernetes API server.
+        :param pulumi.Input[Sequence[pulumi.Input[str]]] groups: The groups to impersonate. Requires `user` to be set.
+        :param pulumi.Input[str] uid: The UID to impersonate. Requires `user` to be set, and Kubernetes v1.22 or later.
+        :param pulumi.Input[str] user: The user to impersonate, e.g., `system:serviceaccount:<namespace>:<name>`.
+        """
+        if groups is not None:
+            pulumi.set(__self__, "groups", groups)
+        if uid is not None:
+            pulumi.set(__self__, "uid", uid)
+        if user is not None:
+            pulumi.set(__self__, "user", user)
+
+    @property
+    @pulumi.getter
+    def groups(self) -> Optional[pulumi.Input[Sequence[pulumi.Input[str]]]]:
+        """
+        The groups to impersonate. Requires `user` to be set.
+        """
+        return pulumi.get(self, "groups")
+
+    @groups.setter
+    def groups(self, value: Optional[pulumi.Input[Sequence[pulumi.Input[str]]]]):
+        pulumi.set(self, "groups", value)
+
+    @property
+    @pulumi.getter
+    def uid(self) -> Optional[pulumi.Input[str]]:
+        """
+        The UID to impersonate. Requires `user` to be set, and Kubernetes v1.22 or later.
+        """
+        return pulumi.get(self, "uid")
+
+    @uid.setter
+    def uid(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "uid", value)
+
+    @property
+    @pulumi.getter
+    def user(self) -> Optional[pulumi.Input[str]]:
+        """
+        The user to impersonate, e.g., `system:serviceaccount:<namespace>:<name>`.
+        """
+        return pulumi.get(self, "user")
+
+    @user.setter
+    def user(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "user", value)
+
+
@@ -0,0 +1,74 @@
+# coding=utf-8
+# *** WARNING: this file was generated by pulumigen. ***
+# *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+import warnings
+import pulumi
+import pulumi.runtime
+from typing import Any, Mapping, Optional, Sequence, Union, overload
+from . import _utilities
+
+__all__ = [
+    'GetObjectResult',
+    'AwaitableGetObjectResult',
+    'get_object',
+]
+
+@pulumi.output_type
+class GetObjectResult:
+    def __init__(__self__, result=None):
+        if result and not isinstance(result, dict):
+            raise TypeError("Expected argument 'result' to be a dict")
+        pulumi.set(__self__, "result", result)
+
+    @property
+    @pulumi.getter
+    def result(self) -> Mapping[str, Any]:
+        """
+        The live object.
+        """
+        return pulumi.get(self, "result")
+
+
+class AwaitableGetObjectResult(GetObjectResult):
+    # pylint: disable=using-constant-test
+    def __await__(self):
+        if False:
+            yield self
+        return GetObjectResult(
+            result=self.result)
+
+
+def get_object(api_version: Optional[str] = None,
+               kind: Optional[str] = None,
+               name: Optional[str] = None,
+               namespace: Optional[str] = None,
+               timeout_seconds: Optional[int] = None,
+               wait_for: Optional[str] = None,
+               opts: Optional[pulumi.InvokeOptions] = None) -> AwaitableGetObjectResult:
+    """
+    Reads a live object from the cluster, optionally waiting until it satisfies a condition, e.g., to use a value that a controller sets on a resource. The data of Secrets, and their last-applied-configuration annotation, are returned as secrets. Managed fields are omitted.
+
+
+    :param str api_version: The apiVersion of the object, e.g., `apps/v1`.
+    :param str kind: The kind of the object, e.g., `Deployment`.
+    :param str name: The name of the object.
+    :param str namespace: The namespace of the object. Defaults to the namespace of the provider for namespaced kinds.
+    :param int timeout_seconds: How long to wait for the `waitFor` condition, in seconds. Defaults to 300.
+    :param str wait_for: A condition that the object must satisfy before it is returned, in the syntax of `kubectl wait --for`: `exists`, `condition=<type>[=<status>]` or `jsonpath={<path>}[=<value>]`. If not set, the object is read once.
+    """
+    __args__ = dict()
+    __args__['apiVersion'] = api_version
+    __args__['kind'] = kind
+    __args__['name'] = name
+    __args__['namespace'] = namespace
+    __args__['timeoutSeconds'] = timeout_seconds
+    __args__['waitFor'] = wait_for
+    if opts is None:
+        opts = pulumi.InvokeOptions()
+    if opts.version is None:
+        opts.version = _utilities.get_version()
+    __ret__ = pulumi.runtime.invoke('kubernetes:index:getObject', __args__, opts=opts, typ=GetObjectResult).value
+
+    return AwaitableGetObjectResult(
+        result=__ret__.result)
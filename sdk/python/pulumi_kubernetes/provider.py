@@ -7,30 +7,68 @@ import pulumi
 import pulumi.runtime
 from typing import Any, Mapping, Optional, Sequence, Union, overload
 from . import _utilities
+from ._inputs import *
 
 __all__ = ['ProviderArgs', 'Provider']
 
 @pulumi.input_type
 class ProviderArgs:
     def __init__(__self__, *,
+                 certificate_authority_data: Optional[pulumi.Input[str]] = None,
+                 client_burst: Optional[pulumi.Input[int]] = None,
+                 client_certificate_data: Optional[pulumi.Input[str]] = None,
+                 client_key_data: Optional[pulumi.Input[str]] = None,
+                 client_qps: Optional[pulumi.Input[float]] = None,
                  cluster: Optional[pulumi.Input[str]] = None,
                  context: Optional[pulumi.Input[str]] = None,
+                 discovery_cache_dir: Optional[pulumi.Input[str]] = None,
+                 discovery_cache_ttl: Optional[pulumi.Input[int]] = None,
+                 drift_ignored_field_managers: Optional[pulumi.Input[str]] = None,
+                 enable_drift_detection: Optional[pulumi.Input[bool]] = None,
                  enable_dry_run: Optional[pulumi.Input[bool]] = None,
                  helm_driver: Optional[pulumi.Input[str]] = None,
                  helm_plugins_path: Optional[pulumi.Input[str]] = None,
                  helm_registry_config_path: Optional[pulumi.Input[str]] = None,
                  helm_repository_cache: Optional[pulumi.Input[str]] = None,
                  helm_repository_config_path: Optional[pulumi.Input[str]] = None,
+                 ignore_fields: Optional[pulumi.Input[str]] = None,
+                 impersonate: Optional[pulumi.Input['ImpersonationArgs']] = None,
+                 insecure_skip_tls_verify: Optional[pulumi.Input[bool]] = None,
+                 kube_version: Optional[pulumi.Input[str]] = None,
                  kubeconfig: Optional[pulumi.Input[str]] = None,
+                 max_concurrent_watches: Optional[pulumi.Input[int]] = None,
+                 migrate_api_versions: Optional[pulumi.Input[bool]] = None,
                  namespace: Optional[pulumi.Input[str]] = None,
+                 policy_directory: Optional[pulumi.Input[str]] = None,
+                 proxy_url: Optional[pulumi.Input[str]] = None,
+                 render_yaml_clean: Optional[pulumi.Input[bool]] = None,
+                 render_yaml_layout: Optional[pulumi.Input[str]] = None,
+                 render_yaml_secrets: Optional[pulumi.Input[str]] = None,
+                 render_yaml_secrets_key: Optional[pulumi.Input[str]] = None,
                  render_yaml_to_directory: Optional[pulumi.Input[str]] = None,
+                 render_yaml_unknowns: Optional[pulumi.Input[str]] = None,
+                 server: Optional[pulumi.Input[str]] = None,
                  suppress_deprecation_warnings: Optional[pulumi.Input[bool]] = None,
                  suppress_helm_hook_warnings: Optional[pulumi.Input[bool]] = None,
-                 suppress_helm_release_beta_warning: Optional[pulumi.Input[bool]] = None):
+                 suppress_helm_release_beta_warning: Optional[pulumi.Input[bool]] = None,
+                 token: Optional[pulumi.Input[str]] = None):
         """
         The set of arguments for constructing a Provider resource.
+        :param pulumi.Input[str] certificate_authority_data: PEM-encoded certificate authority certificates for the API server given by `server`, either as PEM text or base64-encoded as in a kubeconfig.
+        :param pulumi.Input[int] client_burst: The maximum burst of requests that the provider sends to the Kubernetes API server above `clientQps`, shared by all of its clients. Defaults to 10.
+        :param pulumi.Input[str] client_certificate_data: A PEM-encoded client certificate to authenticate to the API server given by `server`, either as PEM text or base64-encoded as in a kubeconfig. Requires `clientKeyData`.
+        :param pulumi.Input[str] client_key_data: The PEM-encoded private key of the client certificate given by `clientCertificateData`, either as PEM text or base64-encoded as in a kubeconfig.
+        :param pulumi.Input[float] client_qps: The maximum number of queries per second that the provider sends to the Kubernetes API server, shared by all of its clients. Defaults to 5.
         :param pulumi.Input[str] cluster: If present, the name of the kubeconfig cluster to use.
         :param pulumi.Input[str] context: If present, the name of the kubeconfig context to use.
+        :param pulumi.Input[str] discovery_cache_dir: The directory in which the provider caches the discovery information and OpenAPI schema of the Kubernetes API server, shared by all provider processes. If this is not set, discovery information is only cached in memory.
+        :param pulumi.Input[int] discovery_cache_ttl: The number of seconds for which the cached API groups and resources of the Kubernetes API server are used, before they are discovered again. The cached OpenAPI schema is revalidated on every use. Only used if `discoveryCacheDir` is set. Set to 0 to disable the on-disk cache. Defaults to 600.
+        :param pulumi.Input[str] drift_ignored_field_managers: BETA FEATURE - A comma-separated list of the field managers whose changes are not reported as drift when `enableDriftDetection` is set, such as the controllers that own the fields they update. Changes by any other field manager than the provider are reported as drift. Defaults to `kube-controller-manager,kube-scheduler,kubelet`.
+        :param pulumi.Input[bool] enable_drift_detection: BETA FEATURE - If present and set to true, compare the inputs of each resource to its live state during diffs, and report fields that were changed out of band (e.g., with `kubectl edit`) as drift. Fields owned by the field managers in `driftIgnoredFieldManagers`, such as `spec.replicas` managed by a HorizontalPodAutoscaler, are not reported.
+               
+               This config can be specified in the following ways, using this precedence:
+               1. This `enableDriftDetection` parameter.
+               2. The `PULUMI_K8S_ENABLE_DRIFT_DETECTION` environment variable.
         :param pulumi.Input[bool] enable_dry_run: BETA FEATURE - If present and set to true, enable server-side diff calculations.
                This feature is in developer preview, and is disabled by default.
         :param pulumi.Input[str] helm_driver: BETA FEATURE - Used for supporting Helm Release resource (Beta). The backend storage driver for Helm. Values are: configmap, secret, memory, sql.
@@ -38,13 +76,37 @@ class ProviderArgs:
         :param pulumi.Input[str] helm_registry_config_path: BETA FEATURE - Used for supporting Helm Release resource (Beta). The path to the registry config file.
         :param pulumi.Input[str] helm_repository_cache: BETA FEATURE - Used for supporting Helm Release resource (Beta). The path to the file containing cached repository indexes.
         :param pulumi.Input[str] helm_repository_config_path: BETA FEATURE - Used for supporting Helm Release resource (Beta). The path to the file containing repository names and URLs.
+        :param pulumi.Input[str] ignore_fields: BETA FEATURE - A JSON object mapping resource types to lists of fields that are excluded from diffs and updates, e.g., `{"apps/v1/Deployment": ["/spec/replicas"]}`. Resource types are given as `<apiVersion>/<kind>`, and fields as JSON pointers or JSONPaths, where `*` matches every map key or list element. This is useful for fields that are set by controllers or mutating webhooks, such as injected sidecar containers or `caBundle` values. Fields can also be ignored for a single resource with the `pulumi.com/ignoreFields` annotation.
+        :param pulumi.Input['ImpersonationArgs'] impersonate: The user, groups and UID that the provider impersonates in every request to the Kubernetes API server, including the requests of Helm releases, e.g., to manage the resources of a tenant with the identity of its service account. If not set, the JSON encoding of the identity is read from the `PULUMI_K8S_IMPERSONATE` environment variable.
+        :param pulumi.Input[bool] insecure_skip_tls_verify: If present and set to true, the certificate of the API server given by `server` is not verified. This makes the connection insecure, and cannot be combined with `certificateAuthorityData`.
+        :param pulumi.Input[str] kube_version: The Kubernetes version to validate resources against when the cluster is unreachable, e.g., in render mode or in CI. When set, resources are validated and scoped using the OpenAPI schema bundled with the provider for that version.
+               
+               This config can be specified in the following ways, using this precedence:
+               1. This `kubeVersion` parameter.
+               2. The `PULUMI_K8S_KUBE_VERSION` environment variable.
         :param pulumi.Input[str] kubeconfig: The contents of a kubeconfig file or the path to a kubeconfig file.
+        :param pulumi.Input[int] max_concurrent_watches: The maximum number of watches that the provider keeps open at the same time, e.g., while it waits for resources to become ready. A watch that would exceed the limit waits up to 10 seconds for another watch to end. If none does, the objects of the resource are listed again periodically until a watch is free, so that waiting for resources that need several watches, e.g., 4 for a Deployment, still completes. Unlimited by default.
+        :param pulumi.Input[bool] migrate_api_versions: If present and set to true, resources that use a deprecated or removed apiVersion are migrated to the newest apiVersion of their kind that the cluster supports, along with the changes of their fields that can be made automatically, such as the backends of an Ingress. Resources that cannot be migrated automatically are left unchanged with a warning.
         :param pulumi.Input[str] namespace: If present, the default namespace to use. This flag is ignored for cluster-scoped resources.
                
                A namespace can be specified in multiple places, and the precedence is as follows:
                1. `.metadata.namespace` set on the resource.
                2. This `namespace` parameter.
                3. `namespace` set for the active context in the kubeconfig.
+        :param pulumi.Input[str] policy_directory: A directory of policies that are evaluated against every resource in `Check`. Each YAML or JSON file in the directory contains one or more policies, whose validations are CEL expressions of the resource as `object` and of its previous inputs as `oldObject`. Violations of `mandatory` policies (the default) fail the check, violations of `advisory` policies are reported as warnings, and `disabled` policies are not evaluated. Validations that depend on values that are unknown in a preview are evaluated again when the resource is created or updated. Rego policies are not supported.
+        :param pulumi.Input[str] proxy_url: The URL of an http, https or socks5 proxy to connect to the API server given by `server` through.
+        :param pulumi.Input[bool] render_yaml_clean: BETA FEATURE - If present and set to true, and `renderYamlToDirectory` is set, the rendered manifests are cleaned up
+               so that they can be applied directly with `kubectl apply -k` or consumed by GitOps tooling. The
+               `last-applied-configuration` and Pulumi-internal annotations are removed, the manifests are ordered so that
+               CustomResourceDefinitions and Namespaces are applied first and webhook configurations last, and a `kustomization.yaml`
+               listing every rendered file is written to the root of the directory.
+        :param pulumi.Input[str] render_yaml_layout: BETA FEATURE - If present, and `renderYamlToDirectory` is set, controls how the rendered manifests are arranged in the
+               directory. Values are:
+               - `directory` (default): one file per resource, in the `0-crd` and `1-manifest` subdirectories.
+               - `component`: one file per resource, in a subdirectory per component resource (e.g., a Chart or ConfigGroup).
+               - `single`: a single multi-document file per stack, named `<stack>.yaml`.
+        :param pulumi.Input[str] render_yaml_secrets: Controls how the data of Secrets is written when `renderYamlToDirectory` is set. `plaintext` (the default) writes it unencrypted. `sops` encrypts the `data` and `stringData` fields in the SOPS format. `sealedSecrets` renders each Secret as a Bitnami SealedSecret.
+        :param pulumi.Input[str] render_yaml_secrets_key: The key used to encrypt Secrets when `renderYamlSecrets` is set. For `sops`, a comma-separated list of age recipients, or the path to (or contents of) an ASCII-armored PGP public key. For `sealedSecrets`, the path to (or contents of) the PEM-encoded certificate of the sealed-secrets controller.
         :param pulumi.Input[str] render_yaml_to_directory: BETA FEATURE - If present, render resource manifests to this directory. In this mode, resources will not
                be created on a Kubernetes cluster, but the rendered manifests will be kept in sync with changes
                to the Pulumi program. This feature is in developer preview, and is disabled by default.
@@ -53,14 +115,47 @@ class ProviderArgs:
                since the resources are not created on a Kubernetes cluster. These Output values will remain undefined,
                and may result in an error if they are referenced by other resources. Also note that any secret values
                used in these resources will be rendered in plaintext to the resulting YAML.
+        :param pulumi.Input[str] render_yaml_unknowns: Controls how resources with unknown values are rendered when `renderYamlToDirectory` is set. `defer` (the default) does not write a resource until all of its values are known, and lists its unknown values in `unresolved-fields.json` in the render directory. `placeholder` renders unknown values as `<unresolved>` and lists them in `unresolved-fields.json` in the render directory.
+        :param pulumi.Input[str] server: The address of the Kubernetes API server, e.g., `https://1.2.3.4:6443`. If this is set, the provider connects to this server with the credentials given by `certificateAuthorityData`, `token`, `clientCertificateData` and `clientKeyData`, and no kubeconfig is loaded. It cannot be combined with `kubeconfig`, `context` or `cluster`.
         :param pulumi.Input[bool] suppress_deprecation_warnings: If present and set to true, suppress apiVersion deprecation warnings from the CLI.
         :param pulumi.Input[bool] suppress_helm_hook_warnings: If present and set to true, suppress unsupported Helm hook warnings from the CLI.
         :param pulumi.Input[bool] suppress_helm_release_beta_warning: While Helm Release provider is in beta, by default 'pulumi up' will log a warning if the resource is used. If present and set to "true", this warning is omitted.
+        :param pulumi.Input[str] token: A bearer token to authenticate to the API server given by `server`.
         """
+        if certificate_authority_data is not None:
+            pulumi.set(__self__, "certificate_authority_data", certificate_authority_data)
+        if client_burst is None:
+            client_burst = _utilities.get_env_int('PULUMI_K8S_CLIENT_BURST')
+        if client_burst is not None:
+            pulumi.set(__self__, "client_burst", client_burst)
+        if client_certificate_data is not None:
+            pulumi.set(__self__, "client_certificate_data", client_certificate_data)
+        if client_key_data is not None:
+            pulumi.set(__self__, "client_key_data", client_key_data)
+        if client_qps is None:
+            client_qps = _utilities.get_env_float('PULUMI_K8S_CLIENT_QPS')
+        if client_qps is not None:
+            pulumi.set(__self__, "client_qps", client_qps)
         if cluster is not None:
             pulumi.set(__self__, "cluster", cluster)
         if context is not None:
             pulumi.set(__self__, "context", context)
+        if discovery_cache_dir is None:
+            discovery_cache_dir = _utilities.get_env('PULUMI_K8S_DISCOVERY_CACHE_DIR')
+        if discovery_cache_dir is not None:
+            pulumi.set(__self__, "discovery_cache_dir", discovery_cache_dir)
+        if discovery_cache_ttl is None:
+            discovery_cache_ttl = _utilities.get_env_int('PULUMI_K8S_DISCOVERY_CACHE_TTL')
+        if discovery_cache_ttl is not None:
+            pulumi.set(__self__, "discovery_cache_ttl", discovery_cache_ttl)
+        if drift_ignored_field_managers is None:
+            drift_ignored_field_managers = _utilities.get_env('PULUMI_K8S_DRIFT_IGNORED_FIELD_MANAGERS')
+        if drift_ignored_field_managers is not None:
+            pulumi.set(__self__, "drift_ignored_field_managers", drift_ignored_field_managers)
+        if enable_drift_detection is None:
+            enable_drift_detection = _utilities.get_env_bool('PULUMI_K8S_ENABLE_DRIFT_DETECTION')
+        if enable_drift_detection is not None:
+            pulumi.set(__self__, "enable_drift_detection", enable_drift_detection)
         if enable_dry_run is None:
             enable_dry_run = _utilities.get_env_bool('PULUMI_K8S_ENABLE_DRY_RUN')
         if enable_dry_run is not None:
@@ -85,14 +180,62 @@ class ProviderArgs:
             helm_repository_config_path = _utilities.get_env('PULUMI_K8S_HELM_REPOSITORY_CONFIG_PATH')
         if helm_repository_config_path is not None:
             pulumi.set(__self__, "helm_repository_config_path", helm_repository_config_path)
+        if ignore_fields is None:
+            ignore_fields = _utilities.get_env('PULUMI_K8S_IGNORE_FIELDS')
+        if ignore_fields is not None:
+            pulumi.set(__self__, "ignore_fields", ignore_fields)
+        if impersonate is not None:
+            pulumi.set(__self__, "impersonate", impersonate)
+        if insecure_skip_tls_verify is not None:
+            pulumi.set(__self__, "insecure_skip_tls_verify", insecure_skip_tls_verify)
+        if kube_version is None:
+            kube_version = _utilities.get_env('PULUMI_K8S_KUBE_VERSION')
+        if kube_version is not None:
+            pulumi.set(__self__, "kube_version", kube_version)
         if kubeconfig is None:
             kubeconfig = _utilities.get_env('KUBECONFIG')
         if kubeconfig is not None:
             pulumi.set(__self__, "kubeconfig", kubeconfig)
+        if max_concurrent_watches is None:
+            max_concurrent_watches = _utilities.get_env_int('PULUMI_K8S_MAX_CONCURRENT_WATCHES')
+        if max_concurrent_watches is not None:
+            pulumi.set(__self__, "max_concurrent_watches", max_concurrent_watches)
+        if migrate_api_versions is None:
+            migrate_api_versions = _utilities.get_env_bool('PULUMI_K8S_MIGRATE_API_VERSIONS')
+        if migrate_api_versions is not None:
+            pulumi.set(__self__, "migrate_api_versions", migrate_api_versions)
         if namespace is not None:
             pulumi.set(__self__, "namespace", namespace)
+        if policy_directory is None:
+            policy_directory = _utilities.get_env('PULUMI_K8S_POLICY_DIRECTORY')
+        if policy_directory is not None:
+            pulumi.set(__self__, "policy_directory", policy_directory)
+        if proxy_url is not None:
+            pulumi.set(__self__, "proxy_url", proxy_url)
+        if render_yaml_clean is None:
+            render_yaml_clean = _utilities.get_env_bool('PULUMI_K8S_RENDER_YAML_CLEAN')
+        if render_yaml_clean is not None:
+            pulumi.set(__self__, "render_yaml_clean", render_yaml_clean)
+        if render_yaml_layout is None:
+            render_yaml_layout = _utilities.get_env('PULUMI_K8S_RENDER_YAML_LAYOUT')
+        if render_yaml_layout is not None:
+            pulumi.set(__self__, "render_yaml_layout", render_yaml_layout)
+        if render_yaml_secrets is None:
+            render_yaml_secrets = _utilities.get_env('PULUMI_K8S_RENDER_YAML_SECRETS')
+        if render_yaml_secrets is not None:
+            pulumi.set(__self__, "render_yaml_secrets", render_yaml_secrets)
+        if render_yaml_secrets_key is None:
+            render_yaml_secrets_key = _utilities.get_env('PULUMI_K8S_RENDER_YAML_SECRETS_KEY')
+        if render_yaml_secrets_key is not None:
+            pulumi.set(__self__, "render_yaml_secrets_key", render_yaml_secrets_key)
         if render_yaml_to_directory is not None:
             pulumi.set(__self__, "render_yaml_to_directory", render_yaml_to_directory)
+        if render_yaml_unknowns is None:
+            render_yaml_unknowns = _utilities.get_env('PULUMI_K8S_RENDER_YAML_UNKNOWNS')
+        if render_yaml_unknowns is not None:
+            pulumi.set(__self__, "render_yaml_unknowns", render_yaml_unknowns)
+        if server is not None:
+            pulumi.set(__self__, "server", server)
         if suppress_deprecation_warnings is None:
             suppress_deprecation_warnings = _utilities.get_env_bool('PULUMI_K8S_SUPPRESS_DEPRECATION_WARNINGS')
         if suppress_deprecation_warnings is not None:
@@ -105,6 +248,68 @@ class ProviderArgs:
             suppress_helm_release_beta_warning = _utilities.get_env_bool('PULUMI_K8S_SUPPRESS_HELM_RELEASE_BETA_WARNING')
         if suppress_helm_release_beta_warning is not None:
             pulumi.set(__self__, "suppress_helm_release_beta_warning", suppress_helm_release_beta_warning)
+        if token is not None:
+            pulumi.set(__self__, "token", token)
+
+    @property
+    @pulumi.getter(name="certificateAuthorityData")
+    def certificate_authority_data(self) -> Optional[pulumi.Input[str]]:
+        """
+        PEM-encoded certificate authority certificates for the API server given by `server`, either as PEM text or base64-encoded as in a kubeconfig.
+        """
+        return pulumi.get(self, "certificate_authority_data")
+
+    @certificate_authority_data.setter
+    def certificate_authority_data(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "certificate_authority_data", value)
+
+    @property
+    @pulumi.getter(name="clientBurst")
+    def client_burst(self) -> Optional[pulumi.Input[int]]:
+        """
+        The maximum burst of requests that the provider sends to the Kubernetes API server above `clientQps`, shared by all of its clients. Defaults to 10.
+        """
+        return pulumi.get(self, "client_burst")
+
+    @client_burst.setter
+    def client_burst(self, value: Optional[pulumi.Input[int]]):
+        pulumi.set(self, "client_burst", value)
+
+    @property
+    @pulumi.getter(name="clientCertificateData")
+    def client_certificate_data(self) -> Optional[pulumi.Input[str]]:
+        """
+        A PEM-encoded client certificate to authenticate to the API server given by `server`, either as PEM text or base64-encoded as in a kubeconfig. Requires `clientKeyData`.
+        """
+        return pulumi.get(self, "client_certificate_data")
+
+    @client_certificate_data.setter
+    def client_certificate_data(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "client_certificate_data", value)
+
+    @property
+    @pulumi.getter(name="clientKeyData")
+    def client_key_data(self) -> Optional[pulumi.Input[str]]:
+        """
+        The PEM-encoded private key of the client certificate given by `clientCertificateData`, either as PEM text or base64-encoded as in a kubeconfig.
+        """
+        return pulumi.get(self, "client_key_data")
+
+    @client_key_data.setter
+    def client_key_data(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "client_key_data", value)
+
+    @property
+    @pulumi.getter(name="clientQps")
+    def client_qps(self) -> Optional[pulumi.Input[float]]:
+        """
+        The maximum number of queries per second that the provider sends to the Kubernetes API server, shared by all of its clients. Defaults to 5.
+        """
+        return pulumi.get(self, "client_qps")
+
+    @client_qps.setter
+    def client_qps(self, value: Optional[pulumi.Input[float]]):
+        pulumi.set(self, "client_qps", value)
 
     @property
     @pulumi.getter
@@ -130,6 +335,58 @@ class ProviderArgs:
     def context(self, value: Optional[pulumi.Input[str]]):
         pulumi.set(self, "context", value)
 
+    @property
+    @pulumi.getter(name="discoveryCacheDir")
+    def discovery_cache_dir(self) -> Optional[pulumi.Input[str]]:
+        """
+        The directory in which the provider caches the discovery information and OpenAPI schema of the Kubernetes API server, shared by all provider processes. If this is not set, discovery information is only cached in memory.
+        """
+        return pulumi.get(self, "discovery_cache_dir")
+
+    @discovery_cache_dir.setter
+    def discovery_cache_dir(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "discovery_cache_dir", value)
+
+    @property
+    @pulumi.getter(name="discoveryCacheTtl")
+    def discovery_cache_ttl(self) -> Optional[pulumi.Input[int]]:
+        """
+        The number of seconds for which the cached API groups and resources of the Kubernetes API server are used, before they are discovered again. The cached OpenAPI schema is revalidated on every use. Only used if `discoveryCacheDir` is set. Set to 0 to disable the on-disk cache. Defaults to 600.
+        """
+        return pulumi.get(self, "discovery_cache_ttl")
+
+    @discovery_cache_ttl.setter
+    def discovery_cache_ttl(self, value: Optional[pulumi.Input[int]]):
+        pulumi.set(self, "discovery_cache_ttl", value)
+
+    @property
+    @pulumi.getter(name="driftIgnoredFieldManagers")
+    def drift_ignored_field_managers(self) -> Optional[pulumi.Input[str]]:
+        """
+        BETA FEATURE - A comma-separated list of the field managers whose changes are not reported as drift when `enableDriftDetection` is set, such as the controllers that own the fields they update. Changes by any other field manager than the provider are reported as drift. Defaults to `kube-controller-manager,kube-scheduler,kubelet`.
+        """
+        return pulumi.get(self, "drift_ignored_field_managers")
+
+    @drift_ignored_field_managers.setter
+    def drift_ignored_field_managers(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "drift_ignored_field_managers", value)
+
+    @property
+    @pulumi.getter(name="enableDriftDetection")
+    def enable_drift_detection(self) -> Optional[pulumi.Input[bool]]:
+        """
+        BETA FEATURE - If present and set to true, compare the inputs of each resource to its live state during diffs, and report fields that were changed out of band (e.g., with `kubectl edit`) as drift. Fields owned by the field managers in `driftIgnoredFieldManagers`, such as `spec.replicas` managed by a HorizontalPodAutoscaler, are not reported.
+
+        This config can be specified in the following ways, using this precedence:
+        1. This `enableDriftDetection` parameter.
+        2. The `PULUMI_K8S_ENABLE_DRIFT_DETECTION` environment variable.
+        """
+        return pulumi.get(self, "enable_drift_detection")
+
+    @enable_drift_detection.setter
+    def enable_drift_detection(self, value: Optional[pulumi.Input[bool]]):
+        pulumi.set(self, "enable_drift_detection", value)
+
     @property
     @pulumi.getter(name="enableDryRun")
     def enable_dry_run(self) -> Optional[pulumi.Input[bool]]:
@@ -203,6 +460,58 @@ class ProviderArgs:
     def helm_repository_config_path(self, value: Optional[pulumi.Input[str]]):
         pulumi.set(self, "helm_repository_config_path", value)
 
+    @property
+    @pulumi.getter(name="ignoreFields")
+    def ignore_fields(self) -> Optional[pulumi.Input[str]]:
+        """
+        BETA FEATURE - A JSON object mapping resource types to lists of fields that are excluded from diffs and updates, e.g., `{"apps/v1/Deployment": ["/spec/replicas"]}`. Resource types are given as `<apiVersion>/<kind>`, and fields as JSON pointers or JSONPaths, where `*` matches every map key or list element. This is useful for fields that are set by controllers or mutating webhooks, such as injected sidecar containers or `caBundle` values. Fields can also be ignored for a single resource with the `pulumi.com/ignoreFields` annotation.
+        """
+        return pulumi.get(self, "ignore_fields")
+
+    @ignore_fields.setter
+    def ignore_fields(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "ignore_fields", value)
+
+    @property
+    @pulumi.getter
+    def impersonate(self) -> Optional[pulumi.Input['ImpersonationArgs']]:
+        """
+        The user, groups and UID that the provider impersonates in every request to the Kubernetes API server, including the requests of Helm releases, e.g., to manage the resources of a tenant with the identity of its service account. If not set, the JSON encoding of the identity is read from the `PULUMI_K8S_IMPERSONATE` environment variable.
+        """
+        return pulumi.get(self, "impersonate")
+
+    @impersonate.setter
+    def impersonate(self, value: Optional[pulumi.Input['ImpersonationArgs']]):
+        pulumi.set(self, "impersonate", value)
+
+    @property
+    @pulumi.getter(name="insecureSkipTlsVerify")
+    def insecure_skip_tls_verify(self) -> Optional[pulumi.Input[bool]]:
+        """
+        If present and set to true, the certificate of the API server given by `server` is not verified. This makes the connection insecure, and cannot be combined with `certificateAuthorityData`.
+        """
+        return pulumi.get(self, "insecure_skip_tls_verify")
+
+    @insecure_skip_tls_verify.setter
+    def insecure_skip_tls_verify(self, value: Optional[pulumi.Input[bool]]):
+        pulumi.set(self, "insecure_skip_tls_verify", value)
+
+    @property
+    @pulumi.getter(name="kubeVersion")
+    def kube_version(self) -> Optional[pulumi.Input[str]]:
+        """
+        The Kubernetes version to validate resources against when the cluster is unreachable, e.g., in render mode or in CI. When set, resources are validated and scoped using the OpenAPI schema bundled with the provider for that version.
+
+        This config can be specified in the following ways, using this precedence:
+        1. This `kubeVersion` parameter.
+        2. The `PULUMI_K8S_KUBE_VERSION` environment variable.
+        """
+        return pulumi.get(self, "kube_version")
+
+    @kube_version.setter
+    def kube_version(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "kube_version", value)
+
     @property
     @pulumi.getter
     def kubeconfig(self) -> Optional[pulumi.Input[str]]:
@@ -215,6 +524,30 @@ class ProviderArgs:
     def kubeconfig(self, value: Optional[pulumi.Input[str]]):
         pulumi.set(self, "kubeconfig", value)
 
+    @property
+    @pulumi.getter(name="maxConcurrentWatches")
+    def max_concurrent_watches(self) -> Optional[pulumi.Input[int]]:
+        """
+        The maximum number of watches that the provider keeps open at the same time, e.g., while it waits for resources to become ready. A watch that would exceed the limit waits up to 10 seconds for another watch to end. If none does, the objects of the resource are listed again periodically until a watch is free, so that waiting for resources that need several watches, e.g., 4 for a Deployment, still completes. Unlimited by default.
+        """
+        return pulumi.get(self, "max_concurrent_watches")
+
+    @max_concurrent_watches.setter
+    def max_concurrent_watches(self, value: Optional[pulumi.Input[int]]):
+        pulumi.set(self, "max_concurrent_watches", value)
+
+    @property
+    @pulumi.getter(name="migrateApiVersions")
+    def migrate_api_versions(self) -> Optional[pulumi.Input[bool]]:
+        """
+        If present and set to true, resources that use a deprecated or removed apiVersion are migrated to the newest apiVersion of their kind that the cluster supports, along with the changes of their fields that can be made automatically, such as the backends of an Ingress. Resources that cannot be migrated automatically are left unchanged with a warning.
+        """
+        return pulumi.get(self, "migrate_api_versions")
+
+    @migrate_api_versions.setter
+    def migrate_api_versions(self, value: Optional[pulumi.Input[bool]]):
+        pulumi.set(self, "migrate_api_versions", value)
+
     @property
     @pulumi.getter
     def namespace(self) -> Optional[pulumi.Input[str]]:
@@ -232,6 +565,86 @@ class ProviderArgs:
     def namespace(self, value: Optional[pulumi.Input[str]]):
         pulumi.set(self, "namespace", value)
 
+    @property
+    @pulumi.getter(name="policyDirectory")
+    def policy_directory(self) -> Optional[pulumi.Input[str]]:
+        """
+        A directory of policies that are evaluated against every resource in `Check`. Each YAML or JSON file in the directory contains one or more policies, whose validations are CEL expressions of the resource as `object` and of its previous inputs as `oldObject`. Violations of `mandatory` policies (the default) fail the check, violations of `advisory` policies are reported as warnings, and `disabled` policies are not evaluated. Validations that depend on values that are unknown in a preview are evaluated again when the resource is created or updated. Rego policies are not supported.
+        """
+        return pulumi.get(self, "policy_directory")
+
+    @policy_directory.setter
+    def policy_directory(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "policy_directory", value)
+
+    @property
+    @pulumi.getter(name="proxyUrl")
+    def proxy_url(self) -> Optional[pulumi.Input[str]]:
+        """
+        The URL of an http, https or socks5 proxy to connect to the API server given by `server` through.
+        """
+        return pulumi.get(self, "proxy_url")
+
+    @proxy_url.setter
+    def proxy_url(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "proxy_url", value)
+
+    @property
+    @pulumi.getter(name="renderYamlClean")
+    def render_yaml_clean(self) -> Optional[pulumi.Input[bool]]:
+        """
+        BETA FEATURE - If present and set to true, and `renderYamlToDirectory` is set, the rendered manifests are cleaned up
+        so that they can be applied directly with `kubectl apply -k` or consumed by GitOps tooling. The
+        `last-applied-configuration` and Pulumi-internal annotations are removed, the manifests are ordered so that
+        CustomResourceDefinitions and Namespaces are applied first and webhook configurations last, and a `kustomization.yaml`
+        listing every rendered file is written to the root of the directory.
+        """
+        return pulumi.get(self, "render_yaml_clean")
+
+    @render_yaml_clean.setter
+    def render_yaml_clean(self, value: Optional[pulumi.Input[bool]]):
+        pulumi.set(self, "render_yaml_clean", value)
+
+    @property
+    @pulumi.getter(name="renderYamlLayout")
+    def render_yaml_layout(self) -> Optional[pulumi.Input[str]]:
+        """
+        BETA FEATURE - If present, and `renderYamlToDirectory` is set, controls how the rendered manifests are arranged in the
+        directory. Values are:
+        - `directory` (default): one file per resource, in the `0-crd` and `1-manifest` subdirectories.
+        - `component`: one file per resource, in a subdirectory per component resource (e.g., a Chart or ConfigGroup).
+        - `single`: a single multi-document file per stack, named `<stack>.yaml`.
+        """
+        return pulumi.get(self, "render_yaml_layout")
+
+    @render_yaml_layout.setter
+    def render_yaml_layout(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "render_yaml_layout", value)
+
+    @property
+    @pulumi.getter(name="renderYamlSecrets")
+    def render_yaml_secrets(self) -> Optional[pulumi.Input[str]]:
+        """
+        Controls how the data of Secrets is written when `renderYamlToDirectory` is set. `plaintext` (the default) writes it unencrypted. `sops` encrypts the `data` and `stringData` fields in the SOPS format. `sealedSecrets` renders each Secret as a Bitnami SealedSecret.
+        """
+        return pulumi.get(self, "render_yaml_secrets")
+
+    @render_yaml_secrets.setter
+    def render_yaml_secrets(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "render_yaml_secrets", value)
+
+    @property
+    @pulumi.getter(name="renderYamlSecretsKey")
+    def render_yaml_secrets_key(self) -> Optional[pulumi.Input[str]]:
+        """
+        The key used to encrypt Secrets when `renderYamlSecrets` is set. For `sops`, a comma-separated list of age recipients, or the path to (or contents of) an ASCII-armored PGP public key. For `sealedSecrets`, the path to (or contents of) the PEM-encoded certificate of the sealed-secrets controller.
+        """
+        return pulumi.get(self, "render_yaml_secrets_key")
+
+    @render_yaml_secrets_key.setter
+    def render_yaml_secrets_key(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "render_yaml_secrets_key", value)
+
     @property
     @pulumi.getter(name="renderYamlToDirectory")
     def render_yaml_to_directory(self) -> Optional[pulumi.Input[str]]:
@@ -251,6 +664,30 @@ class ProviderArgs:
     def render_yaml_to_directory(self, value: Optional[pulumi.Input[str]]):
         pulumi.set(self, "render_yaml_to_directory", value)
 
+    @property
+    @pulumi.getter(name="renderYamlUnknowns")
+    def render_yaml_unknowns(self) -> Optional[pulumi.Input[str]]:
+        """
+        Controls how resources with unknown values are rendered when `renderYamlToDirectory` is set. `defer` (the default) does not write a resource until all of its values are known, and lists its unknown values in `unresolved-fields.json` in the render directory. `placeholder` renders unknown values as `<unresolved>` and lists them in `unresolved-fields.json` in the render directory.
+        """
+        return pulumi.get(self, "render_yaml_unknowns")
+
+    @render_yaml_unknowns.setter
+    def render_yaml_unknowns(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "render_yaml_unknowns", value)
+
+    @property
+    @pulumi.getter
+    def server(self) -> Optional[pulumi.Input[str]]:
+        """
+        The address of the Kubernetes API server, e.g., `https://1.2.3.4:6443`. If this is set, the provider connects to this server with the credentials given by `certificateAuthorityData`, `token`, `clientCertificateData` and `clientKeyData`, and no kubeconfig is loaded. It cannot be combined with `kubeconfig`, `context` or `cluster`.
+        """
+        return pulumi.get(self, "server")
+
+    @server.setter
+    def server(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "server", value)
+
     @property
     @pulumi.getter(name="suppressDeprecationWarnings")
     def suppress_deprecation_warnings(self) -> Optional[pulumi.Input[bool]]:
@@ -287,34 +724,83 @@ class ProviderArgs:
     def suppress_helm_release_beta_warning(self, value: Optional[pulumi.Input[bool]]):
         pulumi.set(self, "suppress_helm_release_beta_warning", value)
 
+    @property
+    @pulumi.getter
+    def token(self) -> Optional[pulumi.Input[str]]:
+        """
+        A bearer token to authenticate to the API server given by `server`.
+        """
+        return pulumi.get(self, "token")
+
+    @token.setter
+    def token(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "token", value)
+
 
 class Provider(pulumi.ProviderResource):
     @overload
     def __init__(__self__,
                  resource_name: str,
                  opts: Optional[pulumi.ResourceOptions] = None,
+                 certificate_authority_data: Optional[pulumi.Input[str]] = None,
+                 client_burst: Optional[pulumi.Input[int]] = None,
+                 client_certificate_data: Optional[pulumi.Input[str]] = None,
+                 client_key_data: Optional[pulumi.Input[str]] = None,
+                 client_qps: Optional[pulumi.Input[float]] = None,
                  cluster: Optional[pulumi.Input[str]] = None,
                  context: Optional[pulumi.Input[str]] = None,
+                 discovery_cache_dir: Optional[pulumi.Input[str]] = None,
+                 discovery_cache_ttl: Optional[pulumi.Input[int]] = None,
+                 drift_ignored_field_managers: Optional[pulumi.Input[str]] = None,
+                 enable_drift_detection: Optional[pulumi.Input[bool]] = None,
                  enable_dry_run: Optional[pulumi.Input[bool]] = None,
                  helm_driver: Optional[pulumi.Input[str]] = None,
                  helm_plugins_path: Optional[pulumi.Input[str]] = None,
                  helm_registry_config_path: Optional[pulumi.Input[str]] = None,
                  helm_repository_cache: Optional[pulumi.Input[str]] = None,
                  helm_repository_config_path: Optional[pulumi.Input[str]] = None,
+                 ignore_fields: Optional[pulumi.Input[str]] = None,
+                 impersonate: Optional[pulumi.Input[pulumi.InputType['ImpersonationArgs']]] = None,
+                 insecure_skip_tls_verify: Optional[pulumi.Input[bool]] = None,
+                 kube_version: Optional[pulumi.Input[str]] = None,
                  kubeconfig: Optional[pulumi.Input[str]] = None,
+                 max_concurrent_watches: Optional[pulumi.Input[int]] = None,
+                 migrate_api_versions: Optional[pulumi.Input[bool]] = None,
                  namespace: Optional[pulumi.Input[str]] = None,
+                 policy_directory: Optional[pulumi.Input[str]] = None,
+                 proxy_url: Optional[pulumi.Input[str]] = None,
+                 render_yaml_clean: Optional[pulumi.Input[bool]] = None,
+                 render_yaml_layout: Optional[pulumi.Input[str]] = None,
+                 render_yaml_secrets: Optional[pulumi.Input[str]] = None,
+                 render_yaml_secrets_key: Optional[pulumi.Input[str]] = None,
                  render_yaml_to_directory: Optional[pulumi.Input[str]] = None,
+                 render_yaml_unknowns: Optional[pulumi.Input[str]] = None,
+                 server: Optional[pulumi.Input[str]] = None,
                  suppress_deprecation_warnings: Optional[pulumi.Input[bool]] = None,
                  suppress_helm_hook_warnings: Optional[pulumi.Input[bool]] = None,
                  suppress_helm_release_beta_warning: Optional[pulumi.Input[bool]] = None,
+                 token: Optional[pulumi.Input[str]] = None,
                  __props__=None):
         """
         The provider type for the kubernetes package.
 
         :param str resource_name: The name of the resource.
         :param pulumi.ResourceOptions opts: Options for the resource.
+        :param pulumi.Input[str] certificate_authority_data: PEM-encoded certificate authority certificates for the API server given by `server`, either as PEM text or base64-encoded as in a kubeconfig.
+        :param pulumi.Input[int] client_burst: The maximum burst of requests that the provider sends to the Kubernetes API server above `clientQps`, shared by all of its clients. Defaults to 10.
+        :param pulumi.Input[str] client_certificate_data: A PEM-encoded client certificate to authenticate to the API server given by `server`, either as PEM text or base64-encoded as in a kubeconfig. Requires `clientKeyData`.
+        :param pulumi.Input[str] client_key_data: The PEM-encoded private key of the client certificate given by `clientCertificateData`, either as PEM text or base64-encoded as in a kubeconfig.
+        :param pulumi.Input[float] client_qps: The maximum number of queries per second that the provider sends to the Kubernetes API server, shared by all of its clients. Defaults to 5.
         :param pulumi.Input[str] cluster: If present, the name of the kubeconfig cluster to use.
         :param pulumi.Input[str] context: If present, the name of the kubeconfig context to use.
+        :param pulumi.Input[str] discovery_cache_dir: The directory in which the provider caches the discovery information and OpenAPI schema of the Kubernetes API server, shared by all provider processes. If this is not set, discovery information is only cached in memory.
+        :param pulumi.Input[int] discovery_cache_ttl: The number of seconds for which the cached API groups and resources of the Kubernetes API server are used, before they are discovered again. The cached OpenAPI schema is revalidated on every use. Only used if `discoveryCacheDir` is set. Set to 0 to disable the on-disk cache. Defaults to 600.
+        :param pulumi.Input[str] drift_ignored_field_managers: BETA FEATURE - A comma-separated list of the field managers whose changes are not reported as drift when `enableDriftDetection` is set, such as the controllers that own the fields they update. Changes by any other field manager than the provider are reported as drift. Defaults to `kube-controller-manager,kube-scheduler,kubelet`.
+        :param pulumi.Input[bool] enable_drift_detection: BETA FEATURE - If present and set to true, compare the inputs of each resource to its live state during diffs, and report fields that were changed out of band (e.g., with `kubectl edit`) as drift. Fields owned by the field managers in `driftIgnoredFieldManagers`, such as `spec.replicas` managed by a HorizontalPodAutoscaler, are not reported.
+               
+               This config can be specified in the following ways, using this precedence:
+               1. This `enableDriftDetection` parameter.
+               2. The `PULUMI_K8S_ENABLE_DRIFT_DETECTION` environment variable.
         :param pulumi.Input[bool] enable_dry_run: BETA FEATURE - If present and set to true, enable server-side diff calculations.
                This feature is in developer preview, and is disabled by default.
         :param pulumi.Input[str] helm_driver: BETA FEATURE - Used for supporting Helm Release resource (Beta). The backend storage driver for Helm. Values are: configmap, secret, memory, sql.
@@ -322,13 +808,37 @@ class Provider(pulumi.ProviderResource):
         :param pulumi.Input[str] helm_registry_config_path: BETA FEATURE - Used for supporting Helm Release resource (Beta). The path to the registry config file.
         :param pulumi.Input[str] helm_repository_cache: BETA FEATURE - Used for supporting Helm Release resource (Beta). The path to the file containing cached repository indexes.
         :param pulumi.Input[str] helm_repository_config_path: BETA FEATURE - Used for supporting Helm Release resource (Beta). The path to the file containing repository names and URLs.
+        :param pulumi.Input[str] ignore_fields: BETA FEATURE - A JSON object mapping resource types to lists of fields that are excluded from diffs and updates, e.g., `{"apps/v1/Deployment": ["/spec/replicas"]}`. Resource types are given as `<apiVersion>/<kind>`, and fields as JSON pointers or JSONPaths, where `*` matches every map key or list element. This is useful for fields that are set by controllers or mutating webhooks, such as injected sidecar containers or `caBundle` values. Fields can also be ignored for a single resource with the `pulumi.com/ignoreFields` annotation.
+        :param pulumi.Input[pulumi.InputType['ImpersonationArgs']] impersonate: The user, groups and UID that the provider impersonates in every request to the Kubernetes API server, including the requests of Helm releases, e.g., to manage the resources of a tenant with the identity of its service account. If not set, the JSON encoding of the identity is read from the `PULUMI_K8S_IMPERSONATE` environment variable.
+        :param pulumi.Input[bool] insecure_skip_tls_verify: If present and set to true, the certificate of the API server given by `server` is not verified. This makes the connection insecure, and cannot be combined with `certificateAuthorityData`.
+        :param pulumi.Input[str] kube_version: The Kubernetes version to validate resources against when the cluster is unreachable, e.g., in render mode or in CI. When set, resources are validated and scoped using the OpenAPI schema bundled with the provider for that version.
+               
+               This config can be specified in the following ways, using this precedence:
+               1. This `kubeVersion` parameter.
+               2. The `PULUMI_K8S_KUBE_VERSION` environment variable.
         :param pulumi.Input[str] kubeconfig: The contents of a kubeconfig file or the path to a kubeconfig file.
+        :param pulumi.Input[int] max_concurrent_watches: The maximum number of watches that the provider keeps open at the same time, e.g., while it waits for resources to become ready. A watch that would exceed the limit waits up to 10 seconds for another watch to end. If none does, the objects of the resource are listed again periodically until a watch is free, so that waiting for resources that need several watches, e.g., 4 for a Deployment, still completes. Unlimited by default.
+        :param pulumi.Input[bool] migrate_api_versions: If present and set to true, resources that use a deprecated or removed apiVersion are migrated to the newest apiVersion of their kind that the cluster supports, along with the changes of their fields that can be made automatically, such as the backends of an Ingress. Resources that cannot be migrated automatically are left unchanged with a warning.
         :param pulumi.Input[str] namespace: If present, the default namespace to use. This flag is ignored for cluster-scoped resources.
                
                A namespace can be specified in multiple places, and the precedence is as follows:
                1. `.metadata.namespace` set on the resource.
                2. This `namespace` parameter.
                3. `namespace` set for the active context in the kubeconfig.
+        :param pulumi.Input[str] policy_directory: A directory of policies that are evaluated against every resource in `Check`. Each YAML or JSON file in the directory contains one or more policies, whose validations are CEL expressions of the resource as `object` and of its previous inputs as `oldObject`. Violations of `mandatory` policies (the default) fail the check, violations of `advisory` policies are reported as warnings, and `disabled` policies are not evaluated. Validations that depend on values that are unknown in a preview are evaluated again when the resource is created or updated. Rego policies are not supported.
+        :param pulumi.Input[str] proxy_url: The URL of an http, https or socks5 proxy to connect to the API server given by `server` through.
+        :param pulumi.Input[bool] render_yaml_clean: BETA FEATURE - If present and set to true, and `renderYamlToDirectory` is set, the rendered manifests are cleaned up
+               so that they can be applied directly with `kubectl apply -k` or consumed by GitOps tooling. The
+               `last-applied-configuration` and Pulumi-internal annotations are removed, the manifests are ordered so that
+               CustomResourceDefinitions and Namespaces are applied first and webhook configurations last, and a `kustomization.yaml`
+               listing every rendered file is written to the root of the directory.
+        :param pulumi.Input[str] render_yaml_layout: BETA FEATURE - If present, and `renderYamlToDirectory` is set, controls how the rendered manifests are arranged in the
+               directory. Values are:
+               - `directory` (default): one file per resource, in the `0-crd` and `1-manifest` subdirectories.
+               - `component`: one file per resource, in a subdirectory per component resource (e.g., a Chart or ConfigGroup).
+               - `single`: a single multi-document file per stack, named `<stack>.yaml`.
+        :param pulumi.Input[str] render_yaml_secrets: Controls how the data of Secrets is written when `renderYamlToDirectory` is set. `plaintext` (the default) writes it unencrypted. `sops` encrypts the `data` and `stringData` fields in the SOPS format. `sealedSecrets` renders each Secret as a Bitnami SealedSecret.
+        :param pulumi.Input[str] render_yaml_secrets_key: The key used to encrypt Secrets when `renderYamlSecrets` is set. For `sops`, a comma-separated list of age recipients, or the path to (or contents of) an ASCII-armored PGP public key. For `sealedSecrets`, the path to (or contents of) the PEM-encoded certificate of the sealed-secrets controller.
         :param pulumi.Input[str] render_yaml_to_directory: BETA FEATURE - If present, render resource manifests to this directory. In this mode, resources will not
                be created on a Kubernetes cluster, but the rendered manifests will be kept in sync with changes
                to the Pulumi program. This feature is in developer preview, and is disabled by default.
@@ -337,9 +847,12 @@ class Provider(pulumi.ProviderResource):
                since the resources are not created on a Kubernetes cluster. These Output values will remain undefined,
                and may result in an error if they are referenced by other resources. Also note that any secret values
                used in these resources will be rendered in plaintext to the resulting YAML.
+        :param pulumi.Input[str] render_yaml_unknowns: Controls how resources with unknown values are rendered when `renderYamlToDirectory` is set. `defer` (the default) does not write a resource until all of its values are known, and lists its unknown values in `unresolved-fields.json` in the render directory. `placeholder` renders unknown values as `<unresolved>` and lists them in `unresolved-fields.json` in the render directory.
+        :param pulumi.Input[str] server: The address of the Kubernetes API server, e.g., `https://1.2.3.4:6443`. If this is set, the provider connects to this server with the credentials given by `certificateAuthorityData`, `token`, `clientCertificateData` and `clientKeyData`, and no kubeconfig is loaded. It cannot be combined with `kubeconfig`, `context` or `cluster`.
         :param pulumi.Input[bool] suppress_deprecation_warnings: If present and set to true, suppress apiVersion deprecation warnings from the CLI.
         :param pulumi.Input[bool] suppress_helm_hook_warnings: If present and set to true, suppress unsupported Helm hook warnings from the CLI.
         :param pulumi.Input[bool] suppress_helm_release_beta_warning: While Helm Release provider is in beta, by default 'pulumi up' will log a warning if the resource is used. If present and set to "true", this warning is omitted.
+        :param pulumi.Input[str] token: A bearer token to authenticate to the API server given by `server`.
         """
         ...
     @overload
@@ -365,20 +878,44 @@ class Provider(pulumi.ProviderResource):
     def _internal_init(__self__,
                  resource_name: str,
                  opts: Optional[pulumi.ResourceOptions] = None,
+                 certificate_authority_data: Optional[pulumi.Input[str]] = None,
+                 client_burst: Optional[pulumi.Input[int]] = None,
+                 client_certificate_data: Optional[pulumi.Input[str]] = None,
+                 client_key_data: Optional[pulumi.Input[str]] = None,
+                 client_qps: Optional[pulumi.Input[float]] = None,
                  cluster: Optional[pulumi.Input[str]] = None,
                  context: Optional[pulumi.Input[str]] = None,
+                 discovery_cache_dir: Optional[pulumi.Input[str]] = None,
+                 discovery_cache_ttl: Optional[pulumi.Input[int]] = None,
+                 drift_ignored_field_managers: Optional[pulumi.Input[str]] = None,
+                 enable_drift_detection: Optional[pulumi.Input[bool]] = None,
                  enable_dry_run: Optional[pulumi.Input[bool]] = None,
                  helm_driver: Optional[pulumi.Input[str]] = None,
                  helm_plugins_path: Optional[pulumi.Input[str]] = None,
                  helm_registry_config_path: Optional[pulumi.Input[str]] = None,
                  helm_repository_cache: Optional[pulumi.Input[str]] = None,
                  helm_repository_config_path: Optional[pulumi.Input[str]] = None,
+                 ignore_fields: Optional[pulumi.Input[str]] = None,
+                 impersonate: Optional[pulumi.Input[pulumi.InputType['ImpersonationArgs']]] = None,
+                 insecure_skip_tls_verify: Optional[pulumi.Input[bool]] = None,
+                 kube_version: Optional[pulumi.Input[str]] = None,
                  kubeconfig: Optional[pulumi.Input[str]] = None,
+                 max_concurrent_watches: Optional[pulumi.Input[int]] = None,
+                 migrate_api_versions: Optional[pulumi.Input[bool]] = None,
                  namespace: Optional[pulumi.Input[str]] = None,
+                 policy_directory: Optional[pulumi.Input[str]] = None,
+                 proxy_url: Optional[pulumi.Input[str]] = None,
+                 render_yaml_clean: Optional[pulumi.Input[bool]] = None,
+                 render_yaml_layout: Optional[pulumi.Input[str]] = None,
+                 render_yaml_secrets: Optional[pulumi.Input[str]] = None,
+                 render_yaml_secrets_key: Optional[pulumi.Input[str]] = None,
                  render_yaml_to_directory: Optional[pulumi.Input[str]] = None,
+                 render_yaml_unknowns: Optional[pulumi.Input[str]] = None,
+                 server: Optional[pulumi.Input[str]] = None,
                  suppress_deprecation_warnings: Optional[pulumi.Input[bool]] = None,
                  suppress_helm_hook_warnings: Optional[pulumi.Input[bool]] = None,
                  suppress_helm_release_beta_warning: Optional[pulumi.Input[bool]] = None,
+                 token: Optional[pulumi.Input[str]] = None,
                  __props__=None):
         if opts is None:
             opts = pulumi.ResourceOptions()
@@ -391,8 +928,29 @@ class Provider(pulumi.ProviderResource):
                 raise TypeError('__props__ is only valid when passed in combination with a valid opts.id to get an existing resource')
             __props__ = ProviderArgs.__new__(ProviderArgs)
 
+            __props__.__dict__["certificate_authority_data"] = certificate_authority_data
+            if client_burst is None:
+                client_burst = _utilities.get_env_int('PULUMI_K8S_CLIENT_BURST')
+            __props__.__dict__["client_burst"] = pulumi.Output.from_input(client_burst).apply(pulumi.runtime.to_json) if client_burst is not None else None
+            __props__.__dict__["client_certificate_data"] = client_certificate_data
+            __props__.__dict__["client_key_data"] = None if client_key_data is None else pulumi.Output.secret(client_key_data)
+            if client_qps is None:
+                client_qps = _utilities.get_env_float('PULUMI_K8S_CLIENT_QPS')
+            __props__.__dict__["client_qps"] = pulumi.Output.from_input(client_qps).apply(pulumi.runtime.to_json) if client_qps is not None else None
             __props__.__dict__["cluster"] = cluster
             __props__.__dict__["context"] = context
+            if discovery_cache_dir is None:
+                discovery_cache_dir = _utilities.get_env('PULUMI_K8S_DISCOVERY_CACHE_DIR')
+            __props__.__dict__["discovery_cache_dir"] = discovery_cache_dir
+            if discovery_cache_ttl is None:
+                discovery_cache_ttl = _utilities.get_env_int('PULUMI_K8S_DISCOVERY_CACHE_TTL')
+            __props__.__dict__["discovery_cache_ttl"] = pulumi.Output.from_input(discovery_cache_ttl).apply(pulumi.runtime.to_json) if discovery_cache_ttl is not None else None
+            if drift_ignored_field_managers is None:
+                drift_ignored_field_managers = _utilities.get_env('PULUMI_K8S_DRIFT_IGNORED_FIELD_MANAGERS')
+            __props__.__dict__["drift_ignored_field_managers"] = drift_ignored_field_managers
+            if enable_drift_detection is None:
+                enable_drift_detection = _utilities.get_env_bool('PULUMI_K8S_ENABLE_DRIFT_DETECTION')
+            __props__.__dict__["enable_drift_detection"] = pulumi.Output.from_input(enable_drift_detection).apply(pulumi.runtime.to_json) if enable_drift_detection is not None else None
             if enable_dry_run is None:
                 enable_dry_run = _utilities.get_env_bool('PULUMI_K8S_ENABLE_DRY_RUN')
             __props__.__dict__["enable_dry_run"] = pulumi.Output.from_input(enable_dry_run).apply(pulumi.runtime.to_json) if enable_dry_run is not None else None
@@ -411,11 +969,45 @@ class Provider(pulumi.ProviderResource):
             if helm_repository_config_path is None:
                 helm_repository_config_path = _utilities.get_env('PULUMI_K8S_HELM_REPOSITORY_CONFIG_PATH')
             __props__.__dict__["helm_repository_config_path"] = helm_repository_config_path
+            if ignore_fields is None:
+                ignore_fields = _utilities.get_env('PULUMI_K8S_IGNORE_FIELDS')
+            __props__.__dict__["ignore_fields"] = ignore_fields
+            __props__.__dict__["impersonate"] = pulumi.Output.from_input(impersonate).apply(pulumi.runtime.to_json) if impersonate is not None else None
+            __props__.__dict__["insecure_skip_tls_verify"] = pulumi.Output.from_input(insecure_skip_tls_verify).apply(pulumi.runtime.to_json) if insecure_skip_tls_verify is not None else None
+            if kube_version is None:
+                kube_version = _utilities.get_env('PULUMI_K8S_KUBE_VERSION')
+            __props__.__dict__["kube_version"] = kube_version
             if kubeconfig is None:
                 kubeconfig = _utilities.get_env('KUBECONFIG')
             __props__.__dict__["kubeconfig"] = kubeconfig
+            if max_concurrent_watches is None:
+                max_concurrent_watches = _utilities.get_env_int('PULUMI_K8S_MAX_CONCURRENT_WATCHES')
+            __props__.__dict__["max_concurrent_watches"] = pulumi.Output.from_input(max_concurrent_watches).apply(pulumi.runtime.to_json) if max_concurrent_watches is not None else None
+            if migrate_api_versions is None:
+                migrate_api_versions = _utilities.get_env_bool('PULUMI_K8S_MIGRATE_API_VERSIONS')
+            __props__.__dict__["migrate_api_versions"] = pulumi.Output.from_input(migrate_api_versions).apply(pulumi.runtime.to_json) if migrate_api_versions is not None else None
             __props__.__dict__["namespace"] = namespace
+            if policy_directory is None:
+                policy_directory = _utilities.get_env('PULUMI_K8S_POLICY_DIRECTORY')
+            __props__.__dict__["policy_directory"] = policy_directory
+            __props__.__dict__["proxy_url"] = proxy_url
+            if render_yaml_clean is None:
+                render_yaml_clean = _utilities.get_env_bool('PULUMI_K8S_RENDER_YAML_CLEAN')
+            __props__.__dict__["render_yaml_clean"] = pulumi.Output.from_input(render_yaml_clean).apply(pulumi.runtime.to_json) if render_yaml_clean is not None else None
+            if render_yaml_layout is None:
+                render_yaml_layout = _utilities.get_env('PULUMI_K8S_RENDER_YAML_LAYOUT')
+            __props__.__dict__["render_yaml_layout"] = render_yaml_layout
+            if render_yaml_secrets is None:
+                render_yaml_secrets = _utilities.get_env('PULUMI_K8S_RENDER_YAML_SECRETS')
+            __props__.__dict__["render_yaml_secrets"] = render_yaml_secrets
+            if render_yaml_secrets_key is None:
+                render_yaml_secrets_key = _utilities.get_env('PULUMI_K8S_RENDER_YAML_SECRETS_KEY')
+            __props__.__dict__["render_yaml_secrets_key"] = render_yaml_secrets_key
             __props__.__dict__["render_yaml_to_directory"] = render_yaml_to_directory
+            if render_yaml_unknowns is None:
+                render_yaml_unknowns = _utilities.get_env('PULUMI_K8S_RENDER_YAML_UNKNOWNS')
+            __props__.__dict__["render_yaml_unknowns"] = render_yaml_unknowns
+            __props__.__dict__["server"] = server
             if suppress_deprecation_warnings is None:
                 suppress_deprecation_warnings = _utilities.get_env_bool('PULUMI_K8S_SUPPRESS_DEPRECATION_WARNINGS')
             __props__.__dict__["suppress_deprecation_warnings"] = pulumi.Output.from_input(suppress_deprecation_warnings).apply(pulumi.runtime.to_json) if suppress_deprecation_warnings is not None else None
@@ -425,6 +1017,7 @@ class Provider(pulumi.ProviderResource):
             if suppress_helm_release_beta_warning is None:
                 suppress_helm_release_beta_warning = _utilities.get_env_bool('PULUMI_K8S_SUPPRESS_HELM_RELEASE_BETA_WARNING')
             __props__.__dict__["suppress_helm_release_beta_warning"] = pulumi.Output.from_input(suppress_helm_release_beta_warning).apply(pulumi.runtime.to_json) if suppress_helm_release_beta_warning is not None else None
+            __props__.__dict__["token"] = None if token is None else pulumi.Output.secret(token)
         super(Provider, __self__).__init__(
             'kubernetes',
             resource_name,